@@ -0,0 +1,50 @@
+package main
+
+import (
+	"flag"
+	"github.com/rinuccia/travels-api/internal/model"
+	"os"
+)
+
+// runExport implements the `export` subcommand:
+//
+//	main export -tenant 2 -format zip -since 2022-10-01 -o dump.zip
+func runExport(apps *tenantApps, defaultTenant uint32, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	tenant := fs.Uint("tenant", uint(defaultTenant), "id of the tenant to export")
+	format := fs.String("format", model.ExportFormatNDJSON, "ndjson, csv or zip")
+	entity := fs.String("entity", "", "users, locations or visits, required unless format is zip")
+	since := fs.String("since", "", "only records changed since this date (2006-01-02) or RFC 3339 timestamp")
+	out := fs.String("o", "", "output file, stdout when empty")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	updatedSince, err := model.ParseUpdatedSince(*since)
+	if err != nil {
+		return err
+	}
+	opts := model.ExportOptions{Format: *format, Entity: *entity, UpdatedSince: updatedSince}
+	if err = opts.Validate(); err != nil {
+		return err
+	}
+
+	app, err := apps.acquire(uint32(*tenant))
+	if err != nil {
+		return err
+	}
+	defer apps.release(app)
+
+	if *out == "" {
+		return app.services.Export.Write(os.Stdout, opts)
+	}
+	f, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+	if err = app.services.Export.Write(f, opts); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
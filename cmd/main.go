@@ -8,7 +8,11 @@ import (
 	"github.com/rinuccia/travels-api/internal/handler"
 	"github.com/rinuccia/travels-api/internal/repository/postgres"
 	"github.com/rinuccia/travels-api/internal/service"
+	"github.com/rinuccia/travels-api/pkg/mailer"
+	"github.com/rinuccia/travels-api/pkg/ratelimit"
 	"github.com/rinuccia/travels-api/pkg/server"
+	"github.com/rinuccia/travels-api/pkg/storage"
+	"github.com/rinuccia/travels-api/pkg/token"
 	"github.com/sirupsen/logrus"
 	"os"
 	"os/signal"
@@ -18,8 +22,19 @@ import (
 // @title Travels API
 // @version 1.0
 // @description API Server for Travels Application
+// @description Every request is served for one tenant: the tenant of its access token or API key, else the tenant
+// @description named by the X-Tenant-ID header, else the default tenant.
 
 // @host localhost:8181
+
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name Authorization
+// @description API key of a machine client as "ApiKey <key>"
 func main() {
 	logrus.SetFormatter(new(logrus.JSONFormatter))
 
@@ -32,22 +47,100 @@ func main() {
 		logrus.Fatalf("error loading env variables: %s", err.Error())
 	}
 
-	//Postgres
+	//Postgres, the owner connection migrates the schema and looks up tenants, tenant data goes through tenant pools
 	dbPostgres, err := postgres.NewPostgresClient(cfg)
 	if err != nil {
 		logrus.Fatalf("failed to initialize db: %s", err.Error())
 	}
 
-	// Services
-	repository := postgres.NewRepository(dbPostgres)
-	services := service.NewService(repository)
-	handlers := handler.NewHandler(services)
+	unmatched, err := postgres.MigrateLocationCountries(dbPostgres)
+	if err != nil {
+		logrus.Fatalf("failed to migrate location countries: %s", err.Error())
+	}
+	for _, u := range unmatched {
+		logrus.WithFields(logrus.Fields{"tenant_id": u.TenantId, "location_id": u.LocationId}).
+			Warnf("no ISO 3166 country matches %q", u.Country)
+	}
+
+	collisions, err := postgres.MigrateUserEmails(dbPostgres)
+	for _, c := range collisions {
+		logrus.WithFields(logrus.Fields{"tenant_id": c.TenantId, "user_ids": c.UserIds}).
+			Errorf("emails equal to %q ignoring case, change or delete all but one of the users", c.Email)
+	}
+	if err != nil {
+		logrus.Fatalf("failed to migrate user emails: %s", err.Error())
+	}
+
+	// Storage
+	store, err := storage.New(cfg.Storage.Driver, cfg.Storage.Dir)
+	if err != nil {
+		logrus.Fatalf("failed to initialize storage: %s", err.Error())
+	}
+
+	// Auth
+	keys, err := token.ParseKeys(os.Getenv("JWT_KEYS"))
+	if err != nil {
+		logrus.Fatalf("failed to read JWT_KEYS: %s", err.Error())
+	}
+	keyring, err := token.NewKeyring(cfg.Auth.SigningKey, keys)
+	if err != nil {
+		logrus.Fatalf("failed to initialize signing keys: %s", err.Error())
+	}
+
+	// Mail
+	mail, err := mailer.New(mailer.Config{
+		Driver:   cfg.Mail.Driver,
+		From:     cfg.Mail.From,
+		File:     cfg.Mail.File,
+		Host:     cfg.Mail.Host,
+		Port:     cfg.Mail.Port,
+		Username: cfg.Mail.Username,
+		Password: os.Getenv("SMTP_PASSWORD"),
+	})
+	if err != nil {
+		logrus.Fatalf("failed to initialize mailer: %s", err.Error())
+	}
+
+	// Tenants
+	tenancy := service.NewTenancy(postgres.NewTenantRepository(dbPostgres), keyring, cfg.Tenants.DefaultTenant)
+	apps := newTenantApps(cfg, tenancy, store, keyring, mail)
+	app, err := apps.acquire(cfg.Tenants.DefaultTenant)
+	if err != nil {
+		logrus.Fatalf("failed to initialize default tenant: %s", err.Error())
+	}
+	apps.release(app)
+
+	if len(os.Args) > 1 && os.Args[1] == "export" {
+		exportErr := runExport(apps, cfg.Tenants.DefaultTenant, os.Args[2:])
+		if err = apps.close(); err != nil {
+			logrus.Errorf("error occured on tenant connection close: %s", err.Error())
+		}
+		if err = dbPostgres.Close(); err != nil {
+			logrus.Errorf("error occured on db connection close: %s", err.Error())
+		}
+		if exportErr != nil {
+			// a failed dump must not look like a successful one to scripts and cron jobs
+			logrus.Fatalf("export failed: %s", exportErr.Error())
+		}
+		return
+	}
+
+	// Purge
+	ctx, stopPurge := context.WithCancel(context.Background())
+	if cfg.Purge.Retention > 0 {
+		go runPurge(ctx, tenancy, apps, cfg.Purge.Interval, cfg.Purge.Retention)
+	}
 
 	// Run server
-	srv := new(server.Server)
 	router := gin.New()
-	handlers.InitRoutes(router)
-
+	if err = router.SetTrustedProxies(cfg.RateLimit.TrustedProxies); err != nil {
+		logrus.Fatalf("failed to set trusted proxies: %s", err.Error())
+	}
+	handler.InitTenantRoutes(router, tenancy, apps.routes, handler.RateLimits{
+		Store: apps.limits,
+		PerIP: ratelimit.Limit{Rate: cfg.RateLimit.IPRate, Burst: cfg.RateLimit.IPBurst},
+	})
+	srv := new(server.Server)
 	go func() {
 		if err = srv.Run(router, cfg.Port); err != nil {
 			logrus.Fatalf("error occurred while running http server: %s", err.Error())
@@ -60,10 +153,15 @@ func main() {
 	<-quit
 
 	// Shutdown
+	stopPurge()
 	if err = srv.Shutdown(context.Background()); err != nil {
 		logrus.Errorf("error occured on server shutting down: %s", err.Error())
 	}
 
+	if err = apps.close(); err != nil {
+		logrus.Errorf("error occured on tenant connection close: %s", err.Error())
+	}
+
 	if err = dbPostgres.Close(); err != nil {
 		logrus.Errorf("error occured on db connection close: %s", err.Error())
 	}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"github.com/rinuccia/travels-api/internal/service"
+	"github.com/sirupsen/logrus"
+	"time"
+)
+
+// runPurge hard deletes rows deleted longer than retention ago every interval until ctx is done, in every tenant
+// with an open app. Tenants without traffic are purged once they are served again.
+func runPurge(ctx context.Context, tenancy service.Tenancy, apps *tenantApps, interval, retention time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			tenants, err := tenancy.GetAll()
+			if err != nil {
+				logrus.Errorf("purge failed: %s", err.Error())
+				continue
+			}
+			for _, tenant := range tenants {
+				purgeTenant(tenant.TenantId, apps, retention)
+			}
+		}
+	}
+}
+
+func purgeTenant(tenantId uint32, apps *tenantApps, retention time.Duration) {
+	apps.withOpenApp(tenantId, func(app *tenantApp) {
+		purge(logrus.WithField("tenant_id", tenantId), app.services.Purge, retention)
+	})
+}
+
+func purge(log *logrus.Entry, purger service.Purge, retention time.Duration) {
+	purged, err := purger.Purge(retention)
+	if err != nil {
+		log.Errorf("purge failed: %s", err.Error())
+	}
+	if purged.Visits+purged.Trips+purged.Categories+purged.Wishlist > 0 {
+		log.WithFields(logrus.Fields{
+			"visits":     purged.Visits,
+			"trips":      purged.Trips,
+			"categories": purged.Categories,
+			"wishlist":   purged.Wishlist,
+			"photos":     len(purged.Photos),
+		}).Info("purged deleted rows")
+	}
+}
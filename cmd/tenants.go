@@ -0,0 +1,282 @@
+package main
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+	"github.com/rinuccia/travels-api/config"
+	"github.com/rinuccia/travels-api/internal/handler"
+	"github.com/rinuccia/travels-api/internal/policy"
+	"github.com/rinuccia/travels-api/internal/repository/postgres"
+	"github.com/rinuccia/travels-api/internal/service"
+	"github.com/rinuccia/travels-api/pkg/apperrors"
+	"github.com/rinuccia/travels-api/pkg/mailer"
+	"github.com/rinuccia/travels-api/pkg/ratelimit"
+	"github.com/rinuccia/travels-api/pkg/storage"
+	"github.com/rinuccia/travels-api/pkg/token"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// unknownTenantTTL is how long a tenant id that doesn't exist is answered without asking the database again.
+const unknownTenantTTL = 30 * time.Second
+
+// tenantApp is the object graph of a tenant, from its connection pool up to its routes.
+type tenantApp struct {
+	db       *sqlx.DB
+	services *service.Service
+	router   *gin.Engine
+
+	// ready is closed once the app is built or failed to build with err
+	ready chan struct{}
+	err   error
+
+	// users counts the requests and jobs holding the app, lastUsed is when the last one released it
+	users    int
+	lastUsed time.Time
+}
+
+// tenantApps builds the app of a tenant on its first use and keeps it until close, or until the room is needed
+// for another tenant. The rate limit buckets of every tenant live in one store, so they outlive their app.
+// Apps are built outside of mu, which only guards the maps, so a slow tenant doesn't hold up the others.
+type tenantApps struct {
+	cfg     *config.Config
+	tenancy service.Tenancy
+	store   storage.Storage
+	keys    *token.Keyring
+	mail    mailer.Mailer
+	limits  ratelimit.Store
+	maxApps int
+
+	mu      sync.Mutex
+	apps    map[uint32]*tenantApp
+	unknown map[uint32]time.Time
+	swept   time.Time
+	closed  bool
+}
+
+func newTenantApps(cfg *config.Config, tenancy service.Tenancy, store storage.Storage, keys *token.Keyring,
+	mail mailer.Mailer) *tenantApps {
+	return &tenantApps{
+		cfg:     cfg,
+		tenancy: tenancy,
+		store:   store,
+		keys:    keys,
+		mail:    mail,
+		limits:  ratelimit.NewMemory(),
+		maxApps: maxTenantApps(cfg),
+		apps:    map[uint32]*tenantApp{},
+		unknown: map[uint32]time.Time{},
+	}
+}
+
+// maxTenantApps is how many tenant pools of max_conns fit into max_total_conns, at least one.
+func maxTenantApps(cfg *config.Config) int {
+	if cfg.Tenants.MaxConns <= 0 || cfg.Tenants.MaxTotalConns < cfg.Tenants.MaxConns {
+		return 1
+	}
+	return cfg.Tenants.MaxTotalConns / cfg.Tenants.MaxConns
+}
+
+// acquire returns the app of the tenant and keeps it open until it is released, ErrUnknownTenant when there is
+// no such tenant. Once maxApps are open the app idle the longest is closed to make room, ErrTenantsBusy is
+// returned when every app is in use. Callers asking for an app being built wait for it.
+func (a *tenantApps) acquire(tenantId uint32) (*tenantApp, error) {
+	a.mu.Lock()
+	if until, ok := a.unknown[tenantId]; ok && time.Now().Before(until) {
+		a.mu.Unlock()
+		return nil, apperrors.ErrUnknownTenant
+	}
+	if app, ok := a.apps[tenantId]; ok {
+		app.users++
+		a.mu.Unlock()
+		<-app.ready
+		if app.err != nil {
+			a.release(app)
+			return nil, app.err
+		}
+		return app, nil
+	}
+
+	evicted, ok := a.evictIdle()
+	if !ok {
+		a.mu.Unlock()
+		return nil, apperrors.ErrTenantsBusy
+	}
+	app := &tenantApp{ready: make(chan struct{}), users: 1}
+	a.apps[tenantId] = app
+	a.mu.Unlock()
+	if evicted != nil {
+		closeTenantApp(tenantId, evicted)
+	}
+
+	built, err := a.build(tenantId)
+
+	a.mu.Lock()
+	if err == nil && a.closed {
+		built.db.Close()
+		err = errors.New("tenant apps are closed")
+	}
+	if err != nil {
+		app.err = err
+		delete(a.apps, tenantId)
+		if errors.Is(err, apperrors.ErrUnknownTenant) {
+			a.rememberUnknown(tenantId)
+		}
+	} else {
+		app.db, app.services, app.router = built.db, built.services, built.router
+	}
+	close(app.ready)
+	a.mu.Unlock()
+
+	if err != nil {
+		a.release(app)
+		return nil, err
+	}
+	return app, nil
+}
+
+// release gives back an app taken by acquire.
+func (a *tenantApps) release(app *tenantApp) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	app.users--
+	app.lastUsed = time.Now()
+}
+
+// withOpenApp calls fn with the app of the tenant if it is open and reports whether it was. It neither opens
+// a pool nor counts as use of the app, so background jobs don't keep idle apps from being evicted.
+func (a *tenantApps) withOpenApp(tenantId uint32, fn func(app *tenantApp)) bool {
+	a.mu.Lock()
+	app, ok := a.apps[tenantId]
+	if ok {
+		select {
+		case <-app.ready:
+			ok = app.err == nil
+		default:
+			ok = false
+		}
+	}
+	if !ok {
+		a.mu.Unlock()
+		return false
+	}
+	app.users++
+	a.mu.Unlock()
+
+	defer func() {
+		a.mu.Lock()
+		app.users--
+		a.mu.Unlock()
+	}()
+	fn(app)
+	return true
+}
+
+// rememberUnknown answers the tenant id as unknown for unknownTenantTTL, expired ids are swept as often.
+func (a *tenantApps) rememberUnknown(tenantId uint32) {
+	now := time.Now()
+	if now.Sub(a.swept) >= unknownTenantTTL {
+		for id, until := range a.unknown {
+			if now.After(until) {
+				delete(a.unknown, id)
+			}
+		}
+		a.swept = now
+	}
+	a.unknown[tenantId] = now.Add(unknownTenantTTL)
+}
+
+// build builds the app of the tenant.
+func (a *tenantApps) build(tenantId uint32) (*tenantApp, error) {
+	if _, err := a.tenancy.GetById(tenantId); err != nil {
+		return nil, err
+	}
+	db, err := postgres.NewTenantClient(a.cfg, tenantId)
+	if err != nil {
+		return nil, err
+	}
+	repository := postgres.NewRepository(db)
+	services := service.NewService(repository, a.store, service.AuthConfig{
+		Keys:       a.keys,
+		TenantId:   tenantId,
+		AccessTTL:  a.cfg.Auth.AccessTTL,
+		RefreshTTL: a.cfg.Auth.RefreshTTL,
+	}, service.UserConfig{
+		LowercaseEmailLocalPart: a.cfg.Users.LowercaseEmailLocalPart,
+		VerifyTTL:               a.cfg.Users.VerifyTTL,
+		VerifyURL:               a.cfg.Users.VerifyURL,
+	}, a.mail)
+
+	router := gin.New()
+	if err = router.SetTrustedProxies(a.cfg.RateLimit.TrustedProxies); err != nil {
+		db.Close()
+		return nil, err
+	}
+	handler.NewHandler(services, policy.New(repository.OwnerRepository)).InitRoutes(router, handler.RateLimits{
+		Store:  ratelimit.Prefixed(a.limits, "tenant:"+strconv.FormatUint(uint64(tenantId), 10)+":"),
+		Reads:  ratelimit.Limit{Rate: a.cfg.RateLimit.ReadRate, Burst: a.cfg.RateLimit.ReadBurst},
+		Writes: ratelimit.Limit{Rate: a.cfg.RateLimit.WriteRate, Burst: a.cfg.RateLimit.WriteBurst},
+	}, a.cfg.Import.MaxBodyBytes)
+
+	return &tenantApp{db: db, services: services, router: router}, nil
+}
+
+// evictIdle makes room for another app, it removes the app idle the longest from the map once maxApps are open
+// and returns it to be closed outside of mu. It reports false when every app is in use.
+func (a *tenantApps) evictIdle() (*tenantApp, bool) {
+	if len(a.apps) < a.maxApps {
+		return nil, true
+	}
+	var idleId uint32
+	var idle *tenantApp
+	for id, app := range a.apps {
+		if app.users == 0 && (idle == nil || app.lastUsed.Before(idle.lastUsed)) {
+			idleId, idle = id, app
+		}
+	}
+	if idle == nil {
+		return nil, false
+	}
+	delete(a.apps, idleId)
+	return idle, true
+}
+
+func closeTenantApp(tenantId uint32, app *tenantApp) {
+	if err := app.db.Close(); err != nil {
+		logrus.WithField("tenant_id", tenantId).Errorf("error occured on tenant connection close: %s", err.Error())
+	}
+}
+
+// routes implements handler.TenantRoutes, the app is held until the request is served.
+func (a *tenantApps) routes(tenantId uint32) (http.Handler, error) {
+	app, err := a.acquire(tenantId)
+	if err != nil {
+		return nil, err
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer a.release(app)
+		app.router.ServeHTTP(w, r)
+	}), nil
+}
+
+// close closes the connection pools of every app built, apps still being built are closed once they are.
+func (a *tenantApps) close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.closed = true
+	var firstErr error
+	for id, app := range a.apps {
+		if app.db == nil {
+			continue
+		}
+		if err := app.db.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		delete(a.apps, id)
+	}
+	return firstErr
+}
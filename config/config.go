@@ -1,6 +1,9 @@
 package config
 
-import "github.com/ilyakaznacheev/cleanenv"
+import (
+	"github.com/ilyakaznacheev/cleanenv"
+	"time"
+)
 
 type Config struct {
 	Port string `yaml:"port"`
@@ -10,6 +13,49 @@ type Config struct {
 		Port     string `yaml:"port"`
 		DBName   string `yaml:"db_name"`
 	} `yaml:"db"`
+	Storage struct {
+		Driver string `yaml:"driver" env-default:"local"`
+		Dir    string `yaml:"dir" env-default:"./uploads"`
+	} `yaml:"storage"`
+	Auth struct {
+		AccessTTL  time.Duration `yaml:"access_ttl" env-default:"15m"`
+		RefreshTTL time.Duration `yaml:"refresh_ttl" env-default:"720h"`
+		SigningKey string        `yaml:"signing_key"`
+	} `yaml:"auth"`
+	RateLimit struct {
+		ReadRate       float64  `yaml:"read_rate" env-default:"10"`
+		ReadBurst      int      `yaml:"read_burst" env-default:"40"`
+		WriteRate      float64  `yaml:"write_rate" env-default:"2"`
+		WriteBurst     int      `yaml:"write_burst" env-default:"10"`
+		IPRate         float64  `yaml:"ip_rate" env-default:"20"`
+		IPBurst        int      `yaml:"ip_burst" env-default:"60"`
+		TrustedProxies []string `yaml:"trusted_proxies"`
+	} `yaml:"rate_limit"`
+	Users struct {
+		LowercaseEmailLocalPart bool          `yaml:"lowercase_email_local_part" env-default:"false"`
+		VerifyTTL               time.Duration `yaml:"verify_ttl" env-default:"24h"`
+		VerifyURL               string        `yaml:"verify_url" env-default:"http://localhost:8181/verify-email"`
+	} `yaml:"users"`
+	Mail struct {
+		Driver   string `yaml:"driver" env-default:"log"`
+		From     string `yaml:"from" env-default:"noreply@travels.local"`
+		File     string `yaml:"file"`
+		Host     string `yaml:"host"`
+		Port     string `yaml:"port" env-default:"587"`
+		Username string `yaml:"username"`
+	} `yaml:"mail"`
+	Tenants struct {
+		DefaultTenant uint32 `yaml:"default_tenant" env-default:"1"`
+		MaxConns      int    `yaml:"max_conns" env-default:"10"`
+		MaxTotalConns int    `yaml:"max_total_conns" env-default:"100"`
+	} `yaml:"tenants"`
+	Import struct {
+		MaxBodyBytes int64 `yaml:"max_body_bytes" env-default:"10485760"`
+	} `yaml:"import"`
+	Purge struct {
+		Retention time.Duration `yaml:"retention" env-default:"720h"`
+		Interval  time.Duration `yaml:"interval" env-default:"1h"`
+	} `yaml:"purge"`
 }
 
 var instance *Config
@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/internal/service"
+	"github.com/rinuccia/travels-api/pkg/apperrors"
+	"net/http"
+)
+
+type apiKeyHandler struct {
+	repo service.APIKey
+}
+
+func newAPIKeyHandler(repository service.APIKey) *apiKeyHandler {
+	return &apiKeyHandler{
+		repo: repository,
+	}
+}
+
+// getAllAPIKeys godoc
+// @Summary Returns a list of all API keys with their usage, admins only
+// @Tags apikey
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} model.APIKeys
+// @Failure 401 {object} errResponse
+// @Failure 403 {object} problemResponse
+// @Failure 500 {object} errResponse
+// @Router /apikeys [get]
+func (h *apiKeyHandler) getAllAPIKeys(c *gin.Context) {
+	keys, err := h.repo.GetAll()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, newErrResponse("something went wrong"))
+		return
+	}
+
+	c.JSON(http.StatusOK, keys)
+}
+
+// createAPIKey godoc
+// @Summary Creates API key for a machine client, the key is returned only once, admins only
+// @Tags apikey
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param input body model.APIKeyRequest true "Key name, scopes and optional expiry"
+// @Success 200 {object} model.NewAPIKey
+// @Failure 400,401 {object} errResponse
+// @Failure 403 {object} problemResponse
+// @Failure 500 {object} errResponse
+// @Router /apikey/new [post]
+func (h *apiKeyHandler) createAPIKey(c *gin.Context) {
+	request := model.APIKeyRequest{}
+	err := c.BindJSON(&request)
+	validationErr := validate.Struct(request)
+	if err != nil || validationErr != nil {
+		c.JSON(http.StatusBadRequest, newErrResponse("invalid input body"))
+		return
+	}
+
+	key, err := h.repo.Create(request)
+	if errors.Is(err, apperrors.ErrIncorrectQuery) {
+		c.JSON(http.StatusBadRequest, newErrResponse("invalid input body"))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, newErrResponse("something went wrong"))
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, key)
+}
+
+// revokeAPIKey godoc
+// @Summary Revokes API key based on given ID, admins only
+// @Tags apikey
+// @Security BearerAuth
+// @Param id path integer true "API key ID"
+// @Success 204
+// @Failure 400,401,404 {object} errResponse
+// @Failure 403 {object} problemResponse
+// @Failure 500 {object} errResponse
+// @Router /apikey/{id} [delete]
+func (h *apiKeyHandler) revokeAPIKey(c *gin.Context) {
+	err := h.repo.Revoke(c.Param("id"))
+	if errors.Is(err, apperrors.ErrIncorrectQuery) {
+		c.JSON(http.StatusBadRequest, newErrResponse(err.Error()))
+		return
+	}
+	if errors.Is(err, apperrors.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, newErrResponse(err.Error()))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, newErrResponse("something went wrong"))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/internal/service"
+	mock_service "github.com/rinuccia/travels-api/internal/service/mocks"
+	"github.com/rinuccia/travels-api/pkg/apperrors"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAPIKeyHandler_createAPIKey(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockAPIKey)
+
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	expiresAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	testTable := []struct {
+		name                 string
+		inputBody            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "Ok",
+			inputBody: `{"name":"partner","scopes":["visits:write"],"role":"admin","expires_at":"2030-01-01T00:00:00Z"}`,
+			mockBehavior: func(s *mock_service.MockAPIKey) {
+				s.EXPECT().Create(model.APIKeyRequest{
+					Name: "partner", Scopes: []string{"visits:write"}, Role: "admin", ExpiresAt: &expiresAt,
+				}).Return(model.NewAPIKey{
+					APIKey: model.APIKey{
+						KeyId: 1, Name: "partner", Prefix: "tk_abcdefgh", Scopes: []string{"visits:write"},
+						Role: "admin", ExpiresAt: &expiresAt, CreatedAt: createdAt,
+					},
+					Key: "tk_abcdefgh-rest",
+				}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{"key_id":1,"name":"partner","prefix":"tk_abcdefgh","scopes":["visits:write"],` +
+				`"role":"admin","expires_at":"2030-01-01T00:00:00Z","created_at":"2024-01-01T00:00:00Z","usage_count":0,` +
+				`"key":"tk_abcdefgh-rest"}`,
+		},
+		{
+			name:                 "Traveller Role",
+			inputBody:            `{"name":"partner","scopes":["visits:write"],"role":"traveller"}`,
+			mockBehavior:         func(s *mock_service.MockAPIKey) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"invalid input body"}`,
+		},
+		{
+			name:                 "Unknown Scope",
+			inputBody:            `{"name":"partner","scopes":["users:write"]}`,
+			mockBehavior:         func(s *mock_service.MockAPIKey) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"invalid input body"}`,
+		},
+		{
+			name:                 "No Scopes",
+			inputBody:            `{"name":"partner","scopes":[]}`,
+			mockBehavior:         func(s *mock_service.MockAPIKey) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"invalid input body"}`,
+		},
+		{
+			name:      "Expired",
+			inputBody: `{"name":"partner","scopes":["visits:write"],"expires_at":"2030-01-01T00:00:00Z"}`,
+			mockBehavior: func(s *mock_service.MockAPIKey) {
+				s.EXPECT().Create(gomock.Any()).Return(model.NewAPIKey{}, apperrors.ErrIncorrectQuery)
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"invalid input body"}`,
+		},
+	}
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			apiKey := mock_service.NewMockAPIKey(controller)
+			test.mockBehavior(apiKey)
+
+			serv := &service.Service{APIKey: apiKey}
+			handle := NewHandler(serv, nil)
+
+			router := gin.New()
+			router.POST("/apikey/new", handle.createAPIKey)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("POST", "/apikey/new", strings.NewReader(test.inputBody))
+
+			router.ServeHTTP(w, r)
+
+			body := strings.Trim(w.Body.String(), "\n")
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, body, test.expectedResponseBody)
+		})
+	}
+}
+
+func TestAPIKeyHandler_revokeAPIKey(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockAPIKey)
+
+	testTable := []struct {
+		name               string
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+	}{
+		{
+			name: "Ok",
+			mockBehavior: func(s *mock_service.MockAPIKey) {
+				s.EXPECT().Revoke("1").Return(nil)
+			},
+			expectedStatusCode: http.StatusNoContent,
+		},
+		{
+			name: "Not Found",
+			mockBehavior: func(s *mock_service.MockAPIKey) {
+				s.EXPECT().Revoke("1").Return(apperrors.ErrRecordNotFound)
+			},
+			expectedStatusCode: http.StatusNotFound,
+		},
+	}
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			apiKey := mock_service.NewMockAPIKey(controller)
+			test.mockBehavior(apiKey)
+
+			serv := &service.Service{APIKey: apiKey}
+			handle := NewHandler(serv, nil)
+
+			router := gin.New()
+			router.DELETE("/apikey/:id", handle.revokeAPIKey)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("DELETE", "/apikey/1", nil)
+
+			router.ServeHTTP(w, r)
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/internal/service"
+	"net/http"
+)
+
+type auditHandler struct {
+	repo service.Audit
+}
+
+func newAuditHandler(repository service.Audit) *auditHandler {
+	return &auditHandler{
+		repo: repository,
+	}
+}
+
+// getAuditLog godoc
+// @Summary Returns changes of users, locations and visits newest first, admins only
+// @Tags audit
+// @Security BearerAuth
+// @Produce json
+// @Param entity query string false "Entity type" Enums(user, location, visit)
+// @Param entity_id query string false "Entity ID"
+// @Param actor query string false "Actor such as user:1, apikey:2 or anonymous"
+// @Param from query string false "Changes at or after this RFC 3339 timestamp"
+// @Param to query string false "Changes before this RFC 3339 timestamp"
+// @Param page query integer false "Page number" default(1)
+// @Param per_page query integer false "Records per page" default(100)
+// @Success 200 {object} model.AuditLog
+// @Failure 400,401 {object} errResponse
+// @Failure 403 {object} problemResponse
+// @Failure 500 {object} errResponse
+// @Router /audit [get]
+func (h *auditHandler) getAuditLog(c *gin.Context) {
+	filter := model.AuditFilter{Page: 1, PerPage: 100}
+	if err := c.ShouldBindQuery(&filter); err != nil || validate.Struct(filter) != nil {
+		c.JSON(http.StatusBadRequest, newErrResponse("invalid query parameters"))
+		return
+	}
+
+	log, err := h.repo.GetAll(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, newErrResponse("something went wrong"))
+		return
+	}
+
+	c.JSON(http.StatusOK, log)
+}
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/internal/service"
+	mock_service "github.com/rinuccia/travels-api/internal/service/mocks"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAuditHandler_getAuditLog(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockAudit)
+
+	createdAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	testTable := []struct {
+		name                 string
+		query                string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:  "Ok",
+			query: "?entity=visit&entity_id=7&from=2024-05-01T00:00:00Z",
+			mockBehavior: func(s *mock_service.MockAudit) {
+				s.EXPECT().GetAll(model.AuditFilter{
+					Entity: "visit", EntityId: "7", From: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), Page: 1, PerPage: 100,
+				}).Return(model.AuditLog{
+					List: []model.AuditRecord{{
+						AuditId: 1, Actor: "user:1", Action: "delete", EntityType: "visit", EntityId: "7",
+						Before: []byte(`{"mark":3}`), RequestId: "req-1", CreatedAt: createdAt,
+					}},
+					Page:    1,
+					PerPage: 100,
+				}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{"list":[{"audit_id":1,"actor":"user:1","action":"delete","entity_type":"visit",` +
+				`"entity_id":"7","before":{"mark":3},"request_id":"req-1","created_at":"2024-05-01T12:00:00Z"}],` +
+				`"page":1,"per_page":100}`,
+		},
+		{
+			name:                 "Unknown Entity",
+			query:                "?entity=trip",
+			mockBehavior:         func(s *mock_service.MockAudit) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"invalid query parameters"}`,
+		},
+		{
+			name:                 "Invalid Time",
+			query:                "?from=yesterday",
+			mockBehavior:         func(s *mock_service.MockAudit) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"invalid query parameters"}`,
+		},
+		{
+			name:                 "Page Too Large",
+			query:                "?per_page=501",
+			mockBehavior:         func(s *mock_service.MockAudit) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"invalid query parameters"}`,
+		},
+		{
+			name:  "Service Error",
+			query: "",
+			mockBehavior: func(s *mock_service.MockAudit) {
+				s.EXPECT().GetAll(gomock.Any()).Return(model.AuditLog{}, errors.New("something went wrong"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"error":"something went wrong"}`,
+		},
+	}
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			audit := mock_service.NewMockAudit(controller)
+			test.mockBehavior(audit)
+
+			serv := &service.Service{Audit: audit}
+			handle := NewHandler(serv, nil)
+
+			router := gin.New()
+			router.GET("/audit", handle.getAuditLog)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "/audit"+test.query, nil)
+
+			router.ServeHTTP(w, r)
+
+			body := strings.Trim(w.Body.String(), "\n")
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, body, test.expectedResponseBody)
+		})
+	}
+}
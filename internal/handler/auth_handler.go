@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/internal/service"
+	"github.com/rinuccia/travels-api/pkg/apperrors"
+	"net/http"
+)
+
+type authHandler struct {
+	repo service.Auth
+}
+
+func newAuthHandler(repository service.Auth) *authHandler {
+	return &authHandler{
+		repo: repository,
+	}
+}
+
+// register godoc
+// @Summary Registers user with a password and mails a verification for the email
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param input body model.Registration true "User info and password"
+// @Success 200 {object} model.User
+// @Failure 400 {object} errResponse
+// @Failure 500 {object} errResponse
+// @Router /auth/register [post]
+func (h *authHandler) register(c *gin.Context) {
+	registration := model.Registration{}
+	err := c.BindJSON(&registration)
+	validationErr := validate.Struct(registration)
+	if err != nil || validationErr != nil {
+		c.JSON(http.StatusBadRequest, newErrResponse("invalid input body"))
+		return
+	}
+
+	user, err := h.repo.Register(auditActor(c), registration)
+	err = ignoreMailNotSent(err)
+	if errors.Is(err, apperrors.ErrIncorrectQuery) {
+		c.JSON(http.StatusBadRequest, newErrResponse(err.Error()))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, newErrResponse("something went wrong"))
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// login godoc
+// @Summary Logs in with email and password
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param input body model.Credentials true "Email and password"
+// @Success 200 {object} model.TokenPair
+// @Failure 400,401 {object} errResponse
+// @Failure 500 {object} errResponse
+// @Router /auth/login [post]
+func (h *authHandler) login(c *gin.Context) {
+	credentials := model.Credentials{}
+	err := c.BindJSON(&credentials)
+	validationErr := validate.Struct(credentials)
+	if err != nil || validationErr != nil {
+		c.JSON(http.StatusBadRequest, newErrResponse("invalid input body"))
+		return
+	}
+
+	tokens, err := h.repo.Login(credentials)
+	respondTokens(c, tokens, err)
+}
+
+// refresh godoc
+// @Summary Exchanges refresh token for new access and refresh tokens, the old refresh token stops working
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param input body model.RefreshRequest true "Refresh token"
+// @Success 200 {object} model.TokenPair
+// @Failure 400,401 {object} errResponse
+// @Failure 500 {object} errResponse
+// @Router /auth/refresh [post]
+func (h *authHandler) refresh(c *gin.Context) {
+	request := model.RefreshRequest{}
+	err := c.BindJSON(&request)
+	validationErr := validate.Struct(request)
+	if err != nil || validationErr != nil {
+		c.JSON(http.StatusBadRequest, newErrResponse("invalid input body"))
+		return
+	}
+
+	tokens, err := h.repo.Refresh(request.RefreshToken)
+	respondTokens(c, tokens, err)
+}
+
+// logout godoc
+// @Summary Revokes refresh token
+// @Tags auth
+// @Accept json
+// @Param input body model.RefreshRequest true "Refresh token"
+// @Success 204
+// @Failure 400 {object} errResponse
+// @Failure 500 {object} errResponse
+// @Router /auth/logout [post]
+func (h *authHandler) logout(c *gin.Context) {
+	request := model.RefreshRequest{}
+	err := c.BindJSON(&request)
+	validationErr := validate.Struct(request)
+	if err != nil || validationErr != nil {
+		c.JSON(http.StatusBadRequest, newErrResponse("invalid input body"))
+		return
+	}
+
+	if err = h.repo.Logout(request.RefreshToken); err != nil {
+		c.JSON(http.StatusInternalServerError, newErrResponse("something went wrong"))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func respondTokens(c *gin.Context, tokens model.TokenPair, err error) {
+	if errors.Is(err, apperrors.ErrInvalidCredentials) || errors.Is(err, apperrors.ErrUnauthorized) {
+		c.JSON(http.StatusUnauthorized, newErrResponse(err.Error()))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, newErrResponse("something went wrong"))
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, tokens)
+}
@@ -0,0 +1,309 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/internal/service"
+	mock_service "github.com/rinuccia/travels-api/internal/service/mocks"
+	"github.com/rinuccia/travels-api/pkg/apperrors"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAuthHandler_register(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockAuth)
+
+	user := model.User{UserId: 1, Email: "test@gmail.com", FirstName: "John", LastName: "Smith", Gender: "m"}
+
+	testTable := []struct {
+		name                 string
+		inputBody            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "Ok",
+			inputBody: `{"user_id":1,"email":"test@gmail.com","first_name":"John","last_name":"Smith","gender":"m","password":"s3cret-pass"}`,
+			mockBehavior: func(s *mock_service.MockAuth) {
+				s.EXPECT().Register(model.Actor{Actor: "anonymous"}, model.Registration{User: user, Password: "s3cret-pass"}).
+					Return(user, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"user_id":1,"email":"test@gmail.com","first_name":"John","last_name":"Smith","gender":"m"}`,
+		},
+		{
+			name:                 "Short Password",
+			inputBody:            `{"user_id":1,"email":"test@gmail.com","first_name":"John","last_name":"Smith","gender":"m","password":"short"}`,
+			mockBehavior:         func(s *mock_service.MockAuth) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"invalid input body"}`,
+		},
+		{
+			name:      "User Exists",
+			inputBody: `{"user_id":1,"email":"test@gmail.com","first_name":"John","last_name":"Smith","gender":"m","password":"s3cret-pass"}`,
+			mockBehavior: func(s *mock_service.MockAuth) {
+				s.EXPECT().Register(model.Actor{Actor: "anonymous"}, model.Registration{User: user, Password: "s3cret-pass"}).
+					Return(user, apperrors.ErrIncorrectQuery)
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"incorrect query"}`,
+		},
+		{
+			name:      "Verification Not Sent",
+			inputBody: `{"user_id":1,"email":"test@gmail.com","first_name":"John","last_name":"Smith","gender":"m","password":"s3cret-pass"}`,
+			mockBehavior: func(s *mock_service.MockAuth) {
+				s.EXPECT().Register(model.Actor{Actor: "anonymous"}, model.Registration{User: user, Password: "s3cret-pass"}).
+					Return(user, apperrors.ErrMailNotSent)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"user_id":1,"email":"test@gmail.com","first_name":"John","last_name":"Smith","gender":"m"}`,
+		},
+	}
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			auth := mock_service.NewMockAuth(controller)
+			test.mockBehavior(auth)
+
+			serv := &service.Service{Auth: auth}
+			handle := NewHandler(serv, nil)
+
+			router := gin.New()
+			router.POST("/auth/register", handle.register)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("POST", "/auth/register", strings.NewReader(test.inputBody))
+
+			router.ServeHTTP(w, r)
+
+			body := strings.Trim(w.Body.String(), "\n")
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, body, test.expectedResponseBody)
+		})
+	}
+}
+
+func TestAuthHandler_login(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockAuth)
+
+	credentials := model.Credentials{Email: "test@gmail.com", Password: "s3cret-pass"}
+
+	testTable := []struct {
+		name                 string
+		inputBody            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "Ok",
+			inputBody: `{"email":"test@gmail.com","password":"s3cret-pass"}`,
+			mockBehavior: func(s *mock_service.MockAuth) {
+				s.EXPECT().Login(credentials).Return(model.TokenPair{
+					AccessToken: "access", RefreshToken: "refresh", TokenType: "Bearer", ExpiresIn: 900,
+				}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"access_token":"access","refresh_token":"refresh","token_type":"Bearer","expires_in":900}`,
+		},
+		{
+			name:      "Wrong Password",
+			inputBody: `{"email":"test@gmail.com","password":"s3cret-pass"}`,
+			mockBehavior: func(s *mock_service.MockAuth) {
+				s.EXPECT().Login(credentials).Return(model.TokenPair{}, apperrors.ErrInvalidCredentials)
+			},
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedResponseBody: `{"error":"invalid email or password"}`,
+		},
+		{
+			name:                 "Invalid Email",
+			inputBody:            `{"email":"test","password":"s3cret-pass"}`,
+			mockBehavior:         func(s *mock_service.MockAuth) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"invalid input body"}`,
+		},
+		{
+			name:      "Service Error",
+			inputBody: `{"email":"test@gmail.com","password":"s3cret-pass"}`,
+			mockBehavior: func(s *mock_service.MockAuth) {
+				s.EXPECT().Login(credentials).Return(model.TokenPair{}, errors.New("something went wrong"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"error":"something went wrong"}`,
+		},
+	}
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			auth := mock_service.NewMockAuth(controller)
+			test.mockBehavior(auth)
+
+			serv := &service.Service{Auth: auth}
+			handle := NewHandler(serv, nil)
+
+			router := gin.New()
+			router.POST("/auth/login", handle.login)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("POST", "/auth/login", strings.NewReader(test.inputBody))
+
+			router.ServeHTTP(w, r)
+
+			body := strings.Trim(w.Body.String(), "\n")
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, body, test.expectedResponseBody)
+		})
+	}
+}
+
+func TestAuthHandler_refresh(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockAuth)
+
+	testTable := []struct {
+		name                 string
+		inputBody            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "Ok",
+			inputBody: `{"refresh_token":"old"}`,
+			mockBehavior: func(s *mock_service.MockAuth) {
+				s.EXPECT().Refresh("old").Return(model.TokenPair{
+					AccessToken: "access", RefreshToken: "new", TokenType: "Bearer", ExpiresIn: 900,
+				}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"access_token":"access","refresh_token":"new","token_type":"Bearer","expires_in":900}`,
+		},
+		{
+			name:      "Used Token",
+			inputBody: `{"refresh_token":"old"}`,
+			mockBehavior: func(s *mock_service.MockAuth) {
+				s.EXPECT().Refresh("old").Return(model.TokenPair{}, apperrors.ErrUnauthorized)
+			},
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedResponseBody: `{"error":"unauthorized"}`,
+		},
+		{
+			name:                 "Missing Token",
+			inputBody:            `{}`,
+			mockBehavior:         func(s *mock_service.MockAuth) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"invalid input body"}`,
+		},
+	}
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			auth := mock_service.NewMockAuth(controller)
+			test.mockBehavior(auth)
+
+			serv := &service.Service{Auth: auth}
+			handle := NewHandler(serv, nil)
+
+			router := gin.New()
+			router.POST("/auth/refresh", handle.refresh)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("POST", "/auth/refresh", strings.NewReader(test.inputBody))
+
+			router.ServeHTTP(w, r)
+
+			body := strings.Trim(w.Body.String(), "\n")
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, body, test.expectedResponseBody)
+		})
+	}
+}
+
+func TestAuthHandler_authenticate(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockAuth)
+
+	testTable := []struct {
+		name                 string
+		authorization        string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:          "Ok",
+			authorization: "Bearer access",
+			mockBehavior: func(s *mock_service.MockAuth) {
+				s.EXPECT().Authenticate("access").Return(model.Identity{UserId: 7, Role: model.RoleTraveller}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"user_id":7,"role":"traveller"}`,
+		},
+		{
+			name:                 "Missing Token",
+			mockBehavior:         func(s *mock_service.MockAuth) {},
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedResponseBody: `{"error":"unauthorized"}`,
+		},
+		{
+			name:                 "Other Scheme",
+			authorization:        "Basic dXNlcjpwYXNz",
+			mockBehavior:         func(s *mock_service.MockAuth) {},
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedResponseBody: `{"error":"unauthorized"}`,
+		},
+		{
+			name:          "Expired Token",
+			authorization: "Bearer access",
+			mockBehavior: func(s *mock_service.MockAuth) {
+				s.EXPECT().Authenticate("access").Return(model.Identity{}, apperrors.ErrUnauthorized)
+			},
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedResponseBody: `{"error":"unauthorized"}`,
+		},
+	}
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			auth := mock_service.NewMockAuth(controller)
+			test.mockBehavior(auth)
+
+			serv := &service.Service{Auth: auth}
+			handle := NewHandler(serv, nil)
+
+			router := gin.New()
+			router.GET("/me", handle.authenticate(), func(c *gin.Context) {
+				identity, _ := c.Get(identityKey)
+				c.JSON(http.StatusOK, identity)
+			})
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "/me", nil)
+			if test.authorization != "" {
+				r.Header.Set("Authorization", test.authorization)
+			}
+
+			router.ServeHTTP(w, r)
+
+			body := strings.Trim(w.Body.String(), "\n")
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, body, test.expectedResponseBody)
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/internal/service"
+	"github.com/rinuccia/travels-api/pkg/apperrors"
+	"net/http"
+)
+
+type categoryHandler struct {
+	repo service.Category
+}
+
+func newCategoryHandler(repository service.Category) *categoryHandler {
+	return &categoryHandler{
+		repo: repository,
+	}
+}
+
+// getAllCategories godoc
+// @Summary Returns a list of all location categories
+// @Tags category
+// @Produce json
+// @Success 200 {object} model.Categories
+// @Failure 500 {object} errResponse
+// @Router /categories [get]
+func (h *categoryHandler) getAllCategories(c *gin.Context) {
+	categories, err := h.repo.GetAll()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, newErrResponse("something went wrong"))
+		return
+	}
+
+	c.JSON(http.StatusOK, categories)
+}
+
+// getCategoryById godoc
+// @Summary Returns category based on given ID
+// @Tags category
+// @Produce json
+// @Param id path integer true "Category ID"
+// @Success 200 {object} model.Category
+// @Failure 404 {object} errResponse
+// @Router /category/{id} [get]
+func (h *categoryHandler) getCategoryById(c *gin.Context) {
+	id := c.Param("id")
+	category, err := h.repo.GetById(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, newErrResponse(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, category)
+}
+
+// createCategory godoc
+// @Summary Create category
+// @Tags category
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param input body model.Category true "Category Info"
+// @Success 200 {object} model.Category
+// @Failure 400,401 {object} errResponse
+// @Failure 403 {object} problemResponse
+// @Router /category/new [post]
+func (h *categoryHandler) createCategory(c *gin.Context) {
+	category := model.Category{}
+	err := c.BindJSON(&category)
+	validationErr := validate.Struct(category)
+	if err != nil || validationErr != nil {
+		c.JSON(http.StatusBadRequest, newErrResponse("invalid input body"))
+		return
+	}
+
+	category, err = h.repo.Create(category)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, newErrResponse(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, category)
+}
+
+// updateCategory godoc
+// @Summary Rename category based on given ID
+// @Tags category
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param id path integer true "Category ID"
+// @Param input body model.Category true "Category Info"
+// @Success 204
+// @Failure 400,401,404 {object} errResponse
+// @Failure 403 {object} problemResponse
+// @Router /category/{id} [put]
+func (h *categoryHandler) updateCategory(c *gin.Context) {
+	id := c.Param("id")
+	category := model.Category{}
+	err := c.BindJSON(&category)
+	validationErr := validate.Struct(category)
+	if err != nil || validationErr != nil {
+		c.JSON(http.StatusBadRequest, newErrResponse("invalid input body"))
+		return
+	}
+
+	err = h.repo.Update(id, category)
+	if errors.Is(err, apperrors.ErrIncorrectQuery) {
+		c.JSON(http.StatusBadRequest, newErrResponse(err.Error()))
+		return
+	}
+	if errors.Is(err, apperrors.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, newErrResponse(err.Error()))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// deleteCategoryById godoc
+// @Summary Removes category based on given ID, its locations read as uncategorized until it is restored
+// @Tags category
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path integer true "Category ID"
+// @Success 204
+// @Failure 401,404 {object} errResponse
+// @Failure 403 {object} problemResponse
+// @Failure 500 {object} errResponse
+// @Router /category/{id} [delete]
+func (h *categoryHandler) deleteCategoryById(c *gin.Context) {
+	id := c.Param("id")
+	err := h.repo.DeleteById(id)
+	if errors.Is(err, apperrors.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, newErrResponse(err.Error()))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, newErrResponse("something went wrong"))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// restoreCategory godoc
+// @Summary Restores deleted category based on given ID, fails when another category took its name
+// @Tags category
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path integer true "Category ID"
+// @Success 204
+// @Failure 400,401,404 {object} errResponse
+// @Failure 403 {object} problemResponse
+// @Failure 500 {object} errResponse
+// @Router /category/{id}/restore [post]
+func (h *categoryHandler) restoreCategory(c *gin.Context) {
+	err := h.repo.Restore(c.Param("id"))
+	respondRestore(c, err)
+}
@@ -0,0 +1,196 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/internal/service"
+	mock_service "github.com/rinuccia/travels-api/internal/service/mocks"
+	"github.com/rinuccia/travels-api/pkg/apperrors"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCategoryHandler_createCategory(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockCategory, category model.Category)
+
+	testTable := []struct {
+		name                 string
+		inputBody            string
+		inputCategory        model.Category
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:          "Ok",
+			inputBody:     `{"category_id":1,"name":"museum"}`,
+			inputCategory: model.Category{CategoryId: 1, Name: "museum"},
+			mockBehavior: func(s *mock_service.MockCategory, category model.Category) {
+				s.EXPECT().Create(category).Return(category, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"category_id":1,"name":"museum"}`,
+		},
+		{
+			name:          "Category exists",
+			inputBody:     `{"category_id":1,"name":"museum"}`,
+			inputCategory: model.Category{CategoryId: 1, Name: "museum"},
+			mockBehavior: func(s *mock_service.MockCategory, category model.Category) {
+				s.EXPECT().Create(category).Return(model.Category{}, apperrors.ErrIncorrectQuery)
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"incorrect query"}`,
+		},
+		{
+			name:                 "Invalid Input",
+			inputBody:            `{"category_id":1}`,
+			mockBehavior:         func(s *mock_service.MockCategory, category model.Category) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"invalid input body"}`,
+		},
+	}
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			category := mock_service.NewMockCategory(controller)
+			test.mockBehavior(category, test.inputCategory)
+
+			serv := &service.Service{Category: category}
+			handle := NewHandler(serv, nil)
+
+			router := gin.New()
+			router.POST("/category/new", handle.createCategory)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("POST", "/category/new", strings.NewReader(test.inputBody))
+
+			router.ServeHTTP(w, r)
+
+			body := strings.Trim(w.Body.String(), "\n")
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, body, test.expectedResponseBody)
+		})
+	}
+}
+
+func TestCategoryHandler_deleteCategoryById(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockCategory, id string)
+
+	testTable := []struct {
+		name                 string
+		id                   string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "Ok",
+			id:   "1",
+			mockBehavior: func(s *mock_service.MockCategory, id string) {
+				s.EXPECT().DeleteById(id).Return(nil)
+			},
+			expectedStatusCode: http.StatusNoContent,
+		},
+		{
+			name: "Not Found",
+			id:   "1",
+			mockBehavior: func(s *mock_service.MockCategory, id string) {
+				s.EXPECT().DeleteById(id).Return(apperrors.ErrRecordNotFound)
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"error":"record not found"}`,
+		},
+	}
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			category := mock_service.NewMockCategory(controller)
+			test.mockBehavior(category, test.id)
+
+			serv := &service.Service{Category: category}
+			handle := NewHandler(serv, nil)
+
+			router := gin.New()
+			router.DELETE("/category/:id", handle.deleteCategoryById)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("DELETE", "/category/1", nil)
+
+			router.ServeHTTP(w, r)
+
+			body := strings.Trim(w.Body.String(), "\n")
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, body, test.expectedResponseBody)
+		})
+	}
+}
+
+func TestCategoryHandler_restoreCategory(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockCategory)
+
+	testTable := []struct {
+		name                 string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "Ok",
+			mockBehavior: func(s *mock_service.MockCategory) {
+				s.EXPECT().Restore("1").Return(nil)
+			},
+			expectedStatusCode: http.StatusNoContent,
+		},
+		{
+			name: "Name Taken",
+			mockBehavior: func(s *mock_service.MockCategory) {
+				s.EXPECT().Restore("1").Return(apperrors.ErrIncorrectQuery)
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"incorrect query"}`,
+		},
+		{
+			name: "Not Deleted",
+			mockBehavior: func(s *mock_service.MockCategory) {
+				s.EXPECT().Restore("1").Return(apperrors.ErrRecordNotFound)
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"error":"record not found"}`,
+		},
+	}
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			category := mock_service.NewMockCategory(controller)
+			test.mockBehavior(category)
+
+			serv := &service.Service{Category: category}
+			handle := NewHandler(serv, nil)
+
+			router := gin.New()
+			router.POST("/category/:id/restore", handle.restoreCategory)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("POST", "/category/1/restore", nil)
+
+			router.ServeHTTP(w, r)
+
+			body := strings.Trim(w.Body.String(), "\n")
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, body, test.expectedResponseBody)
+		})
+	}
+}
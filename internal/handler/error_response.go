@@ -1,5 +1,13 @@
 package handler
 
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/rinuccia/travels-api/pkg/apperrors"
+	"github.com/sirupsen/logrus"
+	"net/http"
+)
+
 type errResponse struct {
 	Error string `json:"error"`
 }
@@ -9,3 +17,49 @@ func newErrResponse(errString string) *errResponse {
 		Error: errString,
 	}
 }
+
+// problemResponse is an RFC 7807 problem details body.
+type problemResponse struct {
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	Detail string `json:"detail,omitempty"`
+}
+
+func abortWithProblem(c *gin.Context, status int, detail string) {
+	c.Header("Content-Type", "application/problem+json")
+	c.AbortWithStatusJSON(status, &problemResponse{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+	})
+}
+
+// respondRestore answers a restore of a deleted entity, only deleted entities are found.
+func respondRestore(c *gin.Context, err error) {
+	if errors.Is(err, apperrors.ErrIncorrectQuery) {
+		c.JSON(http.StatusBadRequest, newErrResponse(err.Error()))
+		return
+	}
+	if errors.Is(err, apperrors.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, newErrResponse(err.Error()))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, newErrResponse("something went wrong"))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ignoreMailNotSent logs a verification email that could not be sent and drops the error,
+// the change it verifies is already saved and the email can be sent again.
+func ignoreMailNotSent(err error) error {
+	if errors.Is(err, apperrors.ErrMailNotSent) {
+		logrus.Warnf("verification email not sent: %s", err.Error())
+		return nil
+	}
+	return err
+}
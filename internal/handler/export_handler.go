@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/internal/service"
+	"github.com/sirupsen/logrus"
+	"net/http"
+)
+
+var exportContentTypes = map[string]string{
+	model.ExportFormatNDJSON: "application/x-ndjson",
+	model.ExportFormatCSV:    "text/csv",
+	model.ExportFormatZip:    "application/zip",
+}
+
+type exportHandler struct {
+	repo service.Export
+}
+
+func newExportHandler(repository service.Export) *exportHandler {
+	return &exportHandler{
+		repo: repository,
+	}
+}
+
+// exportData godoc
+// @Summary Streams users, locations and visits as NDJSON, CSV or a zip archive of CSV files
+// @Tags export
+// @Produce application/x-ndjson,text/csv,application/zip
+// @Param format query string false "ndjson, csv or zip" default(ndjson)
+// @Param entity query string false "users, locations or visits, required unless format is zip"
+// @Param updated_since query string false "Only records changed since this date (2006-01-02) or RFC 3339 timestamp"
+// @Security BearerAuth
+// @Success 200 {file} file
+// @Failure 400,401 {object} errResponse
+// @Failure 403 {object} problemResponse
+// @Router /export [get]
+func (h *exportHandler) exportData(c *gin.Context) {
+	since, err := model.ParseUpdatedSince(c.Query("updated_since"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, newErrResponse("invalid updated_since"))
+		return
+	}
+	opts := model.ExportOptions{
+		Format:       c.DefaultQuery("format", model.ExportFormatNDJSON),
+		Entity:       c.Query("entity"),
+		UpdatedSince: since,
+	}
+	if err = opts.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, newErrResponse(err.Error()))
+		return
+	}
+
+	filename := opts.Entity + "." + opts.Format
+	if opts.Format == model.ExportFormatZip {
+		filename = "export.zip"
+	}
+	c.Header("Content-Type", exportContentTypes[opts.Format])
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Status(http.StatusOK)
+
+	// The status line is already sent once streaming starts, so a failure can only be logged.
+	if err = h.repo.Write(c.Writer, opts); err != nil {
+		logrus.Errorf("export failed: %s", err.Error())
+		_ = c.Error(err)
+	}
+}
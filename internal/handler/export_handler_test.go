@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/internal/service"
+	mock_service "github.com/rinuccia/travels-api/internal/service/mocks"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestExportHandler_exportData(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockExport)
+
+	testTable := []struct {
+		name                 string
+		query                string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedContentType  string
+		expectedResponseBody string
+	}{
+		{
+			name:  "Ok",
+			query: "?format=ndjson&entity=locations",
+			mockBehavior: func(s *mock_service.MockExport) {
+				s.EXPECT().Write(gomock.Any(), model.ExportOptions{Format: "ndjson", Entity: "locations"}).
+					DoAndReturn(func(w io.Writer, opts model.ExportOptions) error {
+						_, err := io.WriteString(w, `{"location_id":1,"place":"Red Square","country":"RF"}`+"\n")
+						return err
+					})
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedContentType:  "application/x-ndjson",
+			expectedResponseBody: `{"location_id":1,"place":"Red Square","country":"RF"}`,
+		},
+		{
+			name:                 "Missing Entity",
+			query:                "?format=csv",
+			mockBehavior:         func(s *mock_service.MockExport) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedContentType:  "application/json; charset=utf-8",
+			expectedResponseBody: `{"error":"entity must be one of users, locations, visits"}`,
+		},
+		{
+			name:                 "Invalid Updated Since",
+			query:                "?format=zip&updated_since=yesterday",
+			mockBehavior:         func(s *mock_service.MockExport) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedContentType:  "application/json; charset=utf-8",
+			expectedResponseBody: `{"error":"invalid updated_since"}`,
+		},
+	}
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			export := mock_service.NewMockExport(controller)
+			test.mockBehavior(export)
+
+			serv := &service.Service{Export: export}
+			handle := NewHandler(serv, nil)
+
+			router := gin.New()
+			router.GET("/export", handle.exportData)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "/export"+test.query, nil)
+
+			router.ServeHTTP(w, r)
+
+			body := strings.Trim(w.Body.String(), "\n")
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, w.Header().Get("Content-Type"), test.expectedContentType)
+			assert.Equal(t, body, test.expectedResponseBody)
+		})
+	}
+}
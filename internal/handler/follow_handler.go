@@ -0,0 +1,184 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/internal/service"
+	"github.com/rinuccia/travels-api/pkg/apperrors"
+	"net/http"
+)
+
+type followHandler struct {
+	repo service.Follow
+}
+
+func newFollowHandler(repository service.Follow) *followHandler {
+	return &followHandler{
+		repo: repository,
+	}
+}
+
+// followUser godoc
+// @Summary Follows target user, following a private user needs the target's approval
+// @Tags follow
+// @Security BearerAuth
+// @Produce json
+// @Param id path integer true "User ID"
+// @Param target path integer true "Followed user ID"
+// @Success 200 {object} model.Follow
+// @Failure 400,401,404 {object} errResponse
+// @Failure 403 {object} problemResponse
+// @Failure 500 {object} errResponse
+// @Router /user/{id}/follow/{target} [post]
+func (h *followHandler) followUser(c *gin.Context) {
+	follow, err := h.repo.Follow(c.Param("id"), c.Param("target"))
+	if errors.Is(err, apperrors.ErrIncorrectQuery) {
+		c.JSON(http.StatusBadRequest, newErrResponse(err.Error()))
+		return
+	}
+	if errors.Is(err, apperrors.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, newErrResponse(err.Error()))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, newErrResponse("something went wrong"))
+		return
+	}
+
+	c.JSON(http.StatusOK, follow)
+}
+
+// unfollowUser godoc
+// @Summary Stops following target user or withdraws a pending follow request
+// @Tags follow
+// @Security BearerAuth
+// @Param id path integer true "User ID"
+// @Param target path integer true "Followed user ID"
+// @Success 204
+// @Failure 400,401,404 {object} errResponse
+// @Failure 403 {object} problemResponse
+// @Failure 500 {object} errResponse
+// @Router /user/{id}/follow/{target} [delete]
+func (h *followHandler) unfollowUser(c *gin.Context) {
+	err := h.repo.Unfollow(c.Param("id"), c.Param("target"))
+	respondFollowChange(c, err)
+}
+
+// getFollowers godoc
+// @Summary Returns followers of user based on given ID, pending requests first
+// @Tags follow
+// @Security BearerAuth
+// @Produce json
+// @Param id path integer true "User ID"
+// @Param status query string false "Follow status" Enums(approved, pending)
+// @Success 200 {object} model.Followers
+// @Failure 400,401,404 {object} errResponse
+// @Failure 403 {object} problemResponse
+// @Failure 500 {object} errResponse
+// @Router /user/{id}/followers [get]
+func (h *followHandler) getFollowers(c *gin.Context) {
+	filter := model.FollowerFilter{}
+	if err := c.ShouldBindQuery(&filter); err != nil || validate.Struct(filter) != nil {
+		c.JSON(http.StatusBadRequest, newErrResponse("invalid query parameters"))
+		return
+	}
+
+	followers, err := h.repo.GetFollowers(c.Param("id"), filter)
+	if errors.Is(err, apperrors.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, newErrResponse(err.Error()))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, newErrResponse("something went wrong"))
+		return
+	}
+
+	c.JSON(http.StatusOK, followers)
+}
+
+// approveFollower godoc
+// @Summary Approves pending follow request of follower
+// @Tags follow
+// @Security BearerAuth
+// @Param id path integer true "User ID"
+// @Param follower path integer true "Follower user ID"
+// @Success 204
+// @Failure 400,401,404 {object} errResponse
+// @Failure 403 {object} problemResponse
+// @Failure 500 {object} errResponse
+// @Router /user/{id}/followers/{follower} [put]
+func (h *followHandler) approveFollower(c *gin.Context) {
+	err := h.repo.Approve(c.Param("id"), c.Param("follower"))
+	respondFollowChange(c, err)
+}
+
+// removeFollower godoc
+// @Summary Removes follower or declines pending follow request
+// @Tags follow
+// @Security BearerAuth
+// @Param id path integer true "User ID"
+// @Param follower path integer true "Follower user ID"
+// @Success 204
+// @Failure 400,401,404 {object} errResponse
+// @Failure 403 {object} problemResponse
+// @Failure 500 {object} errResponse
+// @Router /user/{id}/followers/{follower} [delete]
+func (h *followHandler) removeFollower(c *gin.Context) {
+	err := h.repo.RemoveFollower(c.Param("id"), c.Param("follower"))
+	respondFollowChange(c, err)
+}
+
+// getFeed godoc
+// @Summary Returns recent visits of users followed by user based on given ID, newest first
+// @Tags follow
+// @Security BearerAuth
+// @Produce json
+// @Param id path integer true "User ID"
+// @Param cursor query string false "Cursor from next_cursor of the previous page"
+// @Param limit query integer false "Visits per page" default(20)
+// @Success 200 {object} model.Feed
+// @Failure 400,401,404 {object} errResponse
+// @Failure 403 {object} problemResponse
+// @Failure 500 {object} errResponse
+// @Router /user/{id}/feed [get]
+func (h *followHandler) getFeed(c *gin.Context) {
+	query := model.FeedQuery{Limit: 20}
+	if err := c.ShouldBindQuery(&query); err != nil || validate.Struct(query) != nil {
+		c.JSON(http.StatusBadRequest, newErrResponse("invalid query parameters"))
+		return
+	}
+
+	feed, err := h.repo.GetFeed(c.Param("id"), query)
+	if errors.Is(err, apperrors.ErrIncorrectQuery) {
+		c.JSON(http.StatusBadRequest, newErrResponse("invalid query parameters"))
+		return
+	}
+	if errors.Is(err, apperrors.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, newErrResponse(err.Error()))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, newErrResponse("something went wrong"))
+		return
+	}
+
+	c.JSON(http.StatusOK, feed)
+}
+
+func respondFollowChange(c *gin.Context, err error) {
+	if errors.Is(err, apperrors.ErrIncorrectQuery) {
+		c.JSON(http.StatusBadRequest, newErrResponse(err.Error()))
+		return
+	}
+	if errors.Is(err, apperrors.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, newErrResponse(err.Error()))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, newErrResponse("something went wrong"))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,213 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/internal/service"
+	mock_service "github.com/rinuccia/travels-api/internal/service/mocks"
+	"github.com/rinuccia/travels-api/pkg/apperrors"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestFollowHandler_followUser(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockFollow)
+
+	testTable := []struct {
+		name                 string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "Ok",
+			mockBehavior: func(s *mock_service.MockFollow) {
+				s.EXPECT().Follow("1", "2").Return(model.Follow{UserId: 1, TargetId: 2, Status: "pending"}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"user_id":1,"target_id":2,"status":"pending"}`,
+		},
+		{
+			name: "Self Follow",
+			mockBehavior: func(s *mock_service.MockFollow) {
+				s.EXPECT().Follow("1", "2").Return(model.Follow{}, apperrors.ErrIncorrectQuery)
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"incorrect query"}`,
+		},
+		{
+			name: "Not Found",
+			mockBehavior: func(s *mock_service.MockFollow) {
+				s.EXPECT().Follow("1", "2").Return(model.Follow{}, apperrors.ErrRecordNotFound)
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"error":"record not found"}`,
+		},
+	}
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			follow := mock_service.NewMockFollow(controller)
+			test.mockBehavior(follow)
+
+			serv := &service.Service{Follow: follow}
+			handle := NewHandler(serv, nil)
+
+			router := gin.New()
+			router.POST("/user/:id/follow/:target", handle.followUser)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("POST", "/user/1/follow/2", nil)
+
+			router.ServeHTTP(w, r)
+
+			body := strings.Trim(w.Body.String(), "\n")
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, body, test.expectedResponseBody)
+		})
+	}
+}
+
+func TestFollowHandler_approveFollower(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockFollow)
+
+	testTable := []struct {
+		name               string
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+	}{
+		{
+			name: "Ok",
+			mockBehavior: func(s *mock_service.MockFollow) {
+				s.EXPECT().Approve("2", "1").Return(nil)
+			},
+			expectedStatusCode: http.StatusNoContent,
+		},
+		{
+			name: "Not Found",
+			mockBehavior: func(s *mock_service.MockFollow) {
+				s.EXPECT().Approve("2", "1").Return(apperrors.ErrRecordNotFound)
+			},
+			expectedStatusCode: http.StatusNotFound,
+		},
+	}
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			follow := mock_service.NewMockFollow(controller)
+			test.mockBehavior(follow)
+
+			serv := &service.Service{Follow: follow}
+			handle := NewHandler(serv, nil)
+
+			router := gin.New()
+			router.PUT("/user/:id/followers/:follower", handle.approveFollower)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("PUT", "/user/2/followers/1", nil)
+
+			router.ServeHTTP(w, r)
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+		})
+	}
+}
+
+func TestFollowHandler_getFeed(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockFollow)
+
+	testTable := []struct {
+		name                 string
+		query                string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:  "Ok",
+			query: "?limit=1&cursor=MjAxOS0wNy0wMSwxMg",
+			mockBehavior: func(s *mock_service.MockFollow) {
+				s.EXPECT().GetFeed("1", model.FeedQuery{Cursor: "MjAxOS0wNy0wMSwxMg", Limit: 1}).Return(model.Feed{
+					Visits: []model.FeedVisit{
+						{
+							VisitId:   9,
+							Author:    model.FeedAuthor{UserId: 2, FirstName: "Jane", LastName: "Doe"},
+							UserVisit: model.UserVisit{Place: "Machu Picchu", Country: "Peru", VisitedAt: "2019-06-15", Mark: 5},
+						},
+					},
+					NextCursor: "MjAxOS0wNi0xNSw5",
+				}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{"visits":[{"visit_id":9,"author":{"user_id":2,"first_name":"Jane","last_name":"Doe"},` +
+				`"place":"Machu Picchu","country":"Peru","visited_at":"2019-06-15","mark":5}],"next_cursor":"MjAxOS0wNi0xNSw5"}`,
+		},
+		{
+			name: "Default Limit",
+			mockBehavior: func(s *mock_service.MockFollow) {
+				s.EXPECT().GetFeed("1", model.FeedQuery{Limit: 20}).Return(model.Feed{}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"visits":null}`,
+		},
+		{
+			name:                 "Invalid Limit",
+			query:                "?limit=500",
+			mockBehavior:         func(s *mock_service.MockFollow) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"invalid query parameters"}`,
+		},
+		{
+			name:  "Invalid Cursor",
+			query: "?cursor=nope",
+			mockBehavior: func(s *mock_service.MockFollow) {
+				s.EXPECT().GetFeed("1", model.FeedQuery{Cursor: "nope", Limit: 20}).Return(model.Feed{}, apperrors.ErrIncorrectQuery)
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"invalid query parameters"}`,
+		},
+		{
+			name: "Service Error",
+			mockBehavior: func(s *mock_service.MockFollow) {
+				s.EXPECT().GetFeed("1", model.FeedQuery{Limit: 20}).Return(model.Feed{}, errors.New("something went wrong"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"error":"something went wrong"}`,
+		},
+	}
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			follow := mock_service.NewMockFollow(controller)
+			test.mockBehavior(follow)
+
+			serv := &service.Service{Follow: follow}
+			handle := NewHandler(serv, nil)
+
+			router := gin.New()
+			router.GET("/user/:id/feed", handle.getFeed)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "/user/1/feed"+test.query, nil)
+
+			router.ServeHTTP(w, r)
+
+			body := strings.Trim(w.Body.String(), "\n")
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, body, test.expectedResponseBody)
+		})
+	}
+}
@@ -3,6 +3,8 @@ package handler
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/internal/policy"
 	"github.com/rinuccia/travels-api/internal/service"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
@@ -11,39 +13,156 @@ import (
 )
 
 const (
-	userURL      = "/user"
-	locationURL  = "/location"
-	locationsURL = "/locations"
-	visitURL     = "/visit"
-	visitsURL    = "/visits"
+	authURL       = "/auth"
+	userURL       = "/user"
+	locationURL   = "/location"
+	locationsURL  = "/locations"
+	visitURL      = "/visit"
+	visitsURL     = "/visits"
+	categoryURL   = "/category"
+	categoriesURL = "/categories"
+	statsURL      = "/stats"
+	tripURL       = "/trip"
+	reviewURL     = "/review"
+	reviewsURL    = "/reviews"
+	photoURL      = "/photo"
+	exportURL     = "/export"
+	apiKeyURL     = "/apikey"
+	apiKeysURL    = "/apikeys"
+	auditURL      = "/audit"
+	erasuresURL   = "/erasures"
 )
 
 var validate = validator.New()
 
 type Handler struct {
+	*authHandler
 	*userHandler
 	*locationHandler
 	*visitHandler
+	*categoryHandler
+	*statsHandler
+	*tripHandler
+	*wishlistHandler
+	*reviewHandler
+	*photoHandler
+	*followHandler
+	*recommendationHandler
+	*apiKeyHandler
+	*auditHandler
+	*exportHandler
+	*privacyHandler
+	*verificationHandler
+	policy policy.Policy
 }
 
-func NewHandler(service *service.Service) *Handler {
+func NewHandler(service *service.Service, rules policy.Policy) *Handler {
 	return &Handler{
+		newAuthHandler(service.Auth),
 		newUserHandler(service.User),
 		newLocationHandler(service.Location),
 		newVisitHandler(service.Visit),
+		newCategoryHandler(service.Category),
+		newStatsHandler(service.Stats),
+		newTripHandler(service.Trip),
+		newWishlistHandler(service.Wishlist),
+		newReviewHandler(service.Review),
+		newPhotoHandler(service.Photo),
+		newFollowHandler(service.Follow),
+		newRecommendationHandler(service.Recommendation),
+		newAPIKeyHandler(service.APIKey),
+		newAuditHandler(service.Audit),
+		newExportHandler(service.Export),
+		newPrivacyHandler(service.Privacy),
+		newVerificationHandler(service.Verification),
+		rules,
 	}
 }
 
-func (h *Handler) InitRoutes(router *gin.Engine) {
+// InitRoutes registers the routes, import bodies are limited to maxImportSize bytes.
+func (h *Handler) InitRoutes(router *gin.Engine, limits RateLimits, maxImportSize int64) {
+	auth := h.authenticate()
+	identify := h.identify()
+	self := h.requireUser()
+	viewer := h.requireViewer()
+	curator := h.requireRole(model.RoleCurator)
+	admin := h.requireRole(model.RoleAdmin)
+	visitOwner := h.requireVisitOwner()
+	tripOwner := h.requireTripOwner()
+	locationsScope := h.authenticateWithScope(model.ScopeLocationsWrite)
+	categoriesScope := h.authenticateWithScope(model.ScopeCategoriesWrite)
+	visitsScope := h.authenticateWithScope(model.ScopeVisitsWrite)
+	read := rateLimit(limits.Store, "reads", limits.Reads)
+	write := rateLimit(limits.Store, "writes", limits.Writes)
+	importBody := limitBody(maxImportSize)
+
+	router.Use(requestId(), ipRateLimit(limits.Store, "requests", limits.PerIP))
+
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
-	router.GET(userURL+"/:id", h.getUserById)
-	router.POST(userURL+"/new", h.createUser)
-	router.PUT(userURL+"/:id", h.updateUser)
-	router.GET(locationsURL, h.getAllLocations)
-	router.GET(locationURL+"/:id", h.getLocationById)
-	router.GET(locationURL+"/:id/avg", h.getAvgRating)
-	router.POST(locationURL+"/new", h.createLocation)
-	router.GET(visitsURL+"/user/:id", h.getAllVisits)
-	router.POST(visitURL+"/new", h.createVisit)
-	router.DELETE(visitURL+"/:id", h.deleteVisitById)
+	router.POST(authURL+"/register", write, h.register)
+	router.POST(authURL+"/login", write, h.login)
+	router.POST(authURL+"/refresh", write, h.refresh)
+	router.POST(authURL+"/verify-email", write, h.verifyEmail)
+	router.POST(authURL+"/logout", write, h.logout)
+	router.GET(userURL+"/:id", read, h.getUserById)
+	router.GET(userURL+"/:id/summary", identify, read, viewer, h.getUserSummary)
+	router.GET(userURL+"/:id/recommendations", read, h.getRecommendations)
+	router.GET(userURL+"/:id/trips", identify, read, viewer, h.getAllTrips)
+	router.GET(userURL+"/:id/wishlist", identify, read, viewer, h.getWishlist)
+	router.POST(userURL+"/:id/wishlist", auth, write, self, h.createWishlistItem)
+	router.PUT(userURL+"/:id/wishlist/:location_id", auth, write, self, h.updateWishlistItem)
+	router.DELETE(userURL+"/:id/wishlist/:location_id", auth, write, self, h.deleteWishlistItem)
+	router.POST(userURL+"/:id/wishlist/:location_id/restore", auth, write, self, h.restoreWishlistItem)
+	router.POST(userURL+"/:id/follow/:target", auth, write, self, h.followUser)
+	router.DELETE(userURL+"/:id/follow/:target", auth, write, self, h.unfollowUser)
+	router.GET(userURL+"/:id/followers", auth, read, self, h.getFollowers)
+	router.PUT(userURL+"/:id/followers/:follower", auth, write, self, h.approveFollower)
+	router.DELETE(userURL+"/:id/followers/:follower", auth, write, self, h.removeFollower)
+	router.GET(userURL+"/:id/feed", auth, read, self, h.getFeed)
+	router.POST(userURL+"/new", write, h.createUser)
+	router.PUT(userURL+"/:id", auth, write, self, h.updateUser)
+	router.POST(userURL+"/:id/verify-email", auth, write, self, h.resendVerification)
+	router.PUT(userURL+"/:id/role", auth, write, admin, h.updateUserRole)
+	router.GET(userURL+"/:id/data-export", auth, read, self, h.exportUserData)
+	router.POST(userURL+"/:id/erase", auth, write, self, h.eraseUser)
+	router.GET(locationsURL, read, h.getAllLocations)
+	router.GET(locationsURL+"/nearby", read, h.getNearbyLocations)
+	router.GET(locationsURL+"/top", read, h.getTopLocations)
+	router.GET(locationsURL+"/wanted", read, h.getMostWantedLocations)
+	router.GET(locationURL+"/:id", read, h.getLocationById)
+	router.GET(locationURL+"/:id/avg", read, h.getAvgRating)
+	router.GET(locationURL+"/:id/ratings", read, h.getRatingDistribution)
+	router.GET(locationURL+"/:id/reviews", read, h.getLocationReviews)
+	router.GET(locationURL+"/:id/photos", read, h.getLocationPhotos)
+	router.POST(locationURL+"/:id/photos", locationsScope, write, curator, h.uploadLocationPhoto)
+	router.POST(locationURL+"/new", locationsScope, write, curator, h.createLocation)
+	router.POST(locationsURL+"/import", locationsScope, write, curator, importBody, h.importLocations)
+	router.GET(visitsURL+"/user/:id", identify, read, viewer, h.getAllVisits)
+	router.POST(visitURL+"/new", visitsScope, write, h.requireBodyUser(), h.createVisit)
+	router.POST(visitsURL+"/import", visitsScope, write, admin, importBody, h.importVisits)
+	router.DELETE(visitURL+"/:id", visitsScope, write, visitOwner, h.deleteVisitById)
+	router.POST(visitURL+"/:id/restore", visitsScope, write, visitOwner, h.restoreVisit)
+	router.GET(visitURL+"/:id/photos", read, h.getVisitPhotos)
+	router.POST(visitURL+"/:id/photos", visitsScope, write, visitOwner, h.uploadVisitPhoto)
+	router.GET(photoURL+"/:id", read, h.getPhoto)
+	router.GET(photoURL+"/:id/thumbnail", read, h.getPhotoThumbnail)
+	router.GET(categoriesURL, read, h.getAllCategories)
+	router.GET(categoryURL+"/:id", read, h.getCategoryById)
+	router.POST(categoryURL+"/new", categoriesScope, write, curator, h.createCategory)
+	router.PUT(categoryURL+"/:id", categoriesScope, write, curator, h.updateCategory)
+	router.DELETE(categoryURL+"/:id", categoriesScope, write, curator, h.deleteCategoryById)
+	router.POST(categoryURL+"/:id/restore", categoriesScope, write, curator, h.restoreCategory)
+	router.GET(statsURL+"/countries", read, h.getCountryStats)
+	router.POST(tripURL+"/new", auth, write, h.requireBodyUser(), h.createTrip)
+	router.PUT(tripURL+"/:id", auth, write, tripOwner, h.updateTrip)
+	router.DELETE(tripURL+"/:id", auth, write, tripOwner, h.deleteTripById)
+	router.POST(tripURL+"/:id/restore", auth, write, tripOwner, h.restoreTrip)
+	router.GET(reviewsURL, auth, read, admin, h.getReviewsByStatus)
+	router.PUT(reviewURL+"/:id/status", auth, write, admin, h.moderateReview)
+	router.GET(exportURL, auth, read, admin, h.exportData)
+	router.GET(apiKeysURL, auth, read, admin, h.getAllAPIKeys)
+	router.POST(apiKeyURL+"/new", auth, write, admin, h.createAPIKey)
+	router.DELETE(apiKeyURL+"/:id", auth, write, admin, h.revokeAPIKey)
+	router.GET(auditURL, auth, read, admin, h.getAuditLog)
+	router.GET(erasuresURL, auth, read, admin, h.getErasures)
 }
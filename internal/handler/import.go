@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/pkg/apperrors"
+	"io"
+	"net/http"
+)
+
+// importer is implemented by services that load entities from csv.
+type importer interface {
+	Import(actor model.Actor, r io.Reader, upsert bool) (model.ImportResult, error)
+}
+
+// importCSV checks the request body is csv and reports the per line result of the import.
+// Lines read before a body reaches the limit of limitBody stay imported.
+func importCSV(c *gin.Context, svc importer) {
+	if c.ContentType() != "text/csv" {
+		c.JSON(http.StatusUnsupportedMediaType, newErrResponse("content type must be text/csv"))
+		return
+	}
+	mode := c.DefaultQuery("mode", "insert")
+	if mode != "insert" && mode != "upsert" {
+		c.JSON(http.StatusBadRequest, newErrResponse("mode must be insert or upsert"))
+		return
+	}
+
+	result, err := svc.Import(auditActor(c), c.Request.Body, mode == "upsert")
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		bodyTooLarge(c, maxBytesErr.Limit)
+		return
+	}
+	if errors.Is(err, apperrors.ErrInvalidCSV) {
+		c.JSON(http.StatusBadRequest, newErrResponse(err.Error()))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, newErrResponse("something went wrong"))
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
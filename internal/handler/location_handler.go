@@ -6,9 +6,23 @@ import (
 	"github.com/rinuccia/travels-api/internal/model"
 	"github.com/rinuccia/travels-api/internal/service"
 	"github.com/rinuccia/travels-api/pkg/apperrors"
+	"github.com/rinuccia/travels-api/pkg/geo"
 	"net/http"
 )
 
+// nearbyQuery represent GET /locations/nearby query parameters
+type nearbyQuery struct {
+	Lat      *float64 `form:"lat" validate:"required,latitude"`
+	Lon      *float64 `form:"lon" validate:"required,longitude"`
+	RadiusKm float64  `form:"radius_km" validate:"gt=0"`
+	Limit    int      `form:"limit" validate:"min=1,max=100"`
+}
+
+// ratingQuery represent GET /location/:id/ratings bucket parameter
+type ratingQuery struct {
+	Bucket string `form:"bucket" validate:"omitempty,oneof=month year"`
+}
+
 type locationHandler struct {
 	repo service.Location
 }
@@ -23,11 +37,20 @@ func newLocationHandler(repository service.Location) *locationHandler {
 // @Summary Returns a list of all locations
 // @Tags location
 // @Produce json
+// @Param category query string false "Category name"
+// @Param tag query string false "Tag"
 // @Success 200 {object} model.Locations
+// @Failure 400 {object} errResponse
 // @Failure 500 {object} errResponse
 // @Router /locations [get]
 func (h *locationHandler) getAllLocations(c *gin.Context) {
-	locations, err := h.repo.GetAll()
+	filter := model.LocationFilter{}
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, newErrResponse("invalid query parameters"))
+		return
+	}
+
+	locations, err := h.repo.GetAll(filter)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, newErrResponse("something went wrong"))
 		return
@@ -60,12 +83,23 @@ func (h *locationHandler) getLocationById(c *gin.Context) {
 // @Tags location
 // @Produce json
 // @Param id path integer true "Location ID"
+// @Param from_date query string false "Only visits on or after this date (2006-01-02)"
+// @Param to_date query string false "Only visits on or before this date (2006-01-02)"
+// @Param gender query string false "Only visits of travellers of this gender" Enums(m, f)
 // @Success 200 {object} model.AvgRating
-// @Failure 404 {object} errResponse
+// @Failure 400,404 {object} errResponse
 // @Router /location/{id}/avg [get]
 func (h *locationHandler) getAvgRating(c *gin.Context) {
 	id := c.Param("id")
-	avg, err := h.repo.GetRating(id)
+	filter := model.VisitFilter{}
+	err := c.ShouldBindQuery(&filter)
+	validationErr := validate.Struct(filter)
+	if err != nil || validationErr != nil {
+		c.JSON(http.StatusBadRequest, newErrResponse("invalid query parameters"))
+		return
+	}
+
+	avg, err := h.repo.GetRating(id, filter)
 	if errors.Is(err, apperrors.ErrRecordNotFound) {
 		c.JSON(http.StatusNotFound, newErrResponse(err.Error()))
 		return
@@ -75,14 +109,153 @@ func (h *locationHandler) getAvgRating(c *gin.Context) {
 	c.JSON(http.StatusOK, rating)
 }
 
+// getRatingDistribution godoc
+// @Summary Retrieves the distribution of location marks based on given id
+// @Description Counts visits per mark 0-5 with total, mean, median and standard deviation.
+// @Description With bucket set the same figures are also returned per month or year of the visit.
+// @Tags location
+// @Produce json
+// @Param id path integer true "Location ID"
+// @Param from_date query string false "Only visits on or after this date (2006-01-02)"
+// @Param to_date query string false "Only visits on or before this date (2006-01-02)"
+// @Param gender query string false "Only visits of travellers of this gender" Enums(m, f)
+// @Param bucket query string false "Split the distribution by visit date" Enums(month, year)
+// @Success 200 {object} model.RatingDistribution
+// @Failure 400,404 {object} errResponse
+// @Failure 500 {object} errResponse
+// @Router /location/{id}/ratings [get]
+func (h *locationHandler) getRatingDistribution(c *gin.Context) {
+	id := c.Param("id")
+	filter := model.VisitFilter{}
+	query := ratingQuery{}
+	errFilter := c.ShouldBindQuery(&filter)
+	errQuery := c.ShouldBindQuery(&query)
+	if errFilter != nil || errQuery != nil || validate.Struct(filter) != nil || validate.Struct(query) != nil {
+		c.JSON(http.StatusBadRequest, newErrResponse("invalid query parameters"))
+		return
+	}
+
+	distribution, err := h.repo.GetRatingDistribution(id, filter, query.Bucket)
+	if errors.Is(err, apperrors.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, newErrResponse(err.Error()))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, newErrResponse("something went wrong"))
+		return
+	}
+
+	c.JSON(http.StatusOK, distribution)
+}
+
+// getTopLocations godoc
+// @Summary Returns locations ranked by average mark or number of visits
+// @Description Only locations with at least min_visits visits in the period are ranked.
+// @Description With bayesian=true the average ranking uses the average pulled towards the mean of all ranked visits.
+// @Tags location
+// @Produce json
+// @Param by query string false "Ranking metric" Enums(avg, visits) default(avg)
+// @Param country query string false "Country name or ISO code"
+// @Param from query string false "Only visits on or after this date (2006-01-02)"
+// @Param to query string false "Only visits on or before this date (2006-01-02)"
+// @Param limit query integer false "Maximum number of locations" default(10)
+// @Param min_visits query integer false "Minimum number of visits" default(3)
+// @Param bayesian query boolean false "Rank by Bayesian average"
+// @Success 200 {object} model.TopLocations
+// @Failure 400 {object} errResponse
+// @Failure 500 {object} errResponse
+// @Router /locations/top [get]
+func (h *locationHandler) getTopLocations(c *gin.Context) {
+	query := model.TopLocationsQuery{Limit: 10, MinVisits: 3}
+	err := c.ShouldBindQuery(&query)
+	validationErr := validate.Struct(query)
+	if err != nil || validationErr != nil {
+		c.JSON(http.StatusBadRequest, newErrResponse("invalid query parameters"))
+		return
+	}
+
+	locations, err := h.repo.GetTop(query)
+	if errors.Is(err, apperrors.ErrUnknownCountry) {
+		c.JSON(http.StatusBadRequest, newErrResponse(err.Error()))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, newErrResponse("something went wrong"))
+		return
+	}
+
+	c.JSON(http.StatusOK, locations)
+}
+
+// getMostWantedLocations godoc
+// @Summary Returns locations ranked by the number of users planning to visit them
+// @Tags location
+// @Produce json
+// @Param limit query integer false "Maximum number of locations" default(10)
+// @Success 200 {object} model.WantedLocations
+// @Failure 400 {object} errResponse
+// @Failure 500 {object} errResponse
+// @Router /locations/wanted [get]
+func (h *locationHandler) getMostWantedLocations(c *gin.Context) {
+	query := limitQuery{Limit: 10}
+	err := c.ShouldBindQuery(&query)
+	validationErr := validate.Struct(query)
+	if err != nil || validationErr != nil {
+		c.JSON(http.StatusBadRequest, newErrResponse("invalid query parameters"))
+		return
+	}
+
+	locations, err := h.repo.GetMostWanted(query.Limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, newErrResponse("something went wrong"))
+		return
+	}
+
+	c.JSON(http.StatusOK, locations)
+}
+
+// getNearbyLocations godoc
+// @Summary Returns locations within a radius of a point, nearest first
+// @Tags location
+// @Produce json
+// @Param lat query number true "Latitude"
+// @Param lon query number true "Longitude"
+// @Param radius_km query number false "Search radius in kilometres" default(10)
+// @Param limit query integer false "Maximum number of locations" default(20)
+// @Success 200 {object} model.NearbyLocations
+// @Failure 400 {object} errResponse
+// @Failure 500 {object} errResponse
+// @Router /locations/nearby [get]
+func (h *locationHandler) getNearbyLocations(c *gin.Context) {
+	query := nearbyQuery{RadiusKm: 10, Limit: 20}
+	err := c.ShouldBindQuery(&query)
+	validationErr := validate.Struct(query)
+	if err != nil || validationErr != nil || query.RadiusKm > geo.MaxDistanceKm {
+		c.JSON(http.StatusBadRequest, newErrResponse("invalid query parameters"))
+		return
+	}
+
+	locations, err := h.repo.GetNearby(*query.Lat, *query.Lon, query.RadiusKm, query.Limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, newErrResponse("something went wrong"))
+		return
+	}
+
+	c.JSON(http.StatusOK, locations)
+}
+
 // createLocation godoc
 // @Summary Create Location
 // @Tags location
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Accept json
 // @Produce json
 // @Param input body model.Location true "Location Info"
 // @Success 200 {object} model.Location
-// @Failure 400 {object} errResponse
+// @Failure 400,401 {object} errResponse
+// @Failure 403 {object} problemResponse
+// @Failure 500 {object} errResponse
 // @Router /location/new [post]
 func (h *locationHandler) createLocation(c *gin.Context) {
 	location := model.Location{}
@@ -93,11 +266,35 @@ func (h *locationHandler) createLocation(c *gin.Context) {
 		return
 	}
 
-	location, err = h.repo.Create(location)
-	if err != nil {
+	location, err = h.repo.Create(auditActor(c), location)
+	if errors.Is(err, apperrors.ErrAlreadyExists) || errors.Is(err, apperrors.ErrIncorrectQuery) ||
+		errors.Is(err, apperrors.ErrUnknownCountry) {
 		c.JSON(http.StatusBadRequest, newErrResponse(err.Error()))
 		return
 	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, newErrResponse("something went wrong"))
+		return
+	}
 
 	c.JSON(http.StatusOK, location)
 }
+
+// importLocations godoc
+// @Summary Import locations from csv
+// @Description The first line is a header with location_id, place and country columns.
+// @Tags location
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Accept text/csv
+// @Produce json
+// @Param mode query string false "insert rejects existing ids, upsert updates them" Enums(insert, upsert)
+// @Param input body string true "CSV document"
+// @Success 200 {object} model.ImportResult
+// @Failure 400,401,413,415 {object} errResponse
+// @Failure 403 {object} problemResponse
+// @Failure 500 {object} errResponse
+// @Router /locations/import [post]
+func (h *locationHandler) importLocations(c *gin.Context) {
+	importCSV(c, h.repo)
+}
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"errors"
+	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
 	"github.com/rinuccia/travels-api/internal/model"
@@ -9,6 +10,7 @@ import (
 	mock_service "github.com/rinuccia/travels-api/internal/service/mocks"
 	"github.com/rinuccia/travels-api/pkg/apperrors"
 	"github.com/stretchr/testify/assert"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -20,6 +22,7 @@ func TestLocationHandler_getAllLocations(t *testing.T) {
 
 	testTable := []struct {
 		name                 string
+		query                string
 		mockBehavior         mockBehavior
 		expectedStatusCode   int
 		expectedResponseBody string
@@ -27,21 +30,34 @@ func TestLocationHandler_getAllLocations(t *testing.T) {
 		{
 			name: "Ok",
 			mockBehavior: func(s *mock_service.MockLocation) {
-				s.EXPECT().GetAll().Return(model.Locations{
+				s.EXPECT().GetAll(model.LocationFilter{}).Return(model.Locations{
 					List: []model.Location{
-						{1, "Red Square", "RF"},
-						{2, "Eiffel Tower", "France"},
-						{3, "Grand Canyon", "USA"},
+						{LocationId: 1, Place: "Red Square", Country: "RF"},
+						{LocationId: 2, Place: "Eiffel Tower", Country: "France"},
+						{LocationId: 3, Place: "Grand Canyon", Country: "USA"},
 					},
 				}, nil)
 			},
 			expectedStatusCode:   http.StatusOK,
 			expectedResponseBody: `{"list":[{"location_id":1,"place":"Red Square","country":"RF"},{"location_id":2,"place":"Eiffel Tower","country":"France"},{"location_id":3,"place":"Grand Canyon","country":"USA"}]}`,
 		},
+		{
+			name:  "Filtered",
+			query: "?category=museum&tag=art",
+			mockBehavior: func(s *mock_service.MockLocation) {
+				s.EXPECT().GetAll(model.LocationFilter{Category: "museum", Tag: "art"}).Return(model.Locations{
+					List: []model.Location{
+						{LocationId: 4, Place: "Louvre", Country: "France", Category: "museum", Tags: []string{"art", "unesco"}},
+					},
+				}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"list":[{"location_id":4,"place":"Louvre","country":"France","category":"museum","tags":["art","unesco"]}]}`,
+		},
 		{
 			name: "Service Error",
 			mockBehavior: func(s *mock_service.MockLocation) {
-				s.EXPECT().GetAll().Return(model.Locations{}, errors.New("something went wrong"))
+				s.EXPECT().GetAll(model.LocationFilter{}).Return(model.Locations{}, errors.New("something went wrong"))
 			},
 			expectedStatusCode:   http.StatusInternalServerError,
 			expectedResponseBody: `{"error":"something went wrong"}`,
@@ -56,13 +72,13 @@ func TestLocationHandler_getAllLocations(t *testing.T) {
 			test.mockBehavior(location)
 
 			serv := &service.Service{Location: location}
-			handle := NewHandler(serv)
+			handle := NewHandler(serv, nil)
 
 			router := gin.New()
 			router.GET("/locations", handle.getAllLocations)
 
 			w := httptest.NewRecorder()
-			r := httptest.NewRequest("GET", "/locations", nil)
+			r := httptest.NewRequest("GET", "/locations"+test.query, nil)
 
 			router.ServeHTTP(w, r)
 
@@ -115,7 +131,7 @@ func TestLocationHandler_getLocationById(t *testing.T) {
 			test.mockBehavior(location, test.id)
 
 			serv := &service.Service{Location: location}
-			handle := NewHandler(serv)
+			handle := NewHandler(serv, nil)
 
 			router := gin.New()
 			router.GET("/location/:id", handle.getLocationById)
@@ -139,6 +155,7 @@ func TestLocationHandler_getAvgRating(t *testing.T) {
 	testTable := []struct {
 		name                 string
 		id                   string
+		query                string
 		mockBehavior         mockBehavior
 		expectedStatusCode   int
 		expectedResponseBody string
@@ -147,16 +164,35 @@ func TestLocationHandler_getAvgRating(t *testing.T) {
 			name: "Ok",
 			id:   "1",
 			mockBehavior: func(s *mock_service.MockLocation, id string) {
-				s.EXPECT().GetRating(id).Return(float32(4.5), nil)
+				s.EXPECT().GetRating(id, model.VisitFilter{}).Return(float32(4.5), nil)
 			},
 			expectedStatusCode:   http.StatusOK,
 			expectedResponseBody: `{"avg":4.5}`,
 		},
+		{
+			name:  "Filtered",
+			id:    "1",
+			query: "?from_date=2018-01-01&to_date=2019-12-31&gender=f",
+			mockBehavior: func(s *mock_service.MockLocation, id string) {
+				s.EXPECT().GetRating(id, model.VisitFilter{FromDate: "2018-01-01", ToDate: "2019-12-31", Gender: "f"}).
+					Return(float32(3.67), nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"avg":3.67}`,
+		},
+		{
+			name:                 "Invalid Gender",
+			id:                   "1",
+			query:                "?gender=x",
+			mockBehavior:         func(s *mock_service.MockLocation, id string) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"invalid query parameters"}`,
+		},
 		{
 			name: "Not Found",
 			id:   "1",
 			mockBehavior: func(s *mock_service.MockLocation, id string) {
-				s.EXPECT().GetRating(id).Return(float32(0), apperrors.ErrRecordNotFound)
+				s.EXPECT().GetRating(id, model.VisitFilter{}).Return(float32(0), apperrors.ErrRecordNotFound)
 			},
 			expectedStatusCode:   http.StatusNotFound,
 			expectedResponseBody: `{"error":"record not found"}`,
@@ -171,13 +207,101 @@ func TestLocationHandler_getAvgRating(t *testing.T) {
 			test.mockBehavior(location, test.id)
 
 			serv := &service.Service{Location: location}
-			handle := NewHandler(serv)
+			handle := NewHandler(serv, nil)
 
 			router := gin.New()
 			router.GET("/location/:id/avg", handle.getAvgRating)
 
 			w := httptest.NewRecorder()
-			r := httptest.NewRequest("GET", "/location/1/avg", nil)
+			r := httptest.NewRequest("GET", "/location/1/avg"+test.query, nil)
+
+			router.ServeHTTP(w, r)
+
+			body := strings.Trim(w.Body.String(), "\n")
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, body, test.expectedResponseBody)
+		})
+	}
+}
+
+func TestLocationHandler_getRatingDistribution(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockLocation, id string)
+
+	histogram := []model.MarkCount{{Mark: 0}, {Mark: 1}, {Mark: 2}, {Mark: 3, Count: 1}, {Mark: 4}, {Mark: 5, Count: 1}}
+	summary := model.RatingSummary{Histogram: histogram, Total: 2, Mean: 4, Median: 4, StdDev: 1}
+
+	testTable := []struct {
+		name                 string
+		id                   string
+		query                string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "Ok",
+			id:   "1",
+			mockBehavior: func(s *mock_service.MockLocation, id string) {
+				s.EXPECT().GetRatingDistribution(id, model.VisitFilter{}, "").
+					Return(model.RatingDistribution{RatingSummary: summary}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{"histogram":[{"mark":0,"count":0},{"mark":1,"count":0},{"mark":2,"count":0},` +
+				`{"mark":3,"count":1},{"mark":4,"count":0},{"mark":5,"count":1}],"total":2,"mean":4,"median":4,"std_dev":1}`,
+		},
+		{
+			name:  "Yearly Buckets",
+			id:    "1",
+			query: "?bucket=year&gender=m",
+			mockBehavior: func(s *mock_service.MockLocation, id string) {
+				s.EXPECT().GetRatingDistribution(id, model.VisitFilter{Gender: "m"}, "year").
+					Return(model.RatingDistribution{
+						RatingSummary: summary,
+						Buckets:       []model.RatingPeriod{{Period: "2019", RatingSummary: summary}},
+					}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{"histogram":[{"mark":0,"count":0},{"mark":1,"count":0},{"mark":2,"count":0},` +
+				`{"mark":3,"count":1},{"mark":4,"count":0},{"mark":5,"count":1}],"total":2,"mean":4,"median":4,"std_dev":1,` +
+				`"buckets":[{"period":"2019","histogram":[{"mark":0,"count":0},{"mark":1,"count":0},{"mark":2,"count":0},` +
+				`{"mark":3,"count":1},{"mark":4,"count":0},{"mark":5,"count":1}],"total":2,"mean":4,"median":4,"std_dev":1}]}`,
+		},
+		{
+			name:                 "Invalid Bucket",
+			id:                   "1",
+			query:                "?bucket=week",
+			mockBehavior:         func(s *mock_service.MockLocation, id string) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"invalid query parameters"}`,
+		},
+		{
+			name: "Not Found",
+			id:   "1",
+			mockBehavior: func(s *mock_service.MockLocation, id string) {
+				s.EXPECT().GetRatingDistribution(id, model.VisitFilter{}, "").
+					Return(model.RatingDistribution{}, apperrors.ErrRecordNotFound)
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"error":"record not found"}`,
+		},
+	}
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			location := mock_service.NewMockLocation(controller)
+			test.mockBehavior(location, test.id)
+
+			serv := &service.Service{Location: location}
+			handle := NewHandler(serv, nil)
+
+			router := gin.New()
+			router.GET("/location/:id/ratings", handle.getRatingDistribution)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "/location/1/ratings"+test.query, nil)
 
 			router.ServeHTTP(w, r)
 
@@ -209,7 +333,7 @@ func TestLocationHandler_createLocation(t *testing.T) {
 				Country:    "Peru",
 			},
 			mockBehavior: func(s *mock_service.MockLocation, location model.Location) {
-				s.EXPECT().Create(location).Return(location, nil)
+				s.EXPECT().Create(model.Actor{Actor: "anonymous"}, location).Return(location, nil)
 			},
 			expectedStatusCode:   http.StatusOK,
 			expectedResponseBody: `{"location_id":1,"place":"Machu Picchu","country":"Peru"}`,
@@ -223,11 +347,53 @@ func TestLocationHandler_createLocation(t *testing.T) {
 				Country:    "Peru",
 			},
 			mockBehavior: func(s *mock_service.MockLocation, location model.Location) {
-				s.EXPECT().Create(location).Return(model.Location{}, apperrors.ErrIncorrectQuery)
+				s.EXPECT().Create(model.Actor{Actor: "anonymous"}, location).Return(model.Location{}, apperrors.ErrIncorrectQuery)
 			},
 			expectedStatusCode:   http.StatusBadRequest,
 			expectedResponseBody: `{"error":"incorrect query"}`,
 		},
+		{
+			name:      "Unknown Country",
+			inputBody: `{"location_id":1,"place":"Minas Tirith","country":"Gondor"}`,
+			inputLocation: model.Location{
+				LocationId: 1,
+				Place:      "Minas Tirith",
+				Country:    "Gondor",
+			},
+			mockBehavior: func(s *mock_service.MockLocation, location model.Location) {
+				s.EXPECT().Create(model.Actor{Actor: "anonymous"}, location).Return(location, apperrors.ErrUnknownCountry)
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"unknown country"}`,
+		},
+		{
+			name:      "Duplicate Id",
+			inputBody: `{"location_id":1,"place":"Machu Picchu","country":"Peru"}`,
+			inputLocation: model.Location{
+				LocationId: 1,
+				Place:      "Machu Picchu",
+				Country:    "Peru",
+			},
+			mockBehavior: func(s *mock_service.MockLocation, location model.Location) {
+				s.EXPECT().Create(model.Actor{Actor: "anonymous"}, location).Return(location, apperrors.ErrAlreadyExists)
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"record already exists"}`,
+		},
+		{
+			name:      "Service Failure",
+			inputBody: `{"location_id":1,"place":"Machu Picchu","country":"Peru"}`,
+			inputLocation: model.Location{
+				LocationId: 1,
+				Place:      "Machu Picchu",
+				Country:    "Peru",
+			},
+			mockBehavior: func(s *mock_service.MockLocation, location model.Location) {
+				s.EXPECT().Create(model.Actor{Actor: "anonymous"}, location).Return(location, errors.New("connection refused"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"error":"something went wrong"}`,
+		},
 	}
 	for _, test := range testTable {
 		t.Run(test.name, func(t *testing.T) {
@@ -238,7 +404,7 @@ func TestLocationHandler_createLocation(t *testing.T) {
 			test.mockBehavior(location, test.inputLocation)
 
 			serv := &service.Service{Location: location}
-			handle := NewHandler(serv)
+			handle := NewHandler(serv, nil)
 
 			router := gin.New()
 			router.POST("/location/new", handle.createLocation)
@@ -255,3 +421,333 @@ func TestLocationHandler_createLocation(t *testing.T) {
 		})
 	}
 }
+
+func TestLocationHandler_importLocations(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockLocation)
+
+	testTable := []struct {
+		name                 string
+		query                string
+		contentType          string
+		inputBody            string
+		chunked              bool
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:        "Ok",
+			query:       "?mode=upsert",
+			contentType: "text/csv",
+			mockBehavior: func(s *mock_service.MockLocation) {
+				s.EXPECT().Import(model.Actor{Actor: "anonymous"}, gomock.Any(), true).Return(model.ImportResult{
+					Inserted: 1,
+					Updated:  1,
+					Rejected: []model.RejectedLine{{Line: 4, Reason: "country: failed required validation"}},
+				}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"inserted":1,"updated":1,"rejected":[{"line":4,"reason":"country: failed required validation"}]}`,
+		},
+		{
+			name:        "Missing Column",
+			contentType: "text/csv",
+			mockBehavior: func(s *mock_service.MockLocation) {
+				s.EXPECT().Import(model.Actor{Actor: "anonymous"}, gomock.Any(), false).
+					Return(model.ImportResult{}, fmt.Errorf("%w: missing column country", apperrors.ErrInvalidCSV))
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"invalid csv: missing column country"}`,
+		},
+		{
+			name:                 "Wrong Content Type",
+			contentType:          "application/json",
+			mockBehavior:         func(s *mock_service.MockLocation) {},
+			expectedStatusCode:   http.StatusUnsupportedMediaType,
+			expectedResponseBody: `{"error":"content type must be text/csv"}`,
+		},
+		{
+			name:                 "Too Large",
+			contentType:          "text/csv",
+			inputBody:            "location_id,place,country\n1,Machu Picchu,Peru\n2,Chichen Itza,Mexico\n",
+			mockBehavior:         func(s *mock_service.MockLocation) {},
+			expectedStatusCode:   http.StatusRequestEntityTooLarge,
+			expectedResponseBody: `{"error":"request body is larger than 64 bytes"}`,
+		},
+		{
+			name:        "Too Large Without Length",
+			contentType: "text/csv",
+			inputBody:   "location_id,place,country\n1,Machu Picchu,Peru\n2,Chichen Itza,Mexico\n",
+			chunked:     true,
+			mockBehavior: func(s *mock_service.MockLocation) {
+				s.EXPECT().Import(model.Actor{Actor: "anonymous"}, gomock.Any(), false).
+					DoAndReturn(func(_ model.Actor, r io.Reader, _ bool) (model.ImportResult, error) {
+						_, err := io.ReadAll(r)
+						return model.ImportResult{}, err
+					})
+			},
+			expectedStatusCode:   http.StatusRequestEntityTooLarge,
+			expectedResponseBody: `{"error":"request body is larger than 64 bytes"}`,
+		},
+		{
+			name:                 "Unknown Mode",
+			query:                "?mode=replace",
+			contentType:          "text/csv",
+			mockBehavior:         func(s *mock_service.MockLocation) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"mode must be insert or upsert"}`,
+		},
+	}
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			location := mock_service.NewMockLocation(controller)
+			test.mockBehavior(location)
+
+			serv := &service.Service{Location: location}
+			handle := NewHandler(serv, nil)
+
+			router := gin.New()
+			router.POST("/locations/import", limitBody(64), handle.importLocations)
+
+			body := "location_id,place,country\n1,Machu Picchu,Peru\n"
+			if test.inputBody != "" {
+				body = test.inputBody
+			}
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("POST", "/locations/import"+test.query, strings.NewReader(body))
+			r.Header.Set("Content-Type", test.contentType)
+			if test.chunked {
+				r.ContentLength = -1
+			}
+
+			router.ServeHTTP(w, r)
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, strings.Trim(w.Body.String(), "\n"), test.expectedResponseBody)
+		})
+	}
+}
+
+func TestLocationHandler_getNearbyLocations(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockLocation)
+
+	lat, lon := 48.8584, 2.2945
+
+	testTable := []struct {
+		name                 string
+		query                string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:  "Ok",
+			query: "?lat=48.8566&lon=2.3522&radius_km=5",
+			mockBehavior: func(s *mock_service.MockLocation) {
+				s.EXPECT().GetNearby(48.8566, 2.3522, 5.0, 20).Return(model.NearbyLocations{
+					List: []model.NearbyLocation{
+						{
+							Location:   model.Location{LocationId: 2, Place: "Eiffel Tower", Country: "France", Lat: &lat, Lon: &lon},
+							DistanceKm: 4.2,
+						},
+					},
+				}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"list":[{"location_id":2,"place":"Eiffel Tower","country":"France","lat":48.8584,"lon":2.2945,"distance_km":4.2}]}`,
+		},
+		{
+			name:                 "Missing Longitude",
+			query:                "?lat=48.8566",
+			mockBehavior:         func(s *mock_service.MockLocation) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"invalid query parameters"}`,
+		},
+		{
+			name:                 "Latitude Out Of Range",
+			query:                "?lat=91&lon=0",
+			mockBehavior:         func(s *mock_service.MockLocation) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"invalid query parameters"}`,
+		},
+		{
+			name:                 "Negative Radius",
+			query:                "?lat=0&lon=0&radius_km=-1",
+			mockBehavior:         func(s *mock_service.MockLocation) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"invalid query parameters"}`,
+		},
+	}
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			location := mock_service.NewMockLocation(controller)
+			test.mockBehavior(location)
+
+			serv := &service.Service{Location: location}
+			handle := NewHandler(serv, nil)
+
+			router := gin.New()
+			router.GET("/locations/nearby", handle.getNearbyLocations)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "/locations/nearby"+test.query, nil)
+
+			router.ServeHTTP(w, r)
+
+			body := strings.Trim(w.Body.String(), "\n")
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, body, test.expectedResponseBody)
+		})
+	}
+}
+
+func TestLocationHandler_getTopLocations(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockLocation)
+
+	testTable := []struct {
+		name                 string
+		query                string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:  "Ok",
+			query: "?by=avg&country=Peru&from=2018-01-01&bayesian=true",
+			mockBehavior: func(s *mock_service.MockLocation) {
+				s.EXPECT().GetTop(model.TopLocationsQuery{By: "avg", Country: "Peru", From: "2018-01-01", Limit: 10,
+					MinVisits: 3, Bayesian: true}).
+					Return(model.TopLocations{
+						List: []model.TopLocation{
+							{
+								Location:    model.Location{LocationId: 1, Place: "Machu Picchu", Country: "Peru", CountryCode: "PE"},
+								Visits:      4,
+								Avg:         4.75,
+								BayesianAvg: 4.43,
+							},
+						},
+					}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{"list":[{"location_id":1,"place":"Machu Picchu","country":"Peru","country_code":"PE",` +
+				`"visits":4,"avg":4.75,"bayesian_avg":4.43}]}`,
+		},
+		{
+			name:                 "Unknown Metric",
+			query:                "?by=distance",
+			mockBehavior:         func(s *mock_service.MockLocation) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"invalid query parameters"}`,
+		},
+		{
+			name:                 "Limit Too Large",
+			query:                "?limit=1000",
+			mockBehavior:         func(s *mock_service.MockLocation) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"invalid query parameters"}`,
+		},
+		{
+			name:  "Unknown Country",
+			query: "?country=Atlantis",
+			mockBehavior: func(s *mock_service.MockLocation) {
+				s.EXPECT().GetTop(model.TopLocationsQuery{Country: "Atlantis", Limit: 10, MinVisits: 3}).
+					Return(model.TopLocations{}, apperrors.ErrUnknownCountry)
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"unknown country"}`,
+		},
+	}
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			location := mock_service.NewMockLocation(controller)
+			test.mockBehavior(location)
+
+			serv := &service.Service{Location: location}
+			handle := NewHandler(serv, nil)
+
+			router := gin.New()
+			router.GET("/locations/top", handle.getTopLocations)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "/locations/top"+test.query, nil)
+
+			router.ServeHTTP(w, r)
+
+			body := strings.Trim(w.Body.String(), "\n")
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, body, test.expectedResponseBody)
+		})
+	}
+}
+
+func TestLocationHandler_getMostWantedLocations(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockLocation)
+
+	testTable := []struct {
+		name                 string
+		query                string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:  "Ok",
+			query: "?limit=5",
+			mockBehavior: func(s *mock_service.MockLocation) {
+				s.EXPECT().GetMostWanted(5).Return(model.WantedLocations{
+					List: []model.WantedLocation{
+						{
+							Location: model.Location{LocationId: 1, Place: "Machu Picchu", Country: "Peru", CountryCode: "PE"},
+							Wishes:   12,
+						},
+					},
+				}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"list":[{"location_id":1,"place":"Machu Picchu","country":"Peru","country_code":"PE","wishes":12}]}`,
+		},
+		{
+			name:                 "Invalid Limit",
+			query:                "?limit=0",
+			mockBehavior:         func(s *mock_service.MockLocation) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"invalid query parameters"}`,
+		},
+	}
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			location := mock_service.NewMockLocation(controller)
+			test.mockBehavior(location)
+
+			serv := &service.Service{Location: location}
+			handle := NewHandler(serv, nil)
+
+			router := gin.New()
+			router.GET("/locations/wanted", handle.getMostWantedLocations)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "/locations/wanted"+test.query, nil)
+
+			router.ServeHTTP(w, r)
+
+			body := strings.Trim(w.Body.String(), "\n")
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, body, test.expectedResponseBody)
+		})
+	}
+}
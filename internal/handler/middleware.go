@@ -0,0 +1,309 @@
+package handler
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/pkg/apperrors"
+	"github.com/rinuccia/travels-api/pkg/ratelimit"
+	"github.com/sirupsen/logrus"
+	"io"
+	"math"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// identityKey is the gin context key of the authenticated caller, requestIdKey of the request id.
+const (
+	identityKey  = "identity"
+	requestIdKey = "request_id"
+)
+
+// requestIdPattern limits client supplied request ids to short tokens that are safe to log and store.
+var requestIdPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// requestId keeps the X-Request-ID of the client or generates one, and echoes it in the response.
+func requestId() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader("X-Request-ID")
+		if !requestIdPattern.MatchString(id) {
+			b := make([]byte, 16)
+			if _, err := rand.Read(b); err != nil {
+				c.AbortWithStatusJSON(http.StatusInternalServerError, newErrResponse("something went wrong"))
+				return
+			}
+			id = hex.EncodeToString(b)
+		}
+		c.Set(requestIdKey, id)
+		c.Header("X-Request-ID", id)
+		c.Next()
+	}
+}
+
+// authenticate rejects requests without a valid bearer access token and stores the caller identity in the context.
+func (h *authHandler) authenticate() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		bearer := strings.TrimPrefix(header, "Bearer ")
+		if bearer == header || bearer == "" {
+			c.Header("WWW-Authenticate", `Bearer realm="travels-api"`)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, newErrResponse("unauthorized"))
+			return
+		}
+
+		identity, err := h.repo.Authenticate(bearer)
+		if err != nil {
+			c.Header("WWW-Authenticate", `Bearer realm="travels-api", error="invalid_token"`)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, newErrResponse("unauthorized"))
+			return
+		}
+		c.Set(identityKey, identity)
+		c.Next()
+	}
+}
+
+// identify authenticates requests carrying a bearer access token like authenticate and lets others through
+// as anonymous callers, for public routes serving more to some callers.
+func (h *authHandler) identify() gin.HandlerFunc {
+	bearer := h.authenticate()
+	return func(c *gin.Context) {
+		if !strings.HasPrefix(c.GetHeader("Authorization"), "Bearer ") {
+			c.Next()
+			return
+		}
+		bearer(c)
+	}
+}
+
+// authenticateWithScope accepts a bearer access token like authenticate or an API key holding the scope,
+// routes without it don't accept API keys.
+func (h *Handler) authenticateWithScope(scope string) gin.HandlerFunc {
+	bearer := h.authenticate()
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		key := strings.TrimPrefix(header, "ApiKey ")
+		if key == header {
+			bearer(c)
+			return
+		}
+
+		identity, err := h.apiKeyHandler.repo.Authenticate(key)
+		if errors.Is(err, apperrors.ErrUnauthorized) {
+			c.Header("WWW-Authenticate", `ApiKey realm="travels-api"`)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, newErrResponse("unauthorized"))
+			return
+		}
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, newErrResponse("something went wrong"))
+			return
+		}
+		if err = h.policy.RequireScope(identity, scope); err != nil {
+			abortWithProblem(c, http.StatusForbidden, "API key lacks the "+scope+" scope")
+			return
+		}
+		c.Set(identityKey, identity)
+		c.Next()
+	}
+}
+
+// callerIdentity returns the caller stored by authenticate.
+func callerIdentity(c *gin.Context) model.Identity {
+	identity, _ := c.Get(identityKey)
+	caller, _ := identity.(model.Identity)
+	return caller
+}
+
+// callerName returns "apikey:<id>" or "user:<id>" for an authenticated caller and "" otherwise.
+func callerName(c *gin.Context) string {
+	caller := callerIdentity(c)
+	switch {
+	case caller.APIKeyId != 0:
+		return "apikey:" + strconv.FormatUint(uint64(caller.APIKeyId), 10)
+	case caller.UserId != 0:
+		return "user:" + strconv.FormatUint(uint64(caller.UserId), 10)
+	default:
+		return ""
+	}
+}
+
+// auditActor returns who makes the change of the request for the audit log.
+func auditActor(c *gin.Context) model.Actor {
+	actor := model.Actor{Actor: callerName(c), RequestId: c.GetString(requestIdKey)}
+	if actor.Actor == "" {
+		actor.Actor = "anonymous"
+	}
+	return actor
+}
+
+// authorize rejects requests of callers the rule denies with a 403 problem response, it runs after authenticate.
+// API keys are held to the rule like users, their scope only decides which routes they may call at all.
+func authorize(rule func(c *gin.Context, caller model.Identity) error) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		err := rule(c, callerIdentity(c))
+		if errors.Is(err, apperrors.ErrForbidden) {
+			abortWithProblem(c, http.StatusForbidden, "you are not allowed to perform this action")
+			return
+		}
+		if errors.Is(err, apperrors.ErrIncorrectQuery) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, newErrResponse("invalid input body"))
+			return
+		}
+		if errors.Is(err, apperrors.ErrRecordNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, newErrResponse(err.Error()))
+			return
+		}
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, newErrResponse("something went wrong"))
+			return
+		}
+		c.Next()
+	}
+}
+
+// requireRole allows callers having one of the roles and admins.
+func (h *Handler) requireRole(roles ...string) gin.HandlerFunc {
+	return authorize(func(c *gin.Context, caller model.Identity) error {
+		return h.policy.RequireRole(caller, roles...)
+	})
+}
+
+// requireUser allows the user given by the id path parameter and admins.
+func (h *Handler) requireUser() gin.HandlerFunc {
+	return authorize(func(c *gin.Context, caller model.Identity) error {
+		return h.policy.RequireUser(caller, c.Param("id"))
+	})
+}
+
+// requireBodyUser allows the user given by user_id of the JSON body and admins, the body is left for the handler.
+func (h *Handler) requireBodyUser() gin.HandlerFunc {
+	return authorize(func(c *gin.Context, caller model.Identity) error {
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			return apperrors.ErrIncorrectQuery
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		owner := struct {
+			UserId uint32 `json:"user_id"`
+		}{}
+		if err = json.Unmarshal(body, &owner); err != nil {
+			return apperrors.ErrIncorrectQuery
+		}
+		return h.policy.RequireUser(caller, strconv.FormatUint(uint64(owner.UserId), 10))
+	})
+}
+
+// requireViewer allows anyone to read the data of a public user given by the id path parameter,
+// the data of a private user only the user, approved followers and admins.
+func (h *Handler) requireViewer() gin.HandlerFunc {
+	return authorize(func(c *gin.Context, caller model.Identity) error {
+		return h.policy.RequireViewer(caller, c.Param("id"))
+	})
+}
+
+// requireVisitOwner allows the user who made the visit given by the id path parameter and admins.
+func (h *Handler) requireVisitOwner() gin.HandlerFunc {
+	return authorize(func(c *gin.Context, caller model.Identity) error {
+		return h.policy.RequireVisitOwner(caller, c.Param("id"))
+	})
+}
+
+// requireTripOwner allows the user whose trip is given by the id path parameter and admins.
+func (h *Handler) requireTripOwner() gin.HandlerFunc {
+	return authorize(func(c *gin.Context, caller model.Identity) error {
+		return h.policy.RequireTripOwner(caller, c.Param("id"))
+	})
+}
+
+// limitBody answers 413 to requests announcing a body larger than limit and stops reading longer bodies there,
+// handlers get a *http.MaxBytesError from the body then. A limit of 0 turns it off.
+func limitBody(limit int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if limit <= 0 {
+			c.Next()
+			return
+		}
+		if c.Request.ContentLength > limit {
+			bodyTooLarge(c, limit)
+			return
+		}
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+		c.Next()
+	}
+}
+
+// bodyTooLarge aborts the request with 413.
+func bodyTooLarge(c *gin.Context, limit int64) {
+	c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge,
+		newErrResponse(fmt.Sprintf("request body is larger than %d bytes", limit)))
+}
+
+// RateLimits are the token buckets of the read and write route groups and of every request of a client IP,
+// a zero rate turns the limit off. Tenant routes leave PerIP off, InitTenantRoutes limits client IPs across tenants.
+type RateLimits struct {
+	Store  ratelimit.Store
+	Reads  ratelimit.Limit
+	Writes ratelimit.Limit
+	PerIP  ratelimit.Limit
+}
+
+// rateLimit takes a token from the caller's bucket of the group and rejects the request with 429 once it's empty.
+// Callers are told apart by API key, user or else client IP, so on protected routes it runs after authentication.
+func rateLimit(store ratelimit.Store, group string, limit ratelimit.Limit) gin.HandlerFunc {
+	return limitBy(store, group, limit, clientKey)
+}
+
+// ipRateLimit is rateLimit by client IP only. It runs before authentication, so that guessing passwords,
+// tokens and API keys is limited too and doesn't reach the database unthrottled.
+func ipRateLimit(store ratelimit.Store, group string, limit ratelimit.Limit) gin.HandlerFunc {
+	return limitBy(store, group, limit, func(c *gin.Context) string {
+		return "ip:" + c.ClientIP()
+	})
+}
+
+func limitBy(store ratelimit.Store, group string, limit ratelimit.Limit, key func(c *gin.Context) string) gin.HandlerFunc {
+	if store == nil || limit.Rate <= 0 || limit.Burst <= 0 {
+		return func(c *gin.Context) {
+			c.Next()
+		}
+	}
+	return func(c *gin.Context) {
+		res, err := store.Take(group+":"+key(c), limit, time.Now())
+		if err != nil {
+			// an unavailable store lets requests through rather than taking the API down with it
+			logrus.Warnf("rate limit store failed: %s", err.Error())
+			c.Next()
+			return
+		}
+
+		c.Header("RateLimit-Limit", strconv.Itoa(limit.Burst))
+		c.Header("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+		c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(res.Reset)))
+		if !res.Allowed {
+			c.Header("Retry-After", strconv.Itoa(ceilSeconds(res.RetryAfter)))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, newErrResponse("too many requests"))
+			return
+		}
+		c.Next()
+	}
+}
+
+// clientKey names the bucket owner of the request.
+func clientKey(c *gin.Context) string {
+	if name := callerName(c); name != "" {
+		return name
+	}
+	return "ip:" + c.ClientIP()
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
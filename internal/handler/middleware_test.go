@@ -0,0 +1,752 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/internal/policy"
+	mock_policy "github.com/rinuccia/travels-api/internal/policy/mocks"
+	"github.com/rinuccia/travels-api/internal/service"
+	mock_service "github.com/rinuccia/travels-api/internal/service/mocks"
+	"github.com/rinuccia/travels-api/pkg/apperrors"
+	"github.com/rinuccia/travels-api/pkg/ratelimit"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandler_authorize(t *testing.T) {
+	type mockBehavior func(p *mock_policy.MockPolicy, caller model.Identity)
+
+	caller := model.Identity{UserId: 1, Role: model.RoleTraveller}
+
+	testTable := []struct {
+		name                 string
+		method               string
+		target               string
+		inputBody            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedContentType  string
+		expectedResponseBody string
+	}{
+		{
+			name:   "Own Profile",
+			method: "PUT",
+			target: "/user/1",
+			mockBehavior: func(p *mock_policy.MockPolicy, caller model.Identity) {
+				p.EXPECT().RequireUser(caller, "1").Return(nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedContentType:  "text/plain; charset=utf-8",
+			expectedResponseBody: "passed",
+		},
+		{
+			name:   "Other Profile",
+			method: "PUT",
+			target: "/user/2",
+			mockBehavior: func(p *mock_policy.MockPolicy, caller model.Identity) {
+				p.EXPECT().RequireUser(caller, "2").Return(apperrors.ErrForbidden)
+			},
+			expectedStatusCode:  http.StatusForbidden,
+			expectedContentType: "application/problem+json",
+			expectedResponseBody: `{"type":"about:blank","title":"Forbidden","status":403,` +
+				`"detail":"you are not allowed to perform this action"}`,
+		},
+		{
+			name:   "Other Visit",
+			method: "DELETE",
+			target: "/visit/10",
+			mockBehavior: func(p *mock_policy.MockPolicy, caller model.Identity) {
+				p.EXPECT().RequireVisitOwner(caller, "10").Return(apperrors.ErrForbidden)
+			},
+			expectedStatusCode:  http.StatusForbidden,
+			expectedContentType: "application/problem+json",
+			expectedResponseBody: `{"type":"about:blank","title":"Forbidden","status":403,` +
+				`"detail":"you are not allowed to perform this action"}`,
+		},
+		{
+			name:   "Missing Visit",
+			method: "DELETE",
+			target: "/visit/10",
+			mockBehavior: func(p *mock_policy.MockPolicy, caller model.Identity) {
+				p.EXPECT().RequireVisitOwner(caller, "10").Return(apperrors.ErrRecordNotFound)
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedContentType:  "application/json; charset=utf-8",
+			expectedResponseBody: `{"error":"record not found"}`,
+		},
+		{
+			name:   "Location As Traveller",
+			method: "POST",
+			target: "/location/new",
+			mockBehavior: func(p *mock_policy.MockPolicy, caller model.Identity) {
+				p.EXPECT().RequireRole(caller, model.RoleCurator).Return(apperrors.ErrForbidden)
+			},
+			expectedStatusCode:  http.StatusForbidden,
+			expectedContentType: "application/problem+json",
+			expectedResponseBody: `{"type":"about:blank","title":"Forbidden","status":403,` +
+				`"detail":"you are not allowed to perform this action"}`,
+		},
+		{
+			name:      "Own Visit Body",
+			method:    "POST",
+			target:    "/visit/new",
+			inputBody: `{"user_id":1,"location_id":2}`,
+			mockBehavior: func(p *mock_policy.MockPolicy, caller model.Identity) {
+				p.EXPECT().RequireUser(caller, "1").Return(nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedContentType:  "text/plain; charset=utf-8",
+			expectedResponseBody: `{"user_id":1,"location_id":2}`,
+		},
+		{
+			name:      "Other Visit Body",
+			method:    "POST",
+			target:    "/visit/new",
+			inputBody: `{"user_id":2,"location_id":2}`,
+			mockBehavior: func(p *mock_policy.MockPolicy, caller model.Identity) {
+				p.EXPECT().RequireUser(caller, "2").Return(apperrors.ErrForbidden)
+			},
+			expectedStatusCode:  http.StatusForbidden,
+			expectedContentType: "application/problem+json",
+			expectedResponseBody: `{"type":"about:blank","title":"Forbidden","status":403,` +
+				`"detail":"you are not allowed to perform this action"}`,
+		},
+		{
+			name:                 "Invalid Body",
+			method:               "POST",
+			target:               "/visit/new",
+			inputBody:            `{"user_id":"one"}`,
+			mockBehavior:         func(p *mock_policy.MockPolicy, caller model.Identity) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedContentType:  "application/json; charset=utf-8",
+			expectedResponseBody: `{"error":"invalid input body"}`,
+		},
+	}
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			rules := mock_policy.NewMockPolicy(controller)
+			test.mockBehavior(rules, caller)
+
+			handle := NewHandler(&service.Service{}, rules)
+
+			// echo replies with the request body, so the test sees what the handler after the middleware reads
+			echo := func(c *gin.Context) {
+				body, _ := io.ReadAll(c.Request.Body)
+				if len(body) == 0 {
+					body = []byte("passed")
+				}
+				c.Data(http.StatusOK, "text/plain; charset=utf-8", body)
+			}
+
+			router := gin.New()
+			router.Use(func(c *gin.Context) {
+				c.Set(identityKey, caller)
+			})
+			router.PUT("/user/:id", handle.requireUser(), echo)
+			router.DELETE("/visit/:id", handle.requireVisitOwner(), echo)
+			router.POST("/location/new", handle.requireRole(model.RoleCurator), echo)
+			router.POST("/visit/new", handle.requireBodyUser(), echo)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(test.method, test.target, strings.NewReader(test.inputBody))
+
+			router.ServeHTTP(w, r)
+
+			body := strings.Trim(w.Body.String(), "\n")
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, w.Header().Get("Content-Type"), test.expectedContentType)
+			assert.Equal(t, body, test.expectedResponseBody)
+		})
+	}
+}
+
+func TestHandler_authenticateWithScope(t *testing.T) {
+	type mockBehavior func(a *mock_service.MockAuth, k *mock_service.MockAPIKey, p *mock_policy.MockPolicy)
+
+	keyCaller := model.Identity{APIKeyId: 1, Role: model.RoleCurator, Scopes: []string{model.ScopeLocationsWrite}}
+	userCaller := model.Identity{UserId: 2, Role: model.RoleCurator}
+
+	testTable := []struct {
+		name                 string
+		authorization        string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:          "API Key",
+			authorization: "ApiKey tk_key",
+			mockBehavior: func(a *mock_service.MockAuth, k *mock_service.MockAPIKey, p *mock_policy.MockPolicy) {
+				k.EXPECT().Authenticate("tk_key").Return(keyCaller, nil)
+				p.EXPECT().RequireScope(keyCaller, model.ScopeLocationsWrite).Return(nil)
+				p.EXPECT().RequireRole(keyCaller, model.RoleCurator).Return(nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"user_id":0,"role":"curator","api_key_id":1,"scopes":["locations:write"]}`,
+		},
+		{
+			name:          "Missing Scope",
+			authorization: "ApiKey tk_key",
+			mockBehavior: func(a *mock_service.MockAuth, k *mock_service.MockAPIKey, p *mock_policy.MockPolicy) {
+				k.EXPECT().Authenticate("tk_key").Return(keyCaller, nil)
+				p.EXPECT().RequireScope(keyCaller, model.ScopeLocationsWrite).Return(apperrors.ErrForbidden)
+			},
+			expectedStatusCode: http.StatusForbidden,
+			expectedResponseBody: `{"type":"about:blank","title":"Forbidden","status":403,` +
+				`"detail":"API key lacks the locations:write scope"}`,
+		},
+		{
+			name:          "Revoked Key",
+			authorization: "ApiKey tk_key",
+			mockBehavior: func(a *mock_service.MockAuth, k *mock_service.MockAPIKey, p *mock_policy.MockPolicy) {
+				k.EXPECT().Authenticate("tk_key").Return(model.Identity{}, apperrors.ErrUnauthorized)
+			},
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedResponseBody: `{"error":"unauthorized"}`,
+		},
+		{
+			name:          "Bearer Token",
+			authorization: "Bearer access",
+			mockBehavior: func(a *mock_service.MockAuth, k *mock_service.MockAPIKey, p *mock_policy.MockPolicy) {
+				a.EXPECT().Authenticate("access").Return(userCaller, nil)
+				p.EXPECT().RequireRole(userCaller, model.RoleCurator).Return(nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"user_id":2,"role":"curator"}`,
+		},
+		{
+			name:                 "Missing Credentials",
+			mockBehavior:         func(a *mock_service.MockAuth, k *mock_service.MockAPIKey, p *mock_policy.MockPolicy) {},
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedResponseBody: `{"error":"unauthorized"}`,
+		},
+	}
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			auth := mock_service.NewMockAuth(controller)
+			apiKey := mock_service.NewMockAPIKey(controller)
+			rules := mock_policy.NewMockPolicy(controller)
+			test.mockBehavior(auth, apiKey, rules)
+
+			serv := &service.Service{Auth: auth, APIKey: apiKey}
+			handle := NewHandler(serv, rules)
+
+			router := gin.New()
+			router.POST("/location/new", handle.authenticateWithScope(model.ScopeLocationsWrite),
+				handle.requireRole(model.RoleCurator), func(c *gin.Context) {
+					c.JSON(http.StatusOK, callerIdentity(c))
+				})
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("POST", "/location/new", nil)
+			if test.authorization != "" {
+				r.Header.Set("Authorization", test.authorization)
+			}
+
+			router.ServeHTTP(w, r)
+
+			body := strings.Trim(w.Body.String(), "\n")
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, body, test.expectedResponseBody)
+		})
+	}
+}
+
+func TestHandler_rateLimit(t *testing.T) {
+	type request struct {
+		caller             string
+		expectedStatusCode int
+		expectedRemaining  string
+		expectedRetryAfter string
+	}
+
+	callers := map[string]model.Identity{
+		"user": {UserId: 1, Role: model.RoleTraveller},
+		"key":  {APIKeyId: 1, Scopes: []string{model.ScopeVisitsWrite}},
+	}
+
+	testTable := []struct {
+		name     string
+		requests []request
+	}{
+		{
+			name: "Burst Then Limited",
+			requests: []request{
+				{expectedStatusCode: http.StatusOK, expectedRemaining: "1"},
+				{expectedStatusCode: http.StatusOK, expectedRemaining: "0"},
+				{expectedStatusCode: http.StatusTooManyRequests, expectedRemaining: "0", expectedRetryAfter: "60"},
+			},
+		},
+		{
+			name: "Separate Callers",
+			requests: []request{
+				{expectedStatusCode: http.StatusOK, expectedRemaining: "1"},
+				{expectedStatusCode: http.StatusOK, expectedRemaining: "0"},
+				{caller: "user", expectedStatusCode: http.StatusOK, expectedRemaining: "1"},
+				{caller: "key", expectedStatusCode: http.StatusOK, expectedRemaining: "1"},
+				{caller: "user", expectedStatusCode: http.StatusOK, expectedRemaining: "0"},
+				{caller: "user", expectedStatusCode: http.StatusTooManyRequests, expectedRemaining: "0",
+					expectedRetryAfter: "60"},
+			},
+		},
+	}
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			limit := ratelimit.Limit{Rate: 1.0 / 60, Burst: 2}
+
+			router := gin.New()
+			router.GET("/locations", func(c *gin.Context) {
+				if caller, ok := callers[c.Query("caller")]; ok {
+					c.Set(identityKey, caller)
+				}
+			}, rateLimit(ratelimit.NewMemory(), "reads", limit), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			for _, req := range test.requests {
+				w := httptest.NewRecorder()
+				r := httptest.NewRequest("GET", "/locations?caller="+req.caller, nil)
+
+				router.ServeHTTP(w, r)
+
+				assert.Equal(t, w.Code, req.expectedStatusCode)
+				assert.Equal(t, w.Header().Get("RateLimit-Limit"), "2")
+				assert.Equal(t, w.Header().Get("RateLimit-Remaining"), req.expectedRemaining)
+				assert.Equal(t, w.Header().Get("Retry-After"), req.expectedRetryAfter)
+			}
+		})
+	}
+}
+
+func TestHandler_auditActor(t *testing.T) {
+	testTable := []struct {
+		name              string
+		caller            *model.Identity
+		requestId         string
+		expectedActor     string
+		expectedRequestId string
+	}{
+		{
+			name:              "User",
+			caller:            &model.Identity{UserId: 7, Role: model.RoleTraveller},
+			requestId:         "req-7.a_b",
+			expectedActor:     "user:7",
+			expectedRequestId: "req-7.a_b",
+		},
+		{
+			name:              "API Key",
+			caller:            &model.Identity{APIKeyId: 3, Scopes: []string{model.ScopeVisitsWrite}},
+			requestId:         "req-3",
+			expectedActor:     "apikey:3",
+			expectedRequestId: "req-3",
+		},
+		{
+			name:          "Anonymous With Generated Id",
+			expectedActor: "anonymous",
+		},
+		{
+			name:          "Unsafe Request Id Replaced",
+			requestId:     "bad id\r\n",
+			expectedActor: "anonymous",
+		},
+	}
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			var actor model.Actor
+
+			router := gin.New()
+			router.Use(requestId())
+			router.POST("/visit/new", func(c *gin.Context) {
+				if test.caller != nil {
+					c.Set(identityKey, *test.caller)
+				}
+				actor = auditActor(c)
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("POST", "/visit/new", nil)
+			if test.requestId != "" {
+				r.Header.Set("X-Request-ID", test.requestId)
+			}
+
+			router.ServeHTTP(w, r)
+
+			assert.Equal(t, actor.Actor, test.expectedActor)
+			assert.Equal(t, w.Header().Get("X-Request-ID"), actor.RequestId)
+			if test.expectedRequestId != "" {
+				assert.Equal(t, actor.RequestId, test.expectedRequestId)
+			} else {
+				assert.Regexp(t, "^[0-9a-f]{32}$", actor.RequestId)
+			}
+		})
+	}
+}
+
+func TestHandler_ipRateLimit(t *testing.T) {
+	type request struct {
+		remoteAddr         string
+		expectedStatusCode int
+	}
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	apiKey := mock_service.NewMockAPIKey(controller)
+	apiKey.EXPECT().Authenticate("tk_guess").Return(model.Identity{}, apperrors.ErrUnauthorized).Times(3)
+
+	router := gin.New()
+	NewHandler(&service.Service{APIKey: apiKey}, policy.New(visitOwners{})).InitRoutes(router, RateLimits{
+		Store: ratelimit.NewMemory(),
+		PerIP: ratelimit.Limit{Rate: 1.0 / 60, Burst: 2},
+	}, 0)
+
+	requests := []request{
+		{remoteAddr: "192.0.2.1:1234", expectedStatusCode: http.StatusUnauthorized},
+		{remoteAddr: "192.0.2.1:1234", expectedStatusCode: http.StatusUnauthorized},
+		{remoteAddr: "192.0.2.1:1234", expectedStatusCode: http.StatusTooManyRequests},
+		{remoteAddr: "192.0.2.2:1234", expectedStatusCode: http.StatusUnauthorized},
+	}
+	for _, req := range requests {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("DELETE", "/visit/10", nil)
+		r.RemoteAddr = req.remoteAddr
+		r.Header.Set("Authorization", "ApiKey tk_guess")
+
+		router.ServeHTTP(w, r)
+
+		assert.Equal(t, req.expectedStatusCode, w.Code)
+	}
+}
+
+// visitOwners maps visit and trip ids to their users and "private:<user id>" to the approved follower
+// of a private user for the real policy.
+type visitOwners map[string]uint32
+
+func (o visitOwners) FindVisitOwner(visitId string) (uint32, error) {
+	return o[visitId], nil
+}
+
+func (o visitOwners) FindTripOwner(tripId string) (uint32, error) {
+	return o[tripId], nil
+}
+
+func (o visitOwners) IsHidden(userId string, viewerId uint32) (bool, error) {
+	follower, private := o["private:"+userId]
+	return private && follower != viewerId, nil
+}
+
+func TestHandler_apiKeyRules(t *testing.T) {
+	type mockBehavior func(k *mock_service.MockAPIKey, l *mock_service.MockLocation)
+
+	key := model.Identity{APIKeyId: 1, Role: model.RoleCurator,
+		Scopes: []string{model.ScopeVisitsWrite, model.ScopeLocationsWrite}}
+	forbidden := `{"type":"about:blank","title":"Forbidden","status":403,` +
+		`"detail":"you are not allowed to perform this action"}`
+
+	testTable := []struct {
+		name                 string
+		method               string
+		target               string
+		contentType          string
+		inputBody            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:   "Other User's Visit",
+			method: "DELETE",
+			target: "/visit/10",
+			mockBehavior: func(k *mock_service.MockAPIKey, l *mock_service.MockLocation) {
+				k.EXPECT().Authenticate("tk_key").Return(key, nil)
+			},
+			expectedStatusCode:   http.StatusForbidden,
+			expectedResponseBody: forbidden,
+		},
+		{
+			name:      "Visit For Other User",
+			method:    "POST",
+			target:    "/visit/new",
+			inputBody: `{"visit_id":1,"user_id":5,"location_id":2,"visited_at":"2019-06-15","mark":4}`,
+			mockBehavior: func(k *mock_service.MockAPIKey, l *mock_service.MockLocation) {
+				k.EXPECT().Authenticate("tk_key").Return(key, nil)
+			},
+			expectedStatusCode:   http.StatusForbidden,
+			expectedResponseBody: forbidden,
+		},
+		{
+			name:        "Admin Only Import",
+			method:      "POST",
+			target:      "/visits/import",
+			contentType: "text/csv",
+			inputBody:   "visit_id,location_id,user_id,visited_at,mark\n",
+			mockBehavior: func(k *mock_service.MockAPIKey, l *mock_service.MockLocation) {
+				k.EXPECT().Authenticate("tk_key").Return(key, nil)
+			},
+			expectedStatusCode:   http.StatusForbidden,
+			expectedResponseBody: forbidden,
+		},
+		{
+			name:      "Curator Route",
+			method:    "POST",
+			target:    "/location/new",
+			inputBody: `{"location_id":1,"place":"Machu Picchu","country":"Peru"}`,
+			mockBehavior: func(k *mock_service.MockAPIKey, l *mock_service.MockLocation) {
+				k.EXPECT().Authenticate("tk_key").Return(key, nil)
+				location := model.Location{LocationId: 1, Place: "Machu Picchu", Country: "Peru"}
+				l.EXPECT().Create(gomock.Any(), location).Return(location, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"location_id":1,"place":"Machu Picchu","country":"Peru"}`,
+		},
+	}
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			apiKey := mock_service.NewMockAPIKey(controller)
+			location := mock_service.NewMockLocation(controller)
+			test.mockBehavior(apiKey, location)
+
+			serv := &service.Service{APIKey: apiKey, Location: location}
+			router := gin.New()
+			NewHandler(serv, policy.New(visitOwners{"10": 5})).InitRoutes(router, RateLimits{}, 0)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(test.method, test.target, strings.NewReader(test.inputBody))
+			r.Header.Set("Authorization", "ApiKey tk_key")
+			if test.contentType != "" {
+				r.Header.Set("Content-Type", test.contentType)
+			}
+
+			router.ServeHTTP(w, r)
+
+			assert.Equal(t, test.expectedStatusCode, w.Code)
+			assert.Equal(t, test.expectedResponseBody, strings.Trim(w.Body.String(), "\n"))
+		})
+	}
+}
+
+func TestHandler_roleRoutes(t *testing.T) {
+	type mockBehavior func(a *mock_service.MockAuth, r *mock_service.MockReview)
+
+	traveller := model.Identity{UserId: 5, Role: model.RoleTraveller}
+	admin := model.Identity{UserId: 1, Role: model.RoleAdmin}
+	forbidden := `{"type":"about:blank","title":"Forbidden","status":403,` +
+		`"detail":"you are not allowed to perform this action"}`
+
+	testTable := []struct {
+		name                 string
+		method               string
+		target               string
+		token                string
+		contentType          string
+		inputBody            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:   "Admin",
+			method: "GET",
+			target: "/reviews",
+			token:  "Bearer admin",
+			mockBehavior: func(a *mock_service.MockAuth, r *mock_service.MockReview) {
+				a.EXPECT().Authenticate("admin").Return(admin, nil)
+				r.EXPECT().GetByStatus(model.ReviewPending, gomock.Any()).Return(model.Reviews{}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"list":null,"total":0,"page":0,"per_page":0}`,
+		},
+		{
+			name:   "Traveller Reviews",
+			method: "GET",
+			target: "/reviews",
+			token:  "Bearer traveller",
+			mockBehavior: func(a *mock_service.MockAuth, r *mock_service.MockReview) {
+				a.EXPECT().Authenticate("traveller").Return(traveller, nil)
+			},
+			expectedStatusCode:   http.StatusForbidden,
+			expectedResponseBody: forbidden,
+		},
+		{
+			name:      "Traveller Moderation",
+			method:    "PUT",
+			target:    "/review/7/status",
+			token:     "Bearer traveller",
+			inputBody: `{"status":"approved"}`,
+			mockBehavior: func(a *mock_service.MockAuth, r *mock_service.MockReview) {
+				a.EXPECT().Authenticate("traveller").Return(traveller, nil)
+			},
+			expectedStatusCode:   http.StatusForbidden,
+			expectedResponseBody: forbidden,
+		},
+		{
+			name:   "Traveller Export",
+			method: "GET",
+			target: "/export",
+			token:  "Bearer traveller",
+			mockBehavior: func(a *mock_service.MockAuth, r *mock_service.MockReview) {
+				a.EXPECT().Authenticate("traveller").Return(traveller, nil)
+			},
+			expectedStatusCode:   http.StatusForbidden,
+			expectedResponseBody: forbidden,
+		},
+		{
+			name:        "Traveller Location Photo",
+			method:      "POST",
+			target:      "/location/3/photos",
+			token:       "Bearer traveller",
+			contentType: "multipart/form-data; boundary=x",
+			inputBody:   "--x\r\nContent-Disposition: form-data; name=\"photo\"; filename=\"a.png\"\r\n\r\npng\r\n--x--\r\n",
+			mockBehavior: func(a *mock_service.MockAuth, r *mock_service.MockReview) {
+				a.EXPECT().Authenticate("traveller").Return(traveller, nil)
+			},
+			expectedStatusCode:   http.StatusForbidden,
+			expectedResponseBody: forbidden,
+		},
+		{
+			name:                 "No Token",
+			method:               "GET",
+			target:               "/export",
+			mockBehavior:         func(a *mock_service.MockAuth, r *mock_service.MockReview) {},
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedResponseBody: `{"error":"unauthorized"}`,
+		},
+	}
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			auth := mock_service.NewMockAuth(controller)
+			review := mock_service.NewMockReview(controller)
+			test.mockBehavior(auth, review)
+
+			serv := &service.Service{Auth: auth, Review: review}
+			router := gin.New()
+			NewHandler(serv, policy.New(visitOwners{})).InitRoutes(router, RateLimits{}, 0)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(test.method, test.target, strings.NewReader(test.inputBody))
+			if test.token != "" {
+				r.Header.Set("Authorization", test.token)
+			}
+			if test.contentType != "" {
+				r.Header.Set("Content-Type", test.contentType)
+			}
+
+			router.ServeHTTP(w, r)
+
+			assert.Equal(t, test.expectedStatusCode, w.Code)
+			assert.Equal(t, test.expectedResponseBody, strings.Trim(w.Body.String(), "\n"))
+		})
+	}
+}
+
+func TestHandler_privateUserReads(t *testing.T) {
+	type mockBehavior func(a *mock_service.MockAuth, u *mock_service.MockUser, v *mock_service.MockVisit)
+
+	follower := model.Identity{UserId: 8, Role: model.RoleTraveller}
+	stranger := model.Identity{UserId: 9, Role: model.RoleTraveller}
+	forbidden := `{"type":"about:blank","title":"Forbidden","status":403,` +
+		`"detail":"you are not allowed to perform this action"}`
+
+	testTable := []struct {
+		name                 string
+		target               string
+		token                string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:   "Public User",
+			target: "/user/4/summary",
+			mockBehavior: func(a *mock_service.MockAuth, u *mock_service.MockUser, v *mock_service.MockVisit) {
+				u.EXPECT().GetSummary("4").Return(model.UserSummary{}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:   "Approved Follower",
+			target: "/visits/user/5",
+			token:  "Bearer follower",
+			mockBehavior: func(a *mock_service.MockAuth, u *mock_service.MockUser, v *mock_service.MockVisit) {
+				a.EXPECT().Authenticate("follower").Return(follower, nil)
+				v.EXPECT().GetAll("5", gomock.Any()).Return(model.UserVisits{}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:                 "Anonymous",
+			target:               "/user/5/trips",
+			mockBehavior:         func(a *mock_service.MockAuth, u *mock_service.MockUser, v *mock_service.MockVisit) {},
+			expectedStatusCode:   http.StatusForbidden,
+			expectedResponseBody: forbidden,
+		},
+		{
+			name:   "Not A Follower",
+			target: "/user/5/wishlist",
+			token:  "Bearer stranger",
+			mockBehavior: func(a *mock_service.MockAuth, u *mock_service.MockUser, v *mock_service.MockVisit) {
+				a.EXPECT().Authenticate("stranger").Return(stranger, nil)
+			},
+			expectedStatusCode:   http.StatusForbidden,
+			expectedResponseBody: forbidden,
+		},
+		{
+			name:   "Invalid Token",
+			target: "/user/5/summary",
+			token:  "Bearer expired",
+			mockBehavior: func(a *mock_service.MockAuth, u *mock_service.MockUser, v *mock_service.MockVisit) {
+				a.EXPECT().Authenticate("expired").Return(model.Identity{}, apperrors.ErrUnauthorized)
+			},
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedResponseBody: `{"error":"unauthorized"}`,
+		},
+	}
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			auth := mock_service.NewMockAuth(controller)
+			user := mock_service.NewMockUser(controller)
+			visit := mock_service.NewMockVisit(controller)
+			test.mockBehavior(auth, user, visit)
+
+			serv := &service.Service{Auth: auth, User: user, Visit: visit}
+			router := gin.New()
+			NewHandler(serv, policy.New(visitOwners{"private:5": follower.UserId})).InitRoutes(router, RateLimits{}, 0)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", test.target, nil)
+			if test.token != "" {
+				r.Header.Set("Authorization", test.token)
+			}
+
+			router.ServeHTTP(w, r)
+
+			assert.Equal(t, test.expectedStatusCode, w.Code)
+			if test.expectedResponseBody != "" {
+				assert.Equal(t, test.expectedResponseBody, strings.Trim(w.Body.String(), "\n"))
+			}
+		})
+	}
+}
@@ -0,0 +1,247 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/internal/service"
+	"github.com/rinuccia/travels-api/pkg/apperrors"
+	"io"
+	"net/http"
+)
+
+const (
+	// maxPhotoSize limits uploaded images to 10 MB
+	maxPhotoSize = 10 << 20
+	// maxPhotoRequestSize leaves room for the multipart framing around the image
+	maxPhotoRequestSize = maxPhotoSize + 1<<20
+	// photoCacheControl lets clients keep served files, a photo id never points to different content
+	photoCacheControl = "public, max-age=31536000, immutable"
+)
+
+var errPhotoTooLarge = fmt.Errorf("photo is larger than %d MB", maxPhotoSize>>20)
+
+type photoQuery struct {
+	StripLocation bool `form:"strip_location"`
+}
+
+type photoHandler struct {
+	repo service.Photo
+}
+
+func newPhotoHandler(repository service.Photo) *photoHandler {
+	return &photoHandler{
+		repo: repository,
+	}
+}
+
+// getLocationPhotos godoc
+// @Summary Returns photos of location based on given ID
+// @Tags photo
+// @Produce json
+// @Param id path integer true "Location ID"
+// @Success 200 {object} model.Photos
+// @Failure 404 {object} errResponse
+// @Failure 500 {object} errResponse
+// @Router /location/{id}/photos [get]
+func (h *photoHandler) getLocationPhotos(c *gin.Context) {
+	photos, err := h.repo.GetByLocation(c.Param("id"))
+	respondPhotos(c, photos, err)
+}
+
+// getVisitPhotos godoc
+// @Summary Returns photos of visit based on given ID
+// @Tags photo
+// @Produce json
+// @Param id path integer true "Visit ID"
+// @Success 200 {object} model.Photos
+// @Failure 404 {object} errResponse
+// @Failure 500 {object} errResponse
+// @Router /visit/{id}/photos [get]
+func (h *photoHandler) getVisitPhotos(c *gin.Context) {
+	photos, err := h.repo.GetByVisit(c.Param("id"))
+	respondPhotos(c, photos, err)
+}
+
+// uploadLocationPhoto godoc
+// @Summary Uploads JPEG or PNG photo of location based on given ID
+// @Tags photo
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path integer true "Location ID"
+// @Param photo formData file true "Image up to 10 MB"
+// @Param strip_location query boolean false "Remove Exif metadata holding the GPS position"
+// @Success 200 {object} model.Photo
+// @Failure 400,401,404,413,415 {object} errResponse
+// @Failure 403 {object} problemResponse
+// @Failure 500 {object} errResponse
+// @Router /location/{id}/photos [post]
+func (h *photoHandler) uploadLocationPhoto(c *gin.Context) {
+	upload, ok := bindPhotoUpload(c)
+	if !ok {
+		return
+	}
+	photo, err := h.repo.UploadForLocation(c.Param("id"), upload)
+	respondPhoto(c, photo, err)
+}
+
+// uploadVisitPhoto godoc
+// @Summary Uploads JPEG or PNG photo of visit based on given ID
+// @Tags photo
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path integer true "Visit ID"
+// @Param photo formData file true "Image up to 10 MB"
+// @Param strip_location query boolean false "Remove Exif metadata holding the GPS position"
+// @Success 200 {object} model.Photo
+// @Failure 400,401,404,413,415 {object} errResponse
+// @Failure 403 {object} problemResponse
+// @Failure 500 {object} errResponse
+// @Router /visit/{id}/photos [post]
+func (h *photoHandler) uploadVisitPhoto(c *gin.Context) {
+	upload, ok := bindPhotoUpload(c)
+	if !ok {
+		return
+	}
+	photo, err := h.repo.UploadForVisit(c.Param("id"), upload)
+	respondPhoto(c, photo, err)
+}
+
+// getPhoto godoc
+// @Summary Returns image of photo based on given ID
+// @Tags photo
+// @Produce image/jpeg,image/png
+// @Param id path integer true "Photo ID"
+// @Success 200 {file} binary
+// @Success 304
+// @Failure 404 {object} errResponse
+// @Failure 500 {object} errResponse
+// @Router /photo/{id} [get]
+func (h *photoHandler) getPhoto(c *gin.Context) {
+	h.servePhoto(c, false)
+}
+
+// getPhotoThumbnail godoc
+// @Summary Returns thumbnail of photo based on given ID, the longer side is at most 320 pixels
+// @Tags photo
+// @Produce image/jpeg,image/png
+// @Param id path integer true "Photo ID"
+// @Success 200 {file} binary
+// @Success 304
+// @Failure 404 {object} errResponse
+// @Failure 500 {object} errResponse
+// @Router /photo/{id}/thumbnail [get]
+func (h *photoHandler) getPhotoThumbnail(c *gin.Context) {
+	h.servePhoto(c, true)
+}
+
+// servePhoto writes the stored file with caching headers, conditional and range requests are answered by ServeContent.
+func (h *photoHandler) servePhoto(c *gin.Context, thumbnail bool) {
+	photo, file, err := h.repo.Open(c.Param("id"), thumbnail)
+	if errors.Is(err, apperrors.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, newErrResponse(err.Error()))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, newErrResponse("something went wrong"))
+		return
+	}
+	defer file.Close()
+
+	etag := photo.StorageKey
+	if thumbnail {
+		etag += "-thumb"
+	}
+	c.Header("Content-Type", photo.ContentType)
+	c.Header("Cache-Control", photoCacheControl)
+	c.Header("ETag", `"`+etag+`"`)
+	http.ServeContent(c.Writer, c.Request, "", photo.CreatedAt, file)
+}
+
+// bindPhotoUpload reads the image from the "photo" form field and writes the error response when it cannot.
+func bindPhotoUpload(c *gin.Context) (model.PhotoUpload, bool) {
+	query := photoQuery{}
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, newErrResponse("invalid query parameters"))
+		return model.PhotoUpload{}, false
+	}
+	if c.Request.ContentLength > maxPhotoRequestSize {
+		c.JSON(http.StatusRequestEntityTooLarge, newErrResponse(errPhotoTooLarge.Error()))
+		return model.PhotoUpload{}, false
+	}
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxPhotoRequestSize)
+
+	header, err := c.FormFile("photo")
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) || (err == nil && header.Size > maxPhotoSize) {
+		c.JSON(http.StatusRequestEntityTooLarge, newErrResponse(errPhotoTooLarge.Error()))
+		return model.PhotoUpload{}, false
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, newErrResponse("invalid input body"))
+		return model.PhotoUpload{}, false
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, newErrResponse("invalid input body"))
+		return model.PhotoUpload{}, false
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, newErrResponse("invalid input body"))
+		return model.PhotoUpload{}, false
+	}
+
+	return model.PhotoUpload{Data: data, StripLocation: query.StripLocation}, true
+}
+
+func respondPhoto(c *gin.Context, photo model.Photo, err error) {
+	if errors.Is(err, apperrors.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, newErrResponse(err.Error()))
+		return
+	}
+	if errors.Is(err, apperrors.ErrUnsupportedImage) || errors.Is(err, apperrors.ErrImageTooLarge) {
+		c.JSON(http.StatusUnsupportedMediaType, newErrResponse(err.Error()))
+		return
+	}
+	if errors.Is(err, apperrors.ErrInvalidImage) || errors.Is(err, apperrors.ErrIncorrectQuery) {
+		c.JSON(http.StatusBadRequest, newErrResponse(err.Error()))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, newErrResponse("something went wrong"))
+		return
+	}
+
+	c.JSON(http.StatusOK, withPhotoURLs(photo))
+}
+
+func respondPhotos(c *gin.Context, photos model.Photos, err error) {
+	if errors.Is(err, apperrors.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, newErrResponse(err.Error()))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, newErrResponse("something went wrong"))
+		return
+	}
+
+	for i := range photos.List {
+		photos.List[i] = withPhotoURLs(photos.List[i])
+	}
+	c.JSON(http.StatusOK, photos)
+}
+
+// withPhotoURLs fills the addresses the image and its thumbnail are served from.
+func withPhotoURLs(photo model.Photo) model.Photo {
+	photo.URL = fmt.Sprintf("%s/%d", photoURL, photo.PhotoId)
+	photo.ThumbnailURL = photo.URL + "/thumbnail"
+	return photo
+}
@@ -0,0 +1,298 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/internal/service"
+	mock_service "github.com/rinuccia/travels-api/internal/service/mocks"
+	"github.com/rinuccia/travels-api/pkg/apperrors"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type photoFile struct {
+	*strings.Reader
+}
+
+func (photoFile) Close() error { return nil }
+
+func multipartPhoto(field string, content []byte) (*bytes.Buffer, string) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, _ := writer.CreateFormFile(field, "photo.jpg")
+	_, _ = part.Write(content)
+	_ = writer.Close()
+	return body, writer.FormDataContentType()
+}
+
+func TestPhotoHandler_getLocationPhotos(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockPhoto)
+
+	createdAt := time.Date(2019, 6, 15, 10, 0, 0, 0, time.UTC)
+
+	testTable := []struct {
+		name                 string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "Ok",
+			mockBehavior: func(s *mock_service.MockPhoto) {
+				s.EXPECT().GetByLocation("1").Return(model.Photos{
+					List: []model.Photo{
+						{PhotoId: 3, LocationId: 1, StorageKey: "ab12", ContentType: "image/jpeg", Size: 52000,
+							Width: 800, Height: 600, CreatedAt: createdAt},
+					},
+				}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{"list":[{"photo_id":3,"location_id":1,"content_type":"image/jpeg","size":52000,` +
+				`"width":800,"height":600,"created_at":"2019-06-15T10:00:00Z","url":"/photo/3","thumbnail_url":"/photo/3/thumbnail"}]}`,
+		},
+		{
+			name: "Not Found",
+			mockBehavior: func(s *mock_service.MockPhoto) {
+				s.EXPECT().GetByLocation("1").Return(model.Photos{}, apperrors.ErrRecordNotFound)
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"error":"record not found"}`,
+		},
+	}
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			photo := mock_service.NewMockPhoto(controller)
+			test.mockBehavior(photo)
+
+			serv := &service.Service{Photo: photo}
+			handle := NewHandler(serv, nil)
+
+			router := gin.New()
+			router.GET("/location/:id/photos", handle.getLocationPhotos)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "/location/1/photos", nil)
+
+			router.ServeHTTP(w, r)
+
+			body := strings.Trim(w.Body.String(), "\n")
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, body, test.expectedResponseBody)
+		})
+	}
+}
+
+func TestPhotoHandler_uploadVisitPhoto(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockPhoto)
+
+	image := []byte("\xFF\xD8\xFF\xE0jpeg")
+	createdAt := time.Date(2019, 6, 15, 10, 0, 0, 0, time.UTC)
+
+	testTable := []struct {
+		name                 string
+		query                string
+		field                string
+		content              []byte
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:    "Ok",
+			query:   "?strip_location=true",
+			field:   "photo",
+			content: image,
+			mockBehavior: func(s *mock_service.MockPhoto) {
+				s.EXPECT().UploadForVisit("7", model.PhotoUpload{Data: image, StripLocation: true}).Return(model.Photo{
+					PhotoId: 3, VisitId: 7, StorageKey: "ab12", ContentType: "image/jpeg", Size: 8,
+					Width: 1, Height: 1, CreatedAt: createdAt,
+				}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{"photo_id":3,"visit_id":7,"content_type":"image/jpeg","size":8,"width":1,"height":1,` +
+				`"created_at":"2019-06-15T10:00:00Z","url":"/photo/3","thumbnail_url":"/photo/3/thumbnail"}`,
+		},
+		{
+			name:                 "Missing File",
+			field:                "image",
+			content:              image,
+			mockBehavior:         func(s *mock_service.MockPhoto) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"invalid input body"}`,
+		},
+		{
+			name:                 "Invalid Query",
+			query:                "?strip_location=maybe",
+			field:                "photo",
+			content:              image,
+			mockBehavior:         func(s *mock_service.MockPhoto) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"invalid query parameters"}`,
+		},
+		{
+			name:                 "Too Large",
+			field:                "photo",
+			content:              make([]byte, maxPhotoSize+1),
+			mockBehavior:         func(s *mock_service.MockPhoto) {},
+			expectedStatusCode:   http.StatusRequestEntityTooLarge,
+			expectedResponseBody: `{"error":"photo is larger than 10 MB"}`,
+		},
+		{
+			name:    "Unsupported Type",
+			field:   "photo",
+			content: []byte("GIF89a"),
+			mockBehavior: func(s *mock_service.MockPhoto) {
+				s.EXPECT().UploadForVisit("7", model.PhotoUpload{Data: []byte("GIF89a")}).
+					Return(model.Photo{}, apperrors.ErrUnsupportedImage)
+			},
+			expectedStatusCode:   http.StatusUnsupportedMediaType,
+			expectedResponseBody: `{"error":"unsupported image type"}`,
+		},
+		{
+			name:    "Too Many Pixels",
+			field:   "photo",
+			content: []byte("\x89PNG"),
+			mockBehavior: func(s *mock_service.MockPhoto) {
+				s.EXPECT().UploadForVisit("7", model.PhotoUpload{Data: []byte("\x89PNG")}).
+					Return(model.Photo{}, apperrors.ErrImageTooLarge)
+			},
+			expectedStatusCode:   http.StatusUnsupportedMediaType,
+			expectedResponseBody: `{"error":"invalid image: dimensions too large"}`,
+		},
+		{
+			name:    "Visit Not Found",
+			field:   "photo",
+			content: image,
+			mockBehavior: func(s *mock_service.MockPhoto) {
+				s.EXPECT().UploadForVisit("7", model.PhotoUpload{Data: image}).
+					Return(model.Photo{}, apperrors.ErrRecordNotFound)
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"error":"record not found"}`,
+		},
+		{
+			name:    "Service Error",
+			field:   "photo",
+			content: image,
+			mockBehavior: func(s *mock_service.MockPhoto) {
+				s.EXPECT().UploadForVisit("7", model.PhotoUpload{Data: image}).
+					Return(model.Photo{}, errors.New("disk full"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"error":"something went wrong"}`,
+		},
+	}
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			photo := mock_service.NewMockPhoto(controller)
+			test.mockBehavior(photo)
+
+			serv := &service.Service{Photo: photo}
+			handle := NewHandler(serv, nil)
+
+			router := gin.New()
+			router.POST("/visit/:id/photos", handle.uploadVisitPhoto)
+
+			body, contentType := multipartPhoto(test.field, test.content)
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("POST", "/visit/7/photos"+test.query, body)
+			r.Header.Set("Content-Type", contentType)
+
+			router.ServeHTTP(w, r)
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, strings.Trim(w.Body.String(), "\n"), test.expectedResponseBody)
+		})
+	}
+}
+
+func TestPhotoHandler_getPhotoThumbnail(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockPhoto)
+
+	photo := model.Photo{PhotoId: 3, StorageKey: "ab12", ContentType: "image/png",
+		CreatedAt: time.Date(2019, 6, 15, 10, 0, 0, 0, time.UTC)}
+
+	testTable := []struct {
+		name                 string
+		ifNoneMatch          string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "Ok",
+			mockBehavior: func(s *mock_service.MockPhoto) {
+				s.EXPECT().Open("3", true).Return(photo, photoFile{strings.NewReader("thumbnail")}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: "thumbnail",
+		},
+		{
+			name:        "Not Modified",
+			ifNoneMatch: `"ab12-thumb"`,
+			mockBehavior: func(s *mock_service.MockPhoto) {
+				s.EXPECT().Open("3", true).Return(photo, photoFile{strings.NewReader("thumbnail")}, nil)
+			},
+			expectedStatusCode: http.StatusNotModified,
+		},
+		{
+			name: "Not Found",
+			mockBehavior: func(s *mock_service.MockPhoto) {
+				s.EXPECT().Open("3", true).Return(model.Photo{}, nil, apperrors.ErrRecordNotFound)
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"error":"record not found"}`,
+		},
+	}
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			photoService := mock_service.NewMockPhoto(controller)
+			test.mockBehavior(photoService)
+
+			serv := &service.Service{Photo: photoService}
+			handle := NewHandler(serv, nil)
+
+			router := gin.New()
+			router.GET("/photo/:id/thumbnail", handle.getPhotoThumbnail)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "/photo/3/thumbnail", nil)
+			if test.ifNoneMatch != "" {
+				r.Header.Set("If-None-Match", test.ifNoneMatch)
+			}
+
+			router.ServeHTTP(w, r)
+
+			body, _ := io.ReadAll(w.Body)
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, strings.Trim(string(body), "\n"), test.expectedResponseBody)
+			if w.Code != http.StatusNotFound {
+				assert.Equal(t, photoCacheControl, w.Header().Get("Cache-Control"))
+				assert.Equal(t, `"ab12-thumb"`, w.Header().Get("ETag"))
+			}
+			if w.Code == http.StatusOK {
+				assert.Equal(t, "image/png", w.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
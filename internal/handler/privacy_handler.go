@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/rinuccia/travels-api/internal/service"
+	"github.com/rinuccia/travels-api/pkg/apperrors"
+	"github.com/sirupsen/logrus"
+	"net/http"
+)
+
+type privacyHandler struct {
+	repo service.Privacy
+}
+
+func newPrivacyHandler(repository service.Privacy) *privacyHandler {
+	return &privacyHandler{
+		repo: repository,
+	}
+}
+
+// exportUserData godoc
+// @Summary Returns a zip archive with a JSON file per kind of record linked to user based on given ID and the photo files
+// @Tags privacy
+// @Security BearerAuth
+// @Produce application/zip
+// @Param id path integer true "User ID"
+// @Success 200 {file} file
+// @Failure 401,404 {object} errResponse
+// @Failure 403 {object} problemResponse
+// @Failure 500 {object} errResponse
+// @Router /user/{id}/data-export [get]
+func (h *privacyHandler) exportUserData(c *gin.Context) {
+	id := c.Param("id")
+	data, err := h.repo.GetSubjectData(id)
+	if errors.Is(err, apperrors.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, newErrResponse(err.Error()))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, newErrResponse("something went wrong"))
+		return
+	}
+
+	c.Header("Content-Type", "application/zip")
+	c.Header("Content-Disposition", `attachment; filename="user-`+id+`-data.zip"`)
+	c.Status(http.StatusOK)
+
+	// The status line is already sent once streaming starts, so a failure can only be logged.
+	if err = h.repo.WriteArchive(c.Writer, data); err != nil {
+		logrus.Errorf("data export failed: %s", err.Error())
+		_ = c.Error(err)
+	}
+}
+
+// eraseUser godoc
+// @Summary Anonymises user based on given ID, keeps the visits as anonymous statistics and records the erasure
+// @Tags privacy
+// @Security BearerAuth
+// @Produce json
+// @Param id path integer true "User ID"
+// @Success 200 {object} model.Erasure
+// @Failure 401,404 {object} errResponse
+// @Failure 403 {object} problemResponse
+// @Failure 500 {object} errResponse
+// @Router /user/{id}/erase [post]
+func (h *privacyHandler) eraseUser(c *gin.Context) {
+	erasure, err := h.repo.Erase(auditActor(c), c.Param("id"))
+	if errors.Is(err, apperrors.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, newErrResponse(err.Error()))
+		return
+	}
+	if erasure.ErasureId == 0 {
+		c.JSON(http.StatusInternalServerError, newErrResponse("something went wrong"))
+		return
+	}
+	// The erasure is recorded, photo files that failed to delete are only orphaned.
+	if err != nil {
+		logrus.Errorf("deleting photo files of erased user failed: %s", err.Error())
+	}
+
+	c.JSON(http.StatusOK, erasure)
+}
+
+// getErasures godoc
+// @Summary Returns the erasure log oldest first and whether its hash chain is intact, admins only
+// @Tags privacy
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} model.ErasureLog
+// @Failure 401 {object} errResponse
+// @Failure 403 {object} problemResponse
+// @Failure 500 {object} errResponse
+// @Router /erasures [get]
+func (h *privacyHandler) getErasures(c *gin.Context) {
+	log, err := h.repo.GetErasures()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, newErrResponse("something went wrong"))
+		return
+	}
+
+	c.JSON(http.StatusOK, log)
+}
@@ -0,0 +1,225 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/internal/service"
+	mock_service "github.com/rinuccia/travels-api/internal/service/mocks"
+	"github.com/rinuccia/travels-api/pkg/apperrors"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPrivacyHandler_exportUserData(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockPrivacy)
+
+	data := model.SubjectData{Sections: []model.SubjectSection{{Name: "profile", Data: []byte(`{"user_id":1}`)}}}
+
+	testTable := []struct {
+		name                 string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedHeader       string
+		expectedResponseBody string
+	}{
+		{
+			name: "Ok",
+			mockBehavior: func(s *mock_service.MockPrivacy) {
+				s.EXPECT().GetSubjectData("1").Return(data, nil)
+				s.EXPECT().WriteArchive(gomock.Any(), data).DoAndReturn(func(w io.Writer, data model.SubjectData) error {
+					_, err := io.WriteString(w, "PK")
+					return err
+				})
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedHeader:       `attachment; filename="user-1-data.zip"`,
+			expectedResponseBody: "PK",
+		},
+		{
+			name: "Not Found",
+			mockBehavior: func(s *mock_service.MockPrivacy) {
+				s.EXPECT().GetSubjectData("1").Return(model.SubjectData{}, apperrors.ErrRecordNotFound)
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"error":"record not found"}`,
+		},
+		{
+			name: "Service Error",
+			mockBehavior: func(s *mock_service.MockPrivacy) {
+				s.EXPECT().GetSubjectData("1").Return(model.SubjectData{}, errors.New("something went wrong"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"error":"something went wrong"}`,
+		},
+	}
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			privacy := mock_service.NewMockPrivacy(controller)
+			test.mockBehavior(privacy)
+
+			serv := &service.Service{Privacy: privacy}
+			handle := NewHandler(serv, nil)
+
+			router := gin.New()
+			router.GET("/user/:id/data-export", handle.exportUserData)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "/user/1/data-export", nil)
+
+			router.ServeHTTP(w, r)
+
+			body := strings.Trim(w.Body.String(), "\n")
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, w.Header().Get("Content-Disposition"), test.expectedHeader)
+			assert.Equal(t, body, test.expectedResponseBody)
+		})
+	}
+}
+
+func TestPrivacyHandler_eraseUser(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockPrivacy)
+
+	erasedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	erasure := model.Erasure{ErasureId: 3, UserId: 1, Actor: "anonymous", ErasedAt: erasedAt,
+		PrevHash: "aa", Hash: "bb"}
+	erasureBody := `{"erasure_id":3,"user_id":1,"actor":"anonymous","request_id":"",` +
+		`"erased_at":"2024-05-01T12:00:00Z","prev_hash":"aa","hash":"bb"}`
+
+	testTable := []struct {
+		name                 string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "Ok",
+			mockBehavior: func(s *mock_service.MockPrivacy) {
+				s.EXPECT().Erase(model.Actor{Actor: "anonymous"}, "1").Return(erasure, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: erasureBody,
+		},
+		{
+			name: "Photo File Error",
+			mockBehavior: func(s *mock_service.MockPrivacy) {
+				s.EXPECT().Erase(model.Actor{Actor: "anonymous"}, "1").Return(erasure, errors.New("disk failure"))
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: erasureBody,
+		},
+		{
+			name: "Not Found",
+			mockBehavior: func(s *mock_service.MockPrivacy) {
+				s.EXPECT().Erase(model.Actor{Actor: "anonymous"}, "1").
+					Return(model.Erasure{}, apperrors.ErrRecordNotFound)
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"error":"record not found"}`,
+		},
+		{
+			name: "Service Error",
+			mockBehavior: func(s *mock_service.MockPrivacy) {
+				s.EXPECT().Erase(model.Actor{Actor: "anonymous"}, "1").
+					Return(model.Erasure{}, errors.New("something went wrong"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"error":"something went wrong"}`,
+		},
+	}
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			privacy := mock_service.NewMockPrivacy(controller)
+			test.mockBehavior(privacy)
+
+			serv := &service.Service{Privacy: privacy}
+			handle := NewHandler(serv, nil)
+
+			router := gin.New()
+			router.POST("/user/:id/erase", handle.eraseUser)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("POST", "/user/1/erase", nil)
+
+			router.ServeHTTP(w, r)
+
+			body := strings.Trim(w.Body.String(), "\n")
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, body, test.expectedResponseBody)
+		})
+	}
+}
+
+func TestPrivacyHandler_getErasures(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockPrivacy)
+
+	erasedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	testTable := []struct {
+		name                 string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "Ok",
+			mockBehavior: func(s *mock_service.MockPrivacy) {
+				s.EXPECT().GetErasures().Return(model.ErasureLog{
+					List: []model.Erasure{{ErasureId: 1, UserId: 2, Actor: "user:2", RequestId: "req-1",
+						ErasedAt: erasedAt, PrevHash: "aa", Hash: "bb"}},
+					BrokenAt: 1,
+				}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{"list":[{"erasure_id":1,"user_id":2,"actor":"user:2","request_id":"req-1",` +
+				`"erased_at":"2024-05-01T12:00:00Z","prev_hash":"aa","hash":"bb"}],"valid":false,"broken_at":1}`,
+		},
+		{
+			name: "Service Error",
+			mockBehavior: func(s *mock_service.MockPrivacy) {
+				s.EXPECT().GetErasures().Return(model.ErasureLog{}, errors.New("something went wrong"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"error":"something went wrong"}`,
+		},
+	}
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			privacy := mock_service.NewMockPrivacy(controller)
+			test.mockBehavior(privacy)
+
+			serv := &service.Service{Privacy: privacy}
+			handle := NewHandler(serv, nil)
+
+			router := gin.New()
+			router.GET("/erasures", handle.getErasures)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "/erasures", nil)
+
+			router.ServeHTTP(w, r)
+
+			body := strings.Trim(w.Body.String(), "\n")
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, body, test.expectedResponseBody)
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/rinuccia/travels-api/internal/service"
+	"github.com/rinuccia/travels-api/pkg/apperrors"
+	"net/http"
+)
+
+// limitQuery represent the limit parameter of ranking endpoints
+type limitQuery struct {
+	Limit int `form:"limit" validate:"min=1,max=50"`
+}
+
+type recommendationHandler struct {
+	repo service.Recommendation
+}
+
+func newRecommendationHandler(repository service.Recommendation) *recommendationHandler {
+	return &recommendationHandler{
+		repo: repository,
+	}
+}
+
+// getRecommendations godoc
+// @Summary Suggests locations the user has not visited yet
+// @Description Locations liked by users with similar marks come first, the rest are top rated
+// @Description locations in countries the user liked. Every suggestion names the user's rated location behind it.
+// @Tags user
+// @Produce json
+// @Param id path integer true "User ID"
+// @Param limit query integer false "Maximum number of locations" default(10)
+// @Success 200 {object} model.Recommendations
+// @Failure 400,404 {object} errResponse
+// @Failure 500 {object} errResponse
+// @Router /user/{id}/recommendations [get]
+func (h *recommendationHandler) getRecommendations(c *gin.Context) {
+	id := c.Param("id")
+	query := limitQuery{Limit: 10}
+	err := c.ShouldBindQuery(&query)
+	validationErr := validate.Struct(query)
+	if err != nil || validationErr != nil {
+		c.JSON(http.StatusBadRequest, newErrResponse("invalid query parameters"))
+		return
+	}
+
+	recommendations, err := h.repo.GetForUser(id, query.Limit)
+	if errors.Is(err, apperrors.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, newErrResponse(err.Error()))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, newErrResponse("something went wrong"))
+		return
+	}
+
+	c.JSON(http.StatusOK, recommendations)
+}
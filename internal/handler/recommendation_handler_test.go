@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/internal/service"
+	mock_service "github.com/rinuccia/travels-api/internal/service/mocks"
+	"github.com/rinuccia/travels-api/pkg/apperrors"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRecommendationHandler_getRecommendations(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockRecommendation)
+
+	testTable := []struct {
+		name                 string
+		query                string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:  "Ok",
+			query: "?limit=1",
+			mockBehavior: func(s *mock_service.MockRecommendation) {
+				s.EXPECT().GetForUser("1", 1).Return(model.Recommendations{
+					List: []model.Recommendation{
+						{
+							Location:          model.Location{LocationId: 3, Place: "Louvre", Country: "France", CountryCode: "FR"},
+							Score:             4.67,
+							Source:            model.RecommendationSimilarUsers,
+							BecauseLocationId: 2,
+							BecausePlace:      "Eiffel Tower",
+							Reason:            "because you rated Eiffel Tower",
+						},
+					},
+				}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{"list":[{"location_id":3,"place":"Louvre","country":"France","country_code":"FR",` +
+				`"score":4.67,"source":"similar_users","because_location_id":2,"because_place":"Eiffel Tower",` +
+				`"reason":"because you rated Eiffel Tower"}]}`,
+		},
+		{
+			name:                 "Invalid Limit",
+			query:                "?limit=0",
+			mockBehavior:         func(s *mock_service.MockRecommendation) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"invalid query parameters"}`,
+		},
+		{
+			name: "Not Found",
+			mockBehavior: func(s *mock_service.MockRecommendation) {
+				s.EXPECT().GetForUser("1", 10).Return(model.Recommendations{}, apperrors.ErrRecordNotFound)
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"error":"record not found"}`,
+		},
+		{
+			name: "Service Error",
+			mockBehavior: func(s *mock_service.MockRecommendation) {
+				s.EXPECT().GetForUser("1", 10).Return(model.Recommendations{}, errors.New("something went wrong"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"error":"something went wrong"}`,
+		},
+	}
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			recommendation := mock_service.NewMockRecommendation(controller)
+			test.mockBehavior(recommendation)
+
+			serv := &service.Service{Recommendation: recommendation}
+			handle := NewHandler(serv, nil)
+
+			router := gin.New()
+			router.GET("/user/:id/recommendations", handle.getRecommendations)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "/user/1/recommendations"+test.query, nil)
+
+			router.ServeHTTP(w, r)
+
+			body := strings.Trim(w.Body.String(), "\n")
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, body, test.expectedResponseBody)
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/internal/service"
+	"github.com/rinuccia/travels-api/pkg/apperrors"
+	"net/http"
+)
+
+type reviewHandler struct {
+	repo service.Review
+}
+
+func newReviewHandler(repository service.Review) *reviewHandler {
+	return &reviewHandler{
+		repo: repository,
+	}
+}
+
+// bindReviewQuery reads review order and page, it defaults to the first 20 reviews
+func bindReviewQuery(c *gin.Context) (model.ReviewQuery, bool) {
+	query := model.ReviewQuery{Page: 1, PerPage: 20}
+	if err := c.ShouldBindQuery(&query); err != nil || validate.Struct(query) != nil {
+		return query, false
+	}
+	return query, true
+}
+
+// getLocationReviews godoc
+// @Summary Returns approved reviews of location based on given ID
+// @Tags review
+// @Produce json
+// @Param id path integer true "Location ID"
+// @Param sort query string false "Sort by visit date or mark" Enums(date, mark) default(date)
+// @Param order query string false "Sort order" Enums(asc, desc) default(desc)
+// @Param page query integer false "Page number" default(1)
+// @Param per_page query integer false "Reviews per page" default(20)
+// @Success 200 {object} model.Reviews
+// @Failure 400,404 {object} errResponse
+// @Failure 500 {object} errResponse
+// @Router /location/{id}/reviews [get]
+func (h *reviewHandler) getLocationReviews(c *gin.Context) {
+	query, ok := bindReviewQuery(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, newErrResponse("invalid query parameters"))
+		return
+	}
+
+	reviews, err := h.repo.GetByLocation(c.Param("id"), query)
+	if errors.Is(err, apperrors.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, newErrResponse(err.Error()))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, newErrResponse("something went wrong"))
+		return
+	}
+
+	c.JSON(http.StatusOK, reviews)
+}
+
+// getReviewsByStatus godoc
+// @Summary Returns reviews with the given moderation status
+// @Tags review
+// @Security BearerAuth
+// @Produce json
+// @Param status query string false "Moderation status" Enums(pending, approved, rejected) default(pending)
+// @Param sort query string false "Sort by visit date or mark" Enums(date, mark) default(date)
+// @Param order query string false "Sort order" Enums(asc, desc) default(desc)
+// @Param page query integer false "Page number" default(1)
+// @Param per_page query integer false "Reviews per page" default(20)
+// @Success 200 {object} model.Reviews
+// @Failure 400,401 {object} errResponse
+// @Failure 403 {object} problemResponse
+// @Failure 500 {object} errResponse
+// @Router /reviews [get]
+func (h *reviewHandler) getReviewsByStatus(c *gin.Context) {
+	status := model.ReviewStatus{Status: model.ReviewPending}
+	query, ok := bindReviewQuery(c)
+	if err := c.ShouldBindQuery(&status); err != nil || validate.Struct(status) != nil || !ok {
+		c.JSON(http.StatusBadRequest, newErrResponse("invalid query parameters"))
+		return
+	}
+
+	reviews, err := h.repo.GetByStatus(status.Status, query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, newErrResponse("something went wrong"))
+		return
+	}
+
+	c.JSON(http.StatusOK, reviews)
+}
+
+// moderateReview godoc
+// @Summary Sets moderation status of the review of visit based on given ID
+// @Tags review
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path integer true "Visit ID"
+// @Param input body model.ReviewStatus true "Moderation decision"
+// @Success 204
+// @Failure 400,401,404 {object} errResponse
+// @Failure 403 {object} problemResponse
+// @Failure 500 {object} errResponse
+// @Router /review/{id}/status [put]
+func (h *reviewHandler) moderateReview(c *gin.Context) {
+	status := model.ReviewStatus{}
+	err := c.BindJSON(&status)
+	validationErr := validate.Struct(status)
+	if err != nil || validationErr != nil {
+		c.JSON(http.StatusBadRequest, newErrResponse("invalid input body"))
+		return
+	}
+
+	err = h.repo.Moderate(c.Param("id"), status.Status)
+	if errors.Is(err, apperrors.ErrIncorrectQuery) {
+		c.JSON(http.StatusBadRequest, newErrResponse(err.Error()))
+		return
+	}
+	if errors.Is(err, apperrors.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, newErrResponse(err.Error()))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, newErrResponse("something went wrong"))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,210 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/internal/service"
+	mock_service "github.com/rinuccia/travels-api/internal/service/mocks"
+	"github.com/rinuccia/travels-api/pkg/apperrors"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestReviewHandler_getLocationReviews(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockReview)
+
+	testTable := []struct {
+		name                 string
+		query                string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:  "Ok",
+			query: "?sort=mark&page=2&per_page=1",
+			mockBehavior: func(s *mock_service.MockReview) {
+				s.EXPECT().GetByLocation("1", model.ReviewQuery{Sort: "mark", Page: 2, PerPage: 1}).Return(model.Reviews{
+					List: []model.ReviewEntry{
+						{
+							VisitId:    7,
+							LocationId: 1,
+							Author:     "John",
+							VisitedAt:  "2019-06-15",
+							Mark:       5,
+							Review:     model.Review{Title: "Breathtaking", Body: "Take the early bus", Language: "en", Status: "approved"},
+						},
+					},
+					Total:   3,
+					Page:    2,
+					PerPage: 1,
+				}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{"list":[{"visit_id":7,"location_id":1,"author":"John","visited_at":"2019-06-15","mark":5,` +
+				`"title":"Breathtaking","body":"Take the early bus","language":"en","status":"approved"}],` +
+				`"total":3,"page":2,"per_page":1}`,
+		},
+		{
+			name:                 "Invalid Sort",
+			query:                "?sort=author",
+			mockBehavior:         func(s *mock_service.MockReview) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"invalid query parameters"}`,
+		},
+		{
+			name: "Not Found",
+			mockBehavior: func(s *mock_service.MockReview) {
+				s.EXPECT().GetByLocation("1", model.ReviewQuery{Page: 1, PerPage: 20}).
+					Return(model.Reviews{}, apperrors.ErrRecordNotFound)
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"error":"record not found"}`,
+		},
+	}
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			review := mock_service.NewMockReview(controller)
+			test.mockBehavior(review)
+
+			serv := &service.Service{Review: review}
+			handle := NewHandler(serv, nil)
+
+			router := gin.New()
+			router.GET("/location/:id/reviews", handle.getLocationReviews)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "/location/1/reviews"+test.query, nil)
+
+			router.ServeHTTP(w, r)
+
+			body := strings.Trim(w.Body.String(), "\n")
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, body, test.expectedResponseBody)
+		})
+	}
+}
+
+func TestReviewHandler_getReviewsByStatus(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockReview)
+
+	testTable := []struct {
+		name                 string
+		query                string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "Pending By Default",
+			mockBehavior: func(s *mock_service.MockReview) {
+				s.EXPECT().GetByStatus("pending", model.ReviewQuery{Page: 1, PerPage: 20}).
+					Return(model.Reviews{Page: 1, PerPage: 20}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"list":null,"total":0,"page":1,"per_page":20}`,
+		},
+		{
+			name:                 "Invalid Status",
+			query:                "?status=hidden",
+			mockBehavior:         func(s *mock_service.MockReview) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"invalid query parameters"}`,
+		},
+	}
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			review := mock_service.NewMockReview(controller)
+			test.mockBehavior(review)
+
+			serv := &service.Service{Review: review}
+			handle := NewHandler(serv, nil)
+
+			router := gin.New()
+			router.GET("/reviews", handle.getReviewsByStatus)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "/reviews"+test.query, nil)
+
+			router.ServeHTTP(w, r)
+
+			body := strings.Trim(w.Body.String(), "\n")
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, body, test.expectedResponseBody)
+		})
+	}
+}
+
+func TestReviewHandler_moderateReview(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockReview)
+
+	testTable := []struct {
+		name                 string
+		inputBody            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "Ok",
+			inputBody: `{"status":"approved"}`,
+			mockBehavior: func(s *mock_service.MockReview) {
+				s.EXPECT().Moderate("7", "approved").Return(nil)
+			},
+			expectedStatusCode: http.StatusNoContent,
+		},
+		{
+			name:                 "Invalid Status",
+			inputBody:            `{"status":"hidden"}`,
+			mockBehavior:         func(s *mock_service.MockReview) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"invalid input body"}`,
+		},
+		{
+			name:      "Not Found",
+			inputBody: `{"status":"rejected"}`,
+			mockBehavior: func(s *mock_service.MockReview) {
+				s.EXPECT().Moderate("7", "rejected").Return(apperrors.ErrRecordNotFound)
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"error":"record not found"}`,
+		},
+	}
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			review := mock_service.NewMockReview(controller)
+			test.mockBehavior(review)
+
+			serv := &service.Service{Review: review}
+			handle := NewHandler(serv, nil)
+
+			router := gin.New()
+			router.PUT("/review/:id/status", handle.moderateReview)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("PUT", "/review/7/status", strings.NewReader(test.inputBody))
+
+			router.ServeHTTP(w, r)
+
+			body := strings.Trim(w.Body.String(), "\n")
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, body, test.expectedResponseBody)
+		})
+	}
+}
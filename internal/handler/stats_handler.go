@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/internal/service"
+	"net/http"
+)
+
+type statsHandler struct {
+	repo service.Stats
+}
+
+func newStatsHandler(repository service.Stats) *statsHandler {
+	return &statsHandler{
+		repo: repository,
+	}
+}
+
+// getCountryStats godoc
+// @Summary Returns location and visit statistics of every country with locations
+// @Tags stats
+// @Produce json
+// @Param from_date query string false "Only visits on or after this date (2006-01-02)"
+// @Param to_date query string false "Only visits on or before this date (2006-01-02)"
+// @Param gender query string false "Only visits of travellers of this gender" Enums(m, f)
+// @Param sort query string false "Sort metric" Enums(country, locations, visits, visitors, avg_mark) default(visits)
+// @Param order query string false "Sort order" Enums(asc, desc) default(desc)
+// @Success 200 {object} model.CountriesStats
+// @Failure 400 {object} errResponse
+// @Failure 500 {object} errResponse
+// @Router /stats/countries [get]
+func (h *statsHandler) getCountryStats(c *gin.Context) {
+	filter := model.VisitFilter{}
+	sort := model.StatsSort{}
+	errFilter := c.ShouldBindQuery(&filter)
+	errSort := c.ShouldBindQuery(&sort)
+	if errFilter != nil || errSort != nil || validate.Struct(filter) != nil || validate.Struct(sort) != nil {
+		c.JSON(http.StatusBadRequest, newErrResponse("invalid query parameters"))
+		return
+	}
+
+	stats, err := h.repo.GetCountryStats(filter, sort)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, newErrResponse("something went wrong"))
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/internal/service"
+	mock_service "github.com/rinuccia/travels-api/internal/service/mocks"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestStatsHandler_getCountryStats(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockStats)
+
+	testTable := []struct {
+		name                 string
+		query                string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:  "Ok",
+			query: "?gender=m&sort=visitors&order=asc",
+			mockBehavior: func(s *mock_service.MockStats) {
+				s.EXPECT().GetCountryStats(model.VisitFilter{Gender: "m"}, model.StatsSort{By: "visitors", Order: "asc"}).
+					Return(model.CountriesStats{
+						List: []model.CountryStats{
+							{CountryCode: "FR", Country: "France", Locations: 2, Visits: 10, Visitors: 4, AvgMark: 4.2},
+						},
+					}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"list":[{"country_code":"FR","country":"France","locations":2,"visits":10,"visitors":4,"avg_mark":4.2}]}`,
+		},
+		{
+			name:                 "Unknown Sort",
+			query:                "?sort=population",
+			mockBehavior:         func(s *mock_service.MockStats) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"invalid query parameters"}`,
+		},
+		{
+			name:                 "Invalid Date",
+			query:                "?from_date=2019-13-01",
+			mockBehavior:         func(s *mock_service.MockStats) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"invalid query parameters"}`,
+		},
+		{
+			name: "Service Error",
+			mockBehavior: func(s *mock_service.MockStats) {
+				s.EXPECT().GetCountryStats(model.VisitFilter{}, model.StatsSort{}).
+					Return(model.CountriesStats{}, errors.New("something went wrong"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"error":"something went wrong"}`,
+		},
+	}
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			stats := mock_service.NewMockStats(controller)
+			test.mockBehavior(stats)
+
+			serv := &service.Service{Stats: stats}
+			handle := NewHandler(serv, nil)
+
+			router := gin.New()
+			router.GET("/stats/countries", handle.getCountryStats)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "/stats/countries"+test.query, nil)
+
+			router.ServeHTTP(w, r)
+
+			body := strings.Trim(w.Body.String(), "\n")
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, body, test.expectedResponseBody)
+		})
+	}
+}
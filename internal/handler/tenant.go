@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/internal/service"
+	"github.com/rinuccia/travels-api/pkg/apperrors"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"strings"
+)
+
+// TenantRoutes returns the routes of a tenant, wired to its own connection pool.
+// It returns ErrUnknownTenant for tenants that don't exist and ErrTenantsBusy when no pool can be opened for the tenant.
+type TenantRoutes func(tenantId uint32) (http.Handler, error)
+
+// InitTenantRoutes passes every request to the routes of its tenant, resolved from the access token or API key,
+// the X-Tenant-ID header or else the default tenant. The client IP limit of limits is taken before the tenant is
+// resolved, so looking up API keys is throttled and naming other tenants doesn't earn a client more requests.
+func InitTenantRoutes(router *gin.Engine, tenancy service.Tenancy, routes TenantRoutes, limits RateLimits) {
+	router.Any("/*path", ipRateLimit(limits.Store, "requests", limits.PerIP), func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		request := model.TenantRequest{Header: c.GetHeader("X-Tenant-ID")}
+		if bearer := strings.TrimPrefix(header, "Bearer "); bearer != header {
+			request.AccessToken = bearer
+		}
+		if key := strings.TrimPrefix(header, "ApiKey "); key != header {
+			request.APIKey = key
+		}
+
+		tenantId, err := tenancy.Resolve(request)
+		if err == nil {
+			var tenantRoutes http.Handler
+			if tenantRoutes, err = routes(tenantId); err == nil {
+				tenantRoutes.ServeHTTP(c.Writer, c.Request)
+				return
+			}
+		}
+		switch {
+		case errors.Is(err, apperrors.ErrUnknownTenant):
+			c.AbortWithStatusJSON(http.StatusBadRequest, newErrResponse(err.Error()))
+		case errors.Is(err, apperrors.ErrForbidden):
+			abortWithProblem(c, http.StatusForbidden, "the credentials belong to another tenant")
+		case errors.Is(err, apperrors.ErrTenantsBusy):
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, newErrResponse(err.Error()))
+		default:
+			logrus.Errorf("failed to resolve tenant: %s", err.Error())
+			c.AbortWithStatusJSON(http.StatusInternalServerError, newErrResponse("something went wrong"))
+		}
+	})
+}
@@ -0,0 +1,171 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/rinuccia/travels-api/internal/model"
+	mock_service "github.com/rinuccia/travels-api/internal/service/mocks"
+	"github.com/rinuccia/travels-api/pkg/apperrors"
+	"github.com/rinuccia/travels-api/pkg/ratelimit"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestInitTenantRoutes(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockTenancy)
+
+	testTable := []struct {
+		name                 string
+		headers              map[string]string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedContentType  string
+		expectedResponseBody string
+	}{
+		{
+			name:    "Header",
+			headers: map[string]string{"X-Tenant-ID": "2"},
+			mockBehavior: func(s *mock_service.MockTenancy) {
+				s.EXPECT().Resolve(model.TenantRequest{Header: "2"}).Return(uint32(2), nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedContentType:  "text/plain; charset=utf-8",
+			expectedResponseBody: "tenant 2: /location/1",
+		},
+		{
+			name:    "Bearer Token",
+			headers: map[string]string{"Authorization": "Bearer abc"},
+			mockBehavior: func(s *mock_service.MockTenancy) {
+				s.EXPECT().Resolve(model.TenantRequest{AccessToken: "abc"}).Return(uint32(3), nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedContentType:  "text/plain; charset=utf-8",
+			expectedResponseBody: "tenant 3: /location/1",
+		},
+		{
+			name:    "API Key",
+			headers: map[string]string{"Authorization": "ApiKey xyz", "X-Tenant-ID": "3"},
+			mockBehavior: func(s *mock_service.MockTenancy) {
+				s.EXPECT().Resolve(model.TenantRequest{Header: "3", APIKey: "xyz"}).Return(uint32(3), nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedContentType:  "text/plain; charset=utf-8",
+			expectedResponseBody: "tenant 3: /location/1",
+		},
+		{
+			name:    "Malformed Header",
+			headers: map[string]string{"X-Tenant-ID": "abc"},
+			mockBehavior: func(s *mock_service.MockTenancy) {
+				s.EXPECT().Resolve(model.TenantRequest{Header: "abc"}).Return(uint32(0), apperrors.ErrUnknownTenant)
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedContentType:  "application/json; charset=utf-8",
+			expectedResponseBody: `{"error":"unknown tenant"}`,
+		},
+		{
+			name:    "Missing Tenant",
+			headers: map[string]string{"X-Tenant-ID": "9"},
+			mockBehavior: func(s *mock_service.MockTenancy) {
+				s.EXPECT().Resolve(model.TenantRequest{Header: "9"}).Return(uint32(9), nil)
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedContentType:  "application/json; charset=utf-8",
+			expectedResponseBody: `{"error":"unknown tenant"}`,
+		},
+		{
+			name:    "Tenants Busy",
+			headers: map[string]string{"X-Tenant-ID": "8"},
+			mockBehavior: func(s *mock_service.MockTenancy) {
+				s.EXPECT().Resolve(model.TenantRequest{Header: "8"}).Return(uint32(8), nil)
+			},
+			expectedStatusCode:   http.StatusServiceUnavailable,
+			expectedContentType:  "application/json; charset=utf-8",
+			expectedResponseBody: `{"error":"too many tenants in use, try again later"}`,
+		},
+		{
+			name:    "Other Tenant",
+			headers: map[string]string{"Authorization": "Bearer abc", "X-Tenant-ID": "2"},
+			mockBehavior: func(s *mock_service.MockTenancy) {
+				s.EXPECT().Resolve(model.TenantRequest{Header: "2", AccessToken: "abc"}).
+					Return(uint32(0), apperrors.ErrForbidden)
+			},
+			expectedStatusCode:  http.StatusForbidden,
+			expectedContentType: "application/problem+json",
+			expectedResponseBody: `{"type":"about:blank","title":"Forbidden","status":403,` +
+				`"detail":"the credentials belong to another tenant"}`,
+		},
+		{
+			name:    "Service Failure",
+			headers: map[string]string{"Authorization": "ApiKey xyz"},
+			mockBehavior: func(s *mock_service.MockTenancy) {
+				s.EXPECT().Resolve(model.TenantRequest{APIKey: "xyz"}).Return(uint32(0), errors.New("db down"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedContentType:  "application/json; charset=utf-8",
+			expectedResponseBody: `{"error":"something went wrong"}`,
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			tenancy := mock_service.NewMockTenancy(c)
+			tt.mockBehavior(tenancy)
+
+			router := gin.New()
+			InitTenantRoutes(router, tenancy, func(tenantId uint32) (http.Handler, error) {
+				switch tenantId {
+				case 8:
+					return nil, apperrors.ErrTenantsBusy
+				case 9:
+					return nil, apperrors.ErrUnknownTenant
+				}
+				return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+					w.Write([]byte("tenant " + strconv.Itoa(int(tenantId)) + ": " + r.URL.Path))
+				}), nil
+			}, RateLimits{})
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/location/1", nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedContentType, w.Header().Get("Content-Type"))
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestInitTenantRoutes_ipRateLimit(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	tenancy := mock_service.NewMockTenancy(c)
+	tenancy.EXPECT().Resolve(gomock.Any()).Return(uint32(2), nil).Times(2)
+
+	router := gin.New()
+	InitTenantRoutes(router, tenancy, func(tenantId uint32) (http.Handler, error) {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}), nil
+	}, RateLimits{Store: ratelimit.NewMemory(), PerIP: ratelimit.Limit{Rate: 1.0 / 60, Burst: 2}})
+
+	// every tenant named draws on the same bucket, the third request never reaches the API key lookup
+	expected := []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests}
+	for i, code := range expected {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/location/1", nil)
+		req.Header.Set("Authorization", "ApiKey tk_guess")
+		req.Header.Set("X-Tenant-ID", strconv.Itoa(i+2))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, code, w.Code)
+	}
+}
@@ -0,0 +1,173 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/internal/service"
+	"github.com/rinuccia/travels-api/pkg/apperrors"
+	"net/http"
+)
+
+// tripQuery represent GET /user/:id/trips query parameters
+type tripQuery struct {
+	GapDays int `form:"gap_days" validate:"min=0,max=365"`
+}
+
+type tripHandler struct {
+	repo service.Trip
+}
+
+func newTripHandler(repository service.Trip) *tripHandler {
+	return &tripHandler{
+		repo: repository,
+	}
+}
+
+// getAllTrips godoc
+// @Summary Returns user trips with their visits and statistics
+// @Description Visits outside manually created trips are grouped automatically,
+// @Description a new trip starts when the country changes or more than gap_days days pass between visits.
+// @Tags trip
+// @Security BearerAuth
+// @Produce json
+// @Param id path integer true "User ID"
+// @Param gap_days query integer false "Longest gap in days inside an automatic trip" default(3)
+// @Success 200 {object} model.Trips
+// @Failure 400,401,404 {object} errResponse
+// @Failure 403 {object} problemResponse
+// @Failure 500 {object} errResponse
+// @Router /user/{id}/trips [get]
+func (h *tripHandler) getAllTrips(c *gin.Context) {
+	id := c.Param("id")
+	query := tripQuery{GapDays: 3}
+	err := c.ShouldBindQuery(&query)
+	validationErr := validate.Struct(query)
+	if err != nil || validationErr != nil {
+		c.JSON(http.StatusBadRequest, newErrResponse("invalid query parameters"))
+		return
+	}
+
+	trips, err := h.repo.GetAll(id, query.GapDays)
+	if errors.Is(err, apperrors.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, newErrResponse(err.Error()))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, newErrResponse("something went wrong"))
+		return
+	}
+
+	c.JSON(http.StatusOK, trips)
+}
+
+// createTrip godoc
+// @Summary Create trip from visits of its user
+// @Tags trip
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param input body model.TripRecord true "Trip Info"
+// @Success 200 {object} model.TripRecord
+// @Failure 400,401 {object} errResponse
+// @Failure 403 {object} problemResponse
+// @Router /trip/new [post]
+func (h *tripHandler) createTrip(c *gin.Context) {
+	trip := model.TripRecord{}
+	err := c.BindJSON(&trip)
+	validationErr := validate.Struct(trip)
+	if err != nil || validationErr != nil {
+		c.JSON(http.StatusBadRequest, newErrResponse("invalid input body"))
+		return
+	}
+
+	trip, err = h.repo.Create(trip)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, newErrResponse(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, trip)
+}
+
+// updateTrip godoc
+// @Summary Rename trip based on given ID, visit_ids replaces its visits when given
+// @Tags trip
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path integer true "Trip ID"
+// @Param input body model.TripUpdate true "Trip Info"
+// @Success 204
+// @Failure 400,401,404 {object} errResponse
+// @Failure 403 {object} problemResponse
+// @Failure 500 {object} errResponse
+// @Router /trip/{id} [put]
+func (h *tripHandler) updateTrip(c *gin.Context) {
+	id := c.Param("id")
+	trip := model.TripUpdate{}
+	err := c.BindJSON(&trip)
+	validationErr := validate.Struct(trip)
+	if err != nil || validationErr != nil {
+		c.JSON(http.StatusBadRequest, newErrResponse("invalid input body"))
+		return
+	}
+
+	err = h.repo.Update(id, trip)
+	if errors.Is(err, apperrors.ErrIncorrectQuery) {
+		c.JSON(http.StatusBadRequest, newErrResponse(err.Error()))
+		return
+	}
+	if errors.Is(err, apperrors.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, newErrResponse(err.Error()))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, newErrResponse("something went wrong"))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// deleteTripById godoc
+// @Summary Removes trip based on given ID, its visits are grouped automatically again until it is restored
+// @Tags trip
+// @Security BearerAuth
+// @Produce json
+// @Param id path integer true "Trip ID"
+// @Success 204
+// @Failure 401,404 {object} errResponse
+// @Failure 403 {object} problemResponse
+// @Failure 500 {object} errResponse
+// @Router /trip/{id} [delete]
+func (h *tripHandler) deleteTripById(c *gin.Context) {
+	id := c.Param("id")
+	err := h.repo.DeleteById(id)
+	if errors.Is(err, apperrors.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, newErrResponse(err.Error()))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, newErrResponse("something went wrong"))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// restoreTrip godoc
+// @Summary Restores deleted trip based on given ID with its visits
+// @Tags trip
+// @Security BearerAuth
+// @Produce json
+// @Param id path integer true "Trip ID"
+// @Success 204
+// @Failure 400,401,404 {object} errResponse
+// @Failure 403 {object} problemResponse
+// @Failure 500 {object} errResponse
+// @Router /trip/{id}/restore [post]
+func (h *tripHandler) restoreTrip(c *gin.Context) {
+	err := h.repo.Restore(c.Param("id"))
+	respondRestore(c, err)
+}
@@ -0,0 +1,332 @@
+package handler
+
+import (
+	"bytes"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/internal/service"
+	mock_service "github.com/rinuccia/travels-api/internal/service/mocks"
+	"github.com/rinuccia/travels-api/pkg/apperrors"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestTripHandler_getAllTrips(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockTrip)
+
+	testTable := []struct {
+		name                 string
+		query                string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:  "Ok",
+			query: "?gap_days=5",
+			mockBehavior: func(s *mock_service.MockTrip) {
+				s.EXPECT().GetAll("1", 5).Return(model.Trips{
+					List: []model.Trip{
+						{
+							Name:      "Peru, June 2019",
+							Automatic: true,
+							StartDate: "2019-06-01",
+							EndDate:   "2019-06-01",
+							Stats:     model.TripStats{Visits: 1, Locations: 1, Countries: 1, Days: 1, AvgMark: 5},
+							Visits: []model.TripVisit{
+								{VisitId: 1, LocationId: 1, Place: "Machu Picchu", Country: "Peru", CountryCode: "PE",
+									VisitedAt: "2019-06-01", Mark: 5},
+							},
+						},
+					},
+				}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{"list":[{"name":"Peru, June 2019","automatic":true,"start_date":"2019-06-01",` +
+				`"end_date":"2019-06-01","stats":{"visits":1,"locations":1,"countries":1,"days":1,"avg_mark":5},` +
+				`"visits":[{"visit_id":1,"location_id":1,"place":"Machu Picchu","country":"Peru","country_code":"PE",` +
+				`"visited_at":"2019-06-01","mark":5}]}]}`,
+		},
+		{
+			name:                 "Invalid Gap",
+			query:                "?gap_days=-1",
+			mockBehavior:         func(s *mock_service.MockTrip) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"invalid query parameters"}`,
+		},
+		{
+			name: "Not Found",
+			mockBehavior: func(s *mock_service.MockTrip) {
+				s.EXPECT().GetAll("1", 3).Return(model.Trips{}, apperrors.ErrRecordNotFound)
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"error":"record not found"}`,
+		},
+	}
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			trip := mock_service.NewMockTrip(controller)
+			test.mockBehavior(trip)
+
+			serv := &service.Service{Trip: trip}
+			handle := NewHandler(serv, nil)
+
+			router := gin.New()
+			router.GET("/user/:id/trips", handle.getAllTrips)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "/user/1/trips"+test.query, nil)
+
+			router.ServeHTTP(w, r)
+
+			body := strings.Trim(w.Body.String(), "\n")
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, body, test.expectedResponseBody)
+		})
+	}
+}
+
+func TestTripHandler_createTrip(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockTrip, trip model.TripRecord)
+
+	testTable := []struct {
+		name                 string
+		inputBody            string
+		inputTrip            model.TripRecord
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "Ok",
+			inputBody: `{"trip_id":1,"user_id":1,"name":"Andes","visit_ids":[1,2]}`,
+			inputTrip: model.TripRecord{TripId: 1, UserId: 1, Name: "Andes", VisitIds: []uint32{1, 2}},
+			mockBehavior: func(s *mock_service.MockTrip, trip model.TripRecord) {
+				s.EXPECT().Create(trip).Return(trip, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"trip_id":1,"user_id":1,"name":"Andes","visit_ids":[1,2]}`,
+		},
+		{
+			name:      "Foreign Visit",
+			inputBody: `{"trip_id":1,"user_id":1,"name":"Andes","visit_ids":[7]}`,
+			inputTrip: model.TripRecord{TripId: 1, UserId: 1, Name: "Andes", VisitIds: []uint32{7}},
+			mockBehavior: func(s *mock_service.MockTrip, trip model.TripRecord) {
+				s.EXPECT().Create(trip).Return(model.TripRecord{}, apperrors.ErrIncorrectQuery)
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"incorrect query"}`,
+		},
+		{
+			name:                 "Missing Name",
+			inputBody:            `{"trip_id":1,"user_id":1}`,
+			mockBehavior:         func(s *mock_service.MockTrip, trip model.TripRecord) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"invalid input body"}`,
+		},
+	}
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			trip := mock_service.NewMockTrip(controller)
+			test.mockBehavior(trip, test.inputTrip)
+
+			serv := &service.Service{Trip: trip}
+			handle := NewHandler(serv, nil)
+
+			router := gin.New()
+			router.POST("/trip/new", handle.createTrip)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("POST", "/trip/new", bytes.NewBufferString(test.inputBody))
+
+			router.ServeHTTP(w, r)
+
+			body := strings.Trim(w.Body.String(), "\n")
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, body, test.expectedResponseBody)
+		})
+	}
+}
+
+func TestTripHandler_updateTrip(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockTrip, trip model.TripUpdate)
+
+	testTable := []struct {
+		name                 string
+		inputBody            string
+		inputTrip            model.TripUpdate
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "Rename",
+			inputBody: `{"name":"Andes"}`,
+			inputTrip: model.TripUpdate{Name: "Andes"},
+			mockBehavior: func(s *mock_service.MockTrip, trip model.TripUpdate) {
+				s.EXPECT().Update("1", trip).Return(nil)
+			},
+			expectedStatusCode: http.StatusNoContent,
+		},
+		{
+			name:      "Replace Visits",
+			inputBody: `{"name":"Andes","visit_ids":[3,4]}`,
+			inputTrip: model.TripUpdate{Name: "Andes", VisitIds: []uint32{3, 4}},
+			mockBehavior: func(s *mock_service.MockTrip, trip model.TripUpdate) {
+				s.EXPECT().Update("1", trip).Return(nil)
+			},
+			expectedStatusCode: http.StatusNoContent,
+		},
+		{
+			name:      "Not Found",
+			inputBody: `{"name":"Andes"}`,
+			inputTrip: model.TripUpdate{Name: "Andes"},
+			mockBehavior: func(s *mock_service.MockTrip, trip model.TripUpdate) {
+				s.EXPECT().Update("1", trip).Return(apperrors.ErrRecordNotFound)
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"error":"record not found"}`,
+		},
+	}
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			trip := mock_service.NewMockTrip(controller)
+			test.mockBehavior(trip, test.inputTrip)
+
+			serv := &service.Service{Trip: trip}
+			handle := NewHandler(serv, nil)
+
+			router := gin.New()
+			router.PUT("/trip/:id", handle.updateTrip)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("PUT", "/trip/1", bytes.NewBufferString(test.inputBody))
+
+			router.ServeHTTP(w, r)
+
+			body := strings.Trim(w.Body.String(), "\n")
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, body, test.expectedResponseBody)
+		})
+	}
+}
+
+func TestTripHandler_deleteTripById(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockTrip)
+
+	testTable := []struct {
+		name                 string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "Ok",
+			mockBehavior: func(s *mock_service.MockTrip) {
+				s.EXPECT().DeleteById("1").Return(nil)
+			},
+			expectedStatusCode: http.StatusNoContent,
+		},
+		{
+			name: "Not Found",
+			mockBehavior: func(s *mock_service.MockTrip) {
+				s.EXPECT().DeleteById("1").Return(apperrors.ErrRecordNotFound)
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"error":"record not found"}`,
+		},
+	}
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			trip := mock_service.NewMockTrip(controller)
+			test.mockBehavior(trip)
+
+			serv := &service.Service{Trip: trip}
+			handle := NewHandler(serv, nil)
+
+			router := gin.New()
+			router.DELETE("/trip/:id", handle.deleteTripById)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("DELETE", "/trip/1", nil)
+
+			router.ServeHTTP(w, r)
+
+			body := strings.Trim(w.Body.String(), "\n")
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, body, test.expectedResponseBody)
+		})
+	}
+}
+
+func TestTripHandler_restoreTrip(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockTrip)
+
+	testTable := []struct {
+		name                 string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "Ok",
+			mockBehavior: func(s *mock_service.MockTrip) {
+				s.EXPECT().Restore("1").Return(nil)
+			},
+			expectedStatusCode: http.StatusNoContent,
+		},
+		{
+			name: "Not Deleted",
+			mockBehavior: func(s *mock_service.MockTrip) {
+				s.EXPECT().Restore("1").Return(apperrors.ErrRecordNotFound)
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"error":"record not found"}`,
+		},
+	}
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			trip := mock_service.NewMockTrip(controller)
+			test.mockBehavior(trip)
+
+			serv := &service.Service{Trip: trip}
+			handle := NewHandler(serv, nil)
+
+			router := gin.New()
+			router.POST("/trip/:id/restore", handle.restoreTrip)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("POST", "/trip/1/restore", nil)
+
+			router.ServeHTTP(w, r)
+
+			body := strings.Trim(w.Body.String(), "\n")
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, body, test.expectedResponseBody)
+		})
+	}
+}
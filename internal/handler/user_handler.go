@@ -39,8 +39,30 @@ func (h *userHandler) getUserById(c *gin.Context) {
 	c.JSON(http.StatusOK, user)
 }
 
+// getUserSummary godoc
+// @Summary Returns travel profile summary of user based on given ID
+// @Tags user
+// @Security BearerAuth
+// @Produce json
+// @Param id path integer true "User ID"
+// @Success 200 {object} model.UserSummary
+// @Failure 401,404 {object} errResponse
+// @Failure 403 {object} problemResponse
+// @Router /user/{id}/summary [get]
+func (h *userHandler) getUserSummary(c *gin.Context) {
+	id := c.Param("id")
+
+	summary, err := h.repo.GetSummary(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, newErrResponse(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
 // createUser godoc
-// @Summary Create user
+// @Summary Create user and mail a verification for the email
 // @Tags user
 // @Accept json
 // @Produce json
@@ -57,8 +79,8 @@ func (h *userHandler) createUser(c *gin.Context) {
 		return
 	}
 
-	user, err = h.repo.Create(user)
-	if err != nil {
+	user, err = h.repo.Create(auditActor(c), user)
+	if err = ignoreMailNotSent(err); err != nil {
 		c.JSON(http.StatusBadRequest, newErrResponse(err.Error()))
 		return
 	}
@@ -67,14 +89,16 @@ func (h *userHandler) createUser(c *gin.Context) {
 }
 
 // updateUser godoc
-// @Summary Update user based on given ID
+// @Summary Update user based on given ID, a new email takes effect once confirmed through the mailed verification
 // @Tags user
+// @Security BearerAuth
 // @Accept json
 // @Produce json
 // @Param id path integer true "User ID"
 // @Param input body model.User true "User Info"
 // @Success 204
-// @Failure 400,404 {object} errResponse
+// @Failure 400,401,404 {object} errResponse
+// @Failure 403 {object} problemResponse
 // @Router /user/{id} [put]
 func (h *userHandler) updateUser(c *gin.Context) {
 	id := c.Param("id")
@@ -86,7 +110,7 @@ func (h *userHandler) updateUser(c *gin.Context) {
 		return
 	}
 
-	err = h.repo.Update(id, user)
+	err = ignoreMailNotSent(h.repo.Update(auditActor(c), id, user))
 	if errors.Is(err, apperrors.ErrIncorrectQuery) {
 		c.JSON(http.StatusBadRequest, newErrResponse(err.Error()))
 		return
@@ -98,3 +122,41 @@ func (h *userHandler) updateUser(c *gin.Context) {
 
 	c.Status(http.StatusNoContent)
 }
+
+// updateUserRole godoc
+// @Summary Changes role of user based on given ID, admins only
+// @Tags user
+// @Security BearerAuth
+// @Accept json
+// @Param id path integer true "User ID"
+// @Param input body model.RoleUpdate true "New role"
+// @Success 204
+// @Failure 400,401,404 {object} errResponse
+// @Failure 403 {object} problemResponse
+// @Failure 500 {object} errResponse
+// @Router /user/{id}/role [put]
+func (h *userHandler) updateUserRole(c *gin.Context) {
+	update := model.RoleUpdate{}
+	err := c.BindJSON(&update)
+	validationErr := validate.Struct(update)
+	if err != nil || validationErr != nil {
+		c.JSON(http.StatusBadRequest, newErrResponse("invalid input body"))
+		return
+	}
+
+	err = h.repo.SetRole(auditActor(c), c.Param("id"), update.Role)
+	if errors.Is(err, apperrors.ErrIncorrectQuery) {
+		c.JSON(http.StatusBadRequest, newErrResponse(err.Error()))
+		return
+	}
+	if errors.Is(err, apperrors.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, newErrResponse(err.Error()))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, newErrResponse("something went wrong"))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
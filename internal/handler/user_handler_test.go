@@ -58,7 +58,7 @@ func TestUserHandler_getUserById(t *testing.T) {
 			test.mockBehavior(user, test.id)
 
 			serv := &service.Service{User: user}
-			handle := NewHandler(serv)
+			handle := NewHandler(serv, nil)
 
 			router := gin.New()
 			router.GET("/user/:id", handle.getUserById)
@@ -76,6 +76,82 @@ func TestUserHandler_getUserById(t *testing.T) {
 	}
 }
 
+func TestUserHandler_getUserSummary(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockUser, id string)
+
+	testTable := []struct {
+		name                 string
+		id                   string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "Ok",
+			id:   "1",
+			mockBehavior: func(s *mock_service.MockUser, id string) {
+				s.EXPECT().GetSummary(id).Return(model.UserSummary{
+					UserId:             1,
+					Visits:             5,
+					Locations:          3,
+					Countries:          2,
+					FirstVisit:         "2015-06-23",
+					LastVisit:          "2019-04-30",
+					AvgMark:            4.2,
+					FavouriteCountry:   "Peru",
+					MostRevisitedPlace: "Machu Picchu",
+				}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{"user_id":1,"visits":5,"locations":3,"countries":2,"first_visit":"2015-06-23",` +
+				`"last_visit":"2019-04-30","avg_mark":4.2,"favourite_country":"Peru","most_revisited_place":"Machu Picchu"}`,
+		},
+		{
+			name: "No Visits",
+			id:   "1",
+			mockBehavior: func(s *mock_service.MockUser, id string) {
+				s.EXPECT().GetSummary(id).Return(model.UserSummary{UserId: 1}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"user_id":1,"visits":0,"locations":0,"countries":0,"avg_mark":0}`,
+		},
+		{
+			name: "Not Found",
+			id:   "1",
+			mockBehavior: func(s *mock_service.MockUser, id string) {
+				s.EXPECT().GetSummary(id).Return(model.UserSummary{}, apperrors.ErrRecordNotFound)
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"error":"record not found"}`,
+		},
+	}
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			user := mock_service.NewMockUser(controller)
+			test.mockBehavior(user, test.id)
+
+			serv := &service.Service{User: user}
+			handle := NewHandler(serv, nil)
+
+			router := gin.New()
+			router.GET("/user/:id/summary", handle.getUserSummary)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "/user/1/summary", nil)
+
+			router.ServeHTTP(w, r)
+
+			body := strings.Trim(w.Body.String(), "\n")
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, body, test.expectedResponseBody)
+		})
+	}
+}
+
 func TestUserHandler_createUser(t *testing.T) {
 	type mockBehavior func(s *mock_service.MockUser, user model.User)
 
@@ -98,7 +174,7 @@ func TestUserHandler_createUser(t *testing.T) {
 				Gender:    "m",
 			},
 			mockBehavior: func(s *mock_service.MockUser, user model.User) {
-				s.EXPECT().Create(user).Return(user, nil)
+				s.EXPECT().Create(model.Actor{Actor: "anonymous"}, user).Return(user, nil)
 			},
 			expectedStatusCode:   http.StatusOK,
 			expectedResponseBody: `{"user_id":1,"email":"test@gmail.com","first_name":"John","last_name":"Smith","gender":"m"}`,
@@ -114,7 +190,7 @@ func TestUserHandler_createUser(t *testing.T) {
 				Gender:    "m",
 			},
 			mockBehavior: func(s *mock_service.MockUser, user model.User) {
-				s.EXPECT().Create(user).Return(model.User{}, apperrors.ErrIncorrectQuery)
+				s.EXPECT().Create(model.Actor{Actor: "anonymous"}, user).Return(model.User{}, apperrors.ErrIncorrectQuery)
 			},
 			expectedStatusCode:   http.StatusBadRequest,
 			expectedResponseBody: `{"error":"incorrect query"}`,
@@ -129,7 +205,7 @@ func TestUserHandler_createUser(t *testing.T) {
 			test.mockBehavior(user, test.inputUser)
 
 			serv := &service.Service{User: user}
-			handle := NewHandler(serv)
+			handle := NewHandler(serv, nil)
 
 			router := gin.New()
 			router.POST("/user/new", handle.createUser)
@@ -171,7 +247,7 @@ func TestUserHandler_updateUser(t *testing.T) {
 				Gender:    "m",
 			},
 			mockBehavior: func(s *mock_service.MockUser, user model.User, id string) {
-				s.EXPECT().Update(id, user).Return(nil)
+				s.EXPECT().Update(model.Actor{Actor: "anonymous"}, id, user).Return(nil)
 			},
 			expectedStatusCode: http.StatusNoContent,
 		},
@@ -187,10 +263,26 @@ func TestUserHandler_updateUser(t *testing.T) {
 				Gender:    "m",
 			},
 			mockBehavior: func(s *mock_service.MockUser, user model.User, id string) {
-				s.EXPECT().Update(id, user).Return(apperrors.ErrRecordNotFound)
+				s.EXPECT().Update(model.Actor{Actor: "anonymous"}, id, user).Return(apperrors.ErrRecordNotFound)
 			},
 			expectedStatusCode: http.StatusNotFound,
 		},
+		{
+			name:      "Verification Not Sent",
+			id:        "1",
+			inputBody: `{"user_id":1,"email":"new@gmail.com","first_name":"John","last_name":"Smith","gender":"m"}`,
+			inputUser: model.User{
+				UserId:    1,
+				Email:     "new@gmail.com",
+				FirstName: "John",
+				LastName:  "Smith",
+				Gender:    "m",
+			},
+			mockBehavior: func(s *mock_service.MockUser, user model.User, id string) {
+				s.EXPECT().Update(model.Actor{Actor: "anonymous"}, id, user).Return(apperrors.ErrMailNotSent)
+			},
+			expectedStatusCode: http.StatusNoContent,
+		},
 	}
 	for _, test := range testTable {
 		t.Run(test.name, func(t *testing.T) {
@@ -201,7 +293,7 @@ func TestUserHandler_updateUser(t *testing.T) {
 			test.mockBehavior(user, test.inputUser, test.id)
 
 			serv := &service.Service{User: user}
-			handle := NewHandler(serv)
+			handle := NewHandler(serv, nil)
 
 			router := gin.New()
 			router.PUT("/user/:id", handle.updateUser)
@@ -215,3 +307,65 @@ func TestUserHandler_updateUser(t *testing.T) {
 		})
 	}
 }
+
+func TestUserHandler_updateUserRole(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockUser)
+
+	testTable := []struct {
+		name                 string
+		inputBody            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "Ok",
+			inputBody: `{"role":"curator"}`,
+			mockBehavior: func(s *mock_service.MockUser) {
+				s.EXPECT().SetRole(model.Actor{Actor: "anonymous"}, "1", model.RoleCurator).Return(nil)
+			},
+			expectedStatusCode: http.StatusNoContent,
+		},
+		{
+			name:                 "Unknown Role",
+			inputBody:            `{"role":"owner"}`,
+			mockBehavior:         func(s *mock_service.MockUser) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"invalid input body"}`,
+		},
+		{
+			name:      "Not Found",
+			inputBody: `{"role":"admin"}`,
+			mockBehavior: func(s *mock_service.MockUser) {
+				s.EXPECT().SetRole(model.Actor{Actor: "anonymous"}, "1", model.RoleAdmin).Return(apperrors.ErrRecordNotFound)
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"error":"record not found"}`,
+		},
+	}
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			user := mock_service.NewMockUser(controller)
+			test.mockBehavior(user)
+
+			serv := &service.Service{User: user}
+			handle := NewHandler(serv, nil)
+
+			router := gin.New()
+			router.PUT("/user/:id/role", handle.updateUserRole)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("PUT", "/user/1/role", strings.NewReader(test.inputBody))
+
+			router.ServeHTTP(w, r)
+
+			body := strings.Trim(w.Body.String(), "\n")
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, body, test.expectedResponseBody)
+		})
+	}
+}
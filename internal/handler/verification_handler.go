@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/internal/service"
+	"github.com/rinuccia/travels-api/pkg/apperrors"
+	"net/http"
+)
+
+type verificationHandler struct {
+	repo service.Verification
+}
+
+func newVerificationHandler(repository service.Verification) *verificationHandler {
+	return &verificationHandler{
+		repo: repository,
+	}
+}
+
+// verifyEmail godoc
+// @Summary Confirms the email a verification token was mailed to and sets it on the user as verified
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param input body model.VerificationToken true "Token from the verification email"
+// @Success 204
+// @Failure 400 {object} errResponse
+// @Failure 500 {object} errResponse
+// @Router /auth/verify-email [post]
+func (h *verificationHandler) verifyEmail(c *gin.Context) {
+	input := model.VerificationToken{}
+	err := c.BindJSON(&input)
+	validationErr := validate.Struct(input)
+	if err != nil || validationErr != nil {
+		c.JSON(http.StatusBadRequest, newErrResponse("invalid input body"))
+		return
+	}
+
+	err = h.repo.Confirm(auditActor(c), input.Token)
+	if errors.Is(err, apperrors.ErrInvalidToken) || errors.Is(err, apperrors.ErrIncorrectQuery) {
+		c.JSON(http.StatusBadRequest, newErrResponse(err.Error()))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, newErrResponse("something went wrong"))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// resendVerification godoc
+// @Summary Mails a new verification for the email of user based on given ID
+// @Tags user
+// @Security BearerAuth
+// @Produce json
+// @Param id path integer true "User ID"
+// @Success 204
+// @Failure 400,401,404 {object} errResponse
+// @Failure 403 {object} problemResponse
+// @Failure 500 {object} errResponse
+// @Router /user/{id}/verify-email [post]
+func (h *verificationHandler) resendVerification(c *gin.Context) {
+	err := h.repo.Resend(c.Param("id"))
+	if errors.Is(err, apperrors.ErrAlreadyVerified) {
+		c.JSON(http.StatusBadRequest, newErrResponse(err.Error()))
+		return
+	}
+	if errors.Is(err, apperrors.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, newErrResponse(err.Error()))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, newErrResponse("something went wrong"))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/internal/service"
+	mock_service "github.com/rinuccia/travels-api/internal/service/mocks"
+	"github.com/rinuccia/travels-api/pkg/apperrors"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestVerificationHandler_verifyEmail(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockVerification)
+
+	testTable := []struct {
+		name                 string
+		inputBody            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "Ok",
+			inputBody: `{"token":"signed"}`,
+			mockBehavior: func(s *mock_service.MockVerification) {
+				s.EXPECT().Confirm(model.Actor{Actor: "anonymous"}, "signed").Return(nil)
+			},
+			expectedStatusCode: http.StatusNoContent,
+		},
+		{
+			name:                 "Missing Token",
+			inputBody:            `{}`,
+			mockBehavior:         func(s *mock_service.MockVerification) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"invalid input body"}`,
+		},
+		{
+			name:      "Used Token",
+			inputBody: `{"token":"signed"}`,
+			mockBehavior: func(s *mock_service.MockVerification) {
+				s.EXPECT().Confirm(model.Actor{Actor: "anonymous"}, "signed").Return(apperrors.ErrInvalidToken)
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"invalid or expired token"}`,
+		},
+		{
+			name:      "Email Taken",
+			inputBody: `{"token":"signed"}`,
+			mockBehavior: func(s *mock_service.MockVerification) {
+				s.EXPECT().Confirm(model.Actor{Actor: "anonymous"}, "signed").Return(apperrors.ErrIncorrectQuery)
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"incorrect query"}`,
+		},
+		{
+			name:      "Service Error",
+			inputBody: `{"token":"signed"}`,
+			mockBehavior: func(s *mock_service.MockVerification) {
+				s.EXPECT().Confirm(model.Actor{Actor: "anonymous"}, "signed").Return(errors.New("something went wrong"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"error":"something went wrong"}`,
+		},
+	}
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			verification := mock_service.NewMockVerification(controller)
+			test.mockBehavior(verification)
+
+			serv := &service.Service{Verification: verification}
+			handle := NewHandler(serv, nil)
+
+			router := gin.New()
+			router.POST("/auth/verify-email", handle.verifyEmail)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("POST", "/auth/verify-email", strings.NewReader(test.inputBody))
+
+			router.ServeHTTP(w, r)
+
+			body := strings.Trim(w.Body.String(), "\n")
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, body, test.expectedResponseBody)
+		})
+	}
+}
+
+func TestVerificationHandler_resendVerification(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockVerification)
+
+	testTable := []struct {
+		name                 string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "Ok",
+			mockBehavior: func(s *mock_service.MockVerification) {
+				s.EXPECT().Resend("1").Return(nil)
+			},
+			expectedStatusCode: http.StatusNoContent,
+		},
+		{
+			name: "Already Verified",
+			mockBehavior: func(s *mock_service.MockVerification) {
+				s.EXPECT().Resend("1").Return(apperrors.ErrAlreadyVerified)
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"email already verified"}`,
+		},
+		{
+			name: "Not Found",
+			mockBehavior: func(s *mock_service.MockVerification) {
+				s.EXPECT().Resend("1").Return(apperrors.ErrRecordNotFound)
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"error":"record not found"}`,
+		},
+		{
+			name: "Mail Error",
+			mockBehavior: func(s *mock_service.MockVerification) {
+				s.EXPECT().Resend("1").Return(apperrors.ErrMailNotSent)
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"error":"something went wrong"}`,
+		},
+	}
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			verification := mock_service.NewMockVerification(controller)
+			test.mockBehavior(verification)
+
+			serv := &service.Service{Verification: verification}
+			handle := NewHandler(serv, nil)
+
+			router := gin.New()
+			router.POST("/user/:id/verify-email", handle.resendVerification)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("POST", "/user/1/verify-email", nil)
+
+			router.ServeHTTP(w, r)
+
+			body := strings.Trim(w.Body.String(), "\n")
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, body, test.expectedResponseBody)
+		})
+	}
+}
@@ -22,15 +22,25 @@ func newVisitHandler(repository service.Visit) *visitHandler {
 // getAllVisits godoc
 // @Summary Returns a list of all user visits
 // @Tags visit
+// @Security BearerAuth
 // @Produce json
 // @Param id path integer true "User ID"
+// @Param category query string false "Location category name"
+// @Param tag query string false "Location tag"
 // @Success 200 {object} model.UserVisits
-// @Failure 404 {object} errResponse
+// @Failure 400,401,404 {object} errResponse
+// @Failure 403 {object} problemResponse
 // @Failure 500 {object} errResponse
 // @Router /visits/user/{id} [get]
 func (h *visitHandler) getAllVisits(c *gin.Context) {
 	id := c.Param("id")
-	visits, err := h.repo.GetAll(id)
+	filter := model.LocationFilter{}
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, newErrResponse("invalid query parameters"))
+		return
+	}
+
+	visits, err := h.repo.GetAll(id, filter)
 	if errors.Is(err, apperrors.ErrRecordNotFound) {
 		c.JSON(http.StatusNotFound, newErrResponse(err.Error()))
 		return
@@ -46,11 +56,15 @@ func (h *visitHandler) getAllVisits(c *gin.Context) {
 // createVisit godoc
 // @Summary Create Visit
 // @Tags visit
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Accept json
 // @Produce json
 // @Param input body model.Visit true "Visit Info"
 // @Success 200 {object} model.Visit
-// @Failure 400 {object} errResponse
+// @Failure 400,401 {object} errResponse
+// @Failure 403 {object} problemResponse
+// @Failure 500 {object} errResponse
 // @Router /visit/new [post]
 func (h *visitHandler) createVisit(c *gin.Context) {
 	visit := model.Visit{}
@@ -61,27 +75,35 @@ func (h *visitHandler) createVisit(c *gin.Context) {
 		return
 	}
 
-	visit, err = h.repo.Create(visit)
-	if err != nil {
+	visit, err = h.repo.Create(auditActor(c), visit)
+	if errors.Is(err, apperrors.ErrAlreadyExists) || errors.Is(err, apperrors.ErrIncorrectQuery) ||
+		errors.Is(err, apperrors.ErrUnknownCountry) {
 		c.JSON(http.StatusBadRequest, newErrResponse(err.Error()))
 		return
 	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, newErrResponse("something went wrong"))
+		return
+	}
 
 	c.JSON(http.StatusOK, visit)
 }
 
 // deleteVisitById godoc
-// @Summary Removes visit based on given ID
+// @Summary Removes visit based on given ID, it can be restored until purged
 // @Tags visit
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Produce json
 // @Param id path integer true "Visit ID"
 // @Success 204
-// @Failure 404 {object} errResponse
+// @Failure 401,404 {object} errResponse
+// @Failure 403 {object} problemResponse
 // @Failure 500 {object} errResponse
 // @Router /visit/{id} [delete]
 func (h *visitHandler) deleteVisitById(c *gin.Context) {
 	id := c.Param("id")
-	err := h.repo.DeleteById(id)
+	err := h.repo.DeleteById(auditActor(c), id)
 	if errors.Is(err, apperrors.ErrRecordNotFound) {
 		c.JSON(http.StatusNotFound, newErrResponse(err.Error()))
 		return
@@ -93,3 +115,39 @@ func (h *visitHandler) deleteVisitById(c *gin.Context) {
 
 	c.Status(http.StatusNoContent)
 }
+
+// restoreVisit godoc
+// @Summary Restores deleted visit based on given ID
+// @Tags visit
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path integer true "Visit ID"
+// @Success 204
+// @Failure 400,401,404 {object} errResponse
+// @Failure 403 {object} problemResponse
+// @Failure 500 {object} errResponse
+// @Router /visit/{id}/restore [post]
+func (h *visitHandler) restoreVisit(c *gin.Context) {
+	err := h.repo.Restore(auditActor(c), c.Param("id"))
+	respondRestore(c, err)
+}
+
+// importVisits godoc
+// @Summary Import visits from csv
+// @Description The first line is a header with visit_id, location_id, user_id, visited_at and mark columns.
+// @Tags visit
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Accept text/csv
+// @Produce json
+// @Param mode query string false "insert rejects existing ids, upsert updates them but not deleted visits" Enums(insert, upsert)
+// @Param input body string true "CSV document"
+// @Success 200 {object} model.ImportResult
+// @Failure 400,401,413,415 {object} errResponse
+// @Failure 403 {object} problemResponse
+// @Failure 500 {object} errResponse
+// @Router /visits/import [post]
+func (h *visitHandler) importVisits(c *gin.Context) {
+	importCSV(c, h.repo)
+}
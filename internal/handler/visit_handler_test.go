@@ -29,7 +29,7 @@ func TestVisitHandler_getAllVisits(t *testing.T) {
 			name: "Ok",
 			id:   "1",
 			mockBehavior: func(s *mock_service.MockVisit, id string) {
-				s.EXPECT().GetAll(id).Return(model.UserVisits{
+				s.EXPECT().GetAll(id, model.LocationFilter{}).Return(model.UserVisits{
 					Visits: []model.UserVisit{
 						{Place: "Eiffel Tower", Country: "France", VisitedAt: "2015-06-12", Mark: 4},
 						{Place: "Grand Canyon", Country: "USA", VisitedAt: "2019-09-02", Mark: 3},
@@ -43,7 +43,7 @@ func TestVisitHandler_getAllVisits(t *testing.T) {
 			name: "Not Found",
 			id:   "1",
 			mockBehavior: func(s *mock_service.MockVisit, id string) {
-				s.EXPECT().GetAll(id).Return(model.UserVisits{}, apperrors.ErrRecordNotFound)
+				s.EXPECT().GetAll(id, model.LocationFilter{}).Return(model.UserVisits{}, apperrors.ErrRecordNotFound)
 			},
 			expectedStatusCode:   http.StatusNotFound,
 			expectedResponseBody: `{"error":"record not found"}`,
@@ -52,7 +52,7 @@ func TestVisitHandler_getAllVisits(t *testing.T) {
 			name: "Service Error",
 			id:   "1",
 			mockBehavior: func(s *mock_service.MockVisit, id string) {
-				s.EXPECT().GetAll(id).Return(model.UserVisits{}, errors.New("something went wrong"))
+				s.EXPECT().GetAll(id, model.LocationFilter{}).Return(model.UserVisits{}, errors.New("something went wrong"))
 			},
 			expectedStatusCode:   http.StatusInternalServerError,
 			expectedResponseBody: `{"error":"something went wrong"}`,
@@ -67,7 +67,7 @@ func TestVisitHandler_getAllVisits(t *testing.T) {
 			test.mockBehavior(visit, test.id)
 
 			serv := &service.Service{Visit: visit}
-			handle := NewHandler(serv)
+			handle := NewHandler(serv, nil)
 
 			router := gin.New()
 			router.GET("/visits/user/:id", handle.getAllVisits)
@@ -107,11 +107,40 @@ func TestVisitHandler_createVisit(t *testing.T) {
 				Mark:       3,
 			},
 			mockBehavior: func(s *mock_service.MockVisit, visit model.Visit) {
-				s.EXPECT().Create(visit).Return(visit, nil)
+				s.EXPECT().Create(model.Actor{Actor: "anonymous"}, visit).Return(visit, nil)
 			},
 			expectedStatusCode:   http.StatusOK,
 			expectedResponseBody: `{"visit_id":1,"location_id":1,"user_id":1,"visited_at":"2018-10-16","mark":3}`,
 		},
+		{
+			name: "With Review",
+			inputBody: `{"visit_id":1,"location_id":1,"user_id":1,"visited_at":"2018-10-16","mark":3,` +
+				`"review":{"title":"Crowded","body":"Come early","language":"en"}}`,
+			inputVisit: model.Visit{
+				VisitId:    1,
+				LocationId: 1,
+				UserId:     1,
+				VisitedAt:  "2018-10-16",
+				Mark:       3,
+				Review:     &model.Review{Title: "Crowded", Body: "Come early", Language: "en"},
+			},
+			mockBehavior: func(s *mock_service.MockVisit, visit model.Visit) {
+				created := visit
+				created.Review = &model.Review{Title: "Crowded", Body: "Come early", Language: "en", Status: model.ReviewPending}
+				s.EXPECT().Create(model.Actor{Actor: "anonymous"}, visit).Return(created, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{"visit_id":1,"location_id":1,"user_id":1,"visited_at":"2018-10-16","mark":3,` +
+				`"review":{"title":"Crowded","body":"Come early","language":"en","status":"pending"}}`,
+		},
+		{
+			name: "Invalid Review Language",
+			inputBody: `{"visit_id":1,"location_id":1,"user_id":1,"visited_at":"2018-10-16","mark":3,` +
+				`"review":{"title":"Crowded","body":"Come early","language":"english!"}}`,
+			mockBehavior:         func(s *mock_service.MockVisit, visit model.Visit) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"invalid input body"}`,
+		},
 		{
 			name:      "Visit exist",
 			inputBody: `{"visit_id":1,"location_id":1,"user_id":1,"visited_at":"2018-10-16","mark":5}`,
@@ -123,7 +152,7 @@ func TestVisitHandler_createVisit(t *testing.T) {
 				Mark:       5,
 			},
 			mockBehavior: func(s *mock_service.MockVisit, visit model.Visit) {
-				s.EXPECT().Create(visit).Return(visit, apperrors.ErrIncorrectQuery)
+				s.EXPECT().Create(model.Actor{Actor: "anonymous"}, visit).Return(visit, apperrors.ErrIncorrectQuery)
 			},
 			expectedStatusCode:   http.StatusBadRequest,
 			expectedResponseBody: `{"error":"incorrect query"}`,
@@ -138,7 +167,7 @@ func TestVisitHandler_createVisit(t *testing.T) {
 			test.mockBehavior(visit, test.inputVisit)
 
 			serv := &service.Service{Visit: visit}
-			handle := NewHandler(serv)
+			handle := NewHandler(serv, nil)
 
 			router := gin.New()
 			router.POST("/visit/new", handle.createVisit)
@@ -170,7 +199,7 @@ func TestVisitHandler_deleteVisitById(t *testing.T) {
 			name: "Ok",
 			id:   "1",
 			mockBehavior: func(s *mock_service.MockVisit, id string) {
-				s.EXPECT().DeleteById(id).Return(nil)
+				s.EXPECT().DeleteById(model.Actor{Actor: "anonymous"}, id).Return(nil)
 			},
 			expectedStatusCode: http.StatusNoContent,
 		},
@@ -178,7 +207,7 @@ func TestVisitHandler_deleteVisitById(t *testing.T) {
 			name: "Not Found",
 			id:   "1",
 			mockBehavior: func(s *mock_service.MockVisit, id string) {
-				s.EXPECT().DeleteById(id).Return(apperrors.ErrRecordNotFound)
+				s.EXPECT().DeleteById(model.Actor{Actor: "anonymous"}, id).Return(apperrors.ErrRecordNotFound)
 			},
 			expectedStatusCode:   http.StatusNotFound,
 			expectedResponseBody: `{"error":"record not found"}`,
@@ -187,7 +216,7 @@ func TestVisitHandler_deleteVisitById(t *testing.T) {
 			name: "Service Error",
 			id:   "1",
 			mockBehavior: func(s *mock_service.MockVisit, id string) {
-				s.EXPECT().DeleteById(id).Return(errors.New("something went wrong"))
+				s.EXPECT().DeleteById(model.Actor{Actor: "anonymous"}, id).Return(errors.New("something went wrong"))
 			},
 			expectedStatusCode:   http.StatusInternalServerError,
 			expectedResponseBody: `{"error":"something went wrong"}`,
@@ -202,7 +231,7 @@ func TestVisitHandler_deleteVisitById(t *testing.T) {
 			test.mockBehavior(visit, test.id)
 
 			serv := &service.Service{Visit: visit}
-			handle := NewHandler(serv)
+			handle := NewHandler(serv, nil)
 
 			router := gin.New()
 			router.DELETE("/visit/:id", handle.deleteVisitById)
@@ -216,3 +245,63 @@ func TestVisitHandler_deleteVisitById(t *testing.T) {
 		})
 	}
 }
+
+func TestVisitHandler_restoreVisit(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockVisit)
+
+	testTable := []struct {
+		name                 string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "Ok",
+			mockBehavior: func(s *mock_service.MockVisit) {
+				s.EXPECT().Restore(model.Actor{Actor: "anonymous"}, "1").Return(nil)
+			},
+			expectedStatusCode: http.StatusNoContent,
+		},
+		{
+			name: "Not Deleted",
+			mockBehavior: func(s *mock_service.MockVisit) {
+				s.EXPECT().Restore(model.Actor{Actor: "anonymous"}, "1").Return(apperrors.ErrRecordNotFound)
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"error":"record not found"}`,
+		},
+		{
+			name: "Service Error",
+			mockBehavior: func(s *mock_service.MockVisit) {
+				s.EXPECT().Restore(model.Actor{Actor: "anonymous"}, "1").Return(errors.New("something went wrong"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"error":"something went wrong"}`,
+		},
+	}
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			visit := mock_service.NewMockVisit(controller)
+			test.mockBehavior(visit)
+
+			serv := &service.Service{Visit: visit}
+			handle := NewHandler(serv, nil)
+
+			router := gin.New()
+			router.POST("/visit/:id/restore", handle.restoreVisit)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("POST", "/visit/1/restore", nil)
+
+			router.ServeHTTP(w, r)
+
+			body := strings.Trim(w.Body.String(), "\n")
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, body, test.expectedResponseBody)
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/internal/service"
+	"github.com/rinuccia/travels-api/pkg/apperrors"
+	"net/http"
+)
+
+type wishlistHandler struct {
+	repo service.Wishlist
+}
+
+func newWishlistHandler(repository service.Wishlist) *wishlistHandler {
+	return &wishlistHandler{
+		repo: repository,
+	}
+}
+
+// getWishlist godoc
+// @Summary Returns user wishlist, open items first by priority and planned date
+// @Tags wishlist
+// @Security BearerAuth
+// @Produce json
+// @Param id path integer true "User ID"
+// @Param status query string false "Only open or only done items" Enums(open, done)
+// @Success 200 {object} model.Wishlist
+// @Failure 400,401,404 {object} errResponse
+// @Failure 403 {object} problemResponse
+// @Failure 500 {object} errResponse
+// @Router /user/{id}/wishlist [get]
+func (h *wishlistHandler) getWishlist(c *gin.Context) {
+	id := c.Param("id")
+	filter := model.WishlistFilter{}
+	err := c.ShouldBindQuery(&filter)
+	validationErr := validate.Struct(filter)
+	if err != nil || validationErr != nil {
+		c.JSON(http.StatusBadRequest, newErrResponse("invalid query parameters"))
+		return
+	}
+
+	wishlist, err := h.repo.GetAll(id, filter)
+	if errors.Is(err, apperrors.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, newErrResponse(err.Error()))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, newErrResponse("something went wrong"))
+		return
+	}
+
+	c.JSON(http.StatusOK, wishlist)
+}
+
+// createWishlistItem godoc
+// @Summary Add location to user wishlist
+// @Description The item is marked as done when the user visit of the location is created.
+// @Tags wishlist
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path integer true "User ID"
+// @Param input body model.WishlistItem true "Wishlist item, priority from 1 to 5 defaults to 3"
+// @Success 200 {object} model.WishlistItem
+// @Failure 400,401 {object} errResponse
+// @Failure 403 {object} problemResponse
+// @Router /user/{id}/wishlist [post]
+func (h *wishlistHandler) createWishlistItem(c *gin.Context) {
+	id := c.Param("id")
+	item := model.WishlistItem{}
+	err := c.BindJSON(&item)
+	validationErr := validate.Struct(item)
+	if err != nil || validationErr != nil {
+		c.JSON(http.StatusBadRequest, newErrResponse("invalid input body"))
+		return
+	}
+
+	item, err = h.repo.Create(id, item)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, newErrResponse(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, item)
+}
+
+// updateWishlistItem godoc
+// @Summary Change planned date and priority of user wishlist item
+// @Tags wishlist
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path integer true "User ID"
+// @Param location_id path integer true "Location ID"
+// @Param input body model.WishlistItem true "Wishlist item"
+// @Success 204
+// @Failure 400,401,404 {object} errResponse
+// @Failure 403 {object} problemResponse
+// @Failure 500 {object} errResponse
+// @Router /user/{id}/wishlist/{location_id} [put]
+func (h *wishlistHandler) updateWishlistItem(c *gin.Context) {
+	id := c.Param("id")
+	locationId := c.Param("location_id")
+	item := model.WishlistItem{}
+	err := c.BindJSON(&item)
+	validationErr := validate.StructExcept(item, "LocationId")
+	if err != nil || validationErr != nil {
+		c.JSON(http.StatusBadRequest, newErrResponse("invalid input body"))
+		return
+	}
+
+	err = h.repo.Update(id, locationId, item)
+	if errors.Is(err, apperrors.ErrIncorrectQuery) {
+		c.JSON(http.StatusBadRequest, newErrResponse(err.Error()))
+		return
+	}
+	if errors.Is(err, apperrors.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, newErrResponse(err.Error()))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, newErrResponse("something went wrong"))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// deleteWishlistItem godoc
+// @Summary Removes location from user wishlist
+// @Tags wishlist
+// @Security BearerAuth
+// @Produce json
+// @Param id path integer true "User ID"
+// @Param location_id path integer true "Location ID"
+// @Success 204
+// @Failure 401,404 {object} errResponse
+// @Failure 403 {object} problemResponse
+// @Failure 500 {object} errResponse
+// @Router /user/{id}/wishlist/{location_id} [delete]
+func (h *wishlistHandler) deleteWishlistItem(c *gin.Context) {
+	err := h.repo.DeleteById(c.Param("id"), c.Param("location_id"))
+	if errors.Is(err, apperrors.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, newErrResponse(err.Error()))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, newErrResponse("something went wrong"))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// restoreWishlistItem godoc
+// @Summary Restores location removed from user wishlist
+// @Tags wishlist
+// @Security BearerAuth
+// @Produce json
+// @Param id path integer true "User ID"
+// @Param location_id path integer true "Location ID"
+// @Success 204
+// @Failure 400,401,404 {object} errResponse
+// @Failure 403 {object} problemResponse
+// @Failure 500 {object} errResponse
+// @Router /user/{id}/wishlist/{location_id}/restore [post]
+func (h *wishlistHandler) restoreWishlistItem(c *gin.Context) {
+	err := h.repo.Restore(c.Param("id"), c.Param("location_id"))
+	respondRestore(c, err)
+}
@@ -0,0 +1,315 @@
+package handler
+
+import (
+	"bytes"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/internal/service"
+	mock_service "github.com/rinuccia/travels-api/internal/service/mocks"
+	"github.com/rinuccia/travels-api/pkg/apperrors"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWishlistHandler_getWishlist(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockWishlist)
+
+	testTable := []struct {
+		name                 string
+		query                string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:  "Ok",
+			query: "?status=done",
+			mockBehavior: func(s *mock_service.MockWishlist) {
+				s.EXPECT().GetAll("1", model.WishlistFilter{Status: "done"}).Return(model.Wishlist{
+					List: []model.WishlistItem{
+						{LocationId: 2, Place: "Eiffel Tower", Country: "France", Priority: 3, Done: true, DoneVisitId: 7},
+					},
+				}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{"list":[{"location_id":2,"place":"Eiffel Tower","country":"France","priority":3,` +
+				`"done":true,"done_visit_id":7}]}`,
+		},
+		{
+			name:                 "Invalid Status",
+			query:                "?status=later",
+			mockBehavior:         func(s *mock_service.MockWishlist) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"invalid query parameters"}`,
+		},
+		{
+			name: "Not Found",
+			mockBehavior: func(s *mock_service.MockWishlist) {
+				s.EXPECT().GetAll("1", model.WishlistFilter{}).Return(model.Wishlist{}, apperrors.ErrRecordNotFound)
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"error":"record not found"}`,
+		},
+	}
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			wishlist := mock_service.NewMockWishlist(controller)
+			test.mockBehavior(wishlist)
+
+			serv := &service.Service{Wishlist: wishlist}
+			handle := NewHandler(serv, nil)
+
+			router := gin.New()
+			router.GET("/user/:id/wishlist", handle.getWishlist)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "/user/1/wishlist"+test.query, nil)
+
+			router.ServeHTTP(w, r)
+
+			body := strings.Trim(w.Body.String(), "\n")
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, body, test.expectedResponseBody)
+		})
+	}
+}
+
+func TestWishlistHandler_createWishlistItem(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockWishlist, item model.WishlistItem)
+
+	testTable := []struct {
+		name                 string
+		inputBody            string
+		inputItem            model.WishlistItem
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "Ok",
+			inputBody: `{"location_id":1,"planned_at":"2024-05-01"}`,
+			inputItem: model.WishlistItem{LocationId: 1, PlannedAt: "2024-05-01"},
+			mockBehavior: func(s *mock_service.MockWishlist, item model.WishlistItem) {
+				s.EXPECT().Create("1", item).Return(model.WishlistItem{LocationId: 1, PlannedAt: "2024-05-01", Priority: 3}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"location_id":1,"planned_at":"2024-05-01","priority":3,"done":false}`,
+		},
+		{
+			name:                 "Invalid Priority",
+			inputBody:            `{"location_id":1,"priority":9}`,
+			mockBehavior:         func(s *mock_service.MockWishlist, item model.WishlistItem) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"invalid input body"}`,
+		},
+		{
+			name:      "Already Listed",
+			inputBody: `{"location_id":1}`,
+			inputItem: model.WishlistItem{LocationId: 1},
+			mockBehavior: func(s *mock_service.MockWishlist, item model.WishlistItem) {
+				s.EXPECT().Create("1", item).Return(model.WishlistItem{}, apperrors.ErrIncorrectQuery)
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"incorrect query"}`,
+		},
+	}
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			wishlist := mock_service.NewMockWishlist(controller)
+			test.mockBehavior(wishlist, test.inputItem)
+
+			serv := &service.Service{Wishlist: wishlist}
+			handle := NewHandler(serv, nil)
+
+			router := gin.New()
+			router.POST("/user/:id/wishlist", handle.createWishlistItem)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("POST", "/user/1/wishlist", bytes.NewBufferString(test.inputBody))
+
+			router.ServeHTTP(w, r)
+
+			body := strings.Trim(w.Body.String(), "\n")
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, body, test.expectedResponseBody)
+		})
+	}
+}
+
+func TestWishlistHandler_updateWishlistItem(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockWishlist)
+
+	testTable := []struct {
+		name                 string
+		inputBody            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "Ok",
+			inputBody: `{"planned_at":"2024-06-01","priority":5}`,
+			mockBehavior: func(s *mock_service.MockWishlist) {
+				s.EXPECT().Update("1", "2", model.WishlistItem{PlannedAt: "2024-06-01", Priority: 5}).Return(nil)
+			},
+			expectedStatusCode: http.StatusNoContent,
+		},
+		{
+			name:      "Not Found",
+			inputBody: `{"priority":5}`,
+			mockBehavior: func(s *mock_service.MockWishlist) {
+				s.EXPECT().Update("1", "2", model.WishlistItem{Priority: 5}).Return(apperrors.ErrRecordNotFound)
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"error":"record not found"}`,
+		},
+		{
+			name:                 "Invalid Date",
+			inputBody:            `{"planned_at":"next year"}`,
+			mockBehavior:         func(s *mock_service.MockWishlist) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"invalid input body"}`,
+		},
+	}
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			wishlist := mock_service.NewMockWishlist(controller)
+			test.mockBehavior(wishlist)
+
+			serv := &service.Service{Wishlist: wishlist}
+			handle := NewHandler(serv, nil)
+
+			router := gin.New()
+			router.PUT("/user/:id/wishlist/:location_id", handle.updateWishlistItem)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("PUT", "/user/1/wishlist/2", bytes.NewBufferString(test.inputBody))
+
+			router.ServeHTTP(w, r)
+
+			body := strings.Trim(w.Body.String(), "\n")
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, body, test.expectedResponseBody)
+		})
+	}
+}
+
+func TestWishlistHandler_deleteWishlistItem(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockWishlist)
+
+	testTable := []struct {
+		name                 string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "Ok",
+			mockBehavior: func(s *mock_service.MockWishlist) {
+				s.EXPECT().DeleteById("1", "2").Return(nil)
+			},
+			expectedStatusCode: http.StatusNoContent,
+		},
+		{
+			name: "Not Found",
+			mockBehavior: func(s *mock_service.MockWishlist) {
+				s.EXPECT().DeleteById("1", "2").Return(apperrors.ErrRecordNotFound)
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"error":"record not found"}`,
+		},
+	}
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			wishlist := mock_service.NewMockWishlist(controller)
+			test.mockBehavior(wishlist)
+
+			serv := &service.Service{Wishlist: wishlist}
+			handle := NewHandler(serv, nil)
+
+			router := gin.New()
+			router.DELETE("/user/:id/wishlist/:location_id", handle.deleteWishlistItem)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("DELETE", "/user/1/wishlist/2", nil)
+
+			router.ServeHTTP(w, r)
+
+			body := strings.Trim(w.Body.String(), "\n")
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, body, test.expectedResponseBody)
+		})
+	}
+}
+
+func TestWishlistHandler_restoreWishlistItem(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockWishlist)
+
+	testTable := []struct {
+		name                 string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "Ok",
+			mockBehavior: func(s *mock_service.MockWishlist) {
+				s.EXPECT().Restore("1", "2").Return(nil)
+			},
+			expectedStatusCode: http.StatusNoContent,
+		},
+		{
+			name: "Not Deleted",
+			mockBehavior: func(s *mock_service.MockWishlist) {
+				s.EXPECT().Restore("1", "2").Return(apperrors.ErrRecordNotFound)
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"error":"record not found"}`,
+		},
+	}
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			wishlist := mock_service.NewMockWishlist(controller)
+			test.mockBehavior(wishlist)
+
+			serv := &service.Service{Wishlist: wishlist}
+			handle := NewHandler(serv, nil)
+
+			router := gin.New()
+			router.POST("/user/:id/wishlist/:location_id/restore", handle.restoreWishlistItem)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("POST", "/user/1/wishlist/2/restore", nil)
+
+			router.ServeHTTP(w, r)
+
+			body := strings.Trim(w.Body.String(), "\n")
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, body, test.expectedResponseBody)
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package model
+
+import "time"
+
+// Scopes of API keys, each allows the routes changing one kind of data
+const (
+	ScopeLocationsWrite  = "locations:write"
+	ScopeCategoriesWrite = "categories:write"
+	ScopeVisitsWrite     = "visits:write"
+)
+
+// APIKey represent API key data model of a machine client, the key itself is never stored.
+// Scopes pick the routes a key may call, Role is checked on them like the role of a user, keys own no user data
+type APIKey struct {
+	KeyId      uint32     `json:"key_id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	Role       string     `json:"role"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	UsageCount int64      `json:"usage_count"`
+}
+
+// APIKeys represents a list of all API keys
+type APIKeys struct {
+	List []APIKey `json:"list"`
+}
+
+// APIKeyRequest represent a new API key, keys without expires_at never expire and keys without role are curators
+type APIKeyRequest struct {
+	Name      string     `json:"name" validate:"required,min=2,max=100"`
+	Scopes    []string   `json:"scopes" validate:"required,min=1,dive,oneof=locations:write categories:write visits:write"`
+	Role      string     `json:"role" validate:"omitempty,oneof=curator admin"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// NewAPIKey represent a created API key, the key is shown only once
+type NewAPIKey struct {
+	APIKey
+	Key string `json:"key"`
+}
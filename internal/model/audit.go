@@ -0,0 +1,56 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Audited entities and the actions recorded for them
+const (
+	AuditEntityUser     = "user"
+	AuditEntityLocation = "location"
+	AuditEntityVisit    = "visit"
+
+	AuditActionCreate  = "create"
+	AuditActionUpdate  = "update"
+	AuditActionDelete  = "delete"
+	AuditActionRestore = "restore"
+)
+
+// Actor represent the caller making a change and the request it is made in, Actor is "user:<id>",
+// "apikey:<id>" or "anonymous"
+type Actor struct {
+	Actor     string
+	RequestId string
+}
+
+// AuditRecord represent one change of an entity, Before is empty for creates and After for deletes
+type AuditRecord struct {
+	AuditId    uint64          `json:"audit_id"`
+	Actor      string          `json:"actor"`
+	Action     string          `json:"action"`
+	EntityType string          `json:"entity_type"`
+	EntityId   string          `json:"entity_id"`
+	Before     json.RawMessage `json:"before,omitempty" swaggertype:"object"`
+	After      json.RawMessage `json:"after,omitempty" swaggertype:"object"`
+	RequestId  string          `json:"request_id"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// AuditLog represents one page of audit records, newest first
+type AuditLog struct {
+	List    []AuditRecord `json:"list"`
+	Page    int           `json:"page"`
+	PerPage int           `json:"per_page"`
+}
+
+// AuditFilter represent audit log filters and page, zero times leave the range open
+type AuditFilter struct {
+	Entity   string    `form:"entity" validate:"omitempty,oneof=user location visit"`
+	EntityId string    `form:"entity_id"`
+	Actor    string    `form:"actor"`
+	From     time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To       time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	Page     int       `form:"page" validate:"min=1"`
+	PerPage  int       `form:"per_page" validate:"min=1,max=500"`
+}
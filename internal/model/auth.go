@@ -0,0 +1,75 @@
+package model
+
+import "time"
+
+// Registration represent a new user with the password used to log in
+type Registration struct {
+	User
+	Password string `json:"password" validate:"required,min=8,max=72"`
+}
+
+// Credentials represent login data
+type Credentials struct {
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required,max=72"`
+}
+
+// UserCredentials represent stored login data of a user
+type UserCredentials struct {
+	UserId       uint32
+	PasswordHash []byte
+}
+
+// RefreshRequest represent a refresh token exchanged for new tokens or revoked
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+// TokenPair represent tokens issued on login, the access token expires after ExpiresIn seconds
+type TokenPair struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"`
+}
+
+// RefreshToken represent a stored refresh token, only a hash of the token is kept
+type RefreshToken struct {
+	TokenHash string
+	UserId    uint32
+	ExpiresAt time.Time
+}
+
+// Roles of users, travellers change their own data, curators also manage locations and categories,
+// admins may change anything
+const (
+	RoleTraveller = "traveller"
+	RoleCurator   = "curator"
+	RoleAdmin     = "admin"
+)
+
+// Identity represent the authenticated caller, a user or a machine client with an API key
+type Identity struct {
+	UserId   uint32   `json:"user_id"`
+	Role     string   `json:"role"`
+	APIKeyId uint32   `json:"api_key_id,omitempty"`
+	Scopes   []string `json:"scopes,omitempty"`
+}
+
+// RoleUpdate represent a new role of a user
+type RoleUpdate struct {
+	Role string `json:"role" validate:"required,oneof=traveller curator admin"`
+}
+
+// EmailVerification represent an email waiting for confirmation, TokenId is the id of the signed token mailed to it
+type EmailVerification struct {
+	TokenId   string
+	UserId    uint32
+	Email     string
+	ExpiresAt time.Time
+}
+
+// VerificationToken represent the token from a verification email
+type VerificationToken struct {
+	Token string `json:"token" validate:"required"`
+}
@@ -0,0 +1,12 @@
+package model
+
+// Category represent location category data model
+type Category struct {
+	CategoryId uint32 `json:"category_id" validate:"required"`
+	Name       string `json:"name" validate:"required,min=2,max=50"`
+}
+
+// Categories represents a list of all categories
+type Categories struct {
+	List []Category `json:"list"`
+}
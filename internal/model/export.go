@@ -0,0 +1,53 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	ExportFormatNDJSON = "ndjson"
+	ExportFormatCSV    = "csv"
+	ExportFormatZip    = "zip"
+
+	ExportEntityUsers     = "users"
+	ExportEntityLocations = "locations"
+	ExportEntityVisits    = "visits"
+)
+
+// ExportEntities lists exported entities in the order they are written to an archive
+var ExportEntities = []string{ExportEntityUsers, ExportEntityLocations, ExportEntityVisits}
+
+// ExportOptions represent dataset export parameters
+type ExportOptions struct {
+	Format       string
+	Entity       string
+	UpdatedSince time.Time
+}
+
+// Validate checks that the format and entity combination can be exported
+func (o ExportOptions) Validate() error {
+	switch o.Format {
+	case ExportFormatZip:
+		return nil
+	case ExportFormatNDJSON, ExportFormatCSV:
+		for _, entity := range ExportEntities {
+			if o.Entity == entity {
+				return nil
+			}
+		}
+		return errors.New("entity must be one of users, locations, visits")
+	}
+	return errors.New("format must be one of ndjson, csv, zip")
+}
+
+// ParseUpdatedSince parses a "2006-01-02" date or an RFC 3339 timestamp, empty string means no filter
+func ParseUpdatedSince(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
@@ -0,0 +1,64 @@
+package model
+
+// Follow states, following a private user needs the user's approval
+const (
+	FollowApproved = "approved"
+	FollowPending  = "pending"
+)
+
+// Follow represent a user following another user
+type Follow struct {
+	UserId   uint32 `json:"user_id"`
+	TargetId uint32 `json:"target_id"`
+	Status   string `json:"status"`
+}
+
+// Follower represent a user following the listed user
+type Follower struct {
+	UserId    uint32 `json:"user_id"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	Status    string `json:"status"`
+}
+
+// Followers represent followers list data model
+type Followers struct {
+	List []Follower `json:"list"`
+}
+
+// FollowerFilter represent follower list filter, empty status lists every follower
+type FollowerFilter struct {
+	Status string `form:"status" validate:"omitempty,oneof=approved pending"`
+}
+
+// FeedAuthor represent the user who made a feed visit
+type FeedAuthor struct {
+	UserId    uint32 `json:"user_id"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+}
+
+// FeedVisit represent a visit of a followed user
+type FeedVisit struct {
+	VisitId uint32     `json:"visit_id"`
+	Author  FeedAuthor `json:"author"`
+	UserVisit
+}
+
+// Feed represent one page of the feed, NextCursor is empty on the last page
+type Feed struct {
+	Visits     []FeedVisit `json:"visits"`
+	NextCursor string      `json:"next_cursor,omitempty"`
+}
+
+// FeedQuery represent feed page request, the cursor comes from the previous page
+type FeedQuery struct {
+	Cursor string `form:"cursor"`
+	Limit  int    `form:"limit" validate:"min=1,max=100"`
+}
+
+// FeedCursor represent the position of the last visit of a feed page, empty VisitedAt starts from the newest visit
+type FeedCursor struct {
+	VisitedAt string
+	VisitId   uint32
+}
@@ -0,0 +1,14 @@
+package model
+
+// ImportResult represent csv import report data model
+type ImportResult struct {
+	Inserted int            `json:"inserted"`
+	Updated  int            `json:"updated"`
+	Rejected []RejectedLine `json:"rejected"`
+}
+
+// RejectedLine represent a csv line that was not imported
+type RejectedLine struct {
+	Line   int    `json:"line"`
+	Reason string `json:"reason"`
+}
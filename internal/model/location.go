@@ -2,9 +2,20 @@ package model
 
 // Location represent location data model
 type Location struct {
-	LocationId uint32 `json:"location_id" validate:"required"`
-	Place      string `json:"place" validate:"required"`
-	Country    string `json:"country" validate:"required,min=2,max=50"`
+	LocationId  uint32   `json:"location_id" validate:"required"`
+	Place       string   `json:"place" validate:"required"`
+	Country     string   `json:"country" validate:"required,min=2,max=50"`
+	CountryCode string   `json:"country_code,omitempty"`
+	Lat         *float64 `json:"lat,omitempty" validate:"required_with=Lon,omitempty,latitude"`
+	Lon         *float64 `json:"lon,omitempty" validate:"required_with=Lat,omitempty,longitude"`
+	Category    string   `json:"category,omitempty" validate:"omitempty,max=50"`
+	Tags        []string `json:"tags,omitempty" validate:"omitempty,max=20,dive,min=1,max=50"`
+}
+
+// LocationFilter represent location list filters, empty fields are ignored
+type LocationFilter struct {
+	Category string `form:"category"`
+	Tag      string `form:"tag"`
 }
 
 type Locs []Location
@@ -14,7 +25,50 @@ type Locations struct {
 	List []Location `json:"list"`
 }
 
+// NearbyLocation represent location with the distance from the search point
+type NearbyLocation struct {
+	Location
+	DistanceKm float64 `json:"distance_km"`
+}
+
+// NearbyLocations represents locations ordered by distance
+type NearbyLocations struct {
+	List []NearbyLocation `json:"list"`
+}
+
+// TopLocationsQuery represent location ranking parameters
+type TopLocationsQuery struct {
+	By        string `form:"by" validate:"omitempty,oneof=avg visits"`
+	Country   string `form:"country" validate:"omitempty,max=50"`
+	From      string `form:"from" validate:"omitempty,datetime=2006-01-02"`
+	To        string `form:"to" validate:"omitempty,datetime=2006-01-02"`
+	Limit     int    `form:"limit" validate:"min=1,max=100"`
+	MinVisits int    `form:"min_visits" validate:"min=1"`
+	Bayesian  bool   `form:"bayesian"`
+}
+
+// TopLocation represent a ranked location with its visit count, average mark
+// and Bayesian average mark pulled towards the mean of all ranked visits
+type TopLocation struct {
+	Location
+	Visits      int     `json:"visits"`
+	Avg         float32 `json:"avg"`
+	BayesianAvg float32 `json:"bayesian_avg"`
+}
+
+// TopLocations represents ranked locations, best first
+type TopLocations struct {
+	List []TopLocation `json:"list"`
+}
+
 // AvgRating represent average location rating data model
 type AvgRating struct {
 	Avg float32 `json:"avg"`
 }
+
+// UnmatchedCountry represent a location whose free-text country has no ISO 3166 match
+type UnmatchedCountry struct {
+	TenantId   uint32 `json:"tenant_id"`
+	LocationId uint32 `json:"location_id"`
+	Country    string `json:"country"`
+}
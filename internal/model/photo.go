@@ -0,0 +1,29 @@
+package model
+
+import "time"
+
+// Photo represent metadata of an uploaded image, the files themselves are kept in storage
+type Photo struct {
+	PhotoId      uint32    `json:"photo_id"`
+	LocationId   uint32    `json:"location_id,omitempty"`
+	VisitId      uint32    `json:"visit_id,omitempty"`
+	StorageKey   string    `json:"-"`
+	ContentType  string    `json:"content_type"`
+	Size         int64     `json:"size"`
+	Width        int       `json:"width"`
+	Height       int       `json:"height"`
+	CreatedAt    time.Time `json:"created_at"`
+	URL          string    `json:"url"`
+	ThumbnailURL string    `json:"thumbnail_url"`
+}
+
+// Photos represent photos of a location or visit
+type Photos struct {
+	List []Photo `json:"list"`
+}
+
+// PhotoUpload represent an uploaded image before it is stored
+type PhotoUpload struct {
+	Data          []byte
+	StripLocation bool
+}
@@ -0,0 +1,56 @@
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErasureGenesisHash is the previous hash of the first erasure log entry
+var ErasureGenesisHash = strings.Repeat("0", 64)
+
+// SubjectSection represent one kind of records linked to a user, Data is a json object or array
+type SubjectSection struct {
+	Name string
+	Data json.RawMessage
+}
+
+// SubjectData represent everything stored about a user, Photos are the photos of the user visits
+type SubjectData struct {
+	Sections []SubjectSection
+	Photos   []Photo
+}
+
+// Erasure represent an entry of the erasure log, Hash covers the entry and the hash of the entry before it
+type Erasure struct {
+	ErasureId uint64    `json:"erasure_id"`
+	UserId    uint32    `json:"user_id"`
+	Actor     string    `json:"actor"`
+	RequestId string    `json:"request_id"`
+	ErasedAt  time.Time `json:"erased_at"`
+	PrevHash  string    `json:"prev_hash"`
+	Hash      string    `json:"hash"`
+}
+
+// ChainHash returns the hash the entry should have given its fields and PrevHash
+func (e Erasure) ChainHash() string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{
+		e.PrevHash,
+		strconv.FormatUint(uint64(e.UserId), 10),
+		e.Actor,
+		e.RequestId,
+		e.ErasedAt.UTC().Format(time.RFC3339Nano),
+	}, "\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+// ErasureLog represent the erasure log oldest first, Valid reports whether every entry matches its hash and
+// links to the entry before it, BrokenAt is the first entry that doesn't
+type ErasureLog struct {
+	List     []Erasure `json:"list"`
+	Valid    bool      `json:"valid"`
+	BrokenAt uint64    `json:"broken_at,omitempty"`
+}
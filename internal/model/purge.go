@@ -0,0 +1,10 @@
+package model
+
+// Purged represent the rows hard deleted by a purge, Photos are the photos of the purged visits
+type Purged struct {
+	Visits     int64
+	Trips      int64
+	Categories int64
+	Wishlist   int64
+	Photos     []Photo
+}
@@ -0,0 +1,35 @@
+package model
+
+// MarkCount represent the number of visits with the given mark
+type MarkCount struct {
+	Mark  uint8 `json:"mark"`
+	Count int   `json:"count"`
+}
+
+// PeriodMarkCount represent the number of visits with the given mark in a period, period is empty without buckets
+type PeriodMarkCount struct {
+	Period string
+	Mark   uint8
+	Count  int
+}
+
+// RatingSummary represent visit marks histogram with its summary statistics
+type RatingSummary struct {
+	Histogram []MarkCount `json:"histogram"`
+	Total     int         `json:"total"`
+	Mean      float32     `json:"mean"`
+	Median    float32     `json:"median"`
+	StdDev    float32     `json:"std_dev"`
+}
+
+// RatingPeriod represent rating distribution of a month (2006-01) or a year (2006)
+type RatingPeriod struct {
+	Period string `json:"period"`
+	RatingSummary
+}
+
+// RatingDistribution represent location rating distribution data model
+type RatingDistribution struct {
+	RatingSummary
+	Buckets []RatingPeriod `json:"buckets,omitempty"`
+}
@@ -0,0 +1,22 @@
+package model
+
+// Recommendation sources
+const (
+	RecommendationSimilarUsers   = "similar_users"
+	RecommendationLikedCountries = "liked_countries"
+)
+
+// Recommendation represent an unvisited location suggested to a user, explained by a location the user rated
+type Recommendation struct {
+	Location
+	Score             float32 `json:"score"`
+	Source            string  `json:"source"`
+	BecauseLocationId uint32  `json:"because_location_id"`
+	BecausePlace      string  `json:"because_place"`
+	Reason            string  `json:"reason"`
+}
+
+// Recommendations represents recommended locations, best first
+type Recommendations struct {
+	List []Recommendation `json:"list"`
+}
@@ -0,0 +1,47 @@
+package model
+
+// Review moderation states, only approved reviews are listed publicly
+const (
+	ReviewPending  = "pending"
+	ReviewApproved = "approved"
+	ReviewRejected = "rejected"
+)
+
+// Review represent written review of a visit
+type Review struct {
+	Title    string `json:"title" validate:"required,max=100"`
+	Body     string `json:"body" validate:"required,max=5000"`
+	Language string `json:"language" validate:"required,bcp47_language_tag"`
+	Status   string `json:"status,omitempty" validate:"-"`
+}
+
+// ReviewEntry represent a review with the visit it belongs to
+type ReviewEntry struct {
+	VisitId    uint32 `json:"visit_id"`
+	LocationId uint32 `json:"location_id"`
+	Author     string `json:"author"`
+	VisitedAt  string `json:"visited_at"`
+	Mark       uint8  `json:"mark"`
+	Review
+}
+
+// Reviews represents one page of reviews, Total counts reviews on every page
+type Reviews struct {
+	List    []ReviewEntry `json:"list"`
+	Total   int           `json:"total"`
+	Page    int           `json:"page"`
+	PerPage int           `json:"per_page"`
+}
+
+// ReviewQuery represent review listing order and page
+type ReviewQuery struct {
+	Sort    string `form:"sort" validate:"omitempty,oneof=date mark"`
+	Order   string `form:"order" validate:"omitempty,oneof=asc desc"`
+	Page    int    `form:"page" validate:"min=1"`
+	PerPage int    `form:"per_page" validate:"min=1,max=100"`
+}
+
+// ReviewStatus represent review moderation decision
+type ReviewStatus struct {
+	Status string `json:"status" form:"status" validate:"required,oneof=pending approved rejected"`
+}
@@ -0,0 +1,29 @@
+package model
+
+// VisitFilter represent visit filters shared by rating and statistics endpoints, empty fields are ignored
+type VisitFilter struct {
+	FromDate string `form:"from_date" validate:"omitempty,datetime=2006-01-02"`
+	ToDate   string `form:"to_date" validate:"omitempty,datetime=2006-01-02"`
+	Gender   string `form:"gender" validate:"omitempty,eq=f|eq=m"`
+}
+
+// CountryStats represent per-country statistics data model
+type CountryStats struct {
+	CountryCode string  `json:"country_code"`
+	Country     string  `json:"country"`
+	Locations   int     `json:"locations"`
+	Visits      int     `json:"visits"`
+	Visitors    int     `json:"visitors"`
+	AvgMark     float32 `json:"avg_mark"`
+}
+
+// CountriesStats represents statistics of every country with locations
+type CountriesStats struct {
+	List []CountryStats `json:"list"`
+}
+
+// StatsSort represent statistics ordering
+type StatsSort struct {
+	By    string `form:"sort" validate:"omitempty,oneof=country locations visits visitors avg_mark"`
+	Order string `form:"order" validate:"omitempty,oneof=asc desc"`
+}
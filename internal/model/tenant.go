@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// Tenant represent a partner brand hosted on the deployment, all of its data is kept apart from other tenants
+type Tenant struct {
+	TenantId  uint32    `json:"tenant_id"`
+	Slug      string    `json:"slug"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TenantRequest represent what a request tells about its tenant: the X-Tenant-ID header, a bearer access token
+// and an API key, each may be empty
+type TenantRequest struct {
+	Header      string
+	AccessToken string
+	APIKey      string
+}
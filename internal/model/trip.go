@@ -0,0 +1,52 @@
+package model
+
+// TripRecord represent manually created trip data model
+type TripRecord struct {
+	TripId   uint32   `json:"trip_id" validate:"required"`
+	UserId   uint32   `json:"user_id" validate:"required"`
+	Name     string   `json:"name" validate:"required,min=2,max=100"`
+	VisitIds []uint32 `json:"visit_ids,omitempty" validate:"omitempty,dive,required"`
+}
+
+// TripUpdate represent trip changes, visits are kept when VisitIds is left out
+type TripUpdate struct {
+	Name     string   `json:"name" validate:"required,min=2,max=100"`
+	VisitIds []uint32 `json:"visit_ids,omitempty" validate:"omitempty,dive,required"`
+}
+
+// TripVisit represent a visit inside a trip, TripId is zero for visits not added to a trip
+type TripVisit struct {
+	VisitId     uint32 `json:"visit_id"`
+	LocationId  uint32 `json:"location_id"`
+	Place       string `json:"place"`
+	Country     string `json:"country"`
+	CountryCode string `json:"country_code,omitempty"`
+	VisitedAt   string `json:"visited_at"`
+	Mark        uint8  `json:"mark"`
+	TripId      uint32 `json:"-"`
+}
+
+// TripStats represent per-trip statistics
+type TripStats struct {
+	Visits    int     `json:"visits"`
+	Locations int     `json:"locations"`
+	Countries int     `json:"countries"`
+	Days      int     `json:"days"`
+	AvgMark   float32 `json:"avg_mark"`
+}
+
+// Trip represent trip data model, automatic trips are grouped from visits and have no id
+type Trip struct {
+	TripId    uint32      `json:"trip_id,omitempty"`
+	Name      string      `json:"name"`
+	Automatic bool        `json:"automatic"`
+	StartDate string      `json:"start_date,omitempty"`
+	EndDate   string      `json:"end_date,omitempty"`
+	Stats     TripStats   `json:"stats"`
+	Visits    []TripVisit `json:"visits"`
+}
+
+// Trips represents user trips ordered by start date
+type Trips struct {
+	List []Trip `json:"list"`
+}
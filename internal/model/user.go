@@ -7,4 +7,27 @@ type User struct {
 	FirstName string `json:"first_name" validate:"required,min=2,max=50"`
 	LastName  string `json:"last_name" validate:"required,min=2,max=50"`
 	Gender    string `json:"gender" validate:"required,eq=f|eq=m"`
+	Private   bool   `json:"private,omitempty"`
+	// Verified is set once the email is confirmed, it is ignored on input
+	Verified bool `json:"verified,omitempty"`
+}
+
+// UserSummary represent user travel profile data model
+type UserSummary struct {
+	UserId             uint32  `json:"user_id"`
+	Visits             int     `json:"visits"`
+	Locations          int     `json:"locations"`
+	Countries          int     `json:"countries"`
+	FirstVisit         string  `json:"first_visit,omitempty"`
+	LastVisit          string  `json:"last_visit,omitempty"`
+	AvgMark            float32 `json:"avg_mark"`
+	FavouriteCountry   string  `json:"favourite_country,omitempty"`
+	MostRevisitedPlace string  `json:"most_revisited_place,omitempty"`
+}
+
+// EmailCollision represent users of a tenant whose emails are equal ignoring case
+type EmailCollision struct {
+	TenantId uint32   `json:"tenant_id"`
+	Email    string   `json:"email"`
+	UserIds  []uint32 `json:"user_ids"`
 }
@@ -2,11 +2,12 @@ package model
 
 // Visit represent visit data model
 type Visit struct {
-	VisitId    uint32 `json:"visit_id" validate:"required"`
-	LocationId uint32 `json:"location_id" validate:"required"`
-	UserId     uint32 `json:"user_id" validate:"required"`
-	VisitedAt  string `json:"visited_at" validate:"required,datetime=2006-01-02"`
-	Mark       uint8  `json:"mark" validate:"required,min=0,max=5"`
+	VisitId    uint32  `json:"visit_id" validate:"required"`
+	LocationId uint32  `json:"location_id" validate:"required"`
+	UserId     uint32  `json:"user_id" validate:"required"`
+	VisitedAt  string  `json:"visited_at" validate:"required,datetime=2006-01-02"`
+	Mark       uint8   `json:"mark" validate:"required,min=0,max=5"`
+	Review     *Review `json:"review,omitempty"`
 }
 
 // UserVisit represent user visit data model
@@ -0,0 +1,33 @@
+package model
+
+// WishlistItem represent a location the user plans to visit, it is done once the user visits the location
+type WishlistItem struct {
+	LocationId  uint32 `json:"location_id" validate:"required"`
+	Place       string `json:"place,omitempty" validate:"-"`
+	Country     string `json:"country,omitempty" validate:"-"`
+	PlannedAt   string `json:"planned_at,omitempty" validate:"omitempty,datetime=2006-01-02"`
+	Priority    uint8  `json:"priority" validate:"omitempty,min=1,max=5"`
+	Done        bool   `json:"done" validate:"-"`
+	DoneVisitId uint32 `json:"done_visit_id,omitempty" validate:"-"`
+}
+
+// Wishlist represents user wishlist, open items first, by priority and planned date
+type Wishlist struct {
+	List []WishlistItem `json:"list"`
+}
+
+// WishlistFilter represent wishlist filters, empty status lists every item
+type WishlistFilter struct {
+	Status string `form:"status" validate:"omitempty,oneof=open done"`
+}
+
+// WantedLocation represent a location with the number of open wishlist items for it
+type WantedLocation struct {
+	Location
+	Wishes int `json:"wishes"`
+}
+
+// WantedLocations represents locations ranked by open wishlist items
+type WantedLocations struct {
+	List []WantedLocation `json:"list"`
+}
@@ -0,0 +1,124 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: policy.go
+
+// Package mock_policy is a generated GoMock package.
+package mock_policy
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	model "github.com/rinuccia/travels-api/internal/model"
+)
+
+// MockPolicy is a mock of Policy interface.
+type MockPolicy struct {
+	ctrl     *gomock.Controller
+	recorder *MockPolicyMockRecorder
+}
+
+// MockPolicyMockRecorder is the mock recorder for MockPolicy.
+type MockPolicyMockRecorder struct {
+	mock *MockPolicy
+}
+
+// NewMockPolicy creates a new mock instance.
+func NewMockPolicy(ctrl *gomock.Controller) *MockPolicy {
+	mock := &MockPolicy{ctrl: ctrl}
+	mock.recorder = &MockPolicyMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPolicy) EXPECT() *MockPolicyMockRecorder {
+	return m.recorder
+}
+
+// RequireRole mocks base method.
+func (m *MockPolicy) RequireRole(caller model.Identity, roles ...string) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{caller}
+	for _, a := range roles {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RequireRole", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RequireRole indicates an expected call of RequireRole.
+func (mr *MockPolicyMockRecorder) RequireRole(caller interface{}, roles ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{caller}, roles...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequireRole", reflect.TypeOf((*MockPolicy)(nil).RequireRole), varargs...)
+}
+
+// RequireScope mocks base method.
+func (m *MockPolicy) RequireScope(caller model.Identity, scope string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequireScope", caller, scope)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RequireScope indicates an expected call of RequireScope.
+func (mr *MockPolicyMockRecorder) RequireScope(caller, scope interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequireScope", reflect.TypeOf((*MockPolicy)(nil).RequireScope), caller, scope)
+}
+
+// RequireTripOwner mocks base method.
+func (m *MockPolicy) RequireTripOwner(caller model.Identity, tripId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequireTripOwner", caller, tripId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RequireTripOwner indicates an expected call of RequireTripOwner.
+func (mr *MockPolicyMockRecorder) RequireTripOwner(caller, tripId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequireTripOwner", reflect.TypeOf((*MockPolicy)(nil).RequireTripOwner), caller, tripId)
+}
+
+// RequireUser mocks base method.
+func (m *MockPolicy) RequireUser(caller model.Identity, userId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequireUser", caller, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RequireUser indicates an expected call of RequireUser.
+func (mr *MockPolicyMockRecorder) RequireUser(caller, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequireUser", reflect.TypeOf((*MockPolicy)(nil).RequireUser), caller, userId)
+}
+
+// RequireViewer mocks base method.
+func (m *MockPolicy) RequireViewer(caller model.Identity, userId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequireViewer", caller, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RequireViewer indicates an expected call of RequireViewer.
+func (mr *MockPolicyMockRecorder) RequireViewer(caller, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequireViewer", reflect.TypeOf((*MockPolicy)(nil).RequireViewer), caller, userId)
+}
+
+// RequireVisitOwner mocks base method.
+func (m *MockPolicy) RequireVisitOwner(caller model.Identity, visitId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequireVisitOwner", caller, visitId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RequireVisitOwner indicates an expected call of RequireVisitOwner.
+func (mr *MockPolicyMockRecorder) RequireVisitOwner(caller, visitId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequireVisitOwner", reflect.TypeOf((*MockPolicy)(nil).RequireVisitOwner), caller, visitId)
+}
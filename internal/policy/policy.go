@@ -0,0 +1,119 @@
+// Package policy decides whether the caller may change a resource, handlers ask it before calling services.
+package policy
+
+import (
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/internal/repository/postgres"
+	"github.com/rinuccia/travels-api/pkg/apperrors"
+	"strconv"
+)
+
+//go:generate mockgen -source=policy.go -destination=mocks/mock.go
+
+// Policy returns apperrors.ErrForbidden when the caller is denied. Admins are allowed everything.
+// API keys have a role but no user, so they are never the owner of user data.
+type Policy interface {
+	// RequireRole allows callers having one of the roles.
+	RequireRole(caller model.Identity, roles ...string) error
+
+	// RequireUser allows the user by id.
+	RequireUser(caller model.Identity, userId string) error
+
+	// RequireVisitOwner allows the user who made the visit by id.
+	RequireVisitOwner(caller model.Identity, visitId string) error
+
+	// RequireTripOwner allows the user whose trip by id it is.
+	RequireTripOwner(caller model.Identity, tripId string) error
+
+	// RequireViewer allows anyone to read the data of a public user by id,
+	// the data of a private user only the user and approved followers.
+	RequireViewer(caller model.Identity, userId string) error
+
+	// RequireScope allows users and API keys having the scope.
+	RequireScope(caller model.Identity, scope string) error
+}
+
+type policy struct {
+	repo postgres.OwnerRepository
+}
+
+func New(r postgres.OwnerRepository) Policy {
+	return &policy{
+		repo: r,
+	}
+}
+
+func (p *policy) RequireRole(caller model.Identity, roles ...string) error {
+	if caller.Role == model.RoleAdmin {
+		return nil
+	}
+	for _, role := range roles {
+		if caller.Role == role {
+			return nil
+		}
+	}
+	return apperrors.ErrForbidden
+}
+
+func (p *policy) RequireUser(caller model.Identity, userId string) error {
+	if caller.Role == model.RoleAdmin {
+		return nil
+	}
+	id, err := strconv.ParseUint(userId, 10, 32)
+	if err != nil || caller.UserId == 0 || uint32(id) != caller.UserId {
+		return apperrors.ErrForbidden
+	}
+	return nil
+}
+
+func (p *policy) RequireVisitOwner(caller model.Identity, visitId string) error {
+	return p.requireOwner(caller, visitId, p.repo.FindVisitOwner)
+}
+
+func (p *policy) RequireTripOwner(caller model.Identity, tripId string) error {
+	return p.requireOwner(caller, tripId, p.repo.FindTripOwner)
+}
+
+func (p *policy) RequireViewer(caller model.Identity, userId string) error {
+	if p.RequireUser(caller, userId) == nil {
+		return nil
+	}
+	hidden, err := p.repo.IsHidden(userId, caller.UserId)
+	if err != nil {
+		return err
+	}
+	if hidden {
+		return apperrors.ErrForbidden
+	}
+	return nil
+}
+
+func (p *policy) RequireScope(caller model.Identity, scope string) error {
+	if caller.APIKeyId == 0 {
+		return nil
+	}
+	for _, s := range caller.Scopes {
+		if s == scope {
+			return nil
+		}
+	}
+	return apperrors.ErrForbidden
+}
+
+// requireOwner skips the lookup for admins, other callers get apperrors.ErrRecordNotFound for a missing resource.
+func (p *policy) requireOwner(caller model.Identity, id string, findOwner func(string) (uint32, error)) error {
+	if caller.Role == model.RoleAdmin {
+		return nil
+	}
+	if caller.UserId == 0 {
+		return apperrors.ErrForbidden
+	}
+	owner, err := findOwner(id)
+	if err != nil {
+		return err
+	}
+	if owner != caller.UserId {
+		return apperrors.ErrForbidden
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package policy
+
+import (
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/pkg/apperrors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// owners maps visit and trip ids to their users and "private:<user id>" to the approved follower of a private user.
+type owners map[string]uint32
+
+func (o owners) FindVisitOwner(visitId string) (uint32, error) {
+	return o.find(visitId)
+}
+
+func (o owners) FindTripOwner(tripId string) (uint32, error) {
+	return o.find(tripId)
+}
+
+func (o owners) IsHidden(userId string, viewerId uint32) (bool, error) {
+	follower, private := o["private:"+userId]
+	return private && (follower == 0 || follower != viewerId), nil
+}
+
+func (o owners) find(id string) (uint32, error) {
+	owner, ok := o[id]
+	if !ok {
+		return 0, apperrors.ErrRecordNotFound
+	}
+	return owner, nil
+}
+
+var (
+	traveller = model.Identity{UserId: 1, Role: model.RoleTraveller}
+	curator   = model.Identity{UserId: 2, Role: model.RoleCurator}
+	admin     = model.Identity{UserId: 3, Role: model.RoleAdmin}
+	// curatorKey is an API key, it has a role but no user
+	curatorKey = model.Identity{APIKeyId: 4, Role: model.RoleCurator, Scopes: []string{model.ScopeVisitsWrite}}
+)
+
+func TestPolicy_RequireRole(t *testing.T) {
+	p := New(owners{})
+
+	assert.NoError(t, p.RequireRole(curator, model.RoleCurator))
+	assert.NoError(t, p.RequireRole(admin, model.RoleCurator))
+	assert.ErrorIs(t, p.RequireRole(traveller, model.RoleCurator), apperrors.ErrForbidden)
+	assert.ErrorIs(t, p.RequireRole(curator, model.RoleAdmin), apperrors.ErrForbidden)
+	assert.ErrorIs(t, p.RequireRole(model.Identity{}, model.RoleTraveller), apperrors.ErrForbidden)
+	assert.NoError(t, p.RequireRole(curatorKey, model.RoleCurator))
+	assert.ErrorIs(t, p.RequireRole(curatorKey, model.RoleAdmin), apperrors.ErrForbidden)
+}
+
+func TestPolicy_RequireUser(t *testing.T) {
+	p := New(owners{})
+
+	testTable := []struct {
+		name    string
+		caller  model.Identity
+		userId  string
+		wantErr bool
+	}{
+		{name: "Self", caller: traveller, userId: "1"},
+		{name: "Admin", caller: admin, userId: "1"},
+		{name: "Other User", caller: traveller, userId: "2", wantErr: true},
+		{name: "Curator", caller: curator, userId: "1", wantErr: true},
+		{name: "Invalid Id", caller: traveller, userId: "one", wantErr: true},
+		{name: "API Key", caller: curatorKey, userId: "1", wantErr: true},
+		{name: "API Key Without User Id", caller: curatorKey, userId: "0", wantErr: true},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			err := p.RequireUser(tt.caller, tt.userId)
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, apperrors.ErrForbidden)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestPolicy_RequireVisitOwner(t *testing.T) {
+	p := New(owners{"10": 1})
+
+	testTable := []struct {
+		name    string
+		caller  model.Identity
+		visitId string
+		wantErr error
+	}{
+		{name: "Owner", caller: traveller, visitId: "10"},
+		{name: "Admin", caller: admin, visitId: "10"},
+		{name: "Admin Missing Visit", caller: admin, visitId: "11"},
+		{name: "Other User", caller: curator, visitId: "10", wantErr: apperrors.ErrForbidden},
+		{name: "Missing Visit", caller: traveller, visitId: "11", wantErr: apperrors.ErrRecordNotFound},
+		{name: "API Key", caller: curatorKey, visitId: "10", wantErr: apperrors.ErrForbidden},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			err := p.RequireVisitOwner(tt.caller, tt.visitId)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestPolicy_RequireViewer(t *testing.T) {
+	p := New(owners{"private:5": curator.UserId, "private:6": 0})
+
+	testTable := []struct {
+		name    string
+		caller  model.Identity
+		userId  string
+		wantErr bool
+	}{
+		{name: "Public User", caller: model.Identity{}, userId: "4"},
+		{name: "Self", caller: model.Identity{UserId: 5, Role: model.RoleTraveller}, userId: "5"},
+		{name: "Admin", caller: admin, userId: "5"},
+		{name: "Approved Follower", caller: curator, userId: "5"},
+		{name: "Other User", caller: traveller, userId: "5", wantErr: true},
+		{name: "Anonymous", caller: model.Identity{}, userId: "5", wantErr: true},
+		{name: "API Key", caller: curatorKey, userId: "6", wantErr: true},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			err := p.RequireViewer(tt.caller, tt.userId)
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, apperrors.ErrForbidden)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestPolicy_RequireScope(t *testing.T) {
+	p := New(owners{})
+	key := model.Identity{APIKeyId: 1, Scopes: []string{model.ScopeVisitsWrite}}
+
+	assert.NoError(t, p.RequireScope(key, model.ScopeVisitsWrite))
+	assert.NoError(t, p.RequireScope(traveller, model.ScopeVisitsWrite))
+	assert.ErrorIs(t, p.RequireScope(key, model.ScopeLocationsWrite), apperrors.ErrForbidden)
+}
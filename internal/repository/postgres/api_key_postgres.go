@@ -0,0 +1,80 @@
+package postgres
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/pkg/apperrors"
+)
+
+type apiKeyRepo struct {
+	*sqlx.DB
+}
+
+func newAPIKeyRepo(db *sqlx.DB) *apiKeyRepo {
+	return &apiKeyRepo{db}
+}
+
+func (r *apiKeyRepo) FindAll() (model.APIKeys, error) {
+	query := `
+			SELECT key_id, name, prefix, scopes, role, expires_at, created_at, revoked_at, last_used_at, usage_count
+			FROM api_keys
+			WHERE tenant_id = current_tenant()
+			ORDER BY key_id`
+	keys := model.APIKeys{List: []model.APIKey{}}
+	rows, err := r.Query(query)
+	if err != nil {
+		return keys, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		k := model.APIKey{}
+		err = rows.Scan(&k.KeyId, &k.Name, &k.Prefix, pq.Array(&k.Scopes), &k.Role, &k.ExpiresAt, &k.CreatedAt,
+			&k.RevokedAt, &k.LastUsedAt, &k.UsageCount)
+		if err != nil {
+			return keys, err
+		}
+		keys.List = append(keys.List, k)
+	}
+	return keys, rows.Err()
+}
+
+func (r *apiKeyRepo) Insert(key model.APIKey, keyHash string) (model.APIKey, error) {
+	query := `
+			INSERT INTO api_keys (name, key_hash, prefix, scopes, role, expires_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING key_id, created_at`
+	row := r.QueryRow(query, key.Name, keyHash, key.Prefix, pq.Array(key.Scopes), key.Role, key.ExpiresAt)
+	if err := row.Scan(&key.KeyId, &key.CreatedAt); err != nil {
+		return key, err
+	}
+	return key, nil
+}
+
+func (r *apiKeyRepo) Revoke(id string) error {
+	query := "UPDATE api_keys SET revoked_at = now() WHERE key_id = $1 AND revoked_at IS NULL AND tenant_id = current_tenant()"
+	res, err := r.Exec(query, id)
+	if err != nil {
+		return apperrors.ErrIncorrectQuery
+	}
+	if rowsAff, err := res.RowsAffected(); rowsAff == 0 && err == nil {
+		return apperrors.ErrRecordNotFound
+	}
+	return err
+}
+
+// Use counts the use in the same statement that checks the key, so concurrent requests are all counted.
+func (r *apiKeyRepo) Use(keyHash string) (model.Identity, error) {
+	query := `
+			UPDATE api_keys SET last_used_at = now(), usage_count = usage_count + 1
+			WHERE key_hash = $1 AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > now())
+			  AND tenant_id = current_tenant()
+			RETURNING key_id, scopes, role`
+	identity := model.Identity{}
+	row := r.QueryRow(query, keyHash)
+	if err := row.Scan(&identity.APIKeyId, pq.Array(&identity.Scopes), &identity.Role); err != nil {
+		return identity, apperrors.ErrRecordNotFound
+	}
+	return identity, nil
+}
@@ -0,0 +1,165 @@
+package postgres
+
+import (
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/pkg/apperrors"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	sqlmock "github.com/zhashkevych/go-sqlxmock"
+	"testing"
+	"time"
+)
+
+func TestAPIKeyRepo_FindAll(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	repository := newAPIKeyRepo(db)
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	usedAt := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	rows := sqlmock.NewRows([]string{"key_id", "name", "prefix", "scopes", "role", "expires_at", "created_at",
+		"revoked_at", "last_used_at", "usage_count"}).
+		AddRow(1, "partner", "tk_abcdefgh", "{locations:write,visits:write}", "curator", nil, createdAt, nil, usedAt, 42).
+		AddRow(2, "old", "tk_12345678", "{visits:write}", "admin", nil, createdAt, usedAt, nil, 0)
+	mock.ExpectQuery("SELECT (.+) FROM api_keys WHERE tenant_id = current_tenant\\(\\) ORDER BY key_id").WillReturnRows(rows)
+
+	got, err := repository.FindAll()
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.APIKeys{List: []model.APIKey{
+		{
+			KeyId: 1, Name: "partner", Prefix: "tk_abcdefgh", Scopes: []string{"locations:write", "visits:write"},
+			Role: "curator", CreatedAt: createdAt, LastUsedAt: &usedAt, UsageCount: 42,
+		},
+		{
+			KeyId: 2, Name: "old", Prefix: "tk_12345678", Scopes: []string{"visits:write"}, Role: "admin",
+			CreatedAt: createdAt, RevokedAt: &usedAt,
+		},
+	}}, got)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAPIKeyRepo_Insert(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	repository := newAPIKeyRepo(db)
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	key := model.APIKey{Name: "partner", Prefix: "tk_abcdefgh", Scopes: []string{"visits:write"}, Role: "curator"}
+
+	mock.ExpectQuery("INSERT INTO api_keys (.+) RETURNING key_id, created_at").
+		WithArgs("partner", "hash", "tk_abcdefgh", "{\"visits:write\"}", "curator", nil).
+		WillReturnRows(sqlmock.NewRows([]string{"key_id", "created_at"}).AddRow(1, createdAt))
+
+	got, err := repository.Insert(key, "hash")
+
+	key.KeyId = 1
+	key.CreatedAt = createdAt
+	assert.NoError(t, err)
+	assert.Equal(t, key, got)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAPIKeyRepo_Revoke(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	repository := newAPIKeyRepo(db)
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		wantErr bool
+	}{
+		{
+			name: "Ok",
+			mock: func() {
+				mock.ExpectExec("UPDATE api_keys SET revoked_at = now\\(\\) WHERE key_id = (.+) AND revoked_at IS NULL").
+					WithArgs("1").WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+		},
+		{
+			name: "Already Revoked",
+			mock: func() {
+				mock.ExpectExec("UPDATE api_keys SET revoked_at").
+					WithArgs("1").WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			err := repository.Revoke("1")
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, apperrors.ErrRecordNotFound)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestAPIKeyRepo_Use(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	repository := newAPIKeyRepo(db)
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		want    model.Identity
+		wantErr bool
+	}{
+		{
+			name: "Ok",
+			mock: func() {
+				mock.ExpectQuery("UPDATE api_keys SET last_used_at = now\\(\\), usage_count = usage_count \\+ 1 " +
+					"WHERE key_hash = (.+) AND revoked_at IS NULL AND \\(expires_at IS NULL OR expires_at > now\\(\\)\\)").
+					WithArgs("hash").
+					WillReturnRows(sqlmock.NewRows([]string{"key_id", "scopes", "role"}).AddRow(1, "{visits:write}", "admin"))
+			},
+			want: model.Identity{APIKeyId: 1, Scopes: []string{"visits:write"}, Role: "admin"},
+		},
+		{
+			name: "Revoked Or Expired",
+			mock: func() {
+				mock.ExpectQuery("UPDATE api_keys SET last_used_at").
+					WithArgs("hash").WillReturnRows(sqlmock.NewRows([]string{"key_id", "scopes", "role"}))
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := repository.Use("hash")
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, apperrors.ErrRecordNotFound)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/jmoiron/sqlx"
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/pkg/apperrors"
+	"strconv"
+	"time"
+)
+
+// auditSnapshots select the audited state of an entity by id and lock its row until the change commits.
+// Password hashes are left out of user snapshots, deleted visits have no state. The tenant is left out as the audit
+// log of a tenant only holds its own entities.
+var auditSnapshots = map[string]string{
+	model.AuditEntityUser: `
+			SELECT to_jsonb(u) - 'password_hash' - 'tenant_id'
+			FROM users u
+			WHERE u.user_id = $1 AND u.tenant_id = current_tenant()
+			FOR UPDATE`,
+	model.AuditEntityLocation: `
+			SELECT to_jsonb(l) - 'tenant_id' || jsonb_build_object('tags', ARRAY(SELECT t.name
+																				 FROM location_tags lt
+																					 JOIN tags t
+																						 ON t.tag_id = lt.tag_id
+																						 AND t.tenant_id = lt.tenant_id
+																				 WHERE lt.location_id = l.location_id
+																				   AND lt.tenant_id = l.tenant_id
+																				 ORDER BY t.name))
+			FROM locations l
+			WHERE l.location_id = $1 AND l.tenant_id = current_tenant()
+			FOR UPDATE OF l`,
+	model.AuditEntityVisit: `
+			SELECT to_jsonb(v) - 'tenant_id'
+			FROM visits v
+			WHERE v.visit_id = $1 AND v.deleted_at IS NULL AND v.tenant_id = current_tenant()
+			FOR UPDATE`,
+}
+
+type auditRepo struct {
+	*sqlx.DB
+}
+
+func newAuditRepo(db *sqlx.DB) *auditRepo {
+	return &auditRepo{db}
+}
+
+func (r *auditRepo) FindAll(filter model.AuditFilter) (model.AuditLog, error) {
+	query := `
+			SELECT audit_id, actor, action, entity_type, entity_id, before, after, request_id, created_at
+			FROM audit_log
+			WHERE tenant_id = current_tenant()
+			  AND ($1 = '' OR entity_type = $1)
+			  AND ($2 = '' OR entity_id = $2)
+			  AND ($3 = '' OR actor = $3)
+			  AND ($4::timestamptz IS NULL OR created_at >= $4)
+			  AND ($5::timestamptz IS NULL OR created_at < $5)
+			ORDER BY audit_id DESC
+			LIMIT $6 OFFSET $7`
+	log := model.AuditLog{List: []model.AuditRecord{}, Page: filter.Page, PerPage: filter.PerPage}
+	rows, err := r.Query(query, filter.Entity, filter.EntityId, filter.Actor, nullTime(filter.From),
+		nullTime(filter.To), filter.PerPage, (filter.Page-1)*filter.PerPage)
+	if err != nil {
+		return log, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		a := model.AuditRecord{}
+		// states are scanned as bytes first, json.RawMessage can't hold the NULL of a missing state
+		var before, after []byte
+		err = rows.Scan(&a.AuditId, &a.Actor, &a.Action, &a.EntityType, &a.EntityId, &before, &after,
+			&a.RequestId, &a.CreatedAt)
+		if err != nil {
+			return log, err
+		}
+		a.Before, a.After = before, after
+		log.List = append(log.List, a)
+	}
+	return log, rows.Err()
+}
+
+// audited runs change in a transaction and records the entity state before and after it in the audit log
+// within the same transaction. The action follows from the states: no state before is a create,
+// no state after is a delete.
+func audited(db *sqlx.DB, actor model.Actor, entity, id string, change func(tx *sqlx.Tx) error) error {
+	return auditedAs(db, actor, "", entity, id, change)
+}
+
+// auditedAs is audited with the recorded action given, an empty action follows from the states.
+func auditedAs(db *sqlx.DB, actor model.Actor, action, entity, id string, change func(tx *sqlx.Tx) error) error {
+	tx, err := db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	before, err := snapshot(tx, entity, id)
+	if err != nil {
+		return apperrors.ErrIncorrectQuery
+	}
+	if err = change(tx); err != nil {
+		return err
+	}
+	after, err := snapshot(tx, entity, id)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case action != "":
+	case before == nil:
+		action = model.AuditActionCreate
+	case after == nil:
+		action = model.AuditActionDelete
+	default:
+		action = model.AuditActionUpdate
+	}
+	query := `
+			INSERT INTO audit_log (actor, action, entity_type, entity_id, before, after, request_id)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	_, err = tx.Exec(query, actor.Actor, action, entity, id, nullJSON(before), nullJSON(after), actor.RequestId)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// snapshot returns the audited state of the entity, nil when it doesn't exist.
+func snapshot(tx *sqlx.Tx, entity, id string) ([]byte, error) {
+	var state []byte
+	err := tx.QueryRow(auditSnapshots[entity], id).Scan(&state)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return state, err
+}
+
+// nullJSON stores a missing state as NULL rather than as an empty document.
+func nullJSON(state []byte) interface{} {
+	if state == nil {
+		return nil
+	}
+	return string(state)
+}
+
+// nullTime leaves an unset time bound NULL.
+func nullTime(t time.Time) interface{} {
+	if t.IsZero() {
+		return nil
+	}
+	return t
+}
+
+// entityId formats a numeric entity id as stored in the audit log.
+func entityId(id uint32) string {
+	return strconv.FormatUint(uint64(id), 10)
+}
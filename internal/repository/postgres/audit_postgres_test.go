@@ -0,0 +1,105 @@
+package postgres
+
+import (
+	"encoding/json"
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	sqlmock "github.com/zhashkevych/go-sqlxmock"
+	"testing"
+	"time"
+)
+
+var testActor = model.Actor{Actor: "user:1", RequestId: "req-1"}
+
+// expectSnapshot expects the audit snapshot of the row in table, state "" means the row doesn't exist.
+func expectSnapshot(mock sqlmock.Sqlmock, table, id, state string) {
+	rows := sqlmock.NewRows([]string{"to_jsonb"})
+	if state != "" {
+		rows.AddRow([]byte(state))
+	}
+	mock.ExpectQuery("SELECT to_jsonb(.+) FROM " + table).WithArgs(id).WillReturnRows(rows)
+}
+
+// expectAuditInsert expects the audit record of testActor, nil states are stored as NULL.
+func expectAuditInsert(mock sqlmock.Sqlmock, action, entity, id string, before, after interface{}) {
+	mock.ExpectExec("INSERT INTO audit_log").
+		WithArgs(testActor.Actor, action, entity, id, before, after, testActor.RequestId).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+}
+
+func TestAuditRepo_FindAll(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	repository := newAuditRepo(db)
+	createdAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	from := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	columns := []string{"audit_id", "actor", "action", "entity_type", "entity_id", "before", "after", "request_id", "created_at"}
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		filter  model.AuditFilter
+		want    model.AuditLog
+		wantErr bool
+	}{
+		{
+			name: "Ok",
+			mock: func() {
+				rows := sqlmock.NewRows(columns).
+					AddRow(2, "user:1", "update", "visit", "7", []byte(`{"mark": 3}`), []byte(`{"mark": 5}`), "req-2", createdAt).
+					AddRow(1, "apikey:3", "create", "visit", "7", nil, []byte(`{"mark": 3}`), "", createdAt)
+				mock.ExpectQuery("SELECT (.+) FROM audit_log WHERE (.+) ORDER BY audit_id DESC LIMIT (.+) OFFSET (.+)").
+					WithArgs("visit", "7", "", from, nil, 100, 0).WillReturnRows(rows)
+			},
+			filter: model.AuditFilter{Entity: "visit", EntityId: "7", From: from, Page: 1, PerPage: 100},
+			want: model.AuditLog{
+				List: []model.AuditRecord{
+					{AuditId: 2, Actor: "user:1", Action: "update", EntityType: "visit", EntityId: "7",
+						Before: json.RawMessage(`{"mark": 3}`), After: json.RawMessage(`{"mark": 5}`), RequestId: "req-2", CreatedAt: createdAt},
+					{AuditId: 1, Actor: "apikey:3", Action: "create", EntityType: "visit", EntityId: "7",
+						After: json.RawMessage(`{"mark": 3}`), CreatedAt: createdAt},
+				},
+				Page:    1,
+				PerPage: 100,
+			},
+		},
+		{
+			name: "Second Page",
+			mock: func() {
+				mock.ExpectQuery("SELECT (.+) FROM audit_log").
+					WithArgs("", "", "user:1", nil, nil, 10, 10).WillReturnRows(sqlmock.NewRows(columns))
+			},
+			filter: model.AuditFilter{Actor: "user:1", Page: 2, PerPage: 10},
+			want:   model.AuditLog{List: []model.AuditRecord{}, Page: 2, PerPage: 10},
+		},
+		{
+			name: "Query Error",
+			mock: func() {
+				mock.ExpectQuery("SELECT (.+) FROM audit_log").
+					WithArgs("", "", "", nil, nil, 100, 0).WillReturnError(sqlmock.ErrCancelled)
+			},
+			filter:  model.AuditFilter{Page: 1, PerPage: 100},
+			wantErr: true,
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := repository.FindAll(tt.filter)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/jmoiron/sqlx"
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/pkg/apperrors"
+)
+
+type authRepo struct {
+	*sqlx.DB
+}
+
+func newAuthRepo(db *sqlx.DB) *authRepo {
+	return &authRepo{db}
+}
+
+// Register records the new user in the audit log like userRepo.Insert, the password hash is never recorded.
+func (r *authRepo) Register(actor model.Actor, user model.User, passwordHash []byte) (model.User, error) {
+	err := audited(r.DB, actor, model.AuditEntityUser, entityId(user.UserId), func(tx *sqlx.Tx) error {
+		query := `
+			INSERT INTO users (user_id, email, first_name, last_name, gender, private, password_hash)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)`
+		_, err := tx.Exec(query, user.UserId, user.Email, user.FirstName, user.LastName, user.Gender, user.Private,
+			passwordHash)
+		if err != nil {
+			return apperrors.ErrIncorrectQuery
+		}
+		return nil
+	})
+	return user, err
+}
+
+// FindCredentials skips users created without a password, they cannot log in.
+func (r *authRepo) FindCredentials(email string) (model.UserCredentials, error) {
+	credentials := model.UserCredentials{}
+	query := `
+			SELECT user_id, password_hash
+			FROM users
+			WHERE lower(email) = lower($1) AND password_hash IS NOT NULL AND tenant_id = current_tenant()`
+	row := r.QueryRow(query, email)
+	if err := row.Scan(&credentials.UserId, &credentials.PasswordHash); err != nil {
+		return credentials, apperrors.ErrRecordNotFound
+	}
+	return credentials, nil
+}
+
+func (r *authRepo) FindIdentity(userId uint32) (model.Identity, error) {
+	identity := model.Identity{}
+	query := "SELECT user_id, role FROM users WHERE user_id = $1 AND erased_at IS NULL AND tenant_id = current_tenant()"
+	row := r.QueryRow(query, userId)
+	if err := row.Scan(&identity.UserId, &identity.Role); err != nil {
+		return identity, apperrors.ErrRecordNotFound
+	}
+	return identity, nil
+}
+
+func (r *authRepo) InsertRefreshToken(token model.RefreshToken) error {
+	query := "INSERT INTO refresh_tokens (token_hash, user_id, expires_at) VALUES ($1, $2, $3)"
+	_, err := r.Exec(query, token.TokenHash, token.UserId, token.ExpiresAt)
+	return err
+}
+
+// RotateRefreshToken consumes the unexpired token with oldHash and stores next for the same user in one transaction,
+// so a refresh token works only once.
+func (r *authRepo) RotateRefreshToken(oldHash string, next model.RefreshToken) (model.RefreshToken, error) {
+	tx, err := r.Beginx()
+	if err != nil {
+		return next, err
+	}
+	defer tx.Rollback()
+
+	query := `
+			DELETE FROM refresh_tokens
+			WHERE token_hash = $1 AND expires_at > now() AND tenant_id = current_tenant()
+			RETURNING user_id`
+	row := tx.QueryRow(query, oldHash)
+	err = row.Scan(&next.UserId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return next, apperrors.ErrRecordNotFound
+	}
+	if err != nil {
+		return next, err
+	}
+
+	query = "INSERT INTO refresh_tokens (token_hash, user_id, expires_at) VALUES ($1, $2, $3)"
+	if _, err = tx.Exec(query, next.TokenHash, next.UserId, next.ExpiresAt); err != nil {
+		return next, err
+	}
+	return next, tx.Commit()
+}
+
+func (r *authRepo) DeleteRefreshToken(tokenHash string) error {
+	res, err := r.Exec("DELETE FROM refresh_tokens WHERE token_hash = $1 AND tenant_id = current_tenant()", tokenHash)
+	if err != nil {
+		return err
+	}
+	if rowsAff, err := res.RowsAffected(); rowsAff == 0 && err == nil {
+		return apperrors.ErrRecordNotFound
+	}
+	return err
+}
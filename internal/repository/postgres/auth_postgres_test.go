@@ -0,0 +1,226 @@
+package postgres
+
+import (
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/pkg/apperrors"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	sqlmock "github.com/zhashkevych/go-sqlxmock"
+	"testing"
+	"time"
+)
+
+func TestAuthRepo_FindCredentials(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	repository := newAuthRepo(db)
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		want    model.UserCredentials
+		wantErr bool
+	}{
+		{
+			name: "Ok",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"user_id", "password_hash"}).AddRow(1, []byte("hash"))
+				mock.ExpectQuery("SELECT user_id, password_hash FROM users WHERE lower\\(email\\) = lower\\((.+)\\) AND password_hash IS NOT NULL").
+					WithArgs("test@gmail.com").WillReturnRows(rows)
+			},
+			want: model.UserCredentials{UserId: 1, PasswordHash: []byte("hash")},
+		},
+		{
+			name: "Not Found",
+			mock: func() {
+				mock.ExpectQuery("SELECT user_id, password_hash FROM users").
+					WithArgs("test@gmail.com").WillReturnRows(sqlmock.NewRows([]string{"user_id", "password_hash"}))
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := repository.FindCredentials("test@gmail.com")
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, apperrors.ErrRecordNotFound)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestAuthRepo_FindIdentity(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	repository := newAuthRepo(db)
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		want    model.Identity
+		wantErr bool
+	}{
+		{
+			name: "Ok",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"user_id", "role"}).AddRow(1, "curator")
+				mock.ExpectQuery("SELECT user_id, role FROM users WHERE user_id = (.+)").
+					WithArgs(uint32(1)).WillReturnRows(rows)
+			},
+			want: model.Identity{UserId: 1, Role: model.RoleCurator},
+		},
+		{
+			name: "Deleted User",
+			mock: func() {
+				mock.ExpectQuery("SELECT user_id, role FROM users").
+					WithArgs(uint32(1)).WillReturnRows(sqlmock.NewRows([]string{"user_id", "role"}))
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := repository.FindIdentity(1)
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, apperrors.ErrRecordNotFound)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestAuthRepo_Register(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	repository := newAuthRepo(db)
+	user := model.User{UserId: 1, Email: "test@gmail.com", FirstName: "John", LastName: "Smith", Gender: "m"}
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		wantErr bool
+	}{
+		{
+			name: "Ok",
+			mock: func() {
+				mock.ExpectBegin()
+				expectSnapshot(mock, "users", "1", "")
+				mock.ExpectExec("INSERT INTO users (.+) password_hash").
+					WithArgs(1, "test@gmail.com", "John", "Smith", "m", false, []byte("hash")).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				expectSnapshot(mock, "users", "1", `{"user_id": 1}`)
+				expectAuditInsert(mock, "create", "user", "1", nil, `{"user_id": 1}`)
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "Email Taken",
+			mock: func() {
+				mock.ExpectBegin()
+				expectSnapshot(mock, "users", "1", "")
+				mock.ExpectExec("INSERT INTO users").
+					WithArgs(1, "test@gmail.com", "John", "Smith", "m", false, []byte("hash")).
+					WillReturnError(apperrors.ErrIncorrectQuery)
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := repository.Register(testActor, user, []byte("hash"))
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, apperrors.ErrIncorrectQuery)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, user, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestAuthRepo_RotateRefreshToken(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	repository := newAuthRepo(db)
+	expiresAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	next := model.RefreshToken{TokenHash: "new", ExpiresAt: expiresAt}
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		want    model.RefreshToken
+		wantErr bool
+	}{
+		{
+			name: "Ok",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("DELETE FROM refresh_tokens WHERE token_hash = (.+) AND expires_at > now\\(\\) AND tenant_id = current_tenant\\(\\) RETURNING user_id").
+					WithArgs("old").WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(1))
+				mock.ExpectExec("INSERT INTO refresh_tokens").
+					WithArgs("new", uint32(1), expiresAt).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			want: model.RefreshToken{TokenHash: "new", UserId: 1, ExpiresAt: expiresAt},
+		},
+		{
+			name: "Used Or Expired",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("DELETE FROM refresh_tokens").
+					WithArgs("old").WillReturnRows(sqlmock.NewRows([]string{"user_id"}))
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := repository.RotateRefreshToken("old", next)
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, apperrors.ErrRecordNotFound)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
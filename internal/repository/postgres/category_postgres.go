@@ -0,0 +1,99 @@
+package postgres
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/pkg/apperrors"
+)
+
+type categoryRepo struct {
+	*sqlx.DB
+}
+
+func newCategoryRepo(db *sqlx.DB) *categoryRepo {
+	return &categoryRepo{db}
+}
+
+func (r *categoryRepo) FindAll() (model.Categories, error) {
+	query := "SELECT category_id, name FROM categories WHERE deleted_at IS NULL AND tenant_id = current_tenant() ORDER BY name"
+	category := model.Category{}
+	categories := model.Categories{}
+	rows, err := r.Query(query)
+	if err != nil {
+		return categories, err
+	}
+	for rows.Next() {
+		err = rows.Scan(&category.CategoryId, &category.Name)
+		if err != nil {
+			return categories, err
+		}
+		categories.List = append(categories.List, category)
+	}
+	return categories, err
+}
+
+func (r *categoryRepo) FindById(id string) (model.Category, error) {
+	query := `
+			SELECT category_id, name
+			FROM categories
+			WHERE category_id = $1 AND deleted_at IS NULL AND tenant_id = current_tenant()`
+	category := model.Category{}
+	row := r.QueryRow(query, id)
+	err := row.Scan(&category.CategoryId, &category.Name)
+	if err != nil {
+		return category, apperrors.ErrRecordNotFound
+	}
+	return category, err
+}
+
+func (r *categoryRepo) Insert(category model.Category) (model.Category, error) {
+	query := "INSERT INTO categories (category_id, name) VALUES ($1, $2)"
+	_, err := r.Exec(query, category.CategoryId, category.Name)
+	if err != nil {
+		return category, apperrors.ErrIncorrectQuery
+	}
+	return category, err
+}
+
+func (r *categoryRepo) Update(id string, category model.Category) error {
+	query := `
+			UPDATE categories SET name = $1
+			WHERE category_id = $2 AND deleted_at IS NULL AND tenant_id = current_tenant()`
+	res, err := r.Exec(query, category.Name, id)
+	if err != nil {
+		return apperrors.ErrIncorrectQuery
+	}
+	if rowsAff, err := res.RowsAffected(); rowsAff == 0 && err == nil {
+		return apperrors.ErrRecordNotFound
+	}
+	return err
+}
+
+func (r *categoryRepo) DeleteById(id string) error {
+	query := `
+			UPDATE categories SET deleted_at = now()
+			WHERE category_id = $1 AND deleted_at IS NULL AND tenant_id = current_tenant()`
+	res, err := r.Exec(query, id)
+	if err != nil {
+		return err
+	}
+	rowsAff, _ := res.RowsAffected()
+	if rowsAff == 0 {
+		return apperrors.ErrRecordNotFound
+	}
+	return err
+}
+
+func (r *categoryRepo) Restore(id string) error {
+	query := `
+			UPDATE categories SET deleted_at = NULL
+			WHERE category_id = $1 AND deleted_at IS NOT NULL AND tenant_id = current_tenant()`
+	res, err := r.Exec(query, id)
+	if err != nil {
+		return apperrors.ErrIncorrectQuery
+	}
+	if rowsAff, err := res.RowsAffected(); rowsAff == 0 && err == nil {
+		return apperrors.ErrRecordNotFound
+	}
+	return err
+}
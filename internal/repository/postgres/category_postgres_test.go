@@ -0,0 +1,213 @@
+package postgres
+
+import (
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/pkg/apperrors"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	sqlmock "github.com/zhashkevych/go-sqlxmock"
+	"testing"
+)
+
+func TestCategoryRepo_FindAll(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	repository := newCategoryRepo(db)
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		want    model.Categories
+		wantErr bool
+	}{
+		{
+			name: "Ok",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"category_id", "name"}).
+					AddRow(1, "monument").
+					AddRow(2, "museum")
+				mock.ExpectQuery("SELECT (.+) FROM categories").WillReturnRows(rows)
+			},
+			want: model.Categories{
+				List: []model.Category{
+					{CategoryId: 1, Name: "monument"},
+					{CategoryId: 2, Name: "museum"},
+				},
+			},
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			got, err := repository.FindAll()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestCategoryRepo_Update(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	repository := newCategoryRepo(db)
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		id      string
+		input   model.Category
+		wantErr error
+	}{
+		{
+			name: "Ok",
+			mock: func() {
+				mock.ExpectExec("UPDATE categories SET (.+) WHERE (.+)").
+					WithArgs("nature", "1").WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			id:    "1",
+			input: model.Category{CategoryId: 1, Name: "nature"},
+		},
+		{
+			name: "Not Found",
+			mock: func() {
+				mock.ExpectExec("UPDATE categories SET (.+) WHERE (.+)").
+					WithArgs("nature", "1").WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			id:      "1",
+			input:   model.Category{CategoryId: 1, Name: "nature"},
+			wantErr: apperrors.ErrRecordNotFound,
+		},
+		{
+			name: "Name Taken",
+			mock: func() {
+				mock.ExpectExec("UPDATE categories SET (.+) WHERE (.+)").
+					WithArgs("museum", "1").WillReturnError(apperrors.ErrIncorrectQuery)
+			},
+			id:      "1",
+			input:   model.Category{CategoryId: 1, Name: "museum"},
+			wantErr: apperrors.ErrIncorrectQuery,
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			err := repository.Update(tt.id, tt.input)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestCategoryRepo_DeleteById(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	repository := newCategoryRepo(db)
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		id      string
+		wantErr bool
+	}{
+		{
+			name: "Ok",
+			mock: func() {
+				mock.ExpectExec("UPDATE categories SET deleted_at = now\\(\\) WHERE (.+) AND deleted_at IS NULL").WithArgs("1").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+			id: "1",
+		},
+		{
+			name: "Not Found",
+			mock: func() {
+				mock.ExpectExec("UPDATE categories SET deleted_at = now\\(\\) WHERE (.+) AND deleted_at IS NULL").WithArgs("1").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			id:      "1",
+			wantErr: true,
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			err = repository.DeleteById(tt.id)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestCategoryRepo_Restore(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	repository := newCategoryRepo(db)
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		wantErr error
+	}{
+		{
+			name: "Ok",
+			mock: func() {
+				mock.ExpectExec("UPDATE categories SET deleted_at = NULL WHERE (.+) AND deleted_at IS NOT NULL").WithArgs("1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "Name Taken",
+			mock: func() {
+				mock.ExpectExec("UPDATE categories SET deleted_at = NULL").WithArgs("1").
+					WillReturnError(sqlmock.ErrCancelled)
+			},
+			wantErr: apperrors.ErrIncorrectQuery,
+		},
+		{
+			name: "Not Deleted",
+			mock: func() {
+				mock.ExpectExec("UPDATE categories SET deleted_at = NULL").WithArgs("1").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantErr: apperrors.ErrRecordNotFound,
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			err := repository.Restore("1")
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package postgres
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/pkg/countries"
+)
+
+// seedCountries loads the embedded ISO 3166-1 dataset, existing rows are refreshed.
+func seedCountries(db *sqlx.DB) error {
+	all := countries.All()
+	codes := make([]string, 0, len(all))
+	alpha3 := make([]string, 0, len(all))
+	names := make([]string, 0, len(all))
+	for _, c := range all {
+		codes = append(codes, c.Alpha2)
+		alpha3 = append(alpha3, c.Alpha3)
+		names = append(names, c.Name)
+	}
+	query := `
+			INSERT INTO countries (code, alpha3, name)
+			SELECT * FROM unnest($1::char(2)[], $2::char(3)[], $3::varchar[])
+			ON CONFLICT (code) DO UPDATE SET alpha3 = EXCLUDED.alpha3, name = EXCLUDED.name`
+	_, err := db.Exec(query, pq.Array(codes), pq.Array(alpha3), pq.Array(names))
+	return err
+}
+
+// MigrateLocationCountries maps free-text countries of locations without a country code to ISO codes.
+// Matched rows get the code and the canonical name, the rest are left untouched and returned.
+func MigrateLocationCountries(db *sqlx.DB) ([]model.UnmatchedCountry, error) {
+	query := "SELECT tenant_id, location_id, country FROM locations WHERE country_code IS NULL ORDER BY tenant_id, location_id"
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// location ids are unique per tenant only
+	type tenantCountry struct {
+		tenantId uint32
+		code     string
+	}
+	var unmatched []model.UnmatchedCountry
+	matched := map[tenantCountry][]uint32{}
+	for rows.Next() {
+		var tenantId, id uint32
+		var country string
+		if err = rows.Scan(&tenantId, &id, &country); err != nil {
+			return nil, err
+		}
+		c, ok := countries.Lookup(country)
+		if !ok {
+			unmatched = append(unmatched, model.UnmatchedCountry{TenantId: tenantId, LocationId: id, Country: country})
+			continue
+		}
+		key := tenantCountry{tenantId, c.Alpha2}
+		matched[key] = append(matched[key], id)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	tx, err := db.Beginx()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	query = `
+			UPDATE locations
+			SET country_code = countries.code, country = countries.name, updated_at = now()
+			FROM countries
+			WHERE countries.code = $1 AND locations.location_id = ANY($2) AND locations.tenant_id = $3`
+	for key, ids := range matched {
+		if _, err = tx.Exec(query, key.code, pq.Array(ids), key.tenantId); err != nil {
+			return nil, err
+		}
+	}
+	return unmatched, tx.Commit()
+}
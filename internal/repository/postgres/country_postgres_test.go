@@ -0,0 +1,41 @@
+package postgres
+
+import (
+	"github.com/lib/pq"
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	sqlmock "github.com/zhashkevych/go-sqlxmock"
+	"testing"
+)
+
+func TestMigrateLocationCountries(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"tenant_id", "location_id", "country"}).
+		AddRow(1, 1, "RF").
+		AddRow(1, 2, "France").
+		AddRow(1, 3, "Middle-earth").
+		AddRow(1, 4, "fra").
+		AddRow(2, 1, "France")
+	mock.ExpectQuery("SELECT (.+) FROM locations WHERE country_code IS NULL").WillReturnRows(rows)
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE locations SET (.+) FROM countries").
+		WithArgs("RU", pq.Array([]uint32{1}), 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE locations SET (.+) FROM countries").
+		WithArgs("FR", pq.Array([]uint32{2, 4}), 1).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("UPDATE locations SET (.+) FROM countries").
+		WithArgs("FR", pq.Array([]uint32{1}), 2).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.MatchExpectationsInOrder(false)
+
+	got, err := MigrateLocationCountries(db)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []model.UnmatchedCountry{{TenantId: 1, LocationId: 3, Country: "Middle-earth"}}, got)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,92 @@
+package postgres
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/rinuccia/travels-api/internal/model"
+	"time"
+)
+
+type exportRepo struct {
+	*sqlx.DB
+}
+
+func newExportRepo(db *sqlx.DB) *exportRepo {
+	return &exportRepo{db}
+}
+
+func (r *exportRepo) EachUser(since time.Time, fn func(model.User) error) error {
+	query := `
+			SELECT user_id, email, first_name, last_name, gender
+			FROM users
+			WHERE updated_at >= $1
+			  AND tenant_id = current_tenant()
+			ORDER BY user_id`
+	rows, err := r.Query(query, since)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	user := model.User{}
+	for rows.Next() {
+		err = rows.Scan(&user.UserId, &user.Email, &user.FirstName, &user.LastName, &user.Gender)
+		if err != nil {
+			return err
+		}
+		if err = fn(user); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func (r *exportRepo) EachLocation(since time.Time, fn func(model.Location) error) error {
+	query := selectLocation + `
+			WHERE l.updated_at >= $1
+			  AND l.tenant_id = current_tenant()
+			ORDER BY l.location_id`
+	rows, err := r.Query(query, since)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	location := model.Location{}
+	for rows.Next() {
+		err = scanLocation(rows, &location)
+		if err != nil {
+			return err
+		}
+		if err = fn(location); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func (r *exportRepo) EachVisit(since time.Time, fn func(model.Visit) error) error {
+	query := `
+			SELECT visit_id, location_id, user_id, visited_at, mark
+			FROM visits
+			WHERE updated_at >= $1
+			  AND deleted_at IS NULL
+			  AND tenant_id = current_tenant()
+			ORDER BY visit_id`
+	rows, err := r.Query(query, since)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	visit := model.Visit{}
+	for rows.Next() {
+		err = rows.Scan(&visit.VisitId, &visit.LocationId, &visit.UserId, &visit.VisitedAt, &visit.Mark)
+		if err != nil {
+			return err
+		}
+		if err = fn(visit); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
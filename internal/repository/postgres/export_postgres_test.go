@@ -0,0 +1,77 @@
+package postgres
+
+import (
+	"errors"
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	sqlmock "github.com/zhashkevych/go-sqlxmock"
+	"testing"
+	"time"
+)
+
+func TestExportRepo_EachLocation(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	repository := newExportRepo(db)
+	since := time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		stopErr error
+		want    []model.Location
+		wantErr bool
+	}{
+		{
+			name: "Ok",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"location_id", "place", "country", "country_code", "lat", "lon", "category", "tags"}).
+					AddRow(1, "Red Square", "RF", "RU", nil, nil, "monument", "{unesco}").
+					AddRow(2, "Eiffel Tower", "France", "FR", nil, nil, "", "{}")
+				mock.ExpectQuery("SELECT (.+) FROM locations (.+) WHERE l.updated_at >= (.+)").
+					WithArgs(since).WillReturnRows(rows)
+			},
+			want: []model.Location{
+				{LocationId: 1, Place: "Red Square", Country: "RF", CountryCode: "RU", Category: "monument", Tags: []string{"unesco"}},
+				{LocationId: 2, Place: "Eiffel Tower", Country: "France", CountryCode: "FR", Tags: []string{}},
+			},
+		},
+		{
+			name: "Callback Error",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"location_id", "place", "country", "country_code", "lat", "lon", "category", "tags"}).
+					AddRow(1, "Red Square", "RF", "RU", nil, nil, "monument", "{unesco}").
+					AddRow(2, "Eiffel Tower", "France", "FR", nil, nil, "", "{}")
+				mock.ExpectQuery("SELECT (.+) FROM locations (.+) WHERE l.updated_at >= (.+)").
+					WithArgs(since).WillReturnRows(rows)
+			},
+			stopErr: errors.New("broken pipe"),
+			want:    []model.Location{{LocationId: 1, Place: "Red Square", Country: "RF", CountryCode: "RU", Category: "monument", Tags: []string{"unesco"}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			var got []model.Location
+			err := repository.EachLocation(since, func(l model.Location) error {
+				got = append(got, l)
+				return tt.stopErr
+			})
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/jmoiron/sqlx"
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/pkg/apperrors"
+)
+
+type followRepo struct {
+	*sqlx.DB
+}
+
+func newFollowRepo(db *sqlx.DB) *followRepo {
+	return &followRepo{db}
+}
+
+// Insert is approved right away unless the target is private, following twice keeps the first follow.
+func (r *followRepo) Insert(userId, targetId string) (model.Follow, error) {
+	follow := model.Follow{}
+	if err := r.userExists(userId); err != nil {
+		return follow, err
+	}
+
+	query := `
+			INSERT INTO follows (follower_id, followee_id, approved)
+			SELECT $1, user_id, NOT private FROM users WHERE user_id = $2 AND tenant_id = current_tenant()
+			ON CONFLICT (tenant_id, follower_id, followee_id) DO UPDATE SET approved = follows.approved
+			RETURNING follower_id, followee_id, approved`
+	var approved bool
+	err := r.QueryRow(query, userId, targetId).Scan(&follow.UserId, &follow.TargetId, &approved)
+	if errors.Is(err, sql.ErrNoRows) {
+		return follow, apperrors.ErrRecordNotFound
+	}
+	if err != nil {
+		return follow, apperrors.ErrIncorrectQuery
+	}
+	follow.Status = followStatus(approved)
+	return follow, nil
+}
+
+func (r *followRepo) Approve(userId, followerId string) error {
+	query := `
+			UPDATE follows SET approved = true
+			WHERE followee_id = $1 AND follower_id = $2 AND tenant_id = current_tenant()`
+	res, err := r.Exec(query, userId, followerId)
+	if err != nil {
+		return apperrors.ErrIncorrectQuery
+	}
+	if rowsAff, err := res.RowsAffected(); rowsAff == 0 && err == nil {
+		return apperrors.ErrRecordNotFound
+	}
+	return err
+}
+
+func (r *followRepo) Delete(userId, targetId string) error {
+	query := "DELETE FROM follows WHERE follower_id = $1 AND followee_id = $2 AND tenant_id = current_tenant()"
+	res, err := r.Exec(query, userId, targetId)
+	if err != nil {
+		return apperrors.ErrIncorrectQuery
+	}
+	if rowsAff, err := res.RowsAffected(); rowsAff == 0 && err == nil {
+		return apperrors.ErrRecordNotFound
+	}
+	return err
+}
+
+func (r *followRepo) FindFollowers(userId string, filter model.FollowerFilter) (model.Followers, error) {
+	if err := r.userExists(userId); err != nil {
+		return model.Followers{}, err
+	}
+
+	query := `
+			SELECT users.user_id, users.first_name, users.last_name, follows.approved
+			FROM follows
+				JOIN users
+					ON users.user_id = follows.follower_id AND users.tenant_id = follows.tenant_id
+			WHERE follows.followee_id = $1
+			  AND follows.tenant_id = current_tenant()
+			  AND ($2 = '' OR follows.approved = ($2 = 'approved'))
+			ORDER BY follows.approved, follows.created_at DESC, users.user_id`
+	var approved bool
+	follower := model.Follower{}
+	followers := model.Followers{}
+	rows, err := r.Query(query, userId, filter.Status)
+	if err != nil {
+		return followers, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		if err = rows.Scan(&follower.UserId, &follower.FirstName, &follower.LastName, &approved); err != nil {
+			return followers, err
+		}
+		follower.Status = followStatus(approved)
+		followers.List = append(followers.List, follower)
+	}
+	return followers, rows.Err()
+}
+
+// FindFeed walks visits newest first from the cursor, only users whose follow is approved are included.
+func (r *followRepo) FindFeed(userId string, after model.FeedCursor, limit int) ([]model.FeedVisit, error) {
+	if err := r.userExists(userId); err != nil {
+		return nil, err
+	}
+
+	query := `
+			SELECT visits.visit_id, users.user_id, users.first_name, users.last_name,
+				   locations.place, COALESCE(countries.name, locations.country), visits.visited_at, visits.mark` + visitJoins + `
+				JOIN follows
+					ON follows.followee_id = visits.user_id AND follows.tenant_id = visits.tenant_id
+				LEFT JOIN countries
+					ON countries.code = locations.country_code
+			WHERE follows.follower_id = $1
+			  AND follows.approved
+			  AND ($2 = '' OR (visits.visited_at, visits.visit_id) < ($2, $3))
+			ORDER BY visits.visited_at DESC, visits.visit_id DESC
+			LIMIT $4`
+	visit := model.FeedVisit{}
+	var visits []model.FeedVisit
+	rows, err := r.Query(query, userId, after.VisitedAt, after.VisitId, limit)
+	if err != nil {
+		return visits, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		err = rows.Scan(&visit.VisitId, &visit.Author.UserId, &visit.Author.FirstName, &visit.Author.LastName,
+			&visit.Place, &visit.Country, &visit.VisitedAt, &visit.Mark)
+		if err != nil {
+			return visits, err
+		}
+		visits = append(visits, visit)
+	}
+	return visits, rows.Err()
+}
+
+func (r *followRepo) userExists(id string) error {
+	var userId int
+	row := r.QueryRow("SELECT user_id FROM users WHERE user_id = $1 AND tenant_id = current_tenant()", id)
+	if err := row.Scan(&userId); err != nil {
+		return apperrors.ErrRecordNotFound
+	}
+	return nil
+}
+
+func followStatus(approved bool) string {
+	if approved {
+		return model.FollowApproved
+	}
+	return model.FollowPending
+}
@@ -0,0 +1,196 @@
+package postgres
+
+import (
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/pkg/apperrors"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	sqlmock "github.com/zhashkevych/go-sqlxmock"
+	"testing"
+)
+
+func TestFollowRepo_Insert(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	repository := newFollowRepo(db)
+	followColumns := []string{"follower_id", "followee_id", "approved"}
+
+	testTable := []struct {
+		name            string
+		mock            func()
+		want            model.Follow
+		wantErr         bool
+		expectedErrType error
+	}{
+		{
+			name: "Public Target",
+			mock: func() {
+				mock.ExpectQuery("SELECT user_id FROM users").WithArgs("1").
+					WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(1))
+				mock.ExpectQuery("INSERT INTO follows (.+) SELECT (.+) NOT private FROM users (.+) ON CONFLICT").
+					WithArgs("1", "2").WillReturnRows(sqlmock.NewRows(followColumns).AddRow(1, 2, true))
+			},
+			want: model.Follow{UserId: 1, TargetId: 2, Status: "approved"},
+		},
+		{
+			name: "Private Target",
+			mock: func() {
+				mock.ExpectQuery("SELECT user_id FROM users").WithArgs("1").
+					WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(1))
+				mock.ExpectQuery("INSERT INTO follows").
+					WithArgs("1", "2").WillReturnRows(sqlmock.NewRows(followColumns).AddRow(1, 2, false))
+			},
+			want: model.Follow{UserId: 1, TargetId: 2, Status: "pending"},
+		},
+		{
+			name: "Target Not Found",
+			mock: func() {
+				mock.ExpectQuery("SELECT user_id FROM users").WithArgs("1").
+					WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(1))
+				mock.ExpectQuery("INSERT INTO follows").
+					WithArgs("1", "2").WillReturnRows(sqlmock.NewRows(followColumns))
+			},
+			wantErr:         true,
+			expectedErrType: apperrors.ErrRecordNotFound,
+		},
+		{
+			name: "User Not Found",
+			mock: func() {
+				mock.ExpectQuery("SELECT user_id FROM users").WithArgs("1").
+					WillReturnRows(sqlmock.NewRows([]string{"user_id"}))
+			},
+			wantErr:         true,
+			expectedErrType: apperrors.ErrRecordNotFound,
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := repository.Insert("1", "2")
+
+			if tt.wantErr {
+				assert.Equal(t, tt.expectedErrType, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestFollowRepo_Approve(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	repository := newFollowRepo(db)
+
+	testTable := []struct {
+		name            string
+		mock            func()
+		wantErr         bool
+		expectedErrType error
+	}{
+		{
+			name: "Ok",
+			mock: func() {
+				mock.ExpectExec("UPDATE follows SET approved = true WHERE followee_id = (.+) AND follower_id = (.+)").
+					WithArgs("2", "1").WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "Not Found",
+			mock: func() {
+				mock.ExpectExec("UPDATE follows").
+					WithArgs("2", "1").WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantErr:         true,
+			expectedErrType: apperrors.ErrRecordNotFound,
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			err := repository.Approve("2", "1")
+
+			if tt.wantErr {
+				assert.Equal(t, tt.expectedErrType, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestFollowRepo_FindFeed(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	repository := newFollowRepo(db)
+	feedColumns := []string{"visit_id", "user_id", "first_name", "last_name", "place", "country", "visited_at", "mark"}
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		after   model.FeedCursor
+		want    []model.FeedVisit
+		wantErr bool
+	}{
+		{
+			name: "Ok",
+			mock: func() {
+				mock.ExpectQuery("SELECT user_id FROM users").WithArgs("1").
+					WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(1))
+				rows := sqlmock.NewRows(feedColumns).
+					AddRow(9, 2, "Jane", "Doe", "Machu Picchu", "Peru", "2019-06-15", 5)
+				mock.ExpectQuery("SELECT (.+) FROM visits (.+) JOIN follows (.+) WHERE follows.follower_id = (.+) "+
+					"AND follows.approved (.+) ORDER BY visits.visited_at DESC, visits.visit_id DESC LIMIT (.+)").
+					WithArgs("1", "2019-07-01", uint32(12), 3).WillReturnRows(rows)
+			},
+			after: model.FeedCursor{VisitedAt: "2019-07-01", VisitId: 12},
+			want: []model.FeedVisit{
+				{
+					VisitId:   9,
+					Author:    model.FeedAuthor{UserId: 2, FirstName: "Jane", LastName: "Doe"},
+					UserVisit: model.UserVisit{Place: "Machu Picchu", Country: "Peru", VisitedAt: "2019-06-15", Mark: 5},
+				},
+			},
+		},
+		{
+			name: "Not Found",
+			mock: func() {
+				mock.ExpectQuery("SELECT user_id FROM users").WithArgs("1").
+					WillReturnRows(sqlmock.NewRows([]string{"user_id"}))
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := repository.FindFeed("1", tt.after, 3)
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, apperrors.ErrRecordNotFound)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
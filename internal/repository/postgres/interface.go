@@ -3,6 +3,8 @@ package postgres
 import (
 	"github.com/jmoiron/sqlx"
 	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/pkg/geo"
+	"time"
 )
 
 type (
@@ -10,49 +12,322 @@ type (
 		// FindById user in DB.
 		FindById(id string) (model.User, error)
 
-		// Insert user with given credentials in DB.
-		Insert(u model.User) (model.User, error)
+		// FindSummary aggregates visits of user by id in DB.
+		FindSummary(id string) (model.UserSummary, error)
 
-		// Update user in DB.
-		Update(id string, u model.User) error
+		// Insert user with given credentials in DB, audited.
+		Insert(actor model.Actor, u model.User) (model.User, error)
+
+		// Update user in DB, audited.
+		Update(actor model.Actor, id string, u model.User) error
+
+		// UpdateRole of user in DB, audited.
+		UpdateRole(actor model.Actor, id string, role string) error
+	}
+
+	AuthRepository interface {
+		// Register user with the bcrypt hash of the password in DB.
+		Register(actor model.Actor, user model.User, passwordHash []byte) (model.User, error)
+
+		// FindCredentials of user with a password by email ignoring case in DB.
+		FindCredentials(email string) (model.UserCredentials, error)
+
+		// FindIdentity id and role of user by id in DB, erased users have none.
+		FindIdentity(userId uint32) (model.Identity, error)
+
+		// InsertRefreshToken in DB.
+		InsertRefreshToken(token model.RefreshToken) error
+
+		// RotateRefreshToken replaces the unexpired refresh token with oldHash by next for the same user in DB.
+		RotateRefreshToken(oldHash string, next model.RefreshToken) (model.RefreshToken, error)
+
+		// DeleteRefreshToken in DB.
+		DeleteRefreshToken(tokenHash string) error
 	}
 
 	LocationRepository interface {
-		// FindAll locations in DB.
-		FindAll() (model.Locations, error)
+		// FindAll locations in DB matching the filter.
+		FindAll(filter model.LocationFilter) (model.Locations, error)
 
 		// FindById user in DB.
 		FindById(id string) (model.Location, error)
 
-		// FindRating location by id.
-		FindRating(id string) (float32, error)
+		// FindRating location by id, counting only visits that match the filter.
+		FindRating(id string, filter model.VisitFilter) (float32, error)
+
+		// FindMarkCounts counts filtered visits of location by id per mark, grouped by month or year when bucket is set.
+		FindMarkCounts(id string, filter model.VisitFilter, bucket string) ([]model.PeriodMarkCount, error)
+
+		// FindTop ranks locations with at least query.MinVisits filtered visits by average mark or visit count.
+		FindTop(query model.TopLocationsQuery) (model.TopLocations, error)
 
-		// Insert location with given credentials in DB.
-		Insert(location model.Location) (model.Location, error)
+		// FindMostWanted locations by the number of open wishlist items.
+		FindMostWanted(limit int) (model.WantedLocations, error)
+
+		// FindNearby locations inside the box within radiusKm of the point, nearest first.
+		FindNearby(lat, lon, radiusKm float64, box geo.BoundingBox, limit int) (model.NearbyLocations, error)
+
+		// Insert location with given credentials in DB, audited.
+		Insert(actor model.Actor, location model.Location) (model.Location, error)
+
+		// Upsert inserts location or updates the existing one with the same id, reports whether it was inserted, audited.
+		Upsert(actor model.Actor, location model.Location) (bool, error)
 	}
 
 	VisitRepository interface {
-		// FindAll user visits by id in DB.
-		FindAll(id string) (model.UserVisits, error)
+		// FindAll user visits by id in DB, filtered by location category and tag.
+		FindAll(id string, filter model.LocationFilter) (model.UserVisits, error)
+
+		// Insert new visit in DB, audited.
+		Insert(actor model.Actor, visit model.Visit) (model.Visit, error)
+
+		// Upsert inserts visit or updates the existing one with the same id, reports whether it was inserted, audited.
+		Upsert(actor model.Actor, visit model.Visit) (bool, error)
+
+		// DeleteById marks user visit deleted in DB, audited.
+		DeleteById(actor model.Actor, id string) error
+
+		// Restore deleted user visit in DB, audited.
+		Restore(actor model.Actor, id string) error
+	}
+
+	CategoryRepository interface {
+		// FindAll categories in DB.
+		FindAll() (model.Categories, error)
+
+		// FindById category in DB.
+		FindById(id string) (model.Category, error)
+
+		// Insert category in DB.
+		Insert(category model.Category) (model.Category, error)
 
-		// Insert new visit in DB.
-		Insert(visit model.Visit) (model.Visit, error)
+		// Update category name in DB.
+		Update(id string, category model.Category) error
 
-		// DeleteById user visit in DB.
+		// DeleteById marks category deleted in DB, its locations read as uncategorized until it is restored.
 		DeleteById(id string) error
+
+		// Restore deleted category in DB, fails when an active category took its name.
+		Restore(id string) error
+	}
+
+	StatsRepository interface {
+		// FindCountryStats aggregates locations and filtered visits of every country with locations.
+		FindCountryStats(filter model.VisitFilter, sort model.StatsSort) (model.CountriesStats, error)
+	}
+
+	TripRepository interface {
+		// FindAll manually created trips of user by id in DB, without their visits.
+		FindAll(userId string) ([]model.TripRecord, error)
+
+		// FindVisits of user by id in DB ordered by date, with the trip each visit belongs to.
+		FindVisits(userId string) ([]model.TripVisit, error)
+
+		// Insert trip in DB and move the listed visits of its user into it.
+		Insert(trip model.TripRecord) (model.TripRecord, error)
+
+		// Update trip name in DB, listed visits replace the trip visits when given.
+		Update(id string, trip model.TripUpdate) error
+
+		// DeleteById marks trip deleted in DB, its visits are grouped automatically until it is restored.
+		DeleteById(id string) error
+
+		// Restore deleted trip in DB with the visits it had.
+		Restore(id string) error
+	}
+
+	WishlistRepository interface {
+		// FindAll wishlist items of user by id in DB matching the filter.
+		FindAll(userId string, filter model.WishlistFilter) (model.Wishlist, error)
+
+		// Insert wishlist item of user in DB.
+		Insert(userId string, item model.WishlistItem) (model.WishlistItem, error)
+
+		// Update planned date and priority of wishlist item in DB.
+		Update(userId, locationId string, item model.WishlistItem) error
+
+		// DeleteById marks wishlist item of user deleted in DB.
+		DeleteById(userId, locationId string) error
+
+		// Restore deleted wishlist item of user in DB.
+		Restore(userId, locationId string) error
+	}
+
+	ReviewRepository interface {
+		// FindByLocation approved reviews of location by id in DB, one page in the requested order.
+		FindByLocation(id string, query model.ReviewQuery) (model.Reviews, error)
+
+		// FindByStatus reviews in DB with the moderation status, one page in the requested order.
+		FindByStatus(status string, query model.ReviewQuery) (model.Reviews, error)
+
+		// UpdateStatus of review of visit by id in DB.
+		UpdateStatus(visitId string, status string) error
+	}
+
+	PhotoRepository interface {
+		// FindByLocation photos of location by id in DB.
+		FindByLocation(id string) (model.Photos, error)
+
+		// FindByVisit photos of visit by id in DB.
+		FindByVisit(id string) (model.Photos, error)
+
+		// FindById photo metadata in DB.
+		FindById(id string) (model.Photo, error)
+
+		// InsertForLocation photo metadata of location by id in DB.
+		InsertForLocation(id string, photo model.Photo) (model.Photo, error)
+
+		// InsertForVisit photo metadata of visit by id in DB.
+		InsertForVisit(id string, photo model.Photo) (model.Photo, error)
+	}
+
+	FollowRepository interface {
+		// Insert follow of target by user in DB, following a private user waits for approval.
+		Insert(userId, targetId string) (model.Follow, error)
+
+		// Approve pending follow of user by follower in DB.
+		Approve(userId, followerId string) error
+
+		// Delete follow of target by user in DB.
+		Delete(userId, targetId string) error
+
+		// FindFollowers of user by id in DB matching the filter, pending requests first.
+		FindFollowers(userId string, filter model.FollowerFilter) (model.Followers, error)
+
+		// FindFeed up to limit visits of users followed by user by id, newest first after the cursor.
+		FindFeed(userId string, after model.FeedCursor, limit int) ([]model.FeedVisit, error)
+	}
+
+	RecommendationRepository interface {
+		// FindBySimilarUsers unvisited locations liked by users whose marks are close to the user's marks.
+		FindBySimilarUsers(id string, limit int) (model.Recommendations, error)
+
+		// FindByLikedCountries best rated unvisited locations in countries where the user liked a location.
+		FindByLikedCountries(id string, limit int) (model.Recommendations, error)
+	}
+
+	APIKeyRepository interface {
+		// FindAll API keys in DB, revoked and expired keys included.
+		FindAll() (model.APIKeys, error)
+
+		// Insert API key with the hash of the key in DB.
+		Insert(key model.APIKey, keyHash string) (model.APIKey, error)
+
+		// Revoke API key by id in DB.
+		Revoke(id string) error
+
+		// Use records a use of the valid API key with the hash in DB and returns its caller identity.
+		Use(keyHash string) (model.Identity, error)
+	}
+
+	OwnerRepository interface {
+		// FindVisitOwner id of the user who made the visit by id in DB, deleted visits included so they can be restored.
+		FindVisitOwner(visitId string) (uint32, error)
+
+		// FindTripOwner id of the user whose trip by id it is in DB, deleted trips included so they can be restored.
+		FindTripOwner(tripId string) (uint32, error)
+
+		// IsHidden tells whether user by id in DB is private and not followed by the viewer with approval.
+		IsHidden(userId string, viewerId uint32) (bool, error)
+	}
+
+	AuditRepository interface {
+		// FindAll audit records in DB matching the filter, one page newest first.
+		FindAll(filter model.AuditFilter) (model.AuditLog, error)
+	}
+
+	PurgeRepository interface {
+		// Purge hard deletes visits, trips and categories deleted before the given time from DB.
+		Purge(before time.Time) (model.Purged, error)
+	}
+
+	ExportRepository interface {
+		// EachUser streams users updated since the given time, ordered by id.
+		EachUser(since time.Time, fn func(model.User) error) error
+
+		// EachLocation streams locations updated since the given time, ordered by id.
+		EachLocation(since time.Time, fn func(model.Location) error) error
+
+		// EachVisit streams visits updated since the given time, ordered by id.
+		EachVisit(since time.Time, fn func(model.Visit) error) error
+	}
+
+	PrivacyRepository interface {
+		// FindSubjectData every record linked to user by id in DB, deleted visits included.
+		FindSubjectData(id string) (model.SubjectData, error)
+
+		// Erase anonymises user by id in DB, deletes the records linked to it except visits and appends the erasure
+		// to the erasure log, returns the deleted photos.
+		Erase(actor model.Actor, id string) (model.Erasure, []model.Photo, error)
+
+		// FindErasures entries of the erasure log in DB, oldest first.
+		FindErasures() ([]model.Erasure, error)
+	}
+
+	VerificationRepository interface {
+		// InsertVerification of email in DB, pending verifications of the same user are dropped.
+		InsertVerification(v model.EmailVerification) error
+
+		// ConfirmEmail uses up an unexpired verification by token id and user id in DB and sets its email
+		// on the user as verified.
+		ConfirmEmail(actor model.Actor, tokenId string, userId uint32) error
 	}
 )
 
+// TenantRepository reads the tenants of the deployment, it is not bound to a tenant.
+type TenantRepository interface {
+	// FindById tenant in DB.
+	FindById(id uint32) (model.Tenant, error)
+
+	// FindAll tenants in DB by id.
+	FindAll() ([]model.Tenant, error)
+
+	// FindAPIKeyTenant the tenant of an API key by key hash in DB, revoked and expired keys included.
+	FindAPIKeyTenant(keyHash string) (uint32, error)
+}
+
 type Repository struct {
 	UserRepository
+	AuthRepository
 	LocationRepository
 	VisitRepository
+	CategoryRepository
+	StatsRepository
+	TripRepository
+	WishlistRepository
+	ReviewRepository
+	PhotoRepository
+	FollowRepository
+	RecommendationRepository
+	APIKeyRepository
+	OwnerRepository
+	AuditRepository
+	PurgeRepository
+	ExportRepository
+	PrivacyRepository
+	VerificationRepository
 }
 
 func NewRepository(db *sqlx.DB) *Repository {
 	return &Repository{
 		newUserRepo(db),
+		newAuthRepo(db),
 		newLocationRepo(db),
 		newVisitRepo(db),
+		newCategoryRepo(db),
+		newStatsRepo(db),
+		newTripRepo(db),
+		newWishlistRepo(db),
+		newReviewRepo(db),
+		newPhotoRepo(db),
+		newFollowRepo(db),
+		newRecommendationRepo(db),
+		newAPIKeyRepo(db),
+		newOwnerRepo(db),
+		newAuditRepo(db),
+		newPurgeRepo(db),
+		newExportRepo(db),
+		newPrivacyRepo(db),
+		newVerificationRepo(db),
 	}
 }
@@ -1,9 +1,13 @@
 package postgres
 
 import (
+	"database/sql"
+	"errors"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/rinuccia/travels-api/internal/model"
 	"github.com/rinuccia/travels-api/pkg/apperrors"
+	"github.com/rinuccia/travels-api/pkg/geo"
 )
 
 type locationRepo struct {
@@ -14,16 +18,24 @@ func newLocationRepo(db *sqlx.DB) *locationRepo {
 	return &locationRepo{db}
 }
 
-func (r *locationRepo) FindAll() (model.Locations, error) {
-	query := "SELECT * FROM locations"
+func (r *locationRepo) FindAll(filter model.LocationFilter) (model.Locations, error) {
+	query := selectLocation + `
+			WHERE l.tenant_id = current_tenant()
+			  AND ($1 = '' OR c.name = $1)
+			  AND ($2 = '' OR EXISTS (SELECT 1
+									  FROM location_tags lt
+										  JOIN tags t
+											  ON t.tag_id = lt.tag_id AND t.tenant_id = lt.tenant_id
+									  WHERE lt.location_id = l.location_id AND lt.tenant_id = l.tenant_id AND t.name = $2))
+			ORDER BY l.location_id`
 	location := model.Location{}
 	locations := model.Locations{}
-	rows, err := r.Query(query)
+	rows, err := r.Query(query, filter.Category, filter.Tag)
 	if err != nil {
 		return locations, err
 	}
 	for rows.Next() {
-		err = rows.Scan(&location.LocationId, &location.Place, &location.Country)
+		err = scanLocation(rows, &location)
 		if err != nil {
 			return locations, err
 		}
@@ -33,43 +45,296 @@ func (r *locationRepo) FindAll() (model.Locations, error) {
 }
 
 func (r *locationRepo) FindById(id string) (model.Location, error) {
-	query := "SELECT * FROM locations WHERE location_id = $1"
+	query := selectLocation + " WHERE l.location_id = $1 AND l.tenant_id = current_tenant()"
 	location := model.Location{}
 	row := r.QueryRow(query, id)
-	err := row.Scan(&location.LocationId, &location.Place, &location.Country)
+	err := scanLocation(row, &location)
 	if err != nil {
 		return location, apperrors.ErrRecordNotFound
 	}
 	return location, err
 }
 
-func (r *locationRepo) FindRating(id string) (float32, error) {
+func (r *locationRepo) FindRating(id string, filter model.VisitFilter) (float32, error) {
 	var locationId int
-	row := r.QueryRow("SELECT location_id FROM locations WHERE location_id = $1", id)
+	row := r.QueryRow(selectLocationId, id)
 	err := row.Scan(&locationId)
 	if err != nil {
 		return 0, apperrors.ErrRecordNotFound
 	}
 	query := `
-			SELECT ROUND(sum * 1.0 / count, 2) AS avg 
-			FROM (SELECT locations.location_id, SUM(visits.mark) AS sum, COUNT(visits.mark) AS count 
-				  FROM locations 
-					  LEFT JOIN visits 
-						  ON locations.location_id = visits.location_id 
-				  GROUP BY locations.location_id 
-				  HAVING locations.location_id = $1) AS abc`
+			SELECT COALESCE(ROUND(AVG(visits.mark), 2), 0) AS avg` + visitJoins + `
+			WHERE visits.location_id = $1` + visitFilterCondition(2)
 	var rating float32
-	row = r.QueryRow(query, id)
+	row = r.QueryRow(query, append([]interface{}{id}, visitFilterArgs(filter)...)...)
 	_ = row.Scan(&rating)
 	return rating, err
 }
 
-func (r *locationRepo) Insert(location model.Location) (model.Location, error) {
-	query := "INSERT INTO locations (location_id, place, country) VALUES ($1, $2, $3)"
-	_, err := r.Exec(query, location.LocationId, location.Place, location.Country)
+func (r *locationRepo) FindMarkCounts(id string, filter model.VisitFilter, bucket string) ([]model.PeriodMarkCount, error) {
+	var locationId int
+	row := r.QueryRow(selectLocationId, id)
+	err := row.Scan(&locationId)
+	if err != nil {
+		return nil, apperrors.ErrRecordNotFound
+	}
+	period, ok := ratingPeriods[bucket]
+	if !ok {
+		period = "''"
+	}
+	query := `
+			SELECT ` + period + ` AS period, visits.mark, COUNT(*) AS count` + visitJoins + `
+			WHERE visits.location_id = $1` + visitFilterCondition(2) + `
+			GROUP BY period, visits.mark
+			ORDER BY period, visits.mark`
+	count := model.PeriodMarkCount{}
+	counts := []model.PeriodMarkCount{}
+	rows, err := r.Query(query, append([]interface{}{id}, visitFilterArgs(filter)...)...)
+	if err != nil {
+		return counts, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		if err = rows.Scan(&count.Period, &count.Mark, &count.Count); err != nil {
+			return counts, err
+		}
+		counts = append(counts, count)
+	}
+	return counts, rows.Err()
+}
+
+func (r *locationRepo) FindTop(top model.TopLocationsQuery) (model.TopLocations, error) {
+	order := "avg DESC, visits DESC"
+	if top.By == "visits" {
+		order = "visits DESC, avg DESC"
+	} else if top.Bayesian {
+		order = "bayesian_avg DESC, visits DESC"
+	}
+	query := `
+			WITH rated AS (
+				SELECT visits.location_id, COUNT(*) AS visits, AVG(visits.mark) AS avg` + visitJoins + `
+				WHERE ($4 = '' OR locations.country_code = $4)` + visitFilterCondition(1) + `
+				GROUP BY visits.location_id
+			), prior AS (
+				SELECT COALESCE(SUM(avg * visits) / NULLIF(SUM(visits), 0), 0) AS mean FROM rated
+			)` + selectLocationColumns + `, rated.visits,
+				   ROUND(rated.avg, 2) AS avg,
+				   ROUND((rated.avg * rated.visits + prior.mean * $5) / (rated.visits + $5), 2) AS bayesian_avg` +
+		selectLocationTables + `
+				JOIN rated
+					ON rated.location_id = l.location_id
+				CROSS JOIN prior
+			WHERE rated.visits >= $5 AND l.tenant_id = current_tenant()
+			ORDER BY ` + order + `, l.location_id
+			LIMIT $6`
+	filter := model.VisitFilter{FromDate: top.From, ToDate: top.To}
+	args := append(visitFilterArgs(filter), top.Country, top.MinVisits, top.Limit)
+
+	location := model.TopLocation{}
+	locations := model.TopLocations{}
+	rows, err := r.Query(query, args...)
+	if err != nil {
+		return locations, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		err = scanLocation(rows, &location.Location, &location.Visits, &location.Avg, &location.BayesianAvg)
+		if err != nil {
+			return locations, err
+		}
+		locations.List = append(locations.List, location)
+	}
+	return locations, rows.Err()
+}
+
+func (r *locationRepo) FindMostWanted(limit int) (model.WantedLocations, error) {
+	query := `
+			WITH wanted AS (
+				SELECT location_id, COUNT(*) AS wishes
+				FROM wishlist
+				WHERE done_visit_id IS NULL AND deleted_at IS NULL AND tenant_id = current_tenant()
+				GROUP BY location_id
+			)` + selectLocationColumns + `, wanted.wishes` + selectLocationTables + `
+				JOIN wanted
+					ON wanted.location_id = l.location_id
+			WHERE l.tenant_id = current_tenant()
+			ORDER BY wanted.wishes DESC, l.location_id
+			LIMIT $1`
+	location := model.WantedLocation{}
+	locations := model.WantedLocations{}
+	rows, err := r.Query(query, limit)
+	if err != nil {
+		return locations, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		err = scanLocation(rows, &location.Location, &location.Wishes)
+		if err != nil {
+			return locations, err
+		}
+		locations.List = append(locations.List, location)
+	}
+	return locations, rows.Err()
+}
+
+// FindNearby clamps the haversine term to 1, rounding can push it just above for near-antipodal points
+// and ASIN would fail.
+func (r *locationRepo) FindNearby(lat, lon, radiusKm float64, box geo.BoundingBox, limit int) (model.NearbyLocations, error) {
+	query := `
+			WITH candidates AS (
+				SELECT location_id,
+					   $3 * 2 * ASIN(LEAST(1, SQRT(
+						   POWER(SIN(RADIANS(lat - $1) / 2), 2) +
+						   COS(RADIANS($1)) * COS(RADIANS(lat)) * POWER(SIN(RADIANS(lon - $2) / 2), 2)
+					   ))) AS distance
+				FROM locations
+				WHERE lat BETWEEN $4 AND $5
+				  AND lon BETWEEN $6 AND $7
+				  AND tenant_id = current_tenant()
+			)` + selectLocationColumns + `, ROUND(candidates.distance::numeric, 2) AS distance_km` + selectLocationTables + `
+				JOIN candidates
+					ON candidates.location_id = l.location_id
+			WHERE candidates.distance <= $8 AND l.tenant_id = current_tenant()
+			ORDER BY candidates.distance
+			LIMIT $9`
+	location := model.NearbyLocation{}
+	locations := model.NearbyLocations{}
+	rows, err := r.Query(query, lat, lon, geo.EarthRadiusKm,
+		box.MinLat, box.MaxLat, box.MinLon, box.MaxLon, radiusKm, limit)
 	if err != nil {
-		return location, apperrors.ErrIncorrectQuery
+		return locations, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		err = scanLocation(rows, &location.Location, &location.DistanceKm)
+		if err != nil {
+			return locations, err
+		}
+		locations.List = append(locations.List, location)
 	}
+	return locations, rows.Err()
+}
 
+func (r *locationRepo) Insert(actor model.Actor, location model.Location) (model.Location, error) {
+	err := audited(r.DB, actor, model.AuditEntityLocation, entityId(location.LocationId), func(tx *sqlx.Tx) error {
+		categoryId, err := findCategoryId(tx, location.Category)
+		if err != nil {
+			return err
+		}
+		query := `
+			INSERT INTO locations (location_id, place, country, country_code, lat, lon, category_id)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)`
+		_, err = tx.Exec(query, location.LocationId, location.Place, location.Country, location.CountryCode,
+			location.Lat, location.Lon, categoryId)
+		if err != nil {
+			return writeError(err)
+		}
+		return writeError(saveLocationTags(tx, location.LocationId, location.Tags))
+	})
 	return location, err
 }
+
+func (r *locationRepo) Upsert(actor model.Actor, location model.Location) (bool, error) {
+	var inserted bool
+	err := audited(r.DB, actor, model.AuditEntityLocation, entityId(location.LocationId), func(tx *sqlx.Tx) error {
+		categoryId, err := findCategoryId(tx, location.Category)
+		if err != nil {
+			return err
+		}
+		query := `
+			INSERT INTO locations (location_id, place, country, country_code, lat, lon, category_id)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			ON CONFLICT (tenant_id, location_id) DO UPDATE
+				SET place = EXCLUDED.place, country = EXCLUDED.country, country_code = EXCLUDED.country_code,
+					lat = EXCLUDED.lat, lon = EXCLUDED.lon,
+					category_id = EXCLUDED.category_id, updated_at = now()
+			RETURNING (xmax = 0) AS inserted`
+		row := tx.QueryRow(query, location.LocationId, location.Place, location.Country, location.CountryCode,
+			location.Lat, location.Lon, categoryId)
+		if err = row.Scan(&inserted); err != nil {
+			return writeError(err)
+		}
+		return writeError(saveLocationTags(tx, location.LocationId, location.Tags))
+	})
+	return inserted, err
+}
+
+// ratingPeriods maps rating buckets to the visit date prefix they group by, user input never reaches the query text.
+var ratingPeriods = map[string]string{
+	"month": "LEFT(visits.visited_at, 7)",
+	"year":  "LEFT(visits.visited_at, 4)",
+}
+
+const (
+	// selectLocationColumns and selectLocationTables read a location with its country name, category name and sorted tags,
+	// readers restrict l to the tenant.
+	// Locations whose free-text country has no ISO match keep the stored text and an empty code,
+	// locations of a deleted category read as uncategorized.
+	selectLocationColumns = `
+			SELECT l.location_id, l.place, COALESCE(co.name, l.country) AS country, COALESCE(l.country_code, '') AS country_code,
+				   l.lat, l.lon, COALESCE(c.name, '') AS category,
+				   ARRAY(SELECT t.name
+						 FROM location_tags lt
+							 JOIN tags t
+								 ON t.tag_id = lt.tag_id AND t.tenant_id = lt.tenant_id
+						 WHERE lt.location_id = l.location_id AND lt.tenant_id = l.tenant_id
+						 ORDER BY t.name) AS tags`
+	selectLocationTables = `
+			FROM locations l
+				LEFT JOIN countries co
+					ON co.code = l.country_code
+				LEFT JOIN categories c
+					ON c.category_id = l.category_id AND c.tenant_id = l.tenant_id AND c.deleted_at IS NULL`
+	selectLocation = selectLocationColumns + selectLocationTables
+
+	// selectLocationId checks that a location of the tenant exists.
+	selectLocationId = "SELECT location_id FROM locations WHERE location_id = $1 AND tenant_id = current_tenant()"
+)
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanLocation scans a row selected with selectLocation, extra destinations follow the location columns.
+func scanLocation(row scanner, l *model.Location, extra ...interface{}) error {
+	dest := []interface{}{&l.LocationId, &l.Place, &l.Country, &l.CountryCode, &l.Lat, &l.Lon, &l.Category, pq.Array(&l.Tags)}
+	return row.Scan(append(dest, extra...)...)
+}
+
+// findCategoryId resolves a category name, empty name means no category.
+func findCategoryId(tx *sqlx.Tx, name string) (*uint32, error) {
+	if name == "" {
+		return nil, nil
+	}
+	var id uint32
+	query := "SELECT category_id FROM categories WHERE name = $1 AND deleted_at IS NULL AND tenant_id = current_tenant()"
+	row := tx.QueryRow(query, name)
+	err := row.Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, apperrors.ErrIncorrectQuery
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
+
+// saveLocationTags replaces location tags, unknown tags are created.
+func saveLocationTags(tx *sqlx.Tx, locationId uint32, tags []string) error {
+	_, err := tx.Exec("DELETE FROM location_tags WHERE location_id = $1 AND tenant_id = current_tenant()", locationId)
+	if err != nil {
+		return err
+	}
+	if len(tags) == 0 {
+		return nil
+	}
+	query := "INSERT INTO tags (name) SELECT unnest($1::varchar[]) ON CONFLICT (tenant_id, name) DO NOTHING"
+	if _, err = tx.Exec(query, pq.Array(tags)); err != nil {
+		return err
+	}
+	query = `
+			INSERT INTO location_tags (location_id, tag_id)
+			SELECT $1, tag_id FROM tags WHERE name = ANY($2) AND tenant_id = current_tenant()`
+	_, err = tx.Exec(query, locationId, pq.Array(tags))
+	return err
+}
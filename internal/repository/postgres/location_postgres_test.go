@@ -3,6 +3,7 @@ package postgres
 import (
 	"github.com/rinuccia/travels-api/internal/model"
 	"github.com/rinuccia/travels-api/pkg/apperrors"
+	"github.com/rinuccia/travels-api/pkg/geo"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	sqlmock "github.com/zhashkevych/go-sqlxmock"
@@ -27,17 +28,17 @@ func TestLocationRepo_FindAll(t *testing.T) {
 		{
 			name: "Ok",
 			mock: func() {
-				rows := sqlmock.NewRows([]string{"location_id", "place", "country"}).
-					AddRow(1, "Red Square", "RF").
-					AddRow(2, "Eiffel Tower", "France").
-					AddRow(3, "Grand Canyon", "USA")
-				mock.ExpectQuery("SELECT (.+) FROM locations").WillReturnRows(rows)
+				rows := sqlmock.NewRows([]string{"location_id", "place", "country", "country_code", "lat", "lon", "category", "tags"}).
+					AddRow(1, "Red Square", "RF", "RU", nil, nil, "monument", "{unesco}").
+					AddRow(2, "Eiffel Tower", "France", "FR", nil, nil, "monument", "{}").
+					AddRow(3, "Grand Canyon", "USA", "US", nil, nil, "nature", "{national-park,unesco}")
+				mock.ExpectQuery("SELECT (.+) FROM locations").WithArgs("", "").WillReturnRows(rows)
 			},
 			want: model.Locations{
 				List: []model.Location{
-					{1, "Red Square", "RF"},
-					{2, "Eiffel Tower", "France"},
-					{3, "Grand Canyon", "USA"},
+					{LocationId: 1, Place: "Red Square", Country: "RF", CountryCode: "RU", Category: "monument", Tags: []string{"unesco"}},
+					{LocationId: 2, Place: "Eiffel Tower", Country: "France", CountryCode: "FR", Category: "monument", Tags: []string{}},
+					{LocationId: 3, Place: "Grand Canyon", Country: "USA", CountryCode: "US", Category: "nature", Tags: []string{"national-park", "unesco"}},
 				},
 			},
 		},
@@ -45,7 +46,7 @@ func TestLocationRepo_FindAll(t *testing.T) {
 	for _, tt := range testTable {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mock()
-			got, err := repository.FindAll()
+			got, err := repository.FindAll(model.LocationFilter{})
 			if tt.wantErr {
 				assert.Error(t, err)
 			} else {
@@ -76,21 +77,25 @@ func TestLocationRepo_FindById(t *testing.T) {
 		{
 			name: "Ok",
 			mock: func() {
-				rows := sqlmock.NewRows([]string{"location_id", "place", "country"}).
-					AddRow("1", "Red Square", "RF")
-				mock.ExpectQuery("SELECT (.+) FROM locations WHERE (.+)").WillReturnRows(rows)
+				rows := sqlmock.NewRows([]string{"location_id", "place", "country", "country_code", "lat", "lon", "category", "tags"}).
+					AddRow("1", "Red Square", "RF", "RU", 55.7539, 37.6208, "", "{}")
+				mock.ExpectQuery("SELECT (.+) FROM locations (.+) WHERE (.+)").WillReturnRows(rows)
 			},
 			id: "1",
 			want: model.Location{
-				LocationId: 1,
-				Place:      "Red Square",
-				Country:    "RF",
+				LocationId:  1,
+				Place:       "Red Square",
+				Country:     "RF",
+				CountryCode: "RU",
+				Lat:         floatPtr(55.7539),
+				Lon:         floatPtr(37.6208),
+				Tags:        []string{},
 			},
 		},
 		{
 			name: "Not Found",
 			mock: func() {
-				mock.ExpectQuery("SELECT (.+) FROM locations WHERE (.+)").
+				mock.ExpectQuery("SELECT (.+) FROM locations (.+) WHERE (.+)").
 					WithArgs("1")
 			},
 			id:      "1",
@@ -133,7 +138,10 @@ func TestLocationRepo_FindRating(t *testing.T) {
 			mock: func() {
 				mock.ExpectQuery(`SELECT (.+) FROM locations`).WithArgs("1").
 					WillReturnRows(sqlmock.NewRows([]string{"locations_id"}).AddRow(1))
-				mock.ExpectQuery(`SELECT (.+) AS avg`).WithArgs("1").
+				mock.ExpectQuery(`SELECT (.+) AS avg FROM visits JOIN users ON (.+) AND users.tenant_id = visits.tenant_id `+
+					`AND visits.tenant_id = current_tenant\(\) (.+) JOIN locations ON (.+) `+
+					`AND locations.tenant_id = visits.tenant_id WHERE visits.location_id = (.+)`).
+					WithArgs("1", "", "", "").
 					WillReturnRows(sqlmock.NewRows([]string{"avg"}).AddRow(4.5))
 			},
 			id:   "1",
@@ -154,7 +162,82 @@ func TestLocationRepo_FindRating(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mock()
 
-			got, err := repository.FindRating(tt.id)
+			got, err := repository.FindRating(tt.id, model.VisitFilter{})
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestLocationRepo_FindMarkCounts(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	repository := newLocationRepo(db)
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		id      string
+		bucket  string
+		want    []model.PeriodMarkCount
+		wantErr bool
+	}{
+		{
+			name: "Ok",
+			mock: func() {
+				mock.ExpectQuery(`SELECT (.+) FROM locations`).WithArgs("1").
+					WillReturnRows(sqlmock.NewRows([]string{"locations_id"}).AddRow(1))
+				mock.ExpectQuery(`SELECT LEFT\(visits.visited_at, 4\) AS period, visits.mark, COUNT(.+) FROM visits (.+) GROUP BY period`).
+					WithArgs("1", "", "", "").
+					WillReturnRows(sqlmock.NewRows([]string{"period", "mark", "count"}).
+						AddRow("2018", 4, 2).
+						AddRow("2019", 5, 1))
+			},
+			id:     "1",
+			bucket: "year",
+			want: []model.PeriodMarkCount{
+				{Period: "2018", Mark: 4, Count: 2},
+				{Period: "2019", Mark: 5, Count: 1},
+			},
+		},
+		{
+			name: "No Bucket",
+			mock: func() {
+				mock.ExpectQuery(`SELECT (.+) FROM locations`).WithArgs("1").
+					WillReturnRows(sqlmock.NewRows([]string{"locations_id"}).AddRow(1))
+				mock.ExpectQuery(`SELECT '' AS period, visits.mark, COUNT(.+) FROM visits`).
+					WithArgs("1", "", "", "").
+					WillReturnRows(sqlmock.NewRows([]string{"period", "mark", "count"}))
+			},
+			id:   "1",
+			want: []model.PeriodMarkCount{},
+		},
+		{
+			name: "Not Found",
+			mock: func() {
+				mock.ExpectQuery(`SELECT (.+) FROM locations`).WithArgs("1").
+					WillReturnError(apperrors.ErrRecordNotFound)
+			},
+			id:      "1",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := repository.FindMarkCounts(tt.id, model.VisitFilter{}, tt.bucket)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -186,27 +269,49 @@ func TestLocationRepo_Insert(t *testing.T) {
 		{
 			name: "Ok",
 			mock: func() {
+				mock.ExpectBegin()
+				expectSnapshot(mock, "locations", "1", "")
+				mock.ExpectQuery("SELECT category_id FROM categories").WithArgs("nature").
+					WillReturnRows(sqlmock.NewRows([]string{"category_id"}).AddRow(2))
 				mock.ExpectExec("INSERT INTO locations").
-					WithArgs(1, "Machu Picchu", "Peru").
+					WithArgs(1, "Machu Picchu", "Peru", "PE", nil, nil, 2).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("DELETE FROM location_tags").WithArgs(1).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("INSERT INTO tags").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("INSERT INTO location_tags").
 					WillReturnResult(sqlmock.NewResult(1, 1))
+				expectSnapshot(mock, "locations", "1", `{"tags": ["unesco"]}`)
+				expectAuditInsert(mock, "create", "location", "1", nil, `{"tags": ["unesco"]}`)
+				mock.ExpectCommit()
 			},
 			input: model.Location{
-				LocationId: 1,
-				Place:      "Machu Picchu",
-				Country:    "Peru",
+				LocationId:  1,
+				Place:       "Machu Picchu",
+				Country:     "Peru",
+				CountryCode: "PE",
+				Category:    "nature",
+				Tags:        []string{"unesco"},
 			},
 			want: model.Location{
-				LocationId: 1,
-				Place:      "Machu Picchu",
-				Country:    "Peru",
+				LocationId:  1,
+				Place:       "Machu Picchu",
+				Country:     "Peru",
+				CountryCode: "PE",
+				Category:    "nature",
+				Tags:        []string{"unesco"},
 			},
 		},
 		{
 			name: "Incorrect Data",
 			mock: func() {
+				mock.ExpectBegin()
+				expectSnapshot(mock, "locations", "1", "")
 				mock.ExpectExec("INSERT INTO locations").
-					WithArgs(1, "Machu Picchu", "").
+					WithArgs(1, "Machu Picchu", "", "", nil, nil, nil).
 					WillReturnError(apperrors.ErrIncorrectQuery)
+				mock.ExpectRollback()
 			},
 			input: model.Location{
 				LocationId: 1,
@@ -221,7 +326,89 @@ func TestLocationRepo_Insert(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mock()
 
-			got, err := repository.Insert(tt.input)
+			got, err := repository.Insert(testActor, tt.input)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestLocationRepo_Upsert(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	repository := newLocationRepo(db)
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		input   model.Location
+		want    bool
+		wantErr bool
+	}{
+		{
+			name: "Inserted",
+			mock: func() {
+				mock.ExpectBegin()
+				expectSnapshot(mock, "locations", "1", "")
+				mock.ExpectQuery("INSERT INTO locations (.+) ON CONFLICT (.+)").
+					WithArgs(1, "Machu Picchu", "Peru", "PE", nil, nil, nil).
+					WillReturnRows(sqlmock.NewRows([]string{"inserted"}).AddRow(true))
+				mock.ExpectExec("DELETE FROM location_tags").WithArgs(1).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				expectSnapshot(mock, "locations", "1", `{"country": "Peru"}`)
+				expectAuditInsert(mock, "create", "location", "1", nil, `{"country": "Peru"}`)
+				mock.ExpectCommit()
+			},
+			input: model.Location{LocationId: 1, Place: "Machu Picchu", Country: "Peru", CountryCode: "PE"},
+			want:  true,
+		},
+		{
+			name: "Updated",
+			mock: func() {
+				mock.ExpectBegin()
+				expectSnapshot(mock, "locations", "1", `{"country": "Chile"}`)
+				mock.ExpectQuery("INSERT INTO locations (.+) ON CONFLICT (.+)").
+					WithArgs(1, "Machu Picchu", "Peru", "PE", nil, nil, nil).
+					WillReturnRows(sqlmock.NewRows([]string{"inserted"}).AddRow(false))
+				mock.ExpectExec("DELETE FROM location_tags").WithArgs(1).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				expectSnapshot(mock, "locations", "1", `{"country": "Peru"}`)
+				expectAuditInsert(mock, "update", "location", "1", `{"country": "Chile"}`, `{"country": "Peru"}`)
+				mock.ExpectCommit()
+			},
+			input: model.Location{LocationId: 1, Place: "Machu Picchu", Country: "Peru", CountryCode: "PE"},
+			want:  false,
+		},
+		{
+			name: "Incorrect Data",
+			mock: func() {
+				mock.ExpectBegin()
+				expectSnapshot(mock, "locations", "1", "")
+				mock.ExpectQuery("INSERT INTO locations (.+) ON CONFLICT (.+)").
+					WithArgs(1, "Machu Picchu", "", "", nil, nil, nil).
+					WillReturnError(apperrors.ErrIncorrectQuery)
+				mock.ExpectRollback()
+			},
+			input:   model.Location{LocationId: 1, Place: "Machu Picchu"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := repository.Upsert(testActor, tt.input)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -233,3 +420,188 @@ func TestLocationRepo_Insert(t *testing.T) {
 		})
 	}
 }
+
+func TestLocationRepo_FindNearby(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	repository := newLocationRepo(db)
+	box := geo.BoundingBox{MinLat: 48.4, MaxLat: 49.3, MinLon: 1.6, MaxLon: 3.0}
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		want    model.NearbyLocations
+		wantErr bool
+	}{
+		{
+			name: "Ok",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"location_id", "place", "country", "country_code", "lat", "lon", "category", "tags", "distance_km"}).
+					AddRow(2, "Eiffel Tower", "France", "FR", 48.8584, 2.2945, "monument", "{}", 0.52).
+					AddRow(4, "Louvre", "France", "FR", 48.8606, 2.3376, "museum", "{art}", 3.1)
+				mock.ExpectQuery("WITH candidates AS (.+) ASIN\\(LEAST\\(1, SQRT\\((.+) "+
+					"WHERE candidates.distance <= (.+) ORDER BY candidates.distance").
+					WithArgs(48.8566, 2.3522, geo.EarthRadiusKm, 48.4, 49.3, 1.6, 3.0, 50.0, 10).
+					WillReturnRows(rows)
+			},
+			want: model.NearbyLocations{
+				List: []model.NearbyLocation{
+					{
+						Location: model.Location{LocationId: 2, Place: "Eiffel Tower", Country: "France", CountryCode: "FR",
+							Lat: floatPtr(48.8584), Lon: floatPtr(2.2945), Category: "monument", Tags: []string{}},
+						DistanceKm: 0.52,
+					},
+					{
+						Location: model.Location{LocationId: 4, Place: "Louvre", Country: "France", CountryCode: "FR",
+							Lat: floatPtr(48.8606), Lon: floatPtr(2.3376), Category: "museum", Tags: []string{"art"}},
+						DistanceKm: 3.1,
+					},
+				},
+			},
+		},
+		{
+			name: "Query Error",
+			mock: func() {
+				mock.ExpectQuery("WITH candidates AS (.+) WHERE candidates.distance <= (.+) ORDER BY candidates.distance").
+					WillReturnError(apperrors.ErrIncorrectQuery)
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := repository.FindNearby(48.8566, 2.3522, 50, box, 10)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestLocationRepo_FindTop(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	repository := newLocationRepo(db)
+	columns := []string{"location_id", "place", "country", "country_code", "lat", "lon", "category", "tags",
+		"visits", "avg", "bayesian_avg"}
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		query   model.TopLocationsQuery
+		want    model.TopLocations
+		wantErr bool
+	}{
+		{
+			name: "Ok",
+			mock: func() {
+				rows := sqlmock.NewRows(columns).
+					AddRow(1, "Machu Picchu", "Peru", "PE", nil, nil, "", "{}", 4, 4.75, 4.43)
+				mock.ExpectQuery("WITH rated AS (.+) WHERE rated.visits >= (.+) ORDER BY avg DESC, visits DESC").
+					WithArgs("2018-01-01", "", "", "PE", 3, 10).
+					WillReturnRows(rows)
+			},
+			query: model.TopLocationsQuery{Country: "PE", From: "2018-01-01", Limit: 10, MinVisits: 3},
+			want: model.TopLocations{
+				List: []model.TopLocation{
+					{
+						Location: model.Location{LocationId: 1, Place: "Machu Picchu", Country: "Peru", CountryCode: "PE",
+							Tags: []string{}},
+						Visits:      4,
+						Avg:         4.75,
+						BayesianAvg: 4.43,
+					},
+				},
+			},
+		},
+		{
+			name: "Bayesian",
+			mock: func() {
+				mock.ExpectQuery("WITH rated AS (.+) ORDER BY bayesian_avg DESC, visits DESC").
+					WithArgs("", "", "", "", 5, 10).
+					WillReturnRows(sqlmock.NewRows(columns))
+			},
+			query: model.TopLocationsQuery{Limit: 10, MinVisits: 5, Bayesian: true},
+			want:  model.TopLocations{},
+		},
+		{
+			name: "By Visits",
+			mock: func() {
+				mock.ExpectQuery("WITH rated AS (.+) ORDER BY visits DESC, avg DESC").
+					WithArgs("", "", "", "", 1, 3).
+					WillReturnRows(sqlmock.NewRows(columns))
+			},
+			query: model.TopLocationsQuery{By: "visits", Limit: 3, MinVisits: 1, Bayesian: true},
+			want:  model.TopLocations{},
+		},
+		{
+			name: "Query Error",
+			mock: func() {
+				mock.ExpectQuery("WITH rated AS").WillReturnError(apperrors.ErrIncorrectQuery)
+			},
+			query:   model.TopLocationsQuery{Limit: 10, MinVisits: 3},
+			wantErr: true,
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := repository.FindTop(tt.query)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestLocationRepo_FindMostWanted(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	repository := newLocationRepo(db)
+
+	rows := sqlmock.NewRows([]string{"location_id", "place", "country", "country_code", "lat", "lon", "category", "tags", "wishes"}).
+		AddRow(1, "Machu Picchu", "Peru", "PE", nil, nil, "", "{}", 12)
+	mock.ExpectQuery("WITH wanted AS (.+) ORDER BY wanted.wishes DESC").WithArgs(10).WillReturnRows(rows)
+
+	got, err := repository.FindMostWanted(10)
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.WantedLocations{
+		List: []model.WantedLocation{
+			{
+				Location: model.Location{LocationId: 1, Place: "Machu Picchu", Country: "Peru", CountryCode: "PE", Tags: []string{}},
+				Wishes:   12,
+			},
+		},
+	}, got)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func floatPtr(f float64) *float64 {
+	return &f
+}
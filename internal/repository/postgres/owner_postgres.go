@@ -0,0 +1,50 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/jmoiron/sqlx"
+	"github.com/rinuccia/travels-api/pkg/apperrors"
+)
+
+type ownerRepo struct {
+	*sqlx.DB
+}
+
+func newOwnerRepo(db *sqlx.DB) *ownerRepo {
+	return &ownerRepo{db}
+}
+
+func (r *ownerRepo) FindVisitOwner(visitId string) (uint32, error) {
+	return r.findOwner("SELECT user_id FROM visits WHERE visit_id = $1 AND tenant_id = current_tenant()", visitId)
+}
+
+func (r *ownerRepo) FindTripOwner(tripId string) (uint32, error) {
+	return r.findOwner("SELECT user_id FROM trips WHERE trip_id = $1 AND tenant_id = current_tenant()", tripId)
+}
+
+// IsHidden is false for unknown users, the route answers for them as it would for a public user.
+func (r *ownerRepo) IsHidden(userId string, viewerId uint32) (bool, error) {
+	query := `
+			SELECT users.private AND NOT EXISTS (
+				SELECT 1 FROM follows
+				WHERE follows.follower_id = $2
+				  AND follows.followee_id = users.user_id
+				  AND follows.tenant_id = users.tenant_id
+				  AND follows.approved)
+			FROM users WHERE user_id = $1 AND tenant_id = current_tenant()`
+	var hidden bool
+	err := r.QueryRow(query, userId, viewerId).Scan(&hidden)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	return hidden, err
+}
+
+func (r *ownerRepo) findOwner(query, id string) (uint32, error) {
+	var userId uint32
+	if err := r.QueryRow(query, id).Scan(&userId); err != nil {
+		return 0, apperrors.ErrRecordNotFound
+	}
+	return userId, nil
+}
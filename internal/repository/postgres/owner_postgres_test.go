@@ -0,0 +1,137 @@
+package postgres
+
+import (
+	"errors"
+	"github.com/rinuccia/travels-api/pkg/apperrors"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	sqlmock "github.com/zhashkevych/go-sqlxmock"
+	"testing"
+)
+
+func TestOwnerRepo_FindVisitOwner(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	repository := newOwnerRepo(db)
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		want    uint32
+		wantErr bool
+	}{
+		{
+			name: "Ok",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"user_id"}).AddRow(3)
+				mock.ExpectQuery("SELECT user_id FROM visits WHERE visit_id = (.+)").
+					WithArgs("10").WillReturnRows(rows)
+			},
+			want: 3,
+		},
+		{
+			name: "Not Found",
+			mock: func() {
+				mock.ExpectQuery("SELECT user_id FROM visits").
+					WithArgs("10").WillReturnRows(sqlmock.NewRows([]string{"user_id"}))
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := repository.FindVisitOwner("10")
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, apperrors.ErrRecordNotFound)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestOwnerRepo_FindTripOwner(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	repository := newOwnerRepo(db)
+
+	rows := sqlmock.NewRows([]string{"user_id"}).AddRow(3)
+	mock.ExpectQuery("SELECT user_id FROM trips WHERE trip_id = (.+)").
+		WithArgs("5").WillReturnRows(rows)
+
+	got, err := repository.FindTripOwner("5")
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(3), got)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOwnerRepo_IsHidden(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	repository := newOwnerRepo(db)
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		want    bool
+		wantErr bool
+	}{
+		{
+			name: "Private",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"hidden"}).AddRow(true)
+				mock.ExpectQuery("SELECT users.private AND NOT EXISTS (.+) FROM users WHERE user_id = (.+)").
+					WithArgs("3", uint32(5)).WillReturnRows(rows)
+			},
+			want: true,
+		},
+		{
+			name: "Unknown User",
+			mock: func() {
+				mock.ExpectQuery("SELECT users.private").
+					WithArgs("3", uint32(5)).WillReturnRows(sqlmock.NewRows([]string{"hidden"}))
+			},
+		},
+		{
+			name: "Error",
+			mock: func() {
+				mock.ExpectQuery("SELECT users.private").
+					WithArgs("3", uint32(5)).WillReturnError(errors.New("db down"))
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := repository.IsHidden("3", 5)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
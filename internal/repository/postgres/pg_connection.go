@@ -33,13 +33,194 @@ var (
 		user_id int not null references users(user_id),
 		visited_at varchar(10) not null,
 		mark int not null
-	)`
+	);
+
+	ALTER TABLE users ADD COLUMN IF NOT EXISTS updated_at timestamptz not null default now();
+	ALTER TABLE locations ADD COLUMN IF NOT EXISTS updated_at timestamptz not null default now();
+	ALTER TABLE visits ADD COLUMN IF NOT EXISTS updated_at timestamptz not null default now();
+
+	ALTER TABLE locations ADD COLUMN IF NOT EXISTS lat double precision check (lat BETWEEN -90 AND 90);
+	ALTER TABLE locations ADD COLUMN IF NOT EXISTS lon double precision check (lon BETWEEN -180 AND 180);
+	CREATE INDEX IF NOT EXISTS locations_lat_lon_idx ON locations (lat, lon);
+
+	CREATE TABLE IF NOT EXISTS categories
+	(
+		category_id serial not null unique,
+		name varchar(50) not null unique
+	);
+
+	ALTER TABLE locations ADD COLUMN IF NOT EXISTS category_id int;
+
+	CREATE TABLE IF NOT EXISTS tags
+	(
+		tag_id serial not null unique,
+		name varchar(50) not null unique
+	);
+
+	CREATE TABLE IF NOT EXISTS location_tags
+	(
+		location_id int not null references locations(location_id) on delete cascade,
+		tag_id int not null references tags(tag_id) on delete cascade,
+		primary key (location_id, tag_id)
+	);
+
+	CREATE TABLE IF NOT EXISTS countries
+	(
+		code char(2) not null primary key,
+		alpha3 char(3) not null unique,
+		name varchar(50) not null
+	);
+
+	ALTER TABLE locations ADD COLUMN IF NOT EXISTS country_code char(2) references countries(code);
+	CREATE INDEX IF NOT EXISTS locations_country_code_idx ON locations (country_code);
+
+	CREATE TABLE IF NOT EXISTS trips
+	(
+		trip_id serial not null unique,
+		user_id int not null references users(user_id) on delete cascade,
+		name varchar(100) not null,
+		updated_at timestamptz not null default now()
+	);
+
+	ALTER TABLE visits ADD COLUMN IF NOT EXISTS trip_id int;
+	CREATE INDEX IF NOT EXISTS visits_trip_id_idx ON visits (trip_id);
+
+	CREATE TABLE IF NOT EXISTS wishlist
+	(
+		user_id int not null references users(user_id) on delete cascade,
+		location_id int not null references locations(location_id) on delete cascade,
+		planned_at varchar(10),
+		priority int not null default 3 check (priority BETWEEN 1 AND 5),
+		done_visit_id int references visits(visit_id) on delete set null,
+		primary key (user_id, location_id)
+	);
+	CREATE INDEX IF NOT EXISTS wishlist_location_id_idx ON wishlist (location_id) WHERE done_visit_id IS NULL;
+
+	CREATE TABLE IF NOT EXISTS reviews
+	(
+		visit_id int not null primary key references visits(visit_id) on delete cascade,
+		title varchar(100) not null,
+		body text not null,
+		language varchar(35) not null,
+		status varchar(10) not null default 'pending' check (status IN ('pending', 'approved', 'rejected')),
+		updated_at timestamptz not null default now()
+	);
+	CREATE INDEX IF NOT EXISTS reviews_status_idx ON reviews (status);
+
+	CREATE TABLE IF NOT EXISTS photos
+	(
+		photo_id serial not null unique,
+		location_id int references locations(location_id) on delete cascade,
+		visit_id int references visits(visit_id) on delete cascade,
+		storage_key varchar(64) not null unique,
+		content_type varchar(20) not null,
+		size int not null,
+		width int not null,
+		height int not null,
+		created_at timestamptz not null default now(),
+		check ((location_id IS NULL) <> (visit_id IS NULL))
+	);
+	CREATE INDEX IF NOT EXISTS photos_location_id_idx ON photos (location_id);
+	CREATE INDEX IF NOT EXISTS photos_visit_id_idx ON photos (visit_id);
+
+	ALTER TABLE users ADD COLUMN IF NOT EXISTS private boolean not null default false;
+
+	CREATE TABLE IF NOT EXISTS follows
+	(
+		follower_id int not null references users(user_id) on delete cascade,
+		followee_id int not null references users(user_id) on delete cascade,
+		approved boolean not null,
+		created_at timestamptz not null default now(),
+		primary key (follower_id, followee_id),
+		check (follower_id <> followee_id)
+	);
+	CREATE INDEX IF NOT EXISTS follows_followee_id_idx ON follows (followee_id);
+	CREATE INDEX IF NOT EXISTS visits_feed_idx ON visits (user_id, visited_at DESC, visit_id DESC);
+
+	ALTER TABLE users ADD COLUMN IF NOT EXISTS password_hash bytea;
+
+	CREATE TABLE IF NOT EXISTS refresh_tokens
+	(
+		token_hash char(64) not null primary key,
+		user_id int not null references users(user_id) on delete cascade,
+		expires_at timestamptz not null,
+		created_at timestamptz not null default now()
+	);
+	CREATE INDEX IF NOT EXISTS refresh_tokens_user_id_idx ON refresh_tokens (user_id);
+
+	ALTER TABLE users ADD COLUMN IF NOT EXISTS role varchar(10) not null default 'traveller'
+		check (role IN ('traveller', 'curator', 'admin'));
+
+	CREATE TABLE IF NOT EXISTS api_keys
+	(
+		key_id serial not null unique,
+		name varchar(100) not null,
+		key_hash char(64) not null unique,
+		prefix varchar(12) not null,
+		scopes text[] not null,
+		expires_at timestamptz,
+		created_at timestamptz not null default now(),
+		revoked_at timestamptz,
+		last_used_at timestamptz,
+		usage_count bigint not null default 0
+	);
+	ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS role varchar(10) not null default 'curator'
+		check (role IN ('curator', 'admin'));
+
+	CREATE TABLE IF NOT EXISTS audit_log
+	(
+		audit_id bigserial not null primary key,
+		actor varchar(30) not null,
+		action varchar(10) not null,
+		entity_type varchar(20) not null,
+		entity_id varchar(20) not null,
+		before jsonb,
+		after jsonb,
+		request_id varchar(64) not null,
+		created_at timestamptz not null default now()
+	);
+	CREATE INDEX IF NOT EXISTS audit_log_entity_idx ON audit_log (entity_type, entity_id);
+	CREATE INDEX IF NOT EXISTS audit_log_actor_idx ON audit_log (actor);
+	CREATE INDEX IF NOT EXISTS audit_log_created_at_idx ON audit_log (created_at);
+
+	ALTER TABLE visits ADD COLUMN IF NOT EXISTS deleted_at timestamptz;
+	ALTER TABLE trips ADD COLUMN IF NOT EXISTS deleted_at timestamptz;
+	ALTER TABLE categories ADD COLUMN IF NOT EXISTS deleted_at timestamptz;
+	ALTER TABLE wishlist ADD COLUMN IF NOT EXISTS deleted_at timestamptz;
+	CREATE INDEX IF NOT EXISTS visits_deleted_at_idx ON visits (deleted_at) WHERE deleted_at IS NOT NULL;
+	CREATE INDEX IF NOT EXISTS trips_deleted_at_idx ON trips (deleted_at) WHERE deleted_at IS NOT NULL;
+	CREATE INDEX IF NOT EXISTS categories_deleted_at_idx ON categories (deleted_at) WHERE deleted_at IS NOT NULL;
+	CREATE INDEX IF NOT EXISTS wishlist_deleted_at_idx ON wishlist (deleted_at) WHERE deleted_at IS NOT NULL;
+	ALTER TABLE categories DROP CONSTRAINT IF EXISTS categories_name_key;
+
+	ALTER TABLE users ADD COLUMN IF NOT EXISTS erased_at timestamptz;
+	CREATE TABLE IF NOT EXISTS erasure_log
+	(
+		erasure_id bigserial primary key,
+		user_id int not null,
+		actor varchar(30) not null,
+		request_id varchar(64) not null,
+		erased_at timestamptz not null,
+		prev_hash char(64) not null,
+		hash char(64) not null unique
+	);
+
+	ALTER TABLE users ADD COLUMN IF NOT EXISTS verified boolean not null default false;
+	CREATE TABLE IF NOT EXISTS email_verifications
+	(
+		token_id char(32) not null primary key,
+		user_id int not null references users(user_id) on delete cascade,
+		email varchar(100) not null,
+		expires_at timestamptz not null,
+		used_at timestamptz,
+		created_at timestamptz not null default now()
+	);
+	CREATE INDEX IF NOT EXISTS email_verifications_user_id_idx ON email_verifications (user_id);`
 )
 
+// NewPostgresClient connects as the owner of the tables, which sees the rows of every tenant, and migrates the schema.
 func NewPostgresClient(cfg *config.Config) (*sqlx.DB, error) {
-	url := fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable",
-		cfg.DB.Username, os.Getenv("DB_PASSWORD"), cfg.DB.Host, cfg.DB.Port, cfg.DB.DBName)
-	db, err := sqlx.Open("postgres", url)
+	db, err := sqlx.Open("postgres", dataSource(cfg))
 	if err != nil {
 		return nil, err
 	}
@@ -50,8 +231,22 @@ func NewPostgresClient(cfg *config.Config) (*sqlx.DB, error) {
 	if err = db.Ping(); err != nil {
 		return nil, err
 	}
+	if err = requireRLSBypass(db); err != nil {
+		db.Close()
+		return nil, err
+	}
 
 	db.MustExec(schema)
+	db.MustExec(tenantSchema())
+
+	if err = seedCountries(db); err != nil {
+		return nil, err
+	}
 
 	return db, nil
 }
+
+func dataSource(cfg *config.Config) string {
+	return fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable",
+		cfg.DB.Username, os.Getenv("DB_PASSWORD"), cfg.DB.Host, cfg.DB.Port, cfg.DB.DBName)
+}
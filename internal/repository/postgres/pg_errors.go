@@ -0,0 +1,24 @@
+package postgres
+
+import (
+	"errors"
+	"github.com/lib/pq"
+	"github.com/rinuccia/travels-api/pkg/apperrors"
+)
+
+// writeError classifies the error of a write: ErrAlreadyExists for a unique violation, ErrIncorrectQuery for
+// other rejected data, anything else such as a lost connection is returned as is.
+func writeError(err error) error {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return err
+	}
+	switch {
+	case pqErr.Code == "23505":
+		return apperrors.ErrAlreadyExists
+	case pqErr.Code.Class() == "22" || pqErr.Code.Class() == "23":
+		return apperrors.ErrIncorrectQuery
+	default:
+		return err
+	}
+}
@@ -0,0 +1,107 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/jmoiron/sqlx"
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/pkg/apperrors"
+)
+
+const selectPhotoColumns = `
+			SELECT photo_id, COALESCE(location_id, 0), COALESCE(visit_id, 0), storage_key, content_type,
+				   size, width, height, created_at
+			FROM photos`
+
+type photoRepo struct {
+	*sqlx.DB
+}
+
+func newPhotoRepo(db *sqlx.DB) *photoRepo {
+	return &photoRepo{db}
+}
+
+func (r *photoRepo) FindByLocation(id string) (model.Photos, error) {
+	return r.findPhotos(selectLocationId, "location_id", id)
+}
+
+func (r *photoRepo) FindByVisit(id string) (model.Photos, error) {
+	query := "SELECT visit_id FROM visits WHERE visit_id = $1 AND deleted_at IS NULL AND tenant_id = current_tenant()"
+	return r.findPhotos(query, "visit_id", id)
+}
+
+func (r *photoRepo) FindById(id string) (model.Photo, error) {
+	photo := model.Photo{}
+	query := selectPhotoColumns + `
+			WHERE photo_id = $1
+			  AND tenant_id = current_tenant()
+			  AND NOT EXISTS (SELECT 1
+							  FROM visits
+							  WHERE visits.visit_id = photos.visit_id AND visits.tenant_id = photos.tenant_id
+								AND visits.deleted_at IS NOT NULL)`
+	row := r.QueryRow(query, id)
+	if err := scanPhoto(row, &photo); err != nil {
+		return photo, apperrors.ErrRecordNotFound
+	}
+	return photo, nil
+}
+
+func (r *photoRepo) InsertForLocation(id string, photo model.Photo) (model.Photo, error) {
+	query := `
+			INSERT INTO photos (location_id, storage_key, content_type, size, width, height)
+			SELECT location_id, $2, $3, $4, $5, $6 FROM locations WHERE location_id = $1 AND tenant_id = current_tenant()
+			RETURNING photo_id, location_id, created_at`
+	row := r.QueryRow(query, id, photo.StorageKey, photo.ContentType, photo.Size, photo.Width, photo.Height)
+	return photo, insertedPhoto(row.Scan(&photo.PhotoId, &photo.LocationId, &photo.CreatedAt))
+}
+
+func (r *photoRepo) InsertForVisit(id string, photo model.Photo) (model.Photo, error) {
+	query := `
+			INSERT INTO photos (visit_id, storage_key, content_type, size, width, height)
+			SELECT visit_id, $2, $3, $4, $5, $6 FROM visits
+			WHERE visit_id = $1 AND deleted_at IS NULL AND tenant_id = current_tenant()
+			RETURNING photo_id, visit_id, created_at`
+	row := r.QueryRow(query, id, photo.StorageKey, photo.ContentType, photo.Size, photo.Width, photo.Height)
+	return photo, insertedPhoto(row.Scan(&photo.PhotoId, &photo.VisitId, &photo.CreatedAt))
+}
+
+// insertedPhoto maps the result of a photo insert, nothing is inserted when the owner does not exist.
+func insertedPhoto(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return apperrors.ErrRecordNotFound
+	}
+	if err != nil {
+		return apperrors.ErrIncorrectQuery
+	}
+	return nil
+}
+
+// findPhotos checks the owner exists with ownerQuery and returns its photos matched on column, oldest first.
+func (r *photoRepo) findPhotos(ownerQuery, column, id string) (model.Photos, error) {
+	var ownerId int
+	row := r.QueryRow(ownerQuery, id)
+	if err := row.Scan(&ownerId); err != nil {
+		return model.Photos{}, apperrors.ErrRecordNotFound
+	}
+
+	photo := model.Photo{}
+	photos := model.Photos{}
+	query := selectPhotoColumns + " WHERE " + column + " = $1 AND tenant_id = current_tenant() ORDER BY photo_id"
+	rows, err := r.Query(query, id)
+	if err != nil {
+		return photos, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		if err = scanPhoto(rows, &photo); err != nil {
+			return photos, err
+		}
+		photos.List = append(photos.List, photo)
+	}
+	return photos, rows.Err()
+}
+
+func scanPhoto(row scanner, photo *model.Photo) error {
+	return row.Scan(&photo.PhotoId, &photo.LocationId, &photo.VisitId, &photo.StorageKey, &photo.ContentType,
+		&photo.Size, &photo.Width, &photo.Height, &photo.CreatedAt)
+}
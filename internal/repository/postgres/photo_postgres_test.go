@@ -0,0 +1,216 @@
+package postgres
+
+import (
+	"database/sql"
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/pkg/apperrors"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	sqlmock "github.com/zhashkevych/go-sqlxmock"
+	"testing"
+	"time"
+)
+
+var photoColumns = []string{"photo_id", "location_id", "visit_id", "storage_key", "content_type", "size", "width", "height", "created_at"}
+
+func TestPhotoRepo_FindByLocation(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	repository := newPhotoRepo(db)
+	createdAt := time.Date(2019, 6, 15, 10, 0, 0, 0, time.UTC)
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		want    model.Photos
+		wantErr bool
+	}{
+		{
+			name: "Ok",
+			mock: func() {
+				mock.ExpectQuery("SELECT location_id FROM locations").WithArgs("1").
+					WillReturnRows(sqlmock.NewRows([]string{"location_id"}).AddRow(1))
+				rows := sqlmock.NewRows(photoColumns).
+					AddRow(3, 1, 0, "ab12", "image/jpeg", 52000, 800, 600, createdAt)
+				mock.ExpectQuery("SELECT (.+) FROM photos WHERE location_id = (.+) ORDER BY photo_id").
+					WithArgs("1").WillReturnRows(rows)
+			},
+			want: model.Photos{
+				List: []model.Photo{
+					{
+						PhotoId:     3,
+						LocationId:  1,
+						StorageKey:  "ab12",
+						ContentType: "image/jpeg",
+						Size:        52000,
+						Width:       800,
+						Height:      600,
+						CreatedAt:   createdAt,
+					},
+				},
+			},
+		},
+		{
+			name: "Not Found",
+			mock: func() {
+				mock.ExpectQuery("SELECT location_id FROM locations").WithArgs("1").
+					WillReturnError(sql.ErrNoRows)
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := repository.FindByLocation("1")
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, apperrors.ErrRecordNotFound)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestPhotoRepo_FindById(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	repository := newPhotoRepo(db)
+	createdAt := time.Date(2019, 6, 15, 10, 0, 0, 0, time.UTC)
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		want    model.Photo
+		wantErr bool
+	}{
+		{
+			name: "Ok",
+			mock: func() {
+				rows := sqlmock.NewRows(photoColumns).
+					AddRow(3, 0, 7, "ab12", "image/png", 1200, 40, 30, createdAt)
+				mock.ExpectQuery("SELECT (.+) FROM photos WHERE photo_id = (.+)").
+					WithArgs("3").WillReturnRows(rows)
+			},
+			want: model.Photo{
+				PhotoId:     3,
+				VisitId:     7,
+				StorageKey:  "ab12",
+				ContentType: "image/png",
+				Size:        1200,
+				Width:       40,
+				Height:      30,
+				CreatedAt:   createdAt,
+			},
+		},
+		{
+			name: "Not Found",
+			mock: func() {
+				mock.ExpectQuery("SELECT (.+) FROM photos WHERE photo_id = (.+)").
+					WithArgs("3").WillReturnRows(sqlmock.NewRows(photoColumns))
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := repository.FindById("3")
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, apperrors.ErrRecordNotFound)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestPhotoRepo_InsertForVisit(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	repository := newPhotoRepo(db)
+	createdAt := time.Date(2019, 6, 15, 10, 0, 0, 0, time.UTC)
+	input := model.Photo{StorageKey: "ab12", ContentType: "image/jpeg", Size: 52000, Width: 800, Height: 600}
+
+	testTable := []struct {
+		name            string
+		mock            func()
+		want            model.Photo
+		wantErr         bool
+		expectedErrType error
+	}{
+		{
+			name: "Ok",
+			mock: func() {
+				mock.ExpectQuery("INSERT INTO photos (.+) SELECT visit_id, (.+) FROM visits WHERE visit_id = (.+) AND tenant_id = (.+) RETURNING").
+					WithArgs("7", "ab12", "image/jpeg", int64(52000), 800, 600).
+					WillReturnRows(sqlmock.NewRows([]string{"photo_id", "visit_id", "created_at"}).AddRow(3, 7, createdAt))
+			},
+			want: model.Photo{
+				PhotoId:     3,
+				VisitId:     7,
+				StorageKey:  "ab12",
+				ContentType: "image/jpeg",
+				Size:        52000,
+				Width:       800,
+				Height:      600,
+				CreatedAt:   createdAt,
+			},
+		},
+		{
+			name: "Visit Not Found",
+			mock: func() {
+				mock.ExpectQuery("INSERT INTO photos").
+					WithArgs("7", "ab12", "image/jpeg", int64(52000), 800, 600).
+					WillReturnRows(sqlmock.NewRows([]string{"photo_id", "visit_id", "created_at"}))
+			},
+			wantErr:         true,
+			expectedErrType: apperrors.ErrRecordNotFound,
+		},
+		{
+			name: "Incorrect Query",
+			mock: func() {
+				mock.ExpectQuery("INSERT INTO photos").
+					WithArgs("7", "ab12", "image/jpeg", int64(52000), 800, 600).
+					WillReturnError(sql.ErrConnDone)
+			},
+			wantErr:         true,
+			expectedErrType: apperrors.ErrIncorrectQuery,
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := repository.InsertForVisit("7", input)
+
+			if tt.wantErr {
+				assert.Equal(t, tt.expectedErrType, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,237 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/pkg/apperrors"
+	"time"
+)
+
+// subjectSections select every kind of record linked to a user as json, in the order they are exported.
+// Secrets and storage internals are left out, the profile section finds no row for unknown users.
+// Audit records of changes the user made to other entities keep their metadata only, the states before and
+// after the change belong to someone else. Every section reads the tenant only.
+var subjectSections = []struct {
+	name  string
+	query string
+}{
+	{"profile", `
+			SELECT to_jsonb(u) - 'password_hash'
+			FROM users u
+			WHERE u.user_id = $1::int AND u.tenant_id = current_tenant()`},
+	{"visits", `
+			SELECT COALESCE(jsonb_agg(to_jsonb(v) ORDER BY v.visit_id), '[]')
+			FROM visits v
+			WHERE v.user_id = $1::int AND v.tenant_id = current_tenant()`},
+	{"reviews", `
+			SELECT COALESCE(jsonb_agg(to_jsonb(r) ORDER BY r.visit_id), '[]')
+			FROM reviews r
+				JOIN visits v
+					ON v.visit_id = r.visit_id AND v.tenant_id = r.tenant_id
+			WHERE v.user_id = $1::int AND v.tenant_id = current_tenant()`},
+	{"photos", `
+			SELECT COALESCE(jsonb_agg(to_jsonb(p) - 'storage_key' ORDER BY p.photo_id), '[]')
+			FROM photos p
+				JOIN visits v
+					ON v.visit_id = p.visit_id AND v.tenant_id = p.tenant_id
+			WHERE v.user_id = $1::int AND v.tenant_id = current_tenant()`},
+	{"trips", `
+			SELECT COALESCE(jsonb_agg(to_jsonb(t) ORDER BY t.trip_id), '[]')
+			FROM trips t
+			WHERE t.user_id = $1::int AND t.tenant_id = current_tenant()`},
+	{"wishlist", `
+			SELECT COALESCE(jsonb_agg(to_jsonb(w) ORDER BY w.location_id), '[]')
+			FROM wishlist w
+			WHERE w.user_id = $1::int AND w.tenant_id = current_tenant()`},
+	{"follows", `
+			SELECT COALESCE(jsonb_agg(to_jsonb(f) ORDER BY f.created_at), '[]')
+			FROM follows f
+			WHERE (f.follower_id = $1::int OR f.followee_id = $1::int) AND f.tenant_id = current_tenant()`},
+	{"sessions", `
+			SELECT COALESCE(jsonb_agg(to_jsonb(s) - 'token_hash' ORDER BY s.created_at), '[]')
+			FROM refresh_tokens s
+			WHERE s.user_id = $1::int AND s.tenant_id = current_tenant()`},
+	{"verifications", `
+			SELECT COALESCE(jsonb_agg(to_jsonb(e) ORDER BY e.created_at), '[]')
+			FROM email_verifications e
+			WHERE e.user_id = $1::int AND e.tenant_id = current_tenant()`},
+	{"audit", `
+			SELECT COALESCE(jsonb_agg(CASE WHEN a.entity_type = 'user' AND a.entity_id = $1::text
+											   THEN to_jsonb(a)
+										   ELSE to_jsonb(a) - 'before' - 'after' END
+									  ORDER BY a.audit_id), '[]')
+			FROM audit_log a
+			WHERE ((a.entity_type = 'user' AND a.entity_id = $1::text) OR a.actor = 'user:' || $1::text)
+			  AND a.tenant_id = current_tenant()`},
+}
+
+// erasedUserFields are removed from user snapshots in the audit log on erasure.
+var erasedUserFields = []string{"email", "first_name", "last_name"}
+
+// erasedRecords delete the content of a user on erasure, visits stay for the statistics of their locations.
+// Deleted visits are not statistics, they go with their reviews and photos.
+var erasedRecords = []string{
+	`
+			DELETE FROM photos
+			WHERE visit_id IN (SELECT visit_id FROM visits WHERE user_id = $1 AND tenant_id = current_tenant())
+			  AND tenant_id = current_tenant()`,
+	`
+			DELETE FROM reviews
+			WHERE visit_id IN (SELECT visit_id FROM visits WHERE user_id = $1 AND tenant_id = current_tenant())
+			  AND tenant_id = current_tenant()`,
+	"DELETE FROM visits WHERE user_id = $1 AND deleted_at IS NOT NULL AND tenant_id = current_tenant()",
+	"DELETE FROM trips WHERE user_id = $1 AND tenant_id = current_tenant()",
+	"DELETE FROM wishlist WHERE user_id = $1 AND tenant_id = current_tenant()",
+	"DELETE FROM follows WHERE (follower_id = $1 OR followee_id = $1) AND tenant_id = current_tenant()",
+	"DELETE FROM refresh_tokens WHERE user_id = $1 AND tenant_id = current_tenant()",
+	"DELETE FROM email_verifications WHERE user_id = $1 AND tenant_id = current_tenant()",
+}
+
+type privacyRepo struct {
+	*sqlx.DB
+}
+
+func newPrivacyRepo(db *sqlx.DB) *privacyRepo {
+	return &privacyRepo{db}
+}
+
+// FindSubjectData reads every section from one snapshot so the sections agree with each other.
+func (r *privacyRepo) FindSubjectData(id string) (model.SubjectData, error) {
+	data := model.SubjectData{}
+	tx, err := r.BeginTxx(context.Background(), &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return data, err
+	}
+	defer tx.Rollback()
+
+	for _, section := range subjectSections {
+		var state []byte
+		if err = tx.QueryRow(section.query, id).Scan(&state); err != nil {
+			if section.name == "profile" {
+				return data, apperrors.ErrRecordNotFound
+			}
+			return data, err
+		}
+		data.Sections = append(data.Sections, model.SubjectSection{Name: section.name, Data: state})
+	}
+
+	data.Photos, err = findUserPhotos(tx, id)
+	if err != nil {
+		return data, err
+	}
+	return data, tx.Commit()
+}
+
+// Erase anonymises the user row, deletes the content of the user and appends the erasure to the log.
+// The log is locked while the entry is chained to the last one, each tenant has a chain of its own.
+func (r *privacyRepo) Erase(actor model.Actor, id string) (model.Erasure, []model.Photo, error) {
+	erasure := model.Erasure{
+		Actor:     actor.Actor,
+		RequestId: actor.RequestId,
+		ErasedAt:  time.Now().UTC().Truncate(time.Microsecond),
+	}
+	tx, err := r.Beginx()
+	if err != nil {
+		return erasure, nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+			UPDATE users SET email = 'erased-' || user_id || '@erased.invalid', first_name = '', last_name = '',
+							 password_hash = NULL, verified = false, erased_at = $2, updated_at = now()
+			WHERE user_id = $1 AND erased_at IS NULL AND tenant_id = current_tenant()
+			RETURNING user_id`
+	if err = tx.QueryRow(query, id, erasure.ErasedAt).Scan(&erasure.UserId); err != nil {
+		return erasure, nil, apperrors.ErrRecordNotFound
+	}
+
+	photos, err := findUserPhotos(tx, id)
+	if err != nil {
+		return erasure, nil, err
+	}
+	for _, query = range erasedRecords {
+		if _, err = tx.Exec(query, erasure.UserId); err != nil {
+			return erasure, nil, err
+		}
+	}
+	query = `
+			UPDATE audit_log SET before = before - $2::text[], after = after - $2::text[]
+			WHERE entity_type = $3 AND entity_id = $1 AND tenant_id = current_tenant()`
+	_, err = tx.Exec(query, entityId(erasure.UserId), pq.Array(erasedUserFields), model.AuditEntityUser)
+	if err != nil {
+		return erasure, nil, err
+	}
+
+	if _, err = tx.Exec("LOCK TABLE erasure_log IN EXCLUSIVE MODE"); err != nil {
+		return erasure, nil, err
+	}
+	erasure.PrevHash = model.ErasureGenesisHash
+	query = "SELECT hash FROM erasure_log WHERE tenant_id = current_tenant() ORDER BY erasure_id DESC LIMIT 1"
+	err = tx.QueryRow(query).Scan(&erasure.PrevHash)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return erasure, nil, err
+	}
+	erasure.Hash = erasure.ChainHash()
+	query = `
+			INSERT INTO erasure_log (user_id, actor, request_id, erased_at, prev_hash, hash)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING erasure_id`
+	row := tx.QueryRow(query, erasure.UserId, erasure.Actor, erasure.RequestId, erasure.ErasedAt, erasure.PrevHash,
+		erasure.Hash)
+	if err = row.Scan(&erasure.ErasureId); err != nil {
+		return erasure, nil, err
+	}
+
+	return erasure, photos, tx.Commit()
+}
+
+func (r *privacyRepo) FindErasures() ([]model.Erasure, error) {
+	query := `
+			SELECT erasure_id, user_id, actor, request_id, erased_at, prev_hash, hash
+			FROM erasure_log
+			WHERE tenant_id = current_tenant()
+			ORDER BY erasure_id`
+	erasure := model.Erasure{}
+	erasures := []model.Erasure{}
+	rows, err := r.Query(query)
+	if err != nil {
+		return erasures, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		err = rows.Scan(&erasure.ErasureId, &erasure.UserId, &erasure.Actor, &erasure.RequestId, &erasure.ErasedAt,
+			&erasure.PrevHash, &erasure.Hash)
+		if err != nil {
+			return erasures, err
+		}
+		erasures = append(erasures, erasure)
+	}
+	return erasures, rows.Err()
+}
+
+// findUserPhotos returns the photos of the visits of a user, oldest first.
+func findUserPhotos(tx *sqlx.Tx, userId string) ([]model.Photo, error) {
+	query := selectPhotoColumns + `
+			WHERE visit_id IN (SELECT visit_id FROM visits WHERE user_id = $1 AND tenant_id = current_tenant())
+			  AND tenant_id = current_tenant()
+			ORDER BY photo_id`
+	rows, err := tx.Query(query, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var photos []model.Photo
+	photo := model.Photo{}
+	for rows.Next() {
+		if err = scanPhoto(rows, &photo); err != nil {
+			return nil, err
+		}
+		photos = append(photos, photo)
+	}
+	return photos, rows.Err()
+}
@@ -0,0 +1,236 @@
+package postgres
+
+import (
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/pkg/apperrors"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	sqlmock "github.com/zhashkevych/go-sqlxmock"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPrivacyRepo_FindSubjectData(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	repository := newPrivacyRepo(db)
+	createdAt := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		want    model.SubjectData
+		wantErr error
+	}{
+		{
+			name: "Ok",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT to_jsonb\\(u\\) - 'password_hash' FROM users").WithArgs("1").
+					WillReturnRows(sqlmock.NewRows([]string{"to_jsonb"}).AddRow([]byte(`{"user_id":1}`)))
+				for range subjectSections[1 : len(subjectSections)-1] {
+					mock.ExpectQuery("SELECT COALESCE\\(jsonb_agg").WithArgs("1").
+						WillReturnRows(sqlmock.NewRows([]string{"coalesce"}).AddRow([]byte("[]")))
+				}
+				mock.ExpectQuery("SELECT COALESCE\\(jsonb_agg\\(CASE WHEN a.entity_type = 'user' AND a.entity_id = (.+) " +
+					"THEN to_jsonb\\(a\\) ELSE to_jsonb\\(a\\) - 'before' - 'after' END").WithArgs("1").
+					WillReturnRows(sqlmock.NewRows([]string{"coalesce"}).AddRow([]byte("[]")))
+				mock.ExpectQuery("SELECT (.+) FROM photos WHERE visit_id IN \\(SELECT visit_id FROM visits WHERE user_id = (.+)\\)").
+					WithArgs("1").
+					WillReturnRows(sqlmock.NewRows(photoColumns).AddRow(4, 0, 1, "ab12", "image/jpeg", 100, 640, 480, createdAt))
+				mock.ExpectCommit()
+			},
+			want: model.SubjectData{
+				Sections: []model.SubjectSection{
+					{Name: "profile", Data: []byte(`{"user_id":1}`)},
+					{Name: "visits", Data: []byte("[]")},
+					{Name: "reviews", Data: []byte("[]")},
+					{Name: "photos", Data: []byte("[]")},
+					{Name: "trips", Data: []byte("[]")},
+					{Name: "wishlist", Data: []byte("[]")},
+					{Name: "follows", Data: []byte("[]")},
+					{Name: "sessions", Data: []byte("[]")},
+					{Name: "verifications", Data: []byte("[]")},
+					{Name: "audit", Data: []byte("[]")},
+				},
+				Photos: []model.Photo{{PhotoId: 4, VisitId: 1, StorageKey: "ab12", ContentType: "image/jpeg",
+					Size: 100, Width: 640, Height: 480, CreatedAt: createdAt}},
+			},
+		},
+		{
+			name: "Not Found",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT to_jsonb\\(u\\) - 'password_hash' FROM users").WithArgs("1").
+					WillReturnRows(sqlmock.NewRows([]string{"to_jsonb"}))
+				mock.ExpectRollback()
+			},
+			wantErr: apperrors.ErrRecordNotFound,
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := repository.FindSubjectData("1")
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestPrivacyRepo_Erase(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	repository := newPrivacyRepo(db)
+	createdAt := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	prevHash := "f" + model.ErasureGenesisHash[1:]
+
+	expectErase := func() {
+		mock.ExpectBegin()
+		mock.ExpectQuery("UPDATE users SET (.+) WHERE user_id = (.+) AND erased_at IS NULL AND tenant_id = current_tenant\\(\\) RETURNING user_id").
+			WithArgs("1", sqlmock.AnyArg()).WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(1))
+		mock.ExpectQuery("SELECT (.+) FROM photos WHERE visit_id IN").WithArgs("1").
+			WillReturnRows(sqlmock.NewRows(photoColumns).AddRow(4, 0, 1, "ab12", "image/jpeg", 100, 640, 480, createdAt))
+		for _, query := range erasedRecords {
+			mock.ExpectExec(regexp.QuoteMeta(strings.Join(strings.Fields(query), " "))).WithArgs(uint32(1)).WillReturnResult(sqlmock.NewResult(0, 1))
+		}
+		mock.ExpectExec("UPDATE audit_log SET before = before - (.+), after = after - (.+) WHERE entity_type = (.+) AND entity_id = (.+)").
+			WithArgs("1", sqlmock.AnyArg(), "user").WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec("LOCK TABLE erasure_log IN EXCLUSIVE MODE").WillReturnResult(sqlmock.NewResult(0, 0))
+	}
+
+	testTable := []struct {
+		name         string
+		mock         func()
+		wantPrevHash string
+		wantPhotos   []model.Photo
+		wantErr      error
+	}{
+		{
+			name: "Ok",
+			mock: func() {
+				expectErase()
+				mock.ExpectQuery("SELECT hash FROM erasure_log WHERE tenant_id = current_tenant\\(\\) ORDER BY erasure_id DESC LIMIT 1").
+					WillReturnRows(sqlmock.NewRows([]string{"hash"}).AddRow(prevHash))
+				mock.ExpectQuery("INSERT INTO erasure_log").
+					WithArgs(uint32(1), testActor.Actor, testActor.RequestId, sqlmock.AnyArg(), prevHash, sqlmock.AnyArg()).
+					WillReturnRows(sqlmock.NewRows([]string{"erasure_id"}).AddRow(7))
+				mock.ExpectCommit()
+			},
+			wantPrevHash: prevHash,
+			wantPhotos: []model.Photo{{PhotoId: 4, VisitId: 1, StorageKey: "ab12", ContentType: "image/jpeg",
+				Size: 100, Width: 640, Height: 480, CreatedAt: createdAt}},
+		},
+		{
+			name: "First Erasure",
+			mock: func() {
+				expectErase()
+				mock.ExpectQuery("SELECT hash FROM erasure_log").WillReturnRows(sqlmock.NewRows([]string{"hash"}))
+				mock.ExpectQuery("INSERT INTO erasure_log").
+					WithArgs(uint32(1), testActor.Actor, testActor.RequestId, sqlmock.AnyArg(), model.ErasureGenesisHash,
+						sqlmock.AnyArg()).
+					WillReturnRows(sqlmock.NewRows([]string{"erasure_id"}).AddRow(1))
+				mock.ExpectCommit()
+			},
+			wantPrevHash: model.ErasureGenesisHash,
+			wantPhotos: []model.Photo{{PhotoId: 4, VisitId: 1, StorageKey: "ab12", ContentType: "image/jpeg",
+				Size: 100, Width: 640, Height: 480, CreatedAt: createdAt}},
+		},
+		{
+			name: "Not Found",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("UPDATE users SET").WithArgs("1", sqlmock.AnyArg()).
+					WillReturnRows(sqlmock.NewRows([]string{"user_id"}))
+				mock.ExpectRollback()
+			},
+			wantErr: apperrors.ErrRecordNotFound,
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, photos, err := repository.Erase(testActor, "1")
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, uint32(1), got.UserId)
+				assert.Equal(t, tt.wantPrevHash, got.PrevHash)
+				assert.Equal(t, got.ChainHash(), got.Hash)
+				assert.Equal(t, tt.wantPhotos, photos)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestPrivacyRepo_FindErasures(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	repository := newPrivacyRepo(db)
+	erasedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	columns := []string{"erasure_id", "user_id", "actor", "request_id", "erased_at", "prev_hash", "hash"}
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		want    []model.Erasure
+		wantErr bool
+	}{
+		{
+			name: "Ok",
+			mock: func() {
+				rows := sqlmock.NewRows(columns).AddRow(1, 2, "user:2", "req-1", erasedAt, "aa", "bb")
+				mock.ExpectQuery("SELECT (.+) FROM erasure_log WHERE tenant_id = current_tenant\\(\\) ORDER BY erasure_id").WillReturnRows(rows)
+			},
+			want: []model.Erasure{{ErasureId: 1, UserId: 2, Actor: "user:2", RequestId: "req-1", ErasedAt: erasedAt,
+				PrevHash: "aa", Hash: "bb"}},
+		},
+		{
+			name: "Query Error",
+			mock: func() {
+				mock.ExpectQuery("SELECT (.+) FROM erasure_log").WillReturnError(sqlmock.ErrCancelled)
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := repository.FindErasures()
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package postgres
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/rinuccia/travels-api/internal/model"
+	"time"
+)
+
+type purgeRepo struct {
+	*sqlx.DB
+}
+
+func newPurgeRepo(db *sqlx.DB) *purgeRepo {
+	return &purgeRepo{db}
+}
+
+// Purge hard deletes in one transaction, the photo rows, reviews and wishlist links of purged visits go with them.
+// Visits of purged trips and locations of purged categories are kept and lose the reference.
+func (r *purgeRepo) Purge(before time.Time) (model.Purged, error) {
+	purged := model.Purged{}
+	tx, err := r.Beginx()
+	if err != nil {
+		return purged, err
+	}
+	defer tx.Rollback()
+
+	query := selectPhotoColumns + `
+			WHERE visit_id IN (SELECT visit_id FROM visits WHERE deleted_at < $1 AND tenant_id = current_tenant())
+			  AND tenant_id = current_tenant()
+			ORDER BY photo_id`
+	rows, err := tx.Query(query, before)
+	if err != nil {
+		return purged, err
+	}
+	photo := model.Photo{}
+	for rows.Next() {
+		if err = scanPhoto(rows, &photo); err != nil {
+			rows.Close()
+			return purged, err
+		}
+		purged.Photos = append(purged.Photos, photo)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return purged, err
+	}
+
+	counts := []struct {
+		table string
+		count *int64
+	}{
+		{"visits", &purged.Visits},
+		{"trips", &purged.Trips},
+		{"categories", &purged.Categories},
+		{"wishlist", &purged.Wishlist},
+	}
+	for _, c := range counts {
+		res, err := tx.Exec("DELETE FROM "+c.table+" WHERE deleted_at < $1 AND tenant_id = current_tenant()", before)
+		if err != nil {
+			return purged, err
+		}
+		if *c.count, err = res.RowsAffected(); err != nil {
+			return purged, err
+		}
+	}
+
+	return purged, tx.Commit()
+}
@@ -0,0 +1,84 @@
+package postgres
+
+import (
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	sqlmock "github.com/zhashkevych/go-sqlxmock"
+	"testing"
+	"time"
+)
+
+func TestPurgeRepo_Purge(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	repository := newPurgeRepo(db)
+	before := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	createdAt := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	photoColumns := []string{"photo_id", "location_id", "visit_id", "storage_key", "content_type", "size", "width",
+		"height", "created_at"}
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		want    model.Purged
+		wantErr bool
+	}{
+		{
+			name: "Ok",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT (.+) FROM photos WHERE visit_id IN \\(SELECT visit_id FROM visits WHERE deleted_at < (.+) " +
+					"AND tenant_id = current_tenant\\(\\)\\)").
+					WithArgs(before).
+					WillReturnRows(sqlmock.NewRows(photoColumns).AddRow(4, 0, 1, "ab12", "image/jpeg", 100, 640, 480, createdAt))
+				mock.ExpectExec("DELETE FROM visits WHERE deleted_at < (.+)").WithArgs(before).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec("DELETE FROM trips WHERE deleted_at < (.+)").WithArgs(before).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("DELETE FROM categories WHERE deleted_at < (.+)").WithArgs(before).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("DELETE FROM wishlist WHERE deleted_at < (.+)").WithArgs(before).
+					WillReturnResult(sqlmock.NewResult(0, 3))
+				mock.ExpectCommit()
+			},
+			want: model.Purged{
+				Visits:   2,
+				Trips:    1,
+				Wishlist: 3,
+				Photos: []model.Photo{{PhotoId: 4, VisitId: 1, StorageKey: "ab12", ContentType: "image/jpeg",
+					Size: 100, Width: 640, Height: 480, CreatedAt: createdAt}},
+			},
+		},
+		{
+			name: "Delete Error",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT (.+) FROM photos").WithArgs(before).
+					WillReturnRows(sqlmock.NewRows(photoColumns))
+				mock.ExpectExec("DELETE FROM visits").WithArgs(before).WillReturnError(sqlmock.ErrCancelled)
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := repository.Purge(before)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
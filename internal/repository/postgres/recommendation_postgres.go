@@ -0,0 +1,157 @@
+package postgres
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/pkg/apperrors"
+)
+
+// likedMark is the lowest average mark that counts as liking a location.
+const likedMark = 4
+
+// maxNeighbours limits how many similar users take part in collaborative filtering.
+const maxNeighbours = 50
+
+type recommendationRepo struct {
+	*sqlx.DB
+}
+
+func newRecommendationRepo(db *sqlx.DB) *recommendationRepo {
+	return &recommendationRepo{db}
+}
+
+// FindBySimilarUsers weights every other user by how closely their marks match the user's marks on shared locations.
+// Locations the nearest users liked and the user has not visited are scored by the weighted average of their marks,
+// the reason is the user's location that contributed most to the agreement with those users.
+func (r *recommendationRepo) FindBySimilarUsers(id string, limit int) (model.Recommendations, error) {
+	if err := r.userExists(id); err != nil {
+		return model.Recommendations{}, err
+	}
+	query := `
+			WITH rated AS (
+				SELECT user_id, location_id, AVG(mark) AS mark
+				FROM visits
+				WHERE deleted_at IS NULL
+				  AND tenant_id = current_tenant()
+				GROUP BY user_id, location_id
+			), mine AS (
+				SELECT location_id, mark FROM rated WHERE user_id = $1
+			), neighbours AS (
+				SELECT rated.user_id, SUM(1 - ABS(rated.mark - mine.mark) / 5) AS similarity
+				FROM rated
+					JOIN mine
+						ON mine.location_id = rated.location_id
+				WHERE rated.user_id <> $1
+				GROUP BY rated.user_id
+				HAVING SUM(1 - ABS(rated.mark - mine.mark) / 5) > 0
+				ORDER BY similarity DESC, rated.user_id
+				LIMIT $3
+			), candidates AS (
+				SELECT rated.location_id, SUM(neighbours.similarity * rated.mark) / SUM(neighbours.similarity) AS score
+				FROM rated
+					JOIN neighbours
+						ON neighbours.user_id = rated.user_id
+				WHERE rated.mark >= $4
+				  AND rated.location_id NOT IN (SELECT location_id FROM mine)
+				GROUP BY rated.location_id
+			), reasons AS (
+				SELECT DISTINCT ON (candidates.location_id) candidates.location_id, mine.location_id AS because_id
+				FROM candidates
+					JOIN rated liked
+						ON liked.location_id = candidates.location_id AND liked.mark >= $4
+					JOIN neighbours
+						ON neighbours.user_id = liked.user_id
+					JOIN rated shared
+						ON shared.user_id = neighbours.user_id
+					JOIN mine
+						ON mine.location_id = shared.location_id
+				GROUP BY candidates.location_id, mine.location_id, mine.mark
+				ORDER BY candidates.location_id, SUM(neighbours.similarity * (1 - ABS(shared.mark - mine.mark) / 5)) DESC,
+						 mine.mark DESC, mine.location_id
+			)` + selectLocationColumns + `, ROUND(candidates.score, 2) AS score, because.location_id, because.place` +
+		selectLocationTables + `
+				JOIN candidates
+					ON candidates.location_id = l.location_id
+				JOIN reasons
+					ON reasons.location_id = l.location_id
+				JOIN locations because
+					ON because.location_id = reasons.because_id AND because.tenant_id = l.tenant_id
+			WHERE l.tenant_id = current_tenant()
+			ORDER BY candidates.score DESC, l.location_id
+			LIMIT $2`
+	return r.findRecommendations(query, id, limit, maxNeighbours, likedMark)
+}
+
+// FindByLikedCountries suggests the best rated unvisited locations in countries where the user liked a location,
+// the reason is the user's best rated location in that country.
+func (r *recommendationRepo) FindByLikedCountries(id string, limit int) (model.Recommendations, error) {
+	if err := r.userExists(id); err != nil {
+		return model.Recommendations{}, err
+	}
+	query := `
+			WITH mine AS (
+				SELECT location_id, AVG(mark) AS mark
+				FROM visits
+				WHERE user_id = $1
+				  AND deleted_at IS NULL
+				  AND tenant_id = current_tenant()
+				GROUP BY location_id
+			), liked AS (
+				SELECT DISTINCT ON (locations.country_code) locations.country_code, mine.location_id AS because_id, mine.mark
+				FROM mine
+					JOIN locations
+						ON locations.location_id = mine.location_id AND locations.tenant_id = current_tenant()
+				WHERE mine.mark >= $3
+				  AND locations.country_code IS NOT NULL
+				ORDER BY locations.country_code, mine.mark DESC, mine.location_id
+			), candidates AS (
+				SELECT visits.location_id, AVG(visits.mark) AS score
+				FROM visits
+					JOIN locations
+						ON locations.location_id = visits.location_id AND locations.tenant_id = visits.tenant_id
+				WHERE visits.tenant_id = current_tenant()
+				  AND locations.country_code IN (SELECT country_code FROM liked)
+				  AND visits.location_id NOT IN (SELECT location_id FROM mine)
+				  AND visits.deleted_at IS NULL
+				GROUP BY visits.location_id
+			)` + selectLocationColumns + `, ROUND(candidates.score, 2) AS score, because.location_id, because.place` +
+		selectLocationTables + `
+				JOIN candidates
+					ON candidates.location_id = l.location_id
+				JOIN liked
+					ON liked.country_code = l.country_code
+				JOIN locations because
+					ON because.location_id = liked.because_id AND because.tenant_id = l.tenant_id
+			WHERE l.tenant_id = current_tenant()
+			ORDER BY liked.mark DESC, candidates.score DESC, l.location_id
+			LIMIT $2`
+	return r.findRecommendations(query, id, limit, likedMark)
+}
+
+func (r *recommendationRepo) userExists(id string) error {
+	var userId int
+	row := r.QueryRow("SELECT user_id FROM users WHERE user_id = $1 AND tenant_id = current_tenant()", id)
+	if err := row.Scan(&userId); err != nil {
+		return apperrors.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *recommendationRepo) findRecommendations(query string, args ...interface{}) (model.Recommendations, error) {
+	recommendation := model.Recommendation{}
+	recommendations := model.Recommendations{}
+	rows, err := r.Query(query, args...)
+	if err != nil {
+		return recommendations, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		err = scanLocation(rows, &recommendation.Location,
+			&recommendation.Score, &recommendation.BecauseLocationId, &recommendation.BecausePlace)
+		if err != nil {
+			return recommendations, err
+		}
+		recommendations.List = append(recommendations.List, recommendation)
+	}
+	return recommendations, rows.Err()
+}
@@ -0,0 +1,139 @@
+package postgres
+
+import (
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/pkg/apperrors"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	sqlmock "github.com/zhashkevych/go-sqlxmock"
+	"testing"
+)
+
+var recommendationColumns = []string{"location_id", "place", "country", "country_code", "lat", "lon", "category", "tags",
+	"score", "because_id", "because_place"}
+
+func TestRecommendationRepo_FindBySimilarUsers(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	repository := newRecommendationRepo(db)
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		want    model.Recommendations
+		wantErr bool
+	}{
+		{
+			name: "Ok",
+			mock: func() {
+				mock.ExpectQuery("SELECT user_id FROM users").WithArgs("1").
+					WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(1))
+				rows := sqlmock.NewRows(recommendationColumns).
+					AddRow(3, "Louvre", "France", "FR", nil, nil, "museum", "{art}", 4.67, 2, "Eiffel Tower")
+				mock.ExpectQuery("WITH rated AS (.+) neighbours AS (.+) ORDER BY candidates.score DESC").
+					WithArgs("1", 10, maxNeighbours, likedMark).WillReturnRows(rows)
+			},
+			want: model.Recommendations{
+				List: []model.Recommendation{
+					{
+						Location: model.Location{LocationId: 3, Place: "Louvre", Country: "France", CountryCode: "FR",
+							Category: "museum", Tags: []string{"art"}},
+						Score:             4.67,
+						BecauseLocationId: 2,
+						BecausePlace:      "Eiffel Tower",
+					},
+				},
+			},
+		},
+		{
+			name: "Not Found",
+			mock: func() {
+				mock.ExpectQuery("SELECT user_id FROM users").WithArgs("1").
+					WillReturnError(apperrors.ErrRecordNotFound)
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := repository.FindBySimilarUsers("1", 10)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRecommendationRepo_FindByLikedCountries(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	repository := newRecommendationRepo(db)
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		want    model.Recommendations
+		wantErr bool
+	}{
+		{
+			name: "Ok",
+			mock: func() {
+				mock.ExpectQuery("SELECT user_id FROM users").WithArgs("1").
+					WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(1))
+				rows := sqlmock.NewRows(recommendationColumns).
+					AddRow(5, "Sacred Valley", "Peru", "PE", nil, nil, "", "{}", 4.5, 1, "Machu Picchu")
+				mock.ExpectQuery("WITH mine AS (.+) liked AS (.+) ORDER BY liked.mark DESC").
+					WithArgs("1", 10, likedMark).WillReturnRows(rows)
+			},
+			want: model.Recommendations{
+				List: []model.Recommendation{
+					{
+						Location:          model.Location{LocationId: 5, Place: "Sacred Valley", Country: "Peru", CountryCode: "PE", Tags: []string{}},
+						Score:             4.5,
+						BecauseLocationId: 1,
+						BecausePlace:      "Machu Picchu",
+					},
+				},
+			},
+		},
+		{
+			name: "Query Error",
+			mock: func() {
+				mock.ExpectQuery("SELECT user_id FROM users").WithArgs("1").
+					WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(1))
+				mock.ExpectQuery("WITH mine AS").WillReturnError(apperrors.ErrIncorrectQuery)
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := repository.FindByLikedCountries("1", 10)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
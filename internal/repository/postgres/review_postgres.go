@@ -0,0 +1,102 @@
+package postgres
+
+import (
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/pkg/apperrors"
+)
+
+// reviewSortColumns maps sort keys to visit columns, user input never reaches the query text.
+var reviewSortColumns = map[string]string{
+	"date": "visits.visited_at",
+	"mark": "visits.mark",
+}
+
+// reviewTables joins reviews of the tenant with their visit and author, reviews of deleted visits are left out.
+const reviewTables = `
+			FROM reviews
+				JOIN visits
+					ON visits.visit_id = reviews.visit_id AND visits.tenant_id = reviews.tenant_id
+					AND reviews.tenant_id = current_tenant() AND visits.deleted_at IS NULL
+				JOIN users
+					ON users.user_id = visits.user_id AND users.tenant_id = visits.tenant_id`
+
+type reviewRepo struct {
+	*sqlx.DB
+}
+
+func newReviewRepo(db *sqlx.DB) *reviewRepo {
+	return &reviewRepo{db}
+}
+
+func (r *reviewRepo) FindByLocation(id string, query model.ReviewQuery) (model.Reviews, error) {
+	var locationId int
+	row := r.QueryRow(selectLocationId, id)
+	if err := row.Scan(&locationId); err != nil {
+		return model.Reviews{}, apperrors.ErrRecordNotFound
+	}
+	return r.findReviews("visits.location_id = $1 AND reviews.status = $2", query, id, model.ReviewApproved)
+}
+
+func (r *reviewRepo) FindByStatus(status string, query model.ReviewQuery) (model.Reviews, error) {
+	return r.findReviews("reviews.status = $1", query, status)
+}
+
+func (r *reviewRepo) UpdateStatus(visitId string, status string) error {
+	query := `
+			UPDATE reviews SET status = $1, updated_at = now()
+			WHERE visit_id = (SELECT visit_id
+							  FROM visits
+							  WHERE visit_id = $2 AND deleted_at IS NULL AND tenant_id = current_tenant())
+			  AND tenant_id = current_tenant()`
+	res, err := r.Exec(query, status, visitId)
+	if err != nil {
+		return apperrors.ErrIncorrectQuery
+	}
+	if rowsAff, err := res.RowsAffected(); rowsAff == 0 && err == nil {
+		return apperrors.ErrRecordNotFound
+	}
+	return err
+}
+
+// findReviews returns one page of the reviews matching the condition, newest first unless sorted otherwise.
+func (r *reviewRepo) findReviews(condition string, opts model.ReviewQuery, args ...interface{}) (model.Reviews, error) {
+	reviews := model.Reviews{Page: opts.Page, PerPage: opts.PerPage}
+	row := r.QueryRow("SELECT COUNT(*)"+reviewTables+" WHERE "+condition, args...)
+	if err := row.Scan(&reviews.Total); err != nil {
+		return reviews, err
+	}
+
+	column, ok := reviewSortColumns[opts.Sort]
+	if !ok {
+		column = reviewSortColumns["date"]
+	}
+	order := "DESC"
+	if opts.Order == "asc" {
+		order = "ASC"
+	}
+	query := `
+			SELECT visits.visit_id, visits.location_id, users.first_name, visits.visited_at, visits.mark,
+				   reviews.title, reviews.body, reviews.language, reviews.status` + reviewTables + `
+			WHERE ` + condition + `
+			ORDER BY ` + column + " " + order + ", visits.visit_id " + order +
+		fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
+	args = append(args, opts.PerPage, (opts.Page-1)*opts.PerPage)
+
+	entry := model.ReviewEntry{}
+	rows, err := r.Query(query, args...)
+	if err != nil {
+		return reviews, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		err = rows.Scan(&entry.VisitId, &entry.LocationId, &entry.Author, &entry.VisitedAt, &entry.Mark,
+			&entry.Title, &entry.Body, &entry.Language, &entry.Status)
+		if err != nil {
+			return reviews, err
+		}
+		reviews.List = append(reviews.List, entry)
+	}
+	return reviews, rows.Err()
+}
@@ -0,0 +1,151 @@
+package postgres
+
+import (
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/pkg/apperrors"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	sqlmock "github.com/zhashkevych/go-sqlxmock"
+	"testing"
+)
+
+var reviewColumns = []string{"visit_id", "location_id", "first_name", "visited_at", "mark", "title", "body", "language", "status"}
+
+func TestReviewRepo_FindByLocation(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	repository := newReviewRepo(db)
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		query   model.ReviewQuery
+		want    model.Reviews
+		wantErr bool
+	}{
+		{
+			name: "Ok",
+			mock: func() {
+				mock.ExpectQuery("SELECT location_id FROM locations").WithArgs("1").
+					WillReturnRows(sqlmock.NewRows([]string{"location_id"}).AddRow(1))
+				mock.ExpectQuery("SELECT COUNT(.+) FROM reviews (.+) WHERE visits.location_id = (.+) AND reviews.status = (.+)").
+					WithArgs("1", "approved").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+				rows := sqlmock.NewRows(reviewColumns).
+					AddRow(7, 1, "John", "2019-06-15", 5, "Breathtaking", "Take the early bus", "en", "approved")
+				mock.ExpectQuery("SELECT (.+) FROM reviews (.+) ORDER BY visits.mark ASC, visits.visit_id ASC LIMIT (.+) OFFSET (.+)").
+					WithArgs("1", "approved", 2, 2).WillReturnRows(rows)
+			},
+			query: model.ReviewQuery{Sort: "mark", Order: "asc", Page: 2, PerPage: 2},
+			want: model.Reviews{
+				List: []model.ReviewEntry{
+					{
+						VisitId:    7,
+						LocationId: 1,
+						Author:     "John",
+						VisitedAt:  "2019-06-15",
+						Mark:       5,
+						Review:     model.Review{Title: "Breathtaking", Body: "Take the early bus", Language: "en", Status: "approved"},
+					},
+				},
+				Total:   3,
+				Page:    2,
+				PerPage: 2,
+			},
+		},
+		{
+			name: "Not Found",
+			mock: func() {
+				mock.ExpectQuery("SELECT location_id FROM locations").WithArgs("1").
+					WillReturnError(apperrors.ErrRecordNotFound)
+			},
+			query:   model.ReviewQuery{Page: 1, PerPage: 20},
+			wantErr: true,
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := repository.FindByLocation("1", tt.query)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestReviewRepo_FindByStatus(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	repository := newReviewRepo(db)
+
+	mock.ExpectQuery("SELECT COUNT(.+) FROM reviews (.+) WHERE reviews.status = (.+)").
+		WithArgs("pending").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery("SELECT (.+) FROM reviews (.+) ORDER BY visits.visited_at DESC, visits.visit_id DESC LIMIT (.+)").
+		WithArgs("pending", 20, 0).WillReturnRows(sqlmock.NewRows(reviewColumns))
+
+	got, err := repository.FindByStatus("pending", model.ReviewQuery{Page: 1, PerPage: 20})
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.Reviews{Page: 1, PerPage: 20}, got)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReviewRepo_UpdateStatus(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	repository := newReviewRepo(db)
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		wantErr bool
+	}{
+		{
+			name: "Ok",
+			mock: func() {
+				mock.ExpectExec("UPDATE reviews SET status = (.+) WHERE visit_id = (.+)").
+					WithArgs("approved", "7").WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "Not Found",
+			mock: func() {
+				mock.ExpectExec("UPDATE reviews SET status").
+					WithArgs("approved", "7").WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			err := repository.UpdateStatus("7", "approved")
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
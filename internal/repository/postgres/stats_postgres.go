@@ -0,0 +1,71 @@
+package postgres
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/rinuccia/travels-api/internal/model"
+)
+
+// statsSortColumns maps sort keys to result columns, user input never reaches the query text.
+var statsSortColumns = map[string]string{
+	"country":   "country",
+	"locations": "locations",
+	"visits":    "visits",
+	"visitors":  "visitors",
+	"avg_mark":  "avg_mark",
+}
+
+type statsRepo struct {
+	*sqlx.DB
+}
+
+func newStatsRepo(db *sqlx.DB) *statsRepo {
+	return &statsRepo{db}
+}
+
+func (r *statsRepo) FindCountryStats(filter model.VisitFilter, sort model.StatsSort) (model.CountriesStats, error) {
+	column, ok := statsSortColumns[sort.By]
+	if !ok {
+		column = "visits"
+	}
+	order := "DESC"
+	if sort.Order == "asc" {
+		order = "ASC"
+	}
+
+	query := `
+			WITH visit_stats AS (
+				SELECT locations.country_code,
+					   COUNT(*) AS visits,
+					   COUNT(DISTINCT visits.user_id) AS visitors,
+					   ROUND(AVG(visits.mark), 2) AS avg_mark` + visitJoins + `
+				WHERE TRUE` + visitFilterCondition(1) + `
+				GROUP BY locations.country_code
+			)
+			SELECT countries.code, countries.name AS country,
+				   COUNT(locations.location_id) AS locations,
+				   COALESCE(visit_stats.visits, 0) AS visits,
+				   COALESCE(visit_stats.visitors, 0) AS visitors,
+				   COALESCE(visit_stats.avg_mark, 0) AS avg_mark
+			FROM countries
+				JOIN locations
+					ON locations.country_code = countries.code AND locations.tenant_id = current_tenant()
+				LEFT JOIN visit_stats
+					ON visit_stats.country_code = countries.code
+			GROUP BY countries.code, countries.name, visit_stats.visits, visit_stats.visitors, visit_stats.avg_mark
+			ORDER BY ` + column + " " + order + `, countries.code`
+	stat := model.CountryStats{}
+	stats := model.CountriesStats{}
+	rows, err := r.Query(query, visitFilterArgs(filter)...)
+	if err != nil {
+		return stats, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		err = rows.Scan(&stat.CountryCode, &stat.Country, &stat.Locations, &stat.Visits, &stat.Visitors, &stat.AvgMark)
+		if err != nil {
+			return stats, err
+		}
+		stats.List = append(stats.List, stat)
+	}
+	return stats, rows.Err()
+}
@@ -0,0 +1,69 @@
+package postgres
+
+import (
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	sqlmock "github.com/zhashkevych/go-sqlxmock"
+	"testing"
+)
+
+func TestStatsRepo_FindCountryStats(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	repository := newStatsRepo(db)
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		filter  model.VisitFilter
+		sort    model.StatsSort
+		want    model.CountriesStats
+		wantErr bool
+	}{
+		{
+			name: "Ok",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"code", "country", "locations", "visits", "visitors", "avg_mark"}).
+					AddRow("FR", "France", 2, 10, 4, 4.2).
+					AddRow("PE", "Peru", 1, 0, 0, 0)
+				mock.ExpectQuery("WITH visit_stats AS (.+) ORDER BY avg_mark ASC, countries.code").
+					WithArgs("2018-01-01", "", "f").WillReturnRows(rows)
+			},
+			filter: model.VisitFilter{FromDate: "2018-01-01", Gender: "f"},
+			sort:   model.StatsSort{By: "avg_mark", Order: "asc"},
+			want: model.CountriesStats{
+				List: []model.CountryStats{
+					{CountryCode: "FR", Country: "France", Locations: 2, Visits: 10, Visitors: 4, AvgMark: 4.2},
+					{CountryCode: "PE", Country: "Peru", Locations: 1},
+				},
+			},
+		},
+		{
+			name: "Default Sort",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"code", "country", "locations", "visits", "visitors", "avg_mark"})
+				mock.ExpectQuery("WITH visit_stats AS (.+) ORDER BY visits DESC, countries.code").
+					WithArgs("", "", "").WillReturnRows(rows)
+			},
+			want: model.CountriesStats{},
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			got, err := repository.FindCountryStats(tt.filter, tt.sort)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
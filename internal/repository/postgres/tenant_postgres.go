@@ -0,0 +1,246 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/rinuccia/travels-api/config"
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/pkg/apperrors"
+	"strings"
+)
+
+// tenantRole is the role tenant connections act as. Unlike the owner of the tables it can't bypass row level security.
+const tenantRole = "travels_tenant"
+
+// tenantTables hold the rows of one tenant each, countries and tenants are shared.
+var tenantTables = []string{"users", "locations", "visits", "categories", "tags", "location_tags", "trips", "wishlist",
+	"reviews", "photos", "follows", "refresh_tokens", "api_keys", "audit_log", "erasure_log", "email_verifications"}
+
+// tenantKeys scopes ids and every reference between tenant tables to the tenant, existing rows go to the default tenant.
+// It runs once, the constraint it creates first marks it as done.
+var tenantKeys = `
+	DO $$
+	BEGIN
+		IF EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'users_tenant_user_id_key') THEN
+			RETURN;
+		END IF;
+
+		ALTER TABLE users DROP CONSTRAINT IF EXISTS users_user_id_key CASCADE;
+		ALTER TABLE locations DROP CONSTRAINT IF EXISTS locations_location_id_key CASCADE;
+		ALTER TABLE visits DROP CONSTRAINT IF EXISTS visits_visit_id_key CASCADE;
+		ALTER TABLE categories DROP CONSTRAINT IF EXISTS categories_category_id_key CASCADE;
+		ALTER TABLE tags DROP CONSTRAINT IF EXISTS tags_tag_id_key CASCADE;
+		ALTER TABLE tags DROP CONSTRAINT IF EXISTS tags_name_key;
+		ALTER TABLE trips DROP CONSTRAINT IF EXISTS trips_trip_id_key CASCADE;
+		ALTER TABLE photos DROP CONSTRAINT IF EXISTS photos_photo_id_key;
+		ALTER TABLE api_keys DROP CONSTRAINT IF EXISTS api_keys_key_id_key;
+		ALTER TABLE location_tags DROP CONSTRAINT IF EXISTS location_tags_pkey;
+		ALTER TABLE wishlist DROP CONSTRAINT IF EXISTS wishlist_pkey;
+		ALTER TABLE reviews DROP CONSTRAINT IF EXISTS reviews_pkey;
+		ALTER TABLE follows DROP CONSTRAINT IF EXISTS follows_pkey;
+
+		ALTER TABLE users ADD CONSTRAINT users_tenant_user_id_key UNIQUE (tenant_id, user_id);
+		ALTER TABLE locations ADD CONSTRAINT locations_tenant_location_id_key UNIQUE (tenant_id, location_id);
+		ALTER TABLE visits ADD CONSTRAINT visits_tenant_visit_id_key UNIQUE (tenant_id, visit_id);
+		ALTER TABLE categories ADD CONSTRAINT categories_tenant_category_id_key UNIQUE (tenant_id, category_id);
+		ALTER TABLE tags ADD CONSTRAINT tags_tenant_tag_id_key UNIQUE (tenant_id, tag_id),
+			ADD CONSTRAINT tags_tenant_name_key UNIQUE (tenant_id, name);
+		ALTER TABLE trips ADD CONSTRAINT trips_tenant_trip_id_key UNIQUE (tenant_id, trip_id);
+		ALTER TABLE photos ADD CONSTRAINT photos_tenant_photo_id_key UNIQUE (tenant_id, photo_id);
+		ALTER TABLE api_keys ADD CONSTRAINT api_keys_tenant_key_id_key UNIQUE (tenant_id, key_id);
+		ALTER TABLE location_tags ADD PRIMARY KEY (tenant_id, location_id, tag_id);
+		ALTER TABLE wishlist ADD PRIMARY KEY (tenant_id, user_id, location_id);
+		ALTER TABLE reviews ADD PRIMARY KEY (tenant_id, visit_id);
+		ALTER TABLE follows ADD PRIMARY KEY (tenant_id, follower_id, followee_id);
+
+		ALTER TABLE users ADD FOREIGN KEY (tenant_id) REFERENCES tenants (tenant_id);
+		ALTER TABLE locations ADD FOREIGN KEY (tenant_id) REFERENCES tenants (tenant_id),
+			ADD FOREIGN KEY (tenant_id, category_id) REFERENCES categories (tenant_id, category_id)
+				ON DELETE SET NULL (category_id);
+		ALTER TABLE categories ADD FOREIGN KEY (tenant_id) REFERENCES tenants (tenant_id);
+		ALTER TABLE tags ADD FOREIGN KEY (tenant_id) REFERENCES tenants (tenant_id);
+		ALTER TABLE api_keys ADD FOREIGN KEY (tenant_id) REFERENCES tenants (tenant_id);
+		ALTER TABLE audit_log ADD FOREIGN KEY (tenant_id) REFERENCES tenants (tenant_id);
+		ALTER TABLE erasure_log ADD FOREIGN KEY (tenant_id) REFERENCES tenants (tenant_id);
+		ALTER TABLE visits ADD FOREIGN KEY (tenant_id, location_id) REFERENCES locations (tenant_id, location_id),
+			ADD FOREIGN KEY (tenant_id, user_id) REFERENCES users (tenant_id, user_id),
+			ADD FOREIGN KEY (tenant_id, trip_id) REFERENCES trips (tenant_id, trip_id) ON DELETE SET NULL (trip_id);
+		ALTER TABLE location_tags
+			ADD FOREIGN KEY (tenant_id, location_id) REFERENCES locations (tenant_id, location_id) ON DELETE CASCADE,
+			ADD FOREIGN KEY (tenant_id, tag_id) REFERENCES tags (tenant_id, tag_id) ON DELETE CASCADE;
+		ALTER TABLE trips ADD FOREIGN KEY (tenant_id, user_id) REFERENCES users (tenant_id, user_id) ON DELETE CASCADE;
+		ALTER TABLE wishlist
+			ADD FOREIGN KEY (tenant_id, user_id) REFERENCES users (tenant_id, user_id) ON DELETE CASCADE,
+			ADD FOREIGN KEY (tenant_id, location_id) REFERENCES locations (tenant_id, location_id) ON DELETE CASCADE,
+			ADD FOREIGN KEY (tenant_id, done_visit_id) REFERENCES visits (tenant_id, visit_id)
+				ON DELETE SET NULL (done_visit_id);
+		ALTER TABLE reviews
+			ADD FOREIGN KEY (tenant_id, visit_id) REFERENCES visits (tenant_id, visit_id) ON DELETE CASCADE;
+		ALTER TABLE photos
+			ADD FOREIGN KEY (tenant_id, location_id) REFERENCES locations (tenant_id, location_id) ON DELETE CASCADE,
+			ADD FOREIGN KEY (tenant_id, visit_id) REFERENCES visits (tenant_id, visit_id) ON DELETE CASCADE;
+		ALTER TABLE follows
+			ADD FOREIGN KEY (tenant_id, follower_id) REFERENCES users (tenant_id, user_id) ON DELETE CASCADE,
+			ADD FOREIGN KEY (tenant_id, followee_id) REFERENCES users (tenant_id, user_id) ON DELETE CASCADE;
+		ALTER TABLE refresh_tokens
+			ADD FOREIGN KEY (tenant_id, user_id) REFERENCES users (tenant_id, user_id) ON DELETE CASCADE;
+		ALTER TABLE email_verifications
+			ADD FOREIGN KEY (tenant_id, user_id) REFERENCES users (tenant_id, user_id) ON DELETE CASCADE;
+	END $$;
+
+	DROP INDEX IF EXISTS categories_name_idx;
+	CREATE UNIQUE INDEX IF NOT EXISTS categories_tenant_name_idx ON categories (tenant_id, name) WHERE deleted_at IS NULL;
+	CREATE INDEX IF NOT EXISTS audit_log_tenant_entity_idx ON audit_log (tenant_id, entity_type, entity_id);
+	CREATE INDEX IF NOT EXISTS erasure_log_tenant_idx ON erasure_log (tenant_id, erasure_id);`
+
+// tenantSchema adds the tenants, the tenant of every row and the row level security policies keeping tenant
+// connections to the rows of their tenant. The tenant of a connection is read by current_tenant() from app.tenant_id,
+// new rows get it by default.
+func tenantSchema() string {
+	var b strings.Builder
+	b.WriteString(`
+	CREATE TABLE IF NOT EXISTS tenants
+	(
+		tenant_id serial not null primary key,
+		slug varchar(50) not null unique,
+		name varchar(100) not null,
+		created_at timestamptz not null default now()
+	);
+	INSERT INTO tenants (tenant_id, slug, name) VALUES (1, 'default', 'Default') ON CONFLICT DO NOTHING;
+	SELECT setval('tenants_tenant_id_seq', (SELECT MAX(tenant_id) FROM tenants));
+
+	CREATE OR REPLACE FUNCTION current_tenant() RETURNS int LANGUAGE sql STABLE
+		AS $$ SELECT NULLIF(current_setting('app.tenant_id', true), '')::int $$;
+	`)
+	for _, table := range tenantTables {
+		fmt.Fprintf(&b, `
+	ALTER TABLE %[1]s ADD COLUMN IF NOT EXISTS tenant_id int not null default 1;
+	ALTER TABLE %[1]s ALTER COLUMN tenant_id SET DEFAULT current_tenant();
+	ALTER TABLE %[1]s ENABLE ROW LEVEL SECURITY;
+	ALTER TABLE %[1]s FORCE ROW LEVEL SECURITY;
+	DROP POLICY IF EXISTS tenant_isolation ON %[1]s;
+	CREATE POLICY tenant_isolation ON %[1]s
+		USING (tenant_id = current_tenant()) WITH CHECK (tenant_id = current_tenant());
+	`, table)
+	}
+	b.WriteString(tenantKeys)
+	fmt.Fprintf(&b, `
+
+	DO $$
+	BEGIN
+		IF NOT EXISTS (SELECT 1 FROM pg_roles WHERE rolname = '%[1]s') THEN
+			CREATE ROLE %[1]s NOLOGIN NOBYPASSRLS;
+		END IF;
+	END $$;
+	GRANT %[1]s TO CURRENT_USER;
+	GRANT SELECT ON tenants, countries TO %[1]s;
+	GRANT SELECT, INSERT, UPDATE, DELETE ON %[2]s TO %[1]s;
+	GRANT USAGE, SELECT ON ALL SEQUENCES IN SCHEMA public TO %[1]s;`, tenantRole, strings.Join(tenantTables, ", "))
+	return b.String()
+}
+
+// requireRLSBypass fails unless the owner connection bypasses row level security. FORCE ROW LEVEL SECURITY holds
+// the table owner to the policies too, and without a tenant current_tenant() is NULL, so the migrations and
+// tenant lookups of the owner connection would silently see no rows.
+func requireRLSBypass(db *sqlx.DB) error {
+	var bypass bool
+	err := db.QueryRow("SELECT rolsuper OR rolbypassrls FROM pg_roles WHERE rolname = current_user").Scan(&bypass)
+	if err != nil {
+		return err
+	}
+	if !bypass {
+		return errors.New("the database user must be a superuser or have BYPASSRLS")
+	}
+	return nil
+}
+
+// NewTenantClient opens a connection pool bound to the tenant, every connection acts as tenantRole with app.tenant_id
+// set, so both the queries and the row level security policies see the rows of the tenant only.
+func NewTenantClient(cfg *config.Config, tenantId uint32) (*sqlx.DB, error) {
+	connector, err := pq.NewConnector(dataSource(cfg))
+	if err != nil {
+		return nil, err
+	}
+	db := sqlx.NewDb(sql.OpenDB(tenantConnector{connector, tenantId}), "postgres")
+
+	db.SetMaxIdleConns(cfg.Tenants.MaxConns)
+	db.SetMaxOpenConns(cfg.Tenants.MaxConns)
+
+	if err = db.Ping(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// tenantConnector binds every new connection to its tenant before the pool hands it out.
+type tenantConnector struct {
+	driver.Connector
+	tenantId uint32
+}
+
+func (c tenantConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	execer, ok := conn.(driver.ExecerContext)
+	if !ok {
+		conn.Close()
+		return nil, errors.New("postgres connection can't execute statements")
+	}
+	query := fmt.Sprintf("SET ROLE %s; SET app.tenant_id = '%d'", tenantRole, c.tenantId)
+	if _, err = execer.ExecContext(ctx, query, nil); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+type tenantRepo struct {
+	*sqlx.DB
+}
+
+// NewTenantRepository reads the tenants with the connection pool of the owner, which sees every tenant.
+func NewTenantRepository(db *sqlx.DB) TenantRepository {
+	return &tenantRepo{db}
+}
+
+func (r *tenantRepo) FindById(id uint32) (model.Tenant, error) {
+	tenant := model.Tenant{}
+	row := r.QueryRow("SELECT tenant_id, slug, name, created_at FROM tenants WHERE tenant_id = $1", id)
+	if err := row.Scan(&tenant.TenantId, &tenant.Slug, &tenant.Name, &tenant.CreatedAt); err != nil {
+		return tenant, apperrors.ErrRecordNotFound
+	}
+	return tenant, nil
+}
+
+func (r *tenantRepo) FindAll() ([]model.Tenant, error) {
+	tenants := []model.Tenant{}
+	rows, err := r.Query("SELECT tenant_id, slug, name, created_at FROM tenants ORDER BY tenant_id")
+	if err != nil {
+		return tenants, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		tenant := model.Tenant{}
+		if err = rows.Scan(&tenant.TenantId, &tenant.Slug, &tenant.Name, &tenant.CreatedAt); err != nil {
+			return tenants, err
+		}
+		tenants = append(tenants, tenant)
+	}
+	return tenants, rows.Err()
+}
+
+func (r *tenantRepo) FindAPIKeyTenant(keyHash string) (uint32, error) {
+	var tenantId uint32
+	if err := r.QueryRow("SELECT tenant_id FROM api_keys WHERE key_hash = $1", keyHash).Scan(&tenantId); err != nil {
+		return 0, apperrors.ErrRecordNotFound
+	}
+	return tenantId, nil
+}
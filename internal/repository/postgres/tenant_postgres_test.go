@@ -0,0 +1,250 @@
+package postgres
+
+import (
+	"context"
+	"database/sql/driver"
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/pkg/apperrors"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	sqlmock "github.com/zhashkevych/go-sqlxmock"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTenantSchema(t *testing.T) {
+	schema := tenantSchema()
+
+	for _, table := range tenantTables {
+		assert.Contains(t, schema, "ALTER TABLE "+table+" FORCE ROW LEVEL SECURITY;")
+		assert.Contains(t, schema, "CREATE POLICY tenant_isolation ON "+table+"\n")
+	}
+	assert.NotContains(t, schema, "ON countries")
+	assert.Contains(t, schema, "GRANT SELECT, INSERT, UPDATE, DELETE ON "+strings.Join(tenantTables, ", ")+" TO travels_tenant;")
+}
+
+func TestTenantRepo_FindById(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	repository := NewTenantRepository(db)
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		want    model.Tenant
+		wantErr bool
+	}{
+		{
+			name: "Ok",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"tenant_id", "slug", "name", "created_at"}).
+					AddRow(2, "nomad", "Nomad Travel", createdAt)
+				mock.ExpectQuery("SELECT tenant_id, slug, name, created_at FROM tenants WHERE tenant_id = (.+)").
+					WithArgs(uint32(2)).WillReturnRows(rows)
+			},
+			want: model.Tenant{TenantId: 2, Slug: "nomad", Name: "Nomad Travel", CreatedAt: createdAt},
+		},
+		{
+			name: "Not Found",
+			mock: func() {
+				mock.ExpectQuery("SELECT (.+) FROM tenants").WithArgs(uint32(2)).
+					WillReturnRows(sqlmock.NewRows([]string{"tenant_id", "slug", "name", "created_at"}))
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := repository.FindById(2)
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, apperrors.ErrRecordNotFound)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestTenantRepo_FindAll(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	repository := NewTenantRepository(db)
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	rows := sqlmock.NewRows([]string{"tenant_id", "slug", "name", "created_at"}).
+		AddRow(1, "default", "Default", createdAt).
+		AddRow(2, "nomad", "Nomad Travel", createdAt)
+	mock.ExpectQuery("SELECT (.+) FROM tenants ORDER BY tenant_id").WillReturnRows(rows)
+
+	got, err := repository.FindAll()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []model.Tenant{
+		{TenantId: 1, Slug: "default", Name: "Default", CreatedAt: createdAt},
+		{TenantId: 2, Slug: "nomad", Name: "Nomad Travel", CreatedAt: createdAt},
+	}, got)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTenantRepo_FindAPIKeyTenant(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	repository := NewTenantRepository(db)
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		want    uint32
+		wantErr bool
+	}{
+		{
+			name: "Ok",
+			mock: func() {
+				mock.ExpectQuery("SELECT tenant_id FROM api_keys WHERE key_hash = (.+)").WithArgs("ab12").
+					WillReturnRows(sqlmock.NewRows([]string{"tenant_id"}).AddRow(2))
+			},
+			want: 2,
+		},
+		{
+			name: "Unknown Key",
+			mock: func() {
+				mock.ExpectQuery("SELECT tenant_id FROM api_keys").WithArgs("ab12").
+					WillReturnRows(sqlmock.NewRows([]string{"tenant_id"}))
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := repository.FindAPIKeyTenant("ab12")
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, apperrors.ErrRecordNotFound)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+// recordingConn records the statements executed on it.
+type recordingConn struct {
+	driver.Conn
+	queries []string
+	err     error
+	closed  bool
+}
+
+func (c *recordingConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	c.queries = append(c.queries, query)
+	return driver.ResultNoRows, c.err
+}
+
+func (c *recordingConn) Close() error {
+	c.closed = true
+	return nil
+}
+
+type recordingConnector struct {
+	driver.Connector
+	conn *recordingConn
+}
+
+func (c recordingConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.conn, nil
+}
+
+func TestTenantConnector_Connect(t *testing.T) {
+	t.Run("Ok", func(t *testing.T) {
+		conn := &recordingConn{}
+
+		got, err := tenantConnector{recordingConnector{conn: conn}, 2}.Connect(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, conn, got)
+		assert.Equal(t, []string{"SET ROLE travels_tenant; SET app.tenant_id = '2'"}, conn.queries)
+	})
+
+	t.Run("Set Error", func(t *testing.T) {
+		conn := &recordingConn{err: sqlmock.ErrCancelled}
+
+		_, err := tenantConnector{recordingConnector{conn: conn}, 2}.Connect(context.Background())
+
+		assert.Error(t, err)
+		assert.True(t, conn.closed)
+	})
+}
+
+func TestRequireRLSBypass(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		wantErr bool
+	}{
+		{
+			name: "Bypasses",
+			mock: func() {
+				mock.ExpectQuery("SELECT rolsuper OR rolbypassrls FROM pg_roles WHERE rolname = current_user").
+					WillReturnRows(sqlmock.NewRows([]string{"bypass"}).AddRow(true))
+			},
+		},
+		{
+			name: "Held To Policies",
+			mock: func() {
+				mock.ExpectQuery("SELECT rolsuper OR rolbypassrls FROM pg_roles").
+					WillReturnRows(sqlmock.NewRows([]string{"bypass"}).AddRow(false))
+			},
+			wantErr: true,
+		},
+		{
+			name: "Query Error",
+			mock: func() {
+				mock.ExpectQuery("SELECT rolsuper OR rolbypassrls FROM pg_roles").WillReturnError(sqlmock.ErrCancelled)
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			err := requireRLSBypass(db)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
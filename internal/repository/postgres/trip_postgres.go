@@ -0,0 +1,176 @@
+package postgres
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/pkg/apperrors"
+)
+
+type tripRepo struct {
+	*sqlx.DB
+}
+
+func newTripRepo(db *sqlx.DB) *tripRepo {
+	return &tripRepo{db}
+}
+
+func (r *tripRepo) FindAll(userId string) ([]model.TripRecord, error) {
+	var id int
+	row := r.QueryRow("SELECT user_id FROM users WHERE user_id = $1 AND tenant_id = current_tenant()", userId)
+	if err := row.Scan(&id); err != nil {
+		return nil, apperrors.ErrRecordNotFound
+	}
+
+	trip := model.TripRecord{}
+	trips := []model.TripRecord{}
+	query := `
+			SELECT trip_id, user_id, name
+			FROM trips
+			WHERE user_id = $1 AND deleted_at IS NULL AND tenant_id = current_tenant()
+			ORDER BY trip_id`
+	rows, err := r.Query(query, userId)
+	if err != nil {
+		return trips, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		if err = rows.Scan(&trip.TripId, &trip.UserId, &trip.Name); err != nil {
+			return trips, err
+		}
+		trips = append(trips, trip)
+	}
+	return trips, rows.Err()
+}
+
+func (r *tripRepo) FindVisits(userId string) ([]model.TripVisit, error) {
+	query := `
+			SELECT visits.visit_id, visits.location_id, locations.place, COALESCE(countries.name, locations.country),
+				   COALESCE(locations.country_code, ''), visits.visited_at, visits.mark, COALESCE(trips.trip_id, 0)
+			FROM visits
+				JOIN locations
+					ON locations.location_id = visits.location_id AND locations.tenant_id = visits.tenant_id
+				LEFT JOIN countries
+					ON countries.code = locations.country_code
+				LEFT JOIN trips
+					ON trips.trip_id = visits.trip_id AND trips.tenant_id = visits.tenant_id AND trips.deleted_at IS NULL
+			WHERE visits.user_id = $1
+			  AND visits.tenant_id = current_tenant()
+			  AND visits.deleted_at IS NULL
+			ORDER BY visits.visited_at, visits.visit_id`
+	visit := model.TripVisit{}
+	visits := []model.TripVisit{}
+	rows, err := r.Query(query, userId)
+	if err != nil {
+		return visits, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		err = rows.Scan(&visit.VisitId, &visit.LocationId, &visit.Place, &visit.Country, &visit.CountryCode,
+			&visit.VisitedAt, &visit.Mark, &visit.TripId)
+		if err != nil {
+			return visits, err
+		}
+		visits = append(visits, visit)
+	}
+	return visits, rows.Err()
+}
+
+func (r *tripRepo) Insert(trip model.TripRecord) (model.TripRecord, error) {
+	tx, err := r.Beginx()
+	if err != nil {
+		return trip, err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec("INSERT INTO trips (trip_id, user_id, name) VALUES ($1, $2, $3)", trip.TripId, trip.UserId, trip.Name)
+	if err != nil {
+		return trip, apperrors.ErrIncorrectQuery
+	}
+	if err = assignTripVisits(tx, trip.TripId, trip.UserId, trip.VisitIds); err != nil {
+		return trip, err
+	}
+
+	return trip, tx.Commit()
+}
+
+func (r *tripRepo) Update(id string, trip model.TripUpdate) error {
+	tx, err := r.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var tripId, userId uint32
+	query := `
+			UPDATE trips SET name = $1, updated_at = now()
+			WHERE trip_id = $2 AND deleted_at IS NULL AND tenant_id = current_tenant()
+			RETURNING trip_id, user_id`
+	row := tx.QueryRow(query, trip.Name, id)
+	if err = row.Scan(&tripId, &userId); err != nil {
+		return apperrors.ErrRecordNotFound
+	}
+	if trip.VisitIds != nil {
+		query = "UPDATE visits SET trip_id = NULL, updated_at = now() WHERE trip_id = $1 AND tenant_id = current_tenant()"
+		if _, err = tx.Exec(query, tripId); err != nil {
+			return err
+		}
+		if err = assignTripVisits(tx, tripId, userId, trip.VisitIds); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *tripRepo) DeleteById(id string) error {
+	query := `
+			UPDATE trips SET deleted_at = now(), updated_at = now()
+			WHERE trip_id = $1 AND deleted_at IS NULL AND tenant_id = current_tenant()`
+	res, err := r.Exec(query, id)
+	if err != nil {
+		return err
+	}
+	rowsAff, _ := res.RowsAffected()
+	if rowsAff == 0 {
+		return apperrors.ErrRecordNotFound
+	}
+	return err
+}
+
+func (r *tripRepo) Restore(id string) error {
+	query := `
+			UPDATE trips SET deleted_at = NULL, updated_at = now()
+			WHERE trip_id = $1 AND deleted_at IS NOT NULL AND tenant_id = current_tenant()`
+	res, err := r.Exec(query, id)
+	if err != nil {
+		return err
+	}
+	rowsAff, _ := res.RowsAffected()
+	if rowsAff == 0 {
+		return apperrors.ErrRecordNotFound
+	}
+	return err
+}
+
+// assignTripVisits moves visits into the trip, every visit must belong to the trip user.
+func assignTripVisits(tx *sqlx.Tx, tripId, userId uint32, visitIds []uint32) error {
+	if len(visitIds) == 0 {
+		return nil
+	}
+	ids := make([]int64, len(visitIds))
+	for i, id := range visitIds {
+		ids[i] = int64(id)
+	}
+	query := `
+			UPDATE visits SET trip_id = $1, updated_at = now()
+			WHERE user_id = $2 AND visit_id = ANY($3) AND deleted_at IS NULL AND tenant_id = current_tenant()`
+	res, err := tx.Exec(query, tripId, userId, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	if rowsAff, err := res.RowsAffected(); err != nil || rowsAff != int64(len(ids)) {
+		return apperrors.ErrIncorrectQuery
+	}
+	return nil
+}
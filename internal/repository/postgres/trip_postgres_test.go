@@ -0,0 +1,324 @@
+package postgres
+
+import (
+	"github.com/lib/pq"
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/pkg/apperrors"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	sqlmock "github.com/zhashkevych/go-sqlxmock"
+	"testing"
+)
+
+func TestTripRepo_FindAll(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	repository := newTripRepo(db)
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		want    []model.TripRecord
+		wantErr bool
+	}{
+		{
+			name: "Ok",
+			mock: func() {
+				mock.ExpectQuery("SELECT user_id FROM users").WithArgs("1").
+					WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(1))
+				rows := sqlmock.NewRows([]string{"trip_id", "user_id", "name"}).
+					AddRow(1, 1, "Honeymoon").
+					AddRow(2, 1, "Andes")
+				mock.ExpectQuery("SELECT trip_id, user_id, name FROM trips WHERE (.+)").
+					WithArgs("1").WillReturnRows(rows)
+			},
+			want: []model.TripRecord{
+				{TripId: 1, UserId: 1, Name: "Honeymoon"},
+				{TripId: 2, UserId: 1, Name: "Andes"},
+			},
+		},
+		{
+			name: "Not Found",
+			mock: func() {
+				mock.ExpectQuery("SELECT user_id FROM users").WithArgs("1").
+					WillReturnError(apperrors.ErrRecordNotFound)
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := repository.FindAll("1")
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestTripRepo_FindVisits(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	repository := newTripRepo(db)
+
+	rows := sqlmock.NewRows([]string{"visit_id", "location_id", "place", "country", "country_code", "visited_at", "mark", "trip_id"}).
+		AddRow(1, 1, "Machu Picchu", "Peru", "PE", "2019-06-01", 5, 2).
+		AddRow(2, 3, "Red Square", "Russia", "RU", "2020-01-05", 4, 0)
+	mock.ExpectQuery("SELECT (.+) FROM visits (.+) WHERE visits.user_id = (.+) ORDER BY visits.visited_at").
+		WithArgs("1").WillReturnRows(rows)
+
+	got, err := repository.FindVisits("1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []model.TripVisit{
+		{VisitId: 1, LocationId: 1, Place: "Machu Picchu", Country: "Peru", CountryCode: "PE", VisitedAt: "2019-06-01", Mark: 5, TripId: 2},
+		{VisitId: 2, LocationId: 3, Place: "Red Square", Country: "Russia", CountryCode: "RU", VisitedAt: "2020-01-05", Mark: 4},
+	}, got)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTripRepo_Insert(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	repository := newTripRepo(db)
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		input   model.TripRecord
+		wantErr bool
+	}{
+		{
+			name: "Ok",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO trips").WithArgs(1, 1, "Andes").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("UPDATE visits SET trip_id = (.+) WHERE user_id = (.+) AND visit_id = ANY(.+)").
+					WithArgs(1, 1, pq.Array([]int64{1, 2})).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectCommit()
+			},
+			input: model.TripRecord{TripId: 1, UserId: 1, Name: "Andes", VisitIds: []uint32{1, 2}},
+		},
+		{
+			name: "Foreign Visit",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO trips").WithArgs(1, 1, "Andes").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("UPDATE visits SET trip_id").
+					WithArgs(1, 1, pq.Array([]int64{1, 7})).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectRollback()
+			},
+			input:   model.TripRecord{TripId: 1, UserId: 1, Name: "Andes", VisitIds: []uint32{1, 7}},
+			wantErr: true,
+		},
+		{
+			name: "Trip Exists",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO trips").WithArgs(1, 1, "Andes").
+					WillReturnError(apperrors.ErrIncorrectQuery)
+				mock.ExpectRollback()
+			},
+			input:   model.TripRecord{TripId: 1, UserId: 1, Name: "Andes"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := repository.Insert(tt.input)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.input, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestTripRepo_Update(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	repository := newTripRepo(db)
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		input   model.TripUpdate
+		wantErr bool
+	}{
+		{
+			name: "Rename",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("UPDATE trips SET name = (.+) RETURNING trip_id, user_id").WithArgs("Andes", "1").
+					WillReturnRows(sqlmock.NewRows([]string{"trip_id", "user_id"}).AddRow(1, 1))
+				mock.ExpectCommit()
+			},
+			input: model.TripUpdate{Name: "Andes"},
+		},
+		{
+			name: "Replace Visits",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("UPDATE trips SET name").WithArgs("Andes", "1").
+					WillReturnRows(sqlmock.NewRows([]string{"trip_id", "user_id"}).AddRow(1, 1))
+				mock.ExpectExec("UPDATE visits SET trip_id = NULL").WithArgs(1).
+					WillReturnResult(sqlmock.NewResult(0, 3))
+				mock.ExpectExec("UPDATE visits SET trip_id = (.+) AND visit_id = ANY(.+)").
+					WithArgs(1, 1, pq.Array([]int64{4})).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			input: model.TripUpdate{Name: "Andes", VisitIds: []uint32{4}},
+		},
+		{
+			name: "Not Found",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("UPDATE trips SET name").WithArgs("Andes", "1").
+					WillReturnRows(sqlmock.NewRows([]string{"trip_id", "user_id"}))
+				mock.ExpectRollback()
+			},
+			input:   model.TripUpdate{Name: "Andes"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			err := repository.Update("1", tt.input)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestTripRepo_DeleteById(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	repository := newTripRepo(db)
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		wantErr bool
+	}{
+		{
+			name: "Ok",
+			mock: func() {
+				mock.ExpectExec("UPDATE trips SET deleted_at = now\\(\\)(.+) WHERE (.+) AND deleted_at IS NULL").WithArgs("1").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+		},
+		{
+			name: "Not Found",
+			mock: func() {
+				mock.ExpectExec("UPDATE trips SET deleted_at = now\\(\\)(.+) WHERE (.+) AND deleted_at IS NULL").WithArgs("1").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			err := repository.DeleteById("1")
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestTripRepo_Restore(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	repository := newTripRepo(db)
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		wantErr bool
+	}{
+		{
+			name: "Ok",
+			mock: func() {
+				mock.ExpectExec("UPDATE trips SET deleted_at = NULL(.+) WHERE (.+) AND deleted_at IS NOT NULL").WithArgs("1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "Not Deleted",
+			mock: func() {
+				mock.ExpectExec("UPDATE trips SET deleted_at = NULL").WithArgs("1").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			err := repository.Restore("1")
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, apperrors.ErrRecordNotFound)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -1,7 +1,9 @@
 package postgres
 
 import (
+	"fmt"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/rinuccia/travels-api/internal/model"
 	"github.com/rinuccia/travels-api/pkg/apperrors"
 )
@@ -15,10 +17,14 @@ func newUserRepo(db *sqlx.DB) *userRepo {
 }
 
 func (r *userRepo) FindById(id string) (model.User, error) {
-	query := "SELECT * FROM users WHERE user_id = $1"
+	query := `
+			SELECT user_id, email, first_name, last_name, gender, private, verified
+			FROM users
+			WHERE user_id = $1 AND tenant_id = current_tenant()`
 	user := model.User{}
 	row := r.QueryRow(query, id)
-	err := row.Scan(&user.UserId, &user.Email, &user.FirstName, &user.LastName, &user.Gender)
+	err := row.Scan(&user.UserId, &user.Email, &user.FirstName, &user.LastName, &user.Gender, &user.Private,
+		&user.Verified)
 	if err != nil {
 		return user, apperrors.ErrRecordNotFound
 	}
@@ -26,25 +32,144 @@ func (r *userRepo) FindById(id string) (model.User, error) {
 	return user, err
 }
 
-func (r *userRepo) Insert(user model.User) (model.User, error) {
-	query := "INSERT INTO users (user_id, email, first_name, last_name, gender) VALUES ($1, $2, $3, $4, $5)"
-	_, err := r.Exec(query, user.UserId, user.Email, user.FirstName, user.LastName, user.Gender)
+// FindSummary favours the most visited country, ties go to the better average mark.
+// The most revisited place needs at least two visits, ties go to the most recent visit.
+func (r *userRepo) FindSummary(id string) (model.UserSummary, error) {
+	query := `
+			WITH user_visits AS (
+				SELECT visits.location_id, visits.visited_at, visits.mark, locations.place,
+					   COALESCE(countries.name, locations.country) AS country
+				FROM visits
+					JOIN locations
+						ON locations.location_id = visits.location_id AND locations.tenant_id = visits.tenant_id
+					LEFT JOIN countries
+						ON countries.code = locations.country_code
+				WHERE visits.user_id = $1
+				  AND visits.tenant_id = current_tenant()
+				  AND visits.deleted_at IS NULL
+			)
+			SELECT users.user_id,
+				   (SELECT COUNT(*) FROM user_visits) AS visits,
+				   (SELECT COUNT(DISTINCT location_id) FROM user_visits) AS locations,
+				   (SELECT COUNT(DISTINCT country) FROM user_visits) AS countries,
+				   (SELECT COALESCE(MIN(visited_at), '') FROM user_visits) AS first_visit,
+				   (SELECT COALESCE(MAX(visited_at), '') FROM user_visits) AS last_visit,
+				   (SELECT COALESCE(ROUND(AVG(mark), 2), 0) FROM user_visits) AS avg_mark,
+				   COALESCE((SELECT country
+							 FROM user_visits
+							 GROUP BY country
+							 ORDER BY COUNT(*) DESC, AVG(mark) DESC, country
+							 LIMIT 1), '') AS favourite_country,
+				   COALESCE((SELECT place
+							 FROM user_visits
+							 GROUP BY location_id, place
+							 HAVING COUNT(*) > 1
+							 ORDER BY COUNT(*) DESC, MAX(visited_at) DESC, location_id
+							 LIMIT 1), '') AS most_revisited_place
+			FROM users
+			WHERE users.user_id = $1 AND users.tenant_id = current_tenant()`
+	summary := model.UserSummary{}
+	row := r.QueryRow(query, id)
+	err := row.Scan(&summary.UserId, &summary.Visits, &summary.Locations, &summary.Countries, &summary.FirstVisit,
+		&summary.LastVisit, &summary.AvgMark, &summary.FavouriteCountry, &summary.MostRevisitedPlace)
 	if err != nil {
-		return user, apperrors.ErrIncorrectQuery
+		return summary, apperrors.ErrRecordNotFound
 	}
 
+	return summary, err
+}
+
+func (r *userRepo) Insert(actor model.Actor, user model.User) (model.User, error) {
+	err := audited(r.DB, actor, model.AuditEntityUser, entityId(user.UserId), func(tx *sqlx.Tx) error {
+		query := "INSERT INTO users (user_id, email, first_name, last_name, gender, private) VALUES ($1, $2, $3, $4, $5, $6)"
+		_, err := tx.Exec(query, user.UserId, user.Email, user.FirstName, user.LastName, user.Gender, user.Private)
+		if err != nil {
+			return apperrors.ErrIncorrectQuery
+		}
+		return nil
+	})
 	return user, err
 }
 
-func (r *userRepo) Update(id string, u model.User) error {
-	query := "UPDATE users SET email = $1, first_name = $2, last_name = $3, gender = $4 WHERE user_id = $5"
+func (r *userRepo) Update(actor model.Actor, id string, u model.User) error {
+	return audited(r.DB, actor, model.AuditEntityUser, id, func(tx *sqlx.Tx) error {
+		query := `
+			UPDATE users SET email = $1, first_name = $2, last_name = $3, gender = $4, private = $5, updated_at = now()
+			WHERE user_id = $6 AND tenant_id = current_tenant()`
+		res, err := tx.Exec(query, u.Email, u.FirstName, u.LastName, u.Gender, u.Private, id)
+		if err != nil {
+			return apperrors.ErrIncorrectQuery
+		}
+		if rowsAff, err := res.RowsAffected(); rowsAff == 0 && err == nil {
+			return apperrors.ErrRecordNotFound
+		}
+		return err
+	})
+}
+
+func (r *userRepo) UpdateRole(actor model.Actor, id string, role string) error {
+	return audited(r.DB, actor, model.AuditEntityUser, id, func(tx *sqlx.Tx) error {
+		query := "UPDATE users SET role = $1, updated_at = now() WHERE user_id = $2 AND tenant_id = current_tenant()"
+		res, err := tx.Exec(query, role, id)
+		if err != nil {
+			return apperrors.ErrIncorrectQuery
+		}
+		if rowsAff, err := res.RowsAffected(); rowsAff == 0 && err == nil {
+			return apperrors.ErrRecordNotFound
+		}
+		return err
+	})
+}
 
-	res, err := r.Exec(query, u.Email, u.FirstName, u.LastName, u.Gender, id)
+// MigrateUserEmails replaces the plain unique constraint on email with a unique index on lower(email) per tenant.
+// Emails already equal ignoring case fail the migration with apperrors.ErrEmailCollision, they are returned and left
+// untouched for an operator to resolve.
+func MigrateUserEmails(db *sqlx.DB) ([]model.EmailCollision, error) {
+	query := `
+			SELECT tenant_id, lower(email), array_agg(user_id ORDER BY user_id)
+			FROM users
+			GROUP BY tenant_id, lower(email)
+			HAVING count(*) > 1
+			ORDER BY tenant_id, lower(email)`
+	rows, err := db.Query(query)
 	if err != nil {
-		return apperrors.ErrIncorrectQuery
+		return nil, err
+	}
+	defer rows.Close()
+
+	var collisions []model.EmailCollision
+	for rows.Next() {
+		var collision model.EmailCollision
+		var ids []int64
+		if err = rows.Scan(&collision.TenantId, &collision.Email, pq.Array(&ids)); err != nil {
+			return nil, err
+		}
+		for _, id := range ids {
+			collision.UserIds = append(collision.UserIds, uint32(id))
+		}
+		collisions = append(collisions, collision)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(collisions) > 0 {
+		return collisions, fmt.Errorf("%w: %d emails", apperrors.ErrEmailCollision, len(collisions))
+	}
+
+	tx, err := db.Beginx()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	query = "CREATE UNIQUE INDEX IF NOT EXISTS users_tenant_email_lower_idx ON users (tenant_id, lower(email))"
+	if _, err = tx.Exec(query); err != nil {
+		return nil, err
+	}
+	if _, err = tx.Exec("DROP INDEX IF EXISTS users_email_lower_idx"); err != nil {
+		return nil, err
 	}
-	if rowsAff, err := res.RowsAffected(); rowsAff == 0 && err == nil {
-		return apperrors.ErrRecordNotFound
+	if _, err = tx.Exec("ALTER TABLE users DROP CONSTRAINT IF EXISTS users_email_key"); err != nil {
+		return nil, err
 	}
-	return err
+	return nil, tx.Commit()
 }
@@ -28,8 +28,8 @@ func TestUserRepo_FindById(t *testing.T) {
 		{
 			name: "Ok",
 			mock: func() {
-				rows := mock.NewRows([]string{"user_id", "email", "first_name", "last_name", "gender"}).
-					AddRow("1", "test@gmail.com", "John", "Smith", "m")
+				rows := mock.NewRows([]string{"user_id", "email", "first_name", "last_name", "gender", "private", "verified"}).
+					AddRow("1", "test@gmail.com", "John", "Smith", "m", true, true)
 				mock.ExpectQuery("SELECT (.+) FROM users").
 					WithArgs("1").WillReturnRows(rows)
 			},
@@ -40,6 +40,8 @@ func TestUserRepo_FindById(t *testing.T) {
 				FirstName: "John",
 				LastName:  "Smith",
 				Gender:    "m",
+				Private:   true,
+				Verified:  true,
 			},
 		},
 		{
@@ -68,6 +70,80 @@ func TestUserRepo_FindById(t *testing.T) {
 	}
 }
 
+func TestUserRepo_FindSummary(t *testing.T) {
+	mockDB, mock, err := sqlmock.Newx()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer mockDB.Close()
+
+	repository := newUserRepo(mockDB)
+	columns := []string{"user_id", "visits", "locations", "countries", "first_visit", "last_visit", "avg_mark",
+		"favourite_country", "most_revisited_place"}
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		id      string
+		want    model.UserSummary
+		wantErr bool
+	}{
+		{
+			name: "Ok",
+			mock: func() {
+				rows := mock.NewRows(columns).
+					AddRow(1, 5, 3, 2, "2015-06-23", "2019-04-30", 4.2, "Peru", "Machu Picchu")
+				mock.ExpectQuery("WITH user_visits AS (.+) FROM users WHERE users.user_id = (.+)").
+					WithArgs("1").WillReturnRows(rows)
+			},
+			id: "1",
+			want: model.UserSummary{
+				UserId:             1,
+				Visits:             5,
+				Locations:          3,
+				Countries:          2,
+				FirstVisit:         "2015-06-23",
+				LastVisit:          "2019-04-30",
+				AvgMark:            4.2,
+				FavouriteCountry:   "Peru",
+				MostRevisitedPlace: "Machu Picchu",
+			},
+		},
+		{
+			name: "No Visits",
+			mock: func() {
+				rows := mock.NewRows(columns).AddRow(1, 0, 0, 0, "", "", 0, "", "")
+				mock.ExpectQuery("WITH user_visits AS").WithArgs("1").WillReturnRows(rows)
+			},
+			id:   "1",
+			want: model.UserSummary{UserId: 1},
+		},
+		{
+			name: "Not Found",
+			mock: func() {
+				mock.ExpectQuery("WITH user_visits AS").WithArgs("1").
+					WillReturnRows(mock.NewRows(columns))
+			},
+			id:      "1",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			got, err := repository.FindSummary(tt.id)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
 func TestUserRepo_Insert(t *testing.T) {
 	mockDB, mock, err := sqlmock.Newx()
 	if err != nil {
@@ -87,9 +163,14 @@ func TestUserRepo_Insert(t *testing.T) {
 		{
 			name: "Ok",
 			mock: func() {
+				mock.ExpectBegin()
+				expectSnapshot(mock, "users", "1", "")
 				mock.ExpectExec("INSERT INTO users").
-					WithArgs(1, "test@gmail.com", "John", "Smith", "m").
+					WithArgs(1, "test@gmail.com", "John", "Smith", "m", false).
 					WillReturnResult(sqlmock.NewResult(1, 1))
+				expectSnapshot(mock, "users", "1", `{"user_id": 1}`)
+				expectAuditInsert(mock, "create", "user", "1", nil, `{"user_id": 1}`)
+				mock.ExpectCommit()
 			},
 			input: model.User{
 				UserId:    1,
@@ -109,9 +190,12 @@ func TestUserRepo_Insert(t *testing.T) {
 		{
 			name: "Incorrect Data",
 			mock: func() {
+				mock.ExpectBegin()
+				expectSnapshot(mock, "users", "1", "")
 				mock.ExpectExec("INSERT INTO users").
-					WithArgs(1, "test@gmail.com", "John", "Smith", "").
+					WithArgs(1, "test@gmail.com", "John", "Smith", "", false).
 					WillReturnError(apperrors.ErrIncorrectQuery)
+				mock.ExpectRollback()
 			},
 			input: model.User{
 				UserId:    1,
@@ -128,7 +212,7 @@ func TestUserRepo_Insert(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mock()
 
-			got, err := repository.Insert(tt.input)
+			got, err := repository.Insert(testActor, tt.input)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -161,9 +245,14 @@ func TestUserRepo_Update(t *testing.T) {
 		{
 			name: "Ok",
 			mock: func() {
+				mock.ExpectBegin()
+				expectSnapshot(mock, "users", "1", `{"gender": "f"}`)
 				mock.ExpectExec("UPDATE users SET (.+) WHERE (.+)").
-					WithArgs("test@gmail.com", "John", "Smith", "m", "1").
+					WithArgs("test@gmail.com", "John", "Smith", "m", false, "1").
 					WillReturnResult(sqlmock.NewResult(1, 1))
+				expectSnapshot(mock, "users", "1", `{"gender": "m"}`)
+				expectAuditInsert(mock, "update", "user", "1", `{"gender": "f"}`, `{"gender": "m"}`)
+				mock.ExpectCommit()
 			},
 			id: "1",
 			input: model.User{
@@ -176,9 +265,12 @@ func TestUserRepo_Update(t *testing.T) {
 		{
 			name: "Incorrect Data",
 			mock: func() {
+				mock.ExpectBegin()
+				expectSnapshot(mock, "users", "1", `{"gender": "f"}`)
 				mock.ExpectExec("UPDATE users SET (.+) WHERE (.+)").
-					WithArgs("test@gmail.com", "John", "Smith", "", "1").
+					WithArgs("test@gmail.com", "John", "Smith", "", false, "1").
 					WillReturnError(apperrors.ErrIncorrectQuery)
+				mock.ExpectRollback()
 			},
 			id: "1",
 			input: model.User{
@@ -193,9 +285,12 @@ func TestUserRepo_Update(t *testing.T) {
 		{
 			name: "Not Found",
 			mock: func() {
+				mock.ExpectBegin()
+				expectSnapshot(mock, "users", "1", "")
 				mock.ExpectExec("UPDATE users SET (.+) WHERE (.+)").
-					WithArgs("test@gmail.com", "John", "Smith", "m", "1").
+					WithArgs("test@gmail.com", "John", "Smith", "m", false, "1").
 					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
 			},
 			id: "1",
 			input: model.User{
@@ -213,7 +308,7 @@ func TestUserRepo_Update(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mock()
 
-			err = repository.Update(tt.id, tt.input)
+			err = repository.Update(testActor, tt.id, tt.input)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -228,3 +323,136 @@ func TestUserRepo_Update(t *testing.T) {
 		})
 	}
 }
+
+func TestUserRepo_UpdateRole(t *testing.T) {
+	mockDB, mock, err := sqlmock.Newx()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer mockDB.Close()
+
+	repository := newUserRepo(mockDB)
+
+	testTable := []struct {
+		name            string
+		mock            func()
+		wantErr         bool
+		expectedErrType error
+	}{
+		{
+			name: "Ok",
+			mock: func() {
+				mock.ExpectBegin()
+				expectSnapshot(mock, "users", "1", `{"role": "traveller"}`)
+				mock.ExpectExec("UPDATE users SET role = (.+) WHERE user_id = (.+)").
+					WithArgs("curator", "1").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				expectSnapshot(mock, "users", "1", `{"role": "curator"}`)
+				expectAuditInsert(mock, "update", "user", "1", `{"role": "traveller"}`, `{"role": "curator"}`)
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "Not Found",
+			mock: func() {
+				mock.ExpectBegin()
+				expectSnapshot(mock, "users", "1", "")
+				mock.ExpectExec("UPDATE users SET role = (.+) WHERE user_id = (.+)").
+					WithArgs("curator", "1").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+			wantErr:         true,
+			expectedErrType: apperrors.ErrRecordNotFound,
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			err := repository.UpdateRole(testActor, "1", "curator")
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, tt.expectedErrType)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestMigrateUserEmails(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		want    []model.EmailCollision
+		wantErr error
+	}{
+		{
+			name: "Ok",
+			mock: func() {
+				mock.ExpectQuery("SELECT tenant_id, lower\\(email\\), array_agg(.+) FROM users " +
+					"GROUP BY tenant_id, lower\\(email\\) HAVING count").
+					WillReturnRows(sqlmock.NewRows([]string{"tenant_id", "lower", "array_agg"}))
+				mock.ExpectBegin()
+				mock.ExpectExec("CREATE UNIQUE INDEX IF NOT EXISTS users_tenant_email_lower_idx " +
+					"ON users \\(tenant_id, lower\\(email\\)\\)").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("DROP INDEX IF EXISTS users_email_lower_idx").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("ALTER TABLE users DROP CONSTRAINT IF EXISTS users_email_key").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "Collisions",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"tenant_id", "lower", "array_agg"}).
+					AddRow(1, "john@gmail.com", []byte("{1,4}")).
+					AddRow(1, "kate@mail.ru", []byte("{2,3,7}")).
+					AddRow(2, "john@gmail.com", []byte("{1,2}"))
+				mock.ExpectQuery("SELECT tenant_id, lower\\(email\\), array_agg(.+) FROM users").WillReturnRows(rows)
+			},
+			want: []model.EmailCollision{
+				{TenantId: 1, Email: "john@gmail.com", UserIds: []uint32{1, 4}},
+				{TenantId: 1, Email: "kate@mail.ru", UserIds: []uint32{2, 3, 7}},
+				{TenantId: 2, Email: "john@gmail.com", UserIds: []uint32{1, 2}},
+			},
+			wantErr: apperrors.ErrEmailCollision,
+		},
+		{
+			name: "Index Error",
+			mock: func() {
+				mock.ExpectQuery("SELECT tenant_id, lower\\(email\\), array_agg(.+) FROM users").
+					WillReturnRows(sqlmock.NewRows([]string{"tenant_id", "lower", "array_agg"}))
+				mock.ExpectBegin()
+				mock.ExpectExec("CREATE UNIQUE INDEX").WillReturnError(sqlmock.ErrCancelled)
+				mock.ExpectRollback()
+			},
+			wantErr: sqlmock.ErrCancelled,
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := MigrateUserEmails(db)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package postgres
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/pkg/apperrors"
+)
+
+type verificationRepo struct {
+	*sqlx.DB
+}
+
+func newVerificationRepo(db *sqlx.DB) *verificationRepo {
+	return &verificationRepo{db}
+}
+
+// InsertVerification replaces the pending verifications of the user, only the latest mailed token works.
+func (r *verificationRepo) InsertVerification(v model.EmailVerification) error {
+	tx, err := r.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := "DELETE FROM email_verifications WHERE user_id = $1 AND used_at IS NULL AND tenant_id = current_tenant()"
+	if _, err = tx.Exec(query, v.UserId); err != nil {
+		return err
+	}
+	query = "INSERT INTO email_verifications (token_id, user_id, email, expires_at) VALUES ($1, $2, $3, $4)"
+	if _, err = tx.Exec(query, v.TokenId, v.UserId, v.Email, v.ExpiresAt); err != nil {
+		return apperrors.ErrRecordNotFound
+	}
+	return tx.Commit()
+}
+
+// ConfirmEmail uses up the verification and sets its email on the user, an email taken in the meantime is rejected.
+func (r *verificationRepo) ConfirmEmail(actor model.Actor, tokenId string, userId uint32) error {
+	return audited(r.DB, actor, model.AuditEntityUser, entityId(userId), func(tx *sqlx.Tx) error {
+		query := `
+			UPDATE email_verifications SET used_at = now()
+			WHERE token_id = $1 AND user_id = $2 AND used_at IS NULL AND expires_at > now()
+			  AND tenant_id = current_tenant()
+			RETURNING email`
+		var email string
+		if err := tx.QueryRow(query, tokenId, userId).Scan(&email); err != nil {
+			return apperrors.ErrRecordNotFound
+		}
+		query = `
+			UPDATE users SET email = $1, verified = true, updated_at = now()
+			WHERE user_id = $2 AND tenant_id = current_tenant()`
+		if _, err := tx.Exec(query, email, userId); err != nil {
+			return apperrors.ErrIncorrectQuery
+		}
+		return nil
+	})
+}
@@ -0,0 +1,146 @@
+package postgres
+
+import (
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/pkg/apperrors"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	sqlmock "github.com/zhashkevych/go-sqlxmock"
+	"testing"
+	"time"
+)
+
+func TestVerificationRepo_InsertVerification(t *testing.T) {
+	mockDB, mock, err := sqlmock.Newx()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer mockDB.Close()
+
+	repository := newVerificationRepo(mockDB)
+	verification := model.EmailVerification{TokenId: "ab12", UserId: 1, Email: "new@gmail.com",
+		ExpiresAt: time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC)}
+
+	testTable := []struct {
+		name            string
+		mock            func()
+		wantErr         bool
+		expectedErrType error
+	}{
+		{
+			name: "Ok",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("DELETE FROM email_verifications WHERE user_id = (.+) AND used_at IS NULL").
+					WithArgs(uint32(1)).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO email_verifications").
+					WithArgs("ab12", uint32(1), "new@gmail.com", verification.ExpiresAt).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "Unknown User",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("DELETE FROM email_verifications").
+					WithArgs(uint32(1)).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("INSERT INTO email_verifications").
+					WithArgs("ab12", uint32(1), "new@gmail.com", verification.ExpiresAt).
+					WillReturnError(sqlmock.ErrCancelled)
+				mock.ExpectRollback()
+			},
+			wantErr:         true,
+			expectedErrType: apperrors.ErrRecordNotFound,
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			err := repository.InsertVerification(verification)
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, tt.expectedErrType)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestVerificationRepo_ConfirmEmail(t *testing.T) {
+	mockDB, mock, err := sqlmock.Newx()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer mockDB.Close()
+
+	repository := newVerificationRepo(mockDB)
+
+	testTable := []struct {
+		name            string
+		mock            func()
+		wantErr         bool
+		expectedErrType error
+	}{
+		{
+			name: "Ok",
+			mock: func() {
+				mock.ExpectBegin()
+				expectSnapshot(mock, "users", "1", `{"email": "old@gmail.com", "verified": false}`)
+				mock.ExpectQuery("UPDATE email_verifications SET used_at = now\\(\\) WHERE token_id = (.+) AND user_id = (.+) AND used_at IS NULL AND expires_at > now\\(\\) AND tenant_id = current_tenant\\(\\) RETURNING email").
+					WithArgs("ab12", uint32(1)).
+					WillReturnRows(sqlmock.NewRows([]string{"email"}).AddRow("new@gmail.com"))
+				mock.ExpectExec("UPDATE users SET email = (.+), verified = true").
+					WithArgs("new@gmail.com", uint32(1)).WillReturnResult(sqlmock.NewResult(0, 1))
+				expectSnapshot(mock, "users", "1", `{"email": "new@gmail.com", "verified": true}`)
+				expectAuditInsert(mock, "update", "user", "1", `{"email": "old@gmail.com", "verified": false}`,
+					`{"email": "new@gmail.com", "verified": true}`)
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "Used Or Expired",
+			mock: func() {
+				mock.ExpectBegin()
+				expectSnapshot(mock, "users", "1", `{"email": "old@gmail.com", "verified": false}`)
+				mock.ExpectQuery("UPDATE email_verifications").
+					WithArgs("ab12", uint32(1)).WillReturnRows(sqlmock.NewRows([]string{"email"}))
+				mock.ExpectRollback()
+			},
+			wantErr:         true,
+			expectedErrType: apperrors.ErrRecordNotFound,
+		},
+		{
+			name: "Email Taken",
+			mock: func() {
+				mock.ExpectBegin()
+				expectSnapshot(mock, "users", "1", `{"email": "old@gmail.com", "verified": false}`)
+				mock.ExpectQuery("UPDATE email_verifications").
+					WithArgs("ab12", uint32(1)).
+					WillReturnRows(sqlmock.NewRows([]string{"email"}).AddRow("new@gmail.com"))
+				mock.ExpectExec("UPDATE users SET email").
+					WithArgs("new@gmail.com", uint32(1)).WillReturnError(sqlmock.ErrCancelled)
+				mock.ExpectRollback()
+			},
+			wantErr:         true,
+			expectedErrType: apperrors.ErrIncorrectQuery,
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			err := repository.ConfirmEmail(testActor, "ab12", 1)
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, tt.expectedErrType)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
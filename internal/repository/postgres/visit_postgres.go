@@ -1,6 +1,8 @@
 package postgres
 
 import (
+	"database/sql"
+	"errors"
 	"github.com/jmoiron/sqlx"
 	"github.com/rinuccia/travels-api/internal/model"
 	"github.com/rinuccia/travels-api/pkg/apperrors"
@@ -14,25 +16,32 @@ func newVisitRepo(db *sqlx.DB) *visitRepo {
 	return &visitRepo{db}
 }
 
-func (r *visitRepo) FindAll(id string) (model.UserVisits, error) {
+func (r *visitRepo) FindAll(id string, filter model.LocationFilter) (model.UserVisits, error) {
 	var userId uint32
 	visit := model.UserVisit{}
 	visits := model.UserVisits{}
-	row := r.QueryRow("SELECT user_id FROM users WHERE user_id = $1", id)
+	row := r.QueryRow("SELECT user_id FROM users WHERE user_id = $1 AND tenant_id = current_tenant()", id)
 	err := row.Scan(&userId)
 	if err != nil {
 		return visits, apperrors.ErrRecordNotFound
 	}
 	query := `
-			SELECT locations.place, locations.country, visits.visited_at, visits.mark
-			FROM users 
-				JOIN visits  
-					ON users.user_id = visits.user_id 
-				JOIN locations 
-					ON locations.location_id = visits.location_id 
+			SELECT locations.place, COALESCE(countries.name, locations.country), visits.visited_at, visits.mark` + visitJoins + `
+				LEFT JOIN countries
+					ON countries.code = locations.country_code
+				LEFT JOIN categories
+					ON categories.category_id = locations.category_id AND categories.tenant_id = locations.tenant_id
+					AND categories.deleted_at IS NULL
 			WHERE users.user_id = $1
+			  AND ($2 = '' OR categories.name = $2)
+			  AND ($3 = '' OR EXISTS (SELECT 1
+									  FROM location_tags
+										  JOIN tags
+											  ON tags.tag_id = location_tags.tag_id AND tags.tenant_id = location_tags.tenant_id
+									  WHERE location_tags.location_id = locations.location_id
+										AND location_tags.tenant_id = locations.tenant_id AND tags.name = $3))
 			ORDER BY visits.visited_at`
-	rows, err := r.Query(query, id)
+	rows, err := r.Query(query, id, filter.Category, filter.Tag)
 	if err != nil {
 		return visits, err
 	}
@@ -46,23 +55,86 @@ func (r *visitRepo) FindAll(id string) (model.UserVisits, error) {
 	return visits, err
 }
 
-func (r *visitRepo) Insert(visit model.Visit) (model.Visit, error) {
-	query := "INSERT INTO visits (visit_id, location_id, user_id, visited_at, mark) VALUES ($1, $2, $3, $4, $5)"
-	_, err := r.Exec(query, visit.VisitId, visit.LocationId, visit.UserId, visit.VisitedAt, visit.Mark)
-	if err != nil {
-		return visit, apperrors.ErrIncorrectQuery
-	}
+// Insert stores the visit with its optional review and marks the matching open wishlist item of the user as done.
+func (r *visitRepo) Insert(actor model.Actor, visit model.Visit) (model.Visit, error) {
+	err := audited(r.DB, actor, model.AuditEntityVisit, entityId(visit.VisitId), func(tx *sqlx.Tx) error {
+		query := "INSERT INTO visits (visit_id, location_id, user_id, visited_at, mark) VALUES ($1, $2, $3, $4, $5)"
+		_, err := tx.Exec(query, visit.VisitId, visit.LocationId, visit.UserId, visit.VisitedAt, visit.Mark)
+		if err != nil {
+			return writeError(err)
+		}
+		if visit.Review != nil {
+			query = "INSERT INTO reviews (visit_id, title, body, language, status) VALUES ($1, $2, $3, $4, $5)"
+			_, err = tx.Exec(query, visit.VisitId, visit.Review.Title, visit.Review.Body, visit.Review.Language, visit.Review.Status)
+			if err != nil {
+				return writeError(err)
+			}
+		}
+		query = `
+			UPDATE wishlist SET done_visit_id = $1
+			WHERE user_id = $2 AND location_id = $3 AND done_visit_id IS NULL AND tenant_id = current_tenant()`
+		_, err = tx.Exec(query, visit.VisitId, visit.UserId, visit.LocationId)
+		return err
+	})
 	return visit, err
 }
 
-func (r *visitRepo) DeleteById(id string) error {
-	res, err := r.Exec("DELETE FROM visits WHERE visit_id = $1", id)
-	if err != nil {
+// Upsert leaves a deleted visit of the id alone and fails with ErrAlreadyExists, it must be restored first.
+func (r *visitRepo) Upsert(actor model.Actor, visit model.Visit) (bool, error) {
+	var inserted bool
+	err := audited(r.DB, actor, model.AuditEntityVisit, entityId(visit.VisitId), func(tx *sqlx.Tx) error {
+		query := `
+			INSERT INTO visits (visit_id, location_id, user_id, visited_at, mark) VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (tenant_id, visit_id) DO UPDATE
+				SET location_id = EXCLUDED.location_id, user_id = EXCLUDED.user_id,
+					visited_at = EXCLUDED.visited_at, mark = EXCLUDED.mark, updated_at = now()
+				WHERE visits.deleted_at IS NULL
+			RETURNING (xmax = 0) AS inserted`
+		row := tx.QueryRow(query, visit.VisitId, visit.LocationId, visit.UserId, visit.VisitedAt, visit.Mark)
+		err := row.Scan(&inserted)
+		if errors.Is(err, sql.ErrNoRows) {
+			return apperrors.ErrAlreadyExists
+		}
+		return writeError(err)
+	})
+	return inserted, err
+}
+
+// DeleteById marks the visit deleted and reopens the wishlist item it completed.
+func (r *visitRepo) DeleteById(actor model.Actor, id string) error {
+	return audited(r.DB, actor, model.AuditEntityVisit, id, func(tx *sqlx.Tx) error {
+		query := `
+			UPDATE visits SET deleted_at = now(), updated_at = now()
+			WHERE visit_id = $1 AND deleted_at IS NULL AND tenant_id = current_tenant()`
+		res, err := tx.Exec(query, id)
+		if err != nil {
+			return err
+		}
+		rowsAff, _ := res.RowsAffected()
+		if rowsAff == 0 {
+			return apperrors.ErrRecordNotFound
+		}
+		query = "UPDATE wishlist SET done_visit_id = NULL WHERE done_visit_id = $1 AND tenant_id = current_tenant()"
+		_, err = tx.Exec(query, id)
 		return err
-	}
-	rowsAff, _ := res.RowsAffected()
-	if rowsAff == 0 {
-		return apperrors.ErrRecordNotFound
-	}
-	return err
+	})
+}
+
+// Restore clears the deletion mark of the visit and completes the matching open wishlist item again.
+func (r *visitRepo) Restore(actor model.Actor, id string) error {
+	return auditedAs(r.DB, actor, model.AuditActionRestore, model.AuditEntityVisit, id, func(tx *sqlx.Tx) error {
+		query := `
+			UPDATE visits SET deleted_at = NULL, updated_at = now()
+			WHERE visit_id = $1 AND deleted_at IS NOT NULL AND tenant_id = current_tenant()
+			RETURNING visit_id, user_id, location_id`
+		var visitId, userId, locationId uint32
+		if err := tx.QueryRow(query, id).Scan(&visitId, &userId, &locationId); err != nil {
+			return apperrors.ErrRecordNotFound
+		}
+		query = `
+			UPDATE wishlist SET done_visit_id = $1
+			WHERE user_id = $2 AND location_id = $3 AND done_visit_id IS NULL AND tenant_id = current_tenant()`
+		_, err := tx.Exec(query, visitId, userId, locationId)
+		return err
+	})
 }
@@ -1,6 +1,8 @@
 package postgres
 
 import (
+	"database/sql/driver"
+	"github.com/lib/pq"
 	"github.com/rinuccia/travels-api/internal/model"
 	"github.com/rinuccia/travels-api/pkg/apperrors"
 	"github.com/sirupsen/logrus"
@@ -34,14 +36,16 @@ func TestVisitRepo_FindAll(t *testing.T) {
 				rows := sqlmock.NewRows([]string{"place", "country", "visited_at", "mark"}).
 					AddRow("Red Square", "RF", "2015-06-23", 4).
 					AddRow("Grand Canyon", "USA", "2019-04-30", 5)
-				mock.ExpectQuery("SELECT (.+) FROM users").
-					WithArgs("1").WillReturnRows(rows)
+				mock.ExpectQuery("SELECT (.+) FROM visits JOIN users ON (.+) AND users.tenant_id = visits.tenant_id "+
+					"AND visits.tenant_id = current_tenant\\(\\) (.+) JOIN locations ON (.+) "+
+					"AND locations.tenant_id = visits.tenant_id (.+) WHERE users.user_id = (.+)").
+					WithArgs("1", "", "").WillReturnRows(rows)
 			},
 			userId: "1",
 			want: model.UserVisits{
 				Visits: []model.UserVisit{
-					{"Red Square", "RF", "2015-06-23", 4},
-					{"Grand Canyon", "USA", "2019-04-30", 5},
+					{Place: "Red Square", Country: "RF", VisitedAt: "2015-06-23", Mark: 4},
+					{Place: "Grand Canyon", Country: "USA", VisitedAt: "2019-04-30", Mark: 5},
 				},
 			},
 		},
@@ -60,7 +64,7 @@ func TestVisitRepo_FindAll(t *testing.T) {
 
 			tt.mock()
 
-			got, err := repository.FindAll(tt.userId)
+			got, err := repository.FindAll(tt.userId, model.LocationFilter{})
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -87,14 +91,22 @@ func TestVisitRepo_Insert(t *testing.T) {
 		mock    func()
 		input   model.Visit
 		want    model.Visit
-		wantErr bool
+		wantErr error
 	}{
 		{
 			name: "Ok",
 			mock: func() {
+				mock.ExpectBegin()
+				expectSnapshot(mock, "visits", "1", "")
 				mock.ExpectExec("INSERT INTO visits (.+)").
 					WithArgs(1, 1, 2, "2019-06-15", 4).
 					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("UPDATE wishlist SET done_visit_id = (.+)").
+					WithArgs(1, 2, 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				expectSnapshot(mock, "visits", "1", `{"visit_id": 1}`)
+				expectAuditInsert(mock, "create", "visit", "1", nil, `{"visit_id": 1}`)
+				mock.ExpectCommit()
 			},
 			input: model.Visit{
 				VisitId:    1,
@@ -111,11 +123,49 @@ func TestVisitRepo_Insert(t *testing.T) {
 				Mark:       4,
 			},
 		},
+		{
+			name: "With Review",
+			mock: func() {
+				mock.ExpectBegin()
+				expectSnapshot(mock, "visits", "1", "")
+				mock.ExpectExec("INSERT INTO visits (.+)").
+					WithArgs(1, 1, 2, "2019-06-15", 4).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("INSERT INTO reviews (.+)").
+					WithArgs(1, "Crowded", "Come early", "en", "pending").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("UPDATE wishlist SET done_visit_id = (.+)").
+					WithArgs(1, 2, 1).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				expectSnapshot(mock, "visits", "1", `{"visit_id": 1}`)
+				expectAuditInsert(mock, "create", "visit", "1", nil, `{"visit_id": 1}`)
+				mock.ExpectCommit()
+			},
+			input: model.Visit{
+				VisitId:    1,
+				LocationId: 1,
+				UserId:     2,
+				VisitedAt:  "2019-06-15",
+				Mark:       4,
+				Review:     &model.Review{Title: "Crowded", Body: "Come early", Language: "en", Status: "pending"},
+			},
+			want: model.Visit{
+				VisitId:    1,
+				LocationId: 1,
+				UserId:     2,
+				VisitedAt:  "2019-06-15",
+				Mark:       4,
+				Review:     &model.Review{Title: "Crowded", Body: "Come early", Language: "en", Status: "pending"},
+			},
+		},
 		{
 			name: "Incorrect Data",
 			mock: func() {
+				mock.ExpectBegin()
+				expectSnapshot(mock, "visits", "1", "")
 				mock.ExpectExec("INSERT INTO visits (.+)").
-					WithArgs(1, 1, 2, "2019-06-15", 10).WillReturnError(apperrors.ErrIncorrectQuery)
+					WithArgs(1, 1, 2, "2019-06-15", 10).WillReturnError(&pq.Error{Code: "23514"})
+				mock.ExpectRollback()
 			},
 			input: model.Visit{
 				VisitId:    1,
@@ -124,7 +174,43 @@ func TestVisitRepo_Insert(t *testing.T) {
 				VisitedAt:  "2019-06-15",
 				Mark:       10,
 			},
-			wantErr: true,
+			wantErr: apperrors.ErrIncorrectQuery,
+		},
+		{
+			name: "Duplicate Id",
+			mock: func() {
+				mock.ExpectBegin()
+				expectSnapshot(mock, "visits", "1", "")
+				mock.ExpectExec("INSERT INTO visits (.+)").
+					WithArgs(1, 1, 2, "2019-06-15", 4).WillReturnError(&pq.Error{Code: "23505"})
+				mock.ExpectRollback()
+			},
+			input: model.Visit{
+				VisitId:    1,
+				LocationId: 1,
+				UserId:     2,
+				VisitedAt:  "2019-06-15",
+				Mark:       4,
+			},
+			wantErr: apperrors.ErrAlreadyExists,
+		},
+		{
+			name: "Connection Lost",
+			mock: func() {
+				mock.ExpectBegin()
+				expectSnapshot(mock, "visits", "1", "")
+				mock.ExpectExec("INSERT INTO visits (.+)").
+					WithArgs(1, 1, 2, "2019-06-15", 4).WillReturnError(driver.ErrBadConn)
+				mock.ExpectRollback()
+			},
+			input: model.Visit{
+				VisitId:    1,
+				LocationId: 1,
+				UserId:     2,
+				VisitedAt:  "2019-06-15",
+				Mark:       4,
+			},
+			wantErr: driver.ErrBadConn,
 		},
 	}
 	for _, tt := range testTable {
@@ -132,10 +218,83 @@ func TestVisitRepo_Insert(t *testing.T) {
 
 			tt.mock()
 
-			got, err := repository.Insert(tt.input)
+			got, err := repository.Insert(testActor, tt.input)
 
-			if tt.wantErr {
-				assert.Error(t, err)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestVisitRepo_Upsert(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	repository := newVisitRepo(db)
+	visit := model.Visit{VisitId: 1, LocationId: 1, UserId: 2, VisitedAt: "2019-06-15", Mark: 4}
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		want    bool
+		wantErr error
+	}{
+		{
+			name: "Inserted",
+			mock: func() {
+				mock.ExpectBegin()
+				expectSnapshot(mock, "visits", "1", "")
+				mock.ExpectQuery("INSERT INTO visits (.+) ON CONFLICT (.+) DO UPDATE (.+) WHERE visits.deleted_at IS NULL").
+					WithArgs(1, 1, 2, "2019-06-15", 4).
+					WillReturnRows(sqlmock.NewRows([]string{"inserted"}).AddRow(true))
+				expectSnapshot(mock, "visits", "1", `{"visit_id": 1}`)
+				expectAuditInsert(mock, "create", "visit", "1", nil, `{"visit_id": 1}`)
+				mock.ExpectCommit()
+			},
+			want: true,
+		},
+		{
+			name: "Updated",
+			mock: func() {
+				mock.ExpectBegin()
+				expectSnapshot(mock, "visits", "1", `{"mark": 3}`)
+				mock.ExpectQuery("INSERT INTO visits").
+					WithArgs(1, 1, 2, "2019-06-15", 4).
+					WillReturnRows(sqlmock.NewRows([]string{"inserted"}).AddRow(false))
+				expectSnapshot(mock, "visits", "1", `{"mark": 4}`)
+				expectAuditInsert(mock, "update", "visit", "1", `{"mark": 3}`, `{"mark": 4}`)
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "Deleted Visit",
+			mock: func() {
+				mock.ExpectBegin()
+				expectSnapshot(mock, "visits", "1", "")
+				mock.ExpectQuery("INSERT INTO visits").
+					WithArgs(1, 1, 2, "2019-06-15", 4).
+					WillReturnRows(sqlmock.NewRows([]string{"inserted"}))
+				mock.ExpectRollback()
+			},
+			wantErr: apperrors.ErrAlreadyExists,
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := repository.Upsert(testActor, visit)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
 			} else {
 				assert.NoError(t, err)
 				assert.Equal(t, tt.want, got)
@@ -163,16 +322,26 @@ func TestVisitRepo_DeleteById(t *testing.T) {
 		{
 			name: "Ok",
 			mock: func() {
-				mock.ExpectExec("DELETE FROM visits WHERE (.+)").WithArgs("1").
+				mock.ExpectBegin()
+				expectSnapshot(mock, "visits", "1", `{"visit_id": 1}`)
+				mock.ExpectExec("UPDATE visits SET deleted_at = now\\(\\)(.+) WHERE (.+) AND deleted_at IS NULL").WithArgs("1").
 					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("UPDATE wishlist SET done_visit_id = NULL WHERE done_visit_id = (.+)").WithArgs("1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				expectSnapshot(mock, "visits", "1", "")
+				expectAuditInsert(mock, "delete", "visit", "1", `{"visit_id": 1}`, nil)
+				mock.ExpectCommit()
 			},
 			id: "1",
 		},
 		{
 			name: "Not Found",
 			mock: func() {
-				mock.ExpectExec("DELETE FROM visits WHERE (.+)").WithArgs("1").
+				mock.ExpectBegin()
+				expectSnapshot(mock, "visits", "1", "")
+				mock.ExpectExec("UPDATE visits SET deleted_at = now\\(\\)").WithArgs("1").
 					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
 			},
 			id:      "1",
 			wantErr: true,
@@ -183,7 +352,7 @@ func TestVisitRepo_DeleteById(t *testing.T) {
 
 			tt.mock()
 
-			err = repository.DeleteById(tt.id)
+			err = repository.DeleteById(testActor, tt.id)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -194,3 +363,59 @@ func TestVisitRepo_DeleteById(t *testing.T) {
 		})
 	}
 }
+
+func TestVisitRepo_Restore(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	repository := newVisitRepo(db)
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		wantErr bool
+	}{
+		{
+			name: "Ok",
+			mock: func() {
+				mock.ExpectBegin()
+				expectSnapshot(mock, "visits", "1", "")
+				mock.ExpectQuery("UPDATE visits SET deleted_at = NULL(.+) WHERE (.+) AND deleted_at IS NOT NULL").
+					WithArgs("1").WillReturnRows(sqlmock.NewRows([]string{"visit_id", "user_id", "location_id"}).AddRow(1, 2, 3))
+				mock.ExpectExec("UPDATE wishlist SET done_visit_id = (.+)").
+					WithArgs(1, 2, 3).WillReturnResult(sqlmock.NewResult(0, 1))
+				expectSnapshot(mock, "visits", "1", `{"visit_id": 1}`)
+				expectAuditInsert(mock, "restore", "visit", "1", nil, `{"visit_id": 1}`)
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "Not Deleted",
+			mock: func() {
+				mock.ExpectBegin()
+				expectSnapshot(mock, "visits", "1", `{"visit_id": 1}`)
+				mock.ExpectQuery("UPDATE visits SET deleted_at = NULL").
+					WithArgs("1").WillReturnRows(sqlmock.NewRows([]string{"visit_id", "user_id", "location_id"}))
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			err := repository.Restore(testActor, "1")
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, apperrors.ErrRecordNotFound)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package postgres
+
+import (
+	"fmt"
+	"github.com/rinuccia/travels-api/internal/model"
+)
+
+// visitJoins joins visits of the tenant with their user and location, listings and aggregates build on it.
+// Deleted visits and other tenants are left out by the join, so no reader built on it sees them.
+const visitJoins = `
+			FROM visits
+				JOIN users
+					ON users.user_id = visits.user_id AND users.tenant_id = visits.tenant_id
+					AND visits.tenant_id = current_tenant() AND visits.deleted_at IS NULL
+				JOIN locations
+					ON locations.location_id = visits.location_id AND locations.tenant_id = visits.tenant_id`
+
+// visitFilterCondition filters joined visits by date range and visitor gender with placeholders numbered from n.
+func visitFilterCondition(n int) string {
+	return fmt.Sprintf(`
+			  AND ($%[1]d = '' OR visits.visited_at >= $%[1]d)
+			  AND ($%[2]d = '' OR visits.visited_at <= $%[2]d)
+			  AND ($%[3]d = '' OR users.gender = $%[3]d)`, n, n+1, n+2)
+}
+
+// visitFilterArgs returns the arguments of visitFilterCondition.
+func visitFilterArgs(filter model.VisitFilter) []interface{} {
+	return []interface{}{filter.FromDate, filter.ToDate, filter.Gender}
+}
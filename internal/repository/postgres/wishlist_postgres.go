@@ -0,0 +1,117 @@
+package postgres
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/pkg/apperrors"
+)
+
+type wishlistRepo struct {
+	*sqlx.DB
+}
+
+func newWishlistRepo(db *sqlx.DB) *wishlistRepo {
+	return &wishlistRepo{db}
+}
+
+func (r *wishlistRepo) FindAll(userId string, filter model.WishlistFilter) (model.Wishlist, error) {
+	var id int
+	row := r.QueryRow("SELECT user_id FROM users WHERE user_id = $1 AND tenant_id = current_tenant()", userId)
+	if err := row.Scan(&id); err != nil {
+		return model.Wishlist{}, apperrors.ErrRecordNotFound
+	}
+
+	query := `
+			SELECT wishlist.location_id, locations.place, COALESCE(countries.name, locations.country),
+				   COALESCE(wishlist.planned_at, ''), wishlist.priority, COALESCE(wishlist.done_visit_id, 0)
+			FROM wishlist
+				JOIN locations
+					ON locations.location_id = wishlist.location_id AND locations.tenant_id = wishlist.tenant_id
+				LEFT JOIN countries
+					ON countries.code = locations.country_code
+			WHERE wishlist.user_id = $1
+			  AND wishlist.deleted_at IS NULL
+			  AND wishlist.tenant_id = current_tenant()
+			  AND ($2 = '' OR ($2 = 'done') = (wishlist.done_visit_id IS NOT NULL))
+			ORDER BY wishlist.done_visit_id IS NOT NULL, wishlist.priority DESC,
+					 wishlist.planned_at NULLS LAST, wishlist.location_id`
+	item := model.WishlistItem{}
+	wishlist := model.Wishlist{}
+	rows, err := r.Query(query, userId, filter.Status)
+	if err != nil {
+		return wishlist, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		err = rows.Scan(&item.LocationId, &item.Place, &item.Country, &item.PlannedAt, &item.Priority, &item.DoneVisitId)
+		if err != nil {
+			return wishlist, err
+		}
+		item.Done = item.DoneVisitId != 0
+		wishlist.List = append(wishlist.List, item)
+	}
+	return wishlist, rows.Err()
+}
+
+// Insert replaces a deleted item of the location with the new one, an existing item fails with ErrIncorrectQuery.
+func (r *wishlistRepo) Insert(userId string, item model.WishlistItem) (model.WishlistItem, error) {
+	query := `
+			INSERT INTO wishlist (user_id, location_id, planned_at, priority)
+			VALUES ($1, $2, NULLIF($3, ''), $4)
+			ON CONFLICT (tenant_id, user_id, location_id) DO UPDATE
+				SET planned_at = excluded.planned_at, priority = excluded.priority, done_visit_id = NULL,
+					deleted_at = NULL
+				WHERE wishlist.deleted_at IS NOT NULL`
+	res, err := r.Exec(query, userId, item.LocationId, item.PlannedAt, item.Priority)
+	if err != nil {
+		return item, apperrors.ErrIncorrectQuery
+	}
+	if rowsAff, err := res.RowsAffected(); rowsAff == 0 && err == nil {
+		return item, apperrors.ErrIncorrectQuery
+	}
+	return item, err
+}
+
+func (r *wishlistRepo) Update(userId, locationId string, item model.WishlistItem) error {
+	query := `
+			UPDATE wishlist SET planned_at = NULLIF($1, ''), priority = $2
+			WHERE user_id = $3 AND location_id = $4 AND deleted_at IS NULL AND tenant_id = current_tenant()`
+	res, err := r.Exec(query, item.PlannedAt, item.Priority, userId, locationId)
+	if err != nil {
+		return apperrors.ErrIncorrectQuery
+	}
+	if rowsAff, err := res.RowsAffected(); rowsAff == 0 && err == nil {
+		return apperrors.ErrRecordNotFound
+	}
+	return err
+}
+
+func (r *wishlistRepo) DeleteById(userId, locationId string) error {
+	query := `
+			UPDATE wishlist SET deleted_at = now()
+			WHERE user_id = $1 AND location_id = $2 AND deleted_at IS NULL AND tenant_id = current_tenant()`
+	res, err := r.Exec(query, userId, locationId)
+	if err != nil {
+		return err
+	}
+	rowsAff, _ := res.RowsAffected()
+	if rowsAff == 0 {
+		return apperrors.ErrRecordNotFound
+	}
+	return err
+}
+
+func (r *wishlistRepo) Restore(userId, locationId string) error {
+	query := `
+			UPDATE wishlist SET deleted_at = NULL
+			WHERE user_id = $1 AND location_id = $2 AND deleted_at IS NOT NULL AND tenant_id = current_tenant()`
+	res, err := r.Exec(query, userId, locationId)
+	if err != nil {
+		return err
+	}
+	rowsAff, _ := res.RowsAffected()
+	if rowsAff == 0 {
+		return apperrors.ErrRecordNotFound
+	}
+	return err
+}
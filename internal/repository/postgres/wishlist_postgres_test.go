@@ -0,0 +1,287 @@
+package postgres
+
+import (
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/pkg/apperrors"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	sqlmock "github.com/zhashkevych/go-sqlxmock"
+	"testing"
+)
+
+func TestWishlistRepo_FindAll(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	repository := newWishlistRepo(db)
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		filter  model.WishlistFilter
+		want    model.Wishlist
+		wantErr bool
+	}{
+		{
+			name: "Ok",
+			mock: func() {
+				mock.ExpectQuery("SELECT user_id FROM users").WithArgs("1").
+					WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(1))
+				rows := sqlmock.NewRows([]string{"location_id", "place", "country", "planned_at", "priority", "done_visit_id"}).
+					AddRow(1, "Machu Picchu", "Peru", "2024-05-01", 5, 0).
+					AddRow(2, "Eiffel Tower", "France", "", 3, 7)
+				mock.ExpectQuery("SELECT (.+) FROM wishlist (.+) WHERE wishlist.user_id = (.+)").
+					WithArgs("1", "").WillReturnRows(rows)
+			},
+			want: model.Wishlist{
+				List: []model.WishlistItem{
+					{LocationId: 1, Place: "Machu Picchu", Country: "Peru", PlannedAt: "2024-05-01", Priority: 5},
+					{LocationId: 2, Place: "Eiffel Tower", Country: "France", Priority: 3, Done: true, DoneVisitId: 7},
+				},
+			},
+		},
+		{
+			name: "Open",
+			mock: func() {
+				mock.ExpectQuery("SELECT user_id FROM users").WithArgs("1").
+					WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(1))
+				mock.ExpectQuery("SELECT (.+) FROM wishlist").WithArgs("1", "open").
+					WillReturnRows(sqlmock.NewRows([]string{"location_id", "place", "country", "planned_at", "priority", "done_visit_id"}))
+			},
+			filter: model.WishlistFilter{Status: "open"},
+			want:   model.Wishlist{},
+		},
+		{
+			name: "Not Found",
+			mock: func() {
+				mock.ExpectQuery("SELECT user_id FROM users").WithArgs("1").
+					WillReturnError(apperrors.ErrRecordNotFound)
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := repository.FindAll("1", tt.filter)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestWishlistRepo_Insert(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	repository := newWishlistRepo(db)
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		input   model.WishlistItem
+		wantErr bool
+	}{
+		{
+			name: "Ok",
+			mock: func() {
+				mock.ExpectExec("INSERT INTO wishlist").WithArgs("1", 1, "2024-05-01", 5).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+			input: model.WishlistItem{LocationId: 1, PlannedAt: "2024-05-01", Priority: 5},
+		},
+		{
+			name: "Replaces Deleted",
+			mock: func() {
+				mock.ExpectExec("INSERT INTO wishlist (.+) ON CONFLICT (.+) DO UPDATE (.+) deleted_at = NULL "+
+					"WHERE wishlist.deleted_at IS NOT NULL").WithArgs("1", 1, "", 3).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			input: model.WishlistItem{LocationId: 1, Priority: 3},
+		},
+		{
+			name: "Already Listed",
+			mock: func() {
+				mock.ExpectExec("INSERT INTO wishlist").WithArgs("1", 1, "", 3).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			input:   model.WishlistItem{LocationId: 1, Priority: 3},
+			wantErr: true,
+		},
+		{
+			name: "Unknown Location",
+			mock: func() {
+				mock.ExpectExec("INSERT INTO wishlist").WithArgs("1", 1, "", 3).
+					WillReturnError(apperrors.ErrIncorrectQuery)
+			},
+			input:   model.WishlistItem{LocationId: 1, Priority: 3},
+			wantErr: true,
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := repository.Insert("1", tt.input)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.input, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestWishlistRepo_Update(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	repository := newWishlistRepo(db)
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		wantErr bool
+	}{
+		{
+			name: "Ok",
+			mock: func() {
+				mock.ExpectExec("UPDATE wishlist SET (.+) WHERE user_id = (.+) AND location_id = (.+)").
+					WithArgs("2024-05-01", 4, "1", "2").WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "Not Found",
+			mock: func() {
+				mock.ExpectExec("UPDATE wishlist SET").
+					WithArgs("2024-05-01", 4, "1", "2").WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			err := repository.Update("1", "2", model.WishlistItem{PlannedAt: "2024-05-01", Priority: 4})
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestWishlistRepo_DeleteById(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	repository := newWishlistRepo(db)
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		wantErr bool
+	}{
+		{
+			name: "Ok",
+			mock: func() {
+				mock.ExpectExec("UPDATE wishlist SET deleted_at = now\\(\\) WHERE (.+) AND deleted_at IS NULL").
+					WithArgs("1", "2").WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "Not Found",
+			mock: func() {
+				mock.ExpectExec("UPDATE wishlist SET deleted_at").WithArgs("1", "2").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			err := repository.DeleteById("1", "2")
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestWishlistRepo_Restore(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer db.Close()
+
+	repository := newWishlistRepo(db)
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		wantErr bool
+	}{
+		{
+			name: "Ok",
+			mock: func() {
+				mock.ExpectExec("UPDATE wishlist SET deleted_at = NULL WHERE (.+) AND deleted_at IS NOT NULL").
+					WithArgs("1", "2").WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "Not Deleted",
+			mock: func() {
+				mock.ExpectExec("UPDATE wishlist SET deleted_at = NULL").WithArgs("1", "2").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			err := repository.Restore("1", "2")
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, apperrors.ErrRecordNotFound)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/internal/repository/postgres"
+	"github.com/rinuccia/travels-api/pkg/apperrors"
+	"time"
+)
+
+// apiKeyPrefix starts every API key so leaked keys are easy to recognize, apiKeyShownPrefix is the part kept
+// to tell keys apart.
+const (
+	apiKeyPrefix      = "tk_"
+	apiKeyShownPrefix = len(apiKeyPrefix) + 8
+)
+
+type apiKeyService struct {
+	repo postgres.APIKeyRepository
+}
+
+func newAPIKeyService(r postgres.APIKeyRepository) *apiKeyService {
+	return &apiKeyService{
+		repo: r,
+	}
+}
+
+func (s *apiKeyService) GetAll() (model.APIKeys, error) {
+	return s.repo.FindAll()
+}
+
+func (s *apiKeyService) Create(request model.APIKeyRequest) (model.NewAPIKey, error) {
+	if request.ExpiresAt != nil && !request.ExpiresAt.After(time.Now()) {
+		return model.NewAPIKey{}, apperrors.ErrIncorrectQuery
+	}
+
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return model.NewAPIKey{}, err
+	}
+	key := apiKeyPrefix + base64.RawURLEncoding.EncodeToString(b)
+
+	if request.Role == "" {
+		request.Role = model.RoleCurator
+	}
+	stored, err := s.repo.Insert(model.APIKey{
+		Name:      request.Name,
+		Prefix:    key[:apiKeyShownPrefix],
+		Scopes:    request.Scopes,
+		Role:      request.Role,
+		ExpiresAt: request.ExpiresAt,
+	}, hashToken(key))
+	if err != nil {
+		return model.NewAPIKey{}, err
+	}
+	return model.NewAPIKey{APIKey: stored, Key: key}, nil
+}
+
+func (s *apiKeyService) Revoke(id string) error {
+	return s.repo.Revoke(id)
+}
+
+func (s *apiKeyService) Authenticate(key string) (model.Identity, error) {
+	identity, err := s.repo.Use(hashToken(key))
+	if errors.Is(err, apperrors.ErrRecordNotFound) {
+		return identity, apperrors.ErrUnauthorized
+	}
+	return identity, err
+}
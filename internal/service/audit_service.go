@@ -0,0 +1,20 @@
+package service
+
+import (
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/internal/repository/postgres"
+)
+
+type auditService struct {
+	repo postgres.AuditRepository
+}
+
+func newAuditService(r postgres.AuditRepository) *auditService {
+	return &auditService{
+		repo: r,
+	}
+}
+
+func (s *auditService) GetAll(filter model.AuditFilter) (model.AuditLog, error) {
+	return s.repo.FindAll(filter)
+}
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/internal/repository/postgres"
+	"github.com/rinuccia/travels-api/pkg/apperrors"
+	"github.com/rinuccia/travels-api/pkg/email"
+	"github.com/rinuccia/travels-api/pkg/token"
+	"golang.org/x/crypto/bcrypt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// AuthConfig holds the keys signing access tokens, the token lifetimes and the tenant the tokens are issued for.
+type AuthConfig struct {
+	Keys       *token.Keyring
+	TenantId   uint32
+	AccessTTL  time.Duration
+	RefreshTTL time.Duration
+}
+
+var (
+	// dummyHash is compared when no user has the email, so unknown emails take as long as wrong passwords
+	dummyHash     []byte
+	dummyHashOnce sync.Once
+)
+
+type authService struct {
+	repo     postgres.AuthRepository
+	cfg      AuthConfig
+	users    UserConfig
+	verifier *verificationService
+}
+
+func newAuthService(r postgres.AuthRepository, cfg AuthConfig, users UserConfig,
+	verifier *verificationService) *authService {
+	return &authService{
+		repo:     r,
+		cfg:      cfg,
+		users:    users,
+		verifier: verifier,
+	}
+}
+
+func (s *authService) Register(actor model.Actor, registration model.Registration) (model.User, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(registration.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return registration.User, err
+	}
+	registration.Email = email.Normalize(registration.Email, s.users.LowercaseEmailLocalPart)
+	registration.Verified = false
+	user, err := s.repo.Register(actor, registration.User, hash)
+	if err != nil {
+		return user, err
+	}
+	return user, s.verifier.send(user.UserId, user.Email)
+}
+
+func (s *authService) Login(credentials model.Credentials) (model.TokenPair, error) {
+	stored, err := s.repo.FindCredentials(email.Normalize(credentials.Email, s.users.LowercaseEmailLocalPart))
+	if errors.Is(err, apperrors.ErrRecordNotFound) {
+		dummyHashOnce.Do(func() {
+			dummyHash, _ = bcrypt.GenerateFromPassword([]byte("no such user"), bcrypt.DefaultCost)
+		})
+		_ = bcrypt.CompareHashAndPassword(dummyHash, []byte(credentials.Password))
+		return model.TokenPair{}, apperrors.ErrInvalidCredentials
+	}
+	if err != nil {
+		return model.TokenPair{}, err
+	}
+	if bcrypt.CompareHashAndPassword(stored.PasswordHash, []byte(credentials.Password)) != nil {
+		return model.TokenPair{}, apperrors.ErrInvalidCredentials
+	}
+
+	refresh, next, err := s.newRefreshToken()
+	if err != nil {
+		return model.TokenPair{}, err
+	}
+	next.UserId = stored.UserId
+	if err = s.repo.InsertRefreshToken(next); err != nil {
+		return model.TokenPair{}, err
+	}
+	return s.tokenPair(stored.UserId, refresh)
+}
+
+func (s *authService) Refresh(refreshToken string) (model.TokenPair, error) {
+	refresh, next, err := s.newRefreshToken()
+	if err != nil {
+		return model.TokenPair{}, err
+	}
+	next, err = s.repo.RotateRefreshToken(hashToken(refreshToken), next)
+	if errors.Is(err, apperrors.ErrRecordNotFound) {
+		return model.TokenPair{}, apperrors.ErrUnauthorized
+	}
+	if err != nil {
+		return model.TokenPair{}, err
+	}
+	return s.tokenPair(next.UserId, refresh)
+}
+
+func (s *authService) Logout(refreshToken string) error {
+	err := s.repo.DeleteRefreshToken(hashToken(refreshToken))
+	if errors.Is(err, apperrors.ErrRecordNotFound) {
+		return nil
+	}
+	return err
+}
+
+func (s *authService) Authenticate(accessToken string) (model.Identity, error) {
+	claims, err := s.cfg.Keys.Verify(accessToken, time.Now())
+	if err != nil || claims.Audience != "" || claims.Tenant != tenantClaim(s.cfg.TenantId) {
+		return model.Identity{}, apperrors.ErrUnauthorized
+	}
+	userId, err := strconv.ParseUint(claims.Subject, 10, 32)
+	if err != nil {
+		return model.Identity{}, apperrors.ErrUnauthorized
+	}
+	// the role is read on every request rather than kept in the token, so role changes apply at once
+	identity, err := s.repo.FindIdentity(uint32(userId))
+	if errors.Is(err, apperrors.ErrRecordNotFound) {
+		return identity, apperrors.ErrUnauthorized
+	}
+	return identity, err
+}
+
+// tokenPair signs an access token for the user and pairs it with the refresh token.
+func (s *authService) tokenPair(userId uint32, refreshToken string) (model.TokenPair, error) {
+	now := time.Now()
+	access, err := s.cfg.Keys.Sign(token.Claims{
+		Subject:   strconv.FormatUint(uint64(userId), 10),
+		Tenant:    tenantClaim(s.cfg.TenantId),
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(s.cfg.AccessTTL).Unix(),
+	})
+	if err != nil {
+		return model.TokenPair{}, err
+	}
+	return model.TokenPair{
+		AccessToken:  access,
+		RefreshToken: refreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(s.cfg.AccessTTL.Seconds()),
+	}, nil
+}
+
+// newRefreshToken returns a random refresh token and its stored form without the user.
+func (s *authService) newRefreshToken() (string, model.RefreshToken, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", model.RefreshToken{}, err
+	}
+	refresh := base64.RawURLEncoding.EncodeToString(b)
+	return refresh, model.RefreshToken{
+		TokenHash: hashToken(refresh),
+		ExpiresAt: time.Now().Add(s.cfg.RefreshTTL),
+	}, nil
+}
+
+// tenantClaim returns the "tid" claim of tokens issued for the tenant.
+func tenantClaim(tenantId uint32) string {
+	return strconv.FormatUint(uint64(tenantId), 10)
+}
+
+// hashToken returns the stored form of a refresh token or API key, the secrets are random so a fast hash is enough.
+func hashToken(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
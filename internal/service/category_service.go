@@ -0,0 +1,40 @@
+package service
+
+import (
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/internal/repository/postgres"
+)
+
+type categoryService struct {
+	repo postgres.CategoryRepository
+}
+
+func newCategoryService(r postgres.CategoryRepository) *categoryService {
+	return &categoryService{
+		repo: r,
+	}
+}
+
+func (s *categoryService) GetAll() (model.Categories, error) {
+	return s.repo.FindAll()
+}
+
+func (s *categoryService) GetById(id string) (model.Category, error) {
+	return s.repo.FindById(id)
+}
+
+func (s *categoryService) Create(category model.Category) (model.Category, error) {
+	return s.repo.Insert(category)
+}
+
+func (s *categoryService) Update(id string, category model.Category) error {
+	return s.repo.Update(id, category)
+}
+
+func (s *categoryService) DeleteById(id string) error {
+	return s.repo.DeleteById(id)
+}
+
+func (s *categoryService) Restore(id string) error {
+	return s.repo.Restore(id)
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/internal/repository/postgres"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// tagSeparator joins location tags in a single csv column
+const tagSeparator = "|"
+
+// exportColumns are the csv header rows, they match the json field names of each entity
+var exportColumns = map[string][]string{
+	model.ExportEntityUsers:     {"user_id", "email", "first_name", "last_name", "gender"},
+	model.ExportEntityLocations: {"location_id", "place", "country", "lat", "lon", "category", "tags"},
+	model.ExportEntityVisits:    {"visit_id", "location_id", "user_id", "visited_at", "mark"},
+}
+
+type exportService struct {
+	repo postgres.ExportRepository
+}
+
+func newExportService(r postgres.ExportRepository) *exportService {
+	return &exportService{
+		repo: r,
+	}
+}
+
+func (s *exportService) Write(w io.Writer, opts model.ExportOptions) error {
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+	if opts.Format != model.ExportFormatZip {
+		return s.writeEntity(w, opts.Format, opts.Entity, opts.UpdatedSince)
+	}
+
+	archive := zip.NewWriter(w)
+	for _, entity := range model.ExportEntities {
+		file, err := archive.Create(entity + ".csv")
+		if err != nil {
+			return err
+		}
+		if err = s.writeEntity(file, model.ExportFormatCSV, entity, opts.UpdatedSince); err != nil {
+			return err
+		}
+	}
+	return archive.Close()
+}
+
+func (s *exportService) writeEntity(w io.Writer, format, entity string, since time.Time) error {
+	enc := newRecordEncoder(w, format)
+	if err := enc.header(exportColumns[entity]); err != nil {
+		return err
+	}
+
+	var err error
+	switch entity {
+	case model.ExportEntityUsers:
+		err = s.repo.EachUser(since, func(u model.User) error {
+			return enc.encode(u, []string{
+				formatUint(u.UserId), u.Email, u.FirstName, u.LastName, u.Gender,
+			})
+		})
+	case model.ExportEntityLocations:
+		err = s.repo.EachLocation(since, func(l model.Location) error {
+			return enc.encode(l, []string{
+				formatUint(l.LocationId), l.Place, l.Country, formatFloat(l.Lat), formatFloat(l.Lon),
+				l.Category, strings.Join(l.Tags, tagSeparator),
+			})
+		})
+	case model.ExportEntityVisits:
+		err = s.repo.EachVisit(since, func(v model.Visit) error {
+			return enc.encode(v, []string{
+				formatUint(v.VisitId), formatUint(v.LocationId), formatUint(v.UserId), v.VisitedAt, formatUint(uint32(v.Mark)),
+			})
+		})
+	}
+	if err != nil {
+		return err
+	}
+	return enc.flush()
+}
+
+// recordEncoder writes one entity per line either as a json object or as a csv record
+type recordEncoder struct {
+	buf  *bufio.Writer
+	json *json.Encoder
+	csv  *csv.Writer
+}
+
+func newRecordEncoder(w io.Writer, format string) *recordEncoder {
+	enc := &recordEncoder{buf: bufio.NewWriter(w)}
+	if format == model.ExportFormatCSV {
+		enc.csv = csv.NewWriter(enc.buf)
+	} else {
+		enc.json = json.NewEncoder(enc.buf)
+	}
+	return enc
+}
+
+func (e *recordEncoder) header(columns []string) error {
+	if e.csv == nil {
+		return nil
+	}
+	return e.csv.Write(columns)
+}
+
+func (e *recordEncoder) encode(v interface{}, record []string) error {
+	if e.csv == nil {
+		return e.json.Encode(v)
+	}
+	return e.csv.Write(record)
+}
+
+func (e *recordEncoder) flush() error {
+	if e.csv != nil {
+		e.csv.Flush()
+		if err := e.csv.Error(); err != nil {
+			return err
+		}
+	}
+	return e.buf.Flush()
+}
+
+func formatUint(n uint32) string {
+	return strconv.FormatUint(uint64(n), 10)
+}
+
+func formatFloat(f *float64) string {
+	if f == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*f, 'f', -1, 64)
+}
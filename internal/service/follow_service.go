@@ -0,0 +1,92 @@
+package service
+
+import (
+	"encoding/base64"
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/internal/repository/postgres"
+	"github.com/rinuccia/travels-api/pkg/apperrors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type followService struct {
+	repo postgres.FollowRepository
+}
+
+func newFollowService(r postgres.FollowRepository) *followService {
+	return &followService{
+		repo: r,
+	}
+}
+
+func (s *followService) Follow(userId, targetId string) (model.Follow, error) {
+	if userId == targetId {
+		return model.Follow{}, apperrors.ErrIncorrectQuery
+	}
+	return s.repo.Insert(userId, targetId)
+}
+
+func (s *followService) Unfollow(userId, targetId string) error {
+	return s.repo.Delete(userId, targetId)
+}
+
+func (s *followService) GetFollowers(userId string, filter model.FollowerFilter) (model.Followers, error) {
+	return s.repo.FindFollowers(userId, filter)
+}
+
+func (s *followService) Approve(userId, followerId string) error {
+	return s.repo.Approve(userId, followerId)
+}
+
+func (s *followService) RemoveFollower(userId, followerId string) error {
+	return s.repo.Delete(followerId, userId)
+}
+
+func (s *followService) GetFeed(userId string, query model.FeedQuery) (model.Feed, error) {
+	after, err := decodeFeedCursor(query.Cursor)
+	if err != nil {
+		return model.Feed{}, err
+	}
+
+	// one extra visit tells whether another page follows
+	visits, err := s.repo.FindFeed(userId, after, query.Limit+1)
+	if err != nil {
+		return model.Feed{}, err
+	}
+	feed := model.Feed{Visits: visits}
+	if len(visits) > query.Limit {
+		feed.Visits = visits[:query.Limit]
+		last := feed.Visits[query.Limit-1]
+		feed.NextCursor = encodeFeedCursor(model.FeedCursor{VisitedAt: last.VisitedAt, VisitId: last.VisitId})
+	}
+	return feed, nil
+}
+
+// encodeFeedCursor makes an opaque cursor from the position of a visit.
+func encodeFeedCursor(cursor model.FeedCursor) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(cursor.VisitedAt + "," + strconv.FormatUint(uint64(cursor.VisitId), 10)))
+}
+
+// decodeFeedCursor reads a cursor made by encodeFeedCursor, an empty cursor starts from the newest visit.
+func decodeFeedCursor(s string) (model.FeedCursor, error) {
+	if s == "" {
+		return model.FeedCursor{}, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return model.FeedCursor{}, apperrors.ErrIncorrectQuery
+	}
+	visitedAt, id, ok := strings.Cut(string(raw), ",")
+	if !ok {
+		return model.FeedCursor{}, apperrors.ErrIncorrectQuery
+	}
+	if _, err = time.Parse(visitDateLayout, visitedAt); err != nil {
+		return model.FeedCursor{}, apperrors.ErrIncorrectQuery
+	}
+	visitId, err := strconv.ParseUint(id, 10, 32)
+	if err != nil {
+		return model.FeedCursor{}, apperrors.ErrIncorrectQuery
+	}
+	return model.FeedCursor{VisitedAt: visitedAt, VisitId: uint32(visitId)}, nil
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/pkg/apperrors"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var validate = newValidator()
+
+// optionalColumns may be left out of the csv header, their values are treated as empty.
+var optionalColumns = map[string]bool{"lat": true, "lon": true, "category": true, "tags": true}
+
+// newValidator reports fields by their json names, so import errors refer to csv columns.
+func newValidator() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		return strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+	})
+	return v
+}
+
+// importRow stores one csv record and reports whether it was inserted (true) or updated (false).
+// Errors reject the record unless wrapped by abortImport.
+type importRow func(fields map[string]string) (bool, error)
+
+// importAbort is a failure of the store rather than of the record, it ends the import.
+type importAbort struct {
+	err error
+}
+
+func (e *importAbort) Error() string {
+	return e.err.Error()
+}
+
+// abortImport ends the import with err.
+func abortImport(err error) error {
+	return &importAbort{err: err}
+}
+
+// storeReason returns the rejection reason of a store error the record caused, others abort the import.
+func storeReason(err error, exists, incorrect string) error {
+	switch {
+	case errors.Is(err, apperrors.ErrAlreadyExists):
+		return errors.New(exists)
+	case errors.Is(err, apperrors.ErrIncorrectQuery):
+		return errors.New(incorrect)
+	default:
+		return abortImport(err)
+	}
+}
+
+// importCSV reads a csv document with a header row and hands every record to row.
+// Records that fail are collected as rejected lines, the import continues with the next one
+// unless the store fails.
+func importCSV(r io.Reader, columns []string, row importRow) (model.ImportResult, error) {
+	result := model.ImportResult{Rejected: []model.RejectedLine{}}
+
+	reader := csv.NewReader(r)
+	header, err := reader.Read()
+	var parseErr *csv.ParseError
+	if err == io.EOF || errors.As(err, &parseErr) {
+		return result, apperrors.ErrInvalidCSV
+	}
+	if err != nil {
+		return result, err
+	}
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		index[strings.TrimSpace(name)] = i
+	}
+	for _, column := range columns {
+		if _, ok := index[column]; !ok && !optionalColumns[column] {
+			return result, fmt.Errorf("%w: missing column %s", apperrors.ErrInvalidCSV, column)
+		}
+	}
+	reader.FieldsPerRecord = len(header)
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if errors.As(err, &parseErr) {
+			result.Rejected = append(result.Rejected, model.RejectedLine{Line: parseErr.StartLine, Reason: parseErr.Err.Error()})
+			continue
+		}
+		if err != nil {
+			return result, err
+		}
+
+		line, _ := reader.FieldPos(0)
+		fields := make(map[string]string, len(columns))
+		for _, column := range columns {
+			if i, ok := index[column]; ok {
+				fields[column] = strings.TrimSpace(record[i])
+			}
+		}
+
+		inserted, err := row(fields)
+		var abort *importAbort
+		if errors.As(err, &abort) {
+			return result, abort.err
+		}
+		if err != nil {
+			result.Rejected = append(result.Rejected, model.RejectedLine{Line: line, Reason: err.Error()})
+			continue
+		}
+		if inserted {
+			result.Inserted++
+		} else {
+			result.Updated++
+		}
+	}
+	return result, nil
+}
+
+func isTagSeparator(r rune) bool {
+	return string(r) == tagSeparator
+}
+
+// parseUint32 parses a csv id column.
+func parseUint32(fields map[string]string, column string) (uint32, error) {
+	n, err := strconv.ParseUint(fields[column], 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("%s: invalid number", column)
+	}
+	return uint32(n), nil
+}
+
+// parseFloat parses an optional csv number column, empty means null.
+func parseFloat(fields map[string]string, column string) (*float64, error) {
+	if fields[column] == "" {
+		return nil, nil
+	}
+	f, err := strconv.ParseFloat(fields[column], 64)
+	if err != nil {
+		return nil, fmt.Errorf("%s: invalid number", column)
+	}
+	return &f, nil
+}
+
+// validationReason turns validator errors into a short human readable reason.
+func validationReason(err error) error {
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return err
+	}
+	reasons := make([]string, 0, len(validationErrs))
+	for _, fe := range validationErrs {
+		reasons = append(reasons, fmt.Sprintf("%s: failed %s validation", fe.Field(), fe.Tag()))
+	}
+	return errors.New(strings.Join(reasons, "; "))
+}
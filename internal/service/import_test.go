@@ -0,0 +1,110 @@
+package service
+
+import (
+	"database/sql/driver"
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/internal/repository/postgres"
+	"github.com/rinuccia/travels-api/pkg/apperrors"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"strings"
+	"testing"
+)
+
+// insertErrVisitRepo fails the insert of a visit with the error listed for its id.
+type insertErrVisitRepo struct {
+	postgres.VisitRepository
+	errs map[uint32]error
+}
+
+func (r insertErrVisitRepo) Insert(_ model.Actor, visit model.Visit) (model.Visit, error) {
+	return visit, r.errs[visit.VisitId]
+}
+
+func TestVisitService_Import(t *testing.T) {
+	csv := "visit_id,location_id,user_id,visited_at,mark\n" +
+		"1,1,1,2019-06-15,4\n" +
+		"2,1,1,2019-06-16,4\n" +
+		"3,9,1,2019-06-17,4\n" +
+		"4,1,1,2019-06-18,4\n"
+
+	testTable := []struct {
+		name    string
+		errs    map[uint32]error
+		want    model.ImportResult
+		wantErr error
+	}{
+		{
+			name: "Ok",
+			want: model.ImportResult{Inserted: 4, Rejected: []model.RejectedLine{}},
+		},
+		{
+			name: "Rejected Rows",
+			errs: map[uint32]error{2: apperrors.ErrAlreadyExists, 3: apperrors.ErrIncorrectQuery},
+			want: model.ImportResult{Inserted: 2, Rejected: []model.RejectedLine{
+				{Line: 3, Reason: "visit_id already exists"},
+				{Line: 4, Reason: "user_id or location_id does not exist"},
+			}},
+		},
+		{
+			name:    "Store Failure Stops Import",
+			errs:    map[uint32]error{2: driver.ErrBadConn},
+			want:    model.ImportResult{Inserted: 1, Rejected: []model.RejectedLine{}},
+			wantErr: driver.ErrBadConn,
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newVisitService(insertErrVisitRepo{errs: tt.errs})
+
+			got, err := s.Import(model.Actor{Actor: "anonymous"}, strings.NewReader(csv), false)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+// failingReader returns err once its data is read.
+type failingReader struct {
+	data *strings.Reader
+	err  error
+}
+
+func (r failingReader) Read(p []byte) (int, error) {
+	if r.data.Len() == 0 {
+		return 0, r.err
+	}
+	return r.data.Read(p)
+}
+
+func TestVisitService_Import_readError(t *testing.T) {
+	testTable := []struct {
+		name    string
+		data    string
+		readErr error
+		wantErr error
+	}{
+		{name: "Empty", readErr: io.EOF, wantErr: apperrors.ErrInvalidCSV},
+		{name: "In Header", data: "visit_id,loc", readErr: driver.ErrBadConn, wantErr: driver.ErrBadConn},
+		{
+			name:    "In Records",
+			data:    "visit_id,location_id,user_id,visited_at,mark\n1,1,1,2019",
+			readErr: driver.ErrBadConn,
+			wantErr: driver.ErrBadConn,
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newVisitService(insertErrVisitRepo{})
+
+			_, err := s.Import(model.Actor{Actor: "anonymous"}, failingReader{strings.NewReader(tt.data), tt.readErr}, false)
+
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
@@ -2,6 +2,9 @@ package service
 
 import (
 	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/pkg/storage"
+	"io"
+	"time"
 )
 
 //go:generate mockgen -source=interface.go -destination=mocks/mock.go
@@ -11,35 +14,264 @@ type (
 		// GetById user.
 		GetById(id string) (model.User, error)
 
-		// Create new user.
-		Create(user model.User) (model.User, error)
+		// GetSummary of user travels.
+		GetSummary(id string) (model.UserSummary, error)
 
-		// Update user by id.
-		Update(id string, user model.User) error
+		// Create new user and mail a verification for the email, the actor is recorded in the audit log.
+		// ErrMailNotSent is returned with the created user when the verification could not be mailed.
+		Create(actor model.Actor, user model.User) (model.User, error)
+
+		// Update user by id, a new email takes effect once confirmed through the mailed verification.
+		// The actor is recorded in the audit log.
+		Update(actor model.Actor, id string, user model.User) error
+
+		// SetRole of user by id, the actor is recorded in the audit log.
+		SetRole(actor model.Actor, id string, role string) error
+	}
+
+	Auth interface {
+		// Register new user with a password and mail a verification for the email.
+		// ErrMailNotSent is returned with the registered user when the verification could not be mailed.
+		Register(actor model.Actor, registration model.Registration) (model.User, error)
+
+		// Login checks email and password and issues an access token with a refresh token.
+		Login(credentials model.Credentials) (model.TokenPair, error)
+
+		// Refresh exchanges a refresh token for new tokens, each refresh token works once.
+		Refresh(refreshToken string) (model.TokenPair, error)
+
+		// Logout revokes the refresh token.
+		Logout(refreshToken string) error
+
+		// Authenticate verifies the access token and returns the caller with the current role.
+		Authenticate(accessToken string) (model.Identity, error)
 	}
 
 	Location interface {
-		// GetAll locations.
-		GetAll() (model.Locations, error)
+		// GetAll locations matching the filter.
+		GetAll(filter model.LocationFilter) (model.Locations, error)
 
 		// GetById location.
 		GetById(id string) (model.Location, error)
 
-		// GetRating location by id.
-		GetRating(id string) (float32, error)
+		// GetRating location by id, counting only visits that match the filter.
+		GetRating(id string, filter model.VisitFilter) (float32, error)
+
+		// GetRatingDistribution of location by id: mark histogram with mean, median and standard deviation,
+		// optionally split into month or year buckets.
+		GetRatingDistribution(id string, filter model.VisitFilter, bucket string) (model.RatingDistribution, error)
+
+		// GetTop locations ranked by average mark or visit count, country may be a name or an ISO code.
+		GetTop(query model.TopLocationsQuery) (model.TopLocations, error)
 
-		// Create new location.
-		Create(loc model.Location) (model.Location, error)
+		// GetMostWanted locations ranked by open wishlist items.
+		GetMostWanted(limit int) (model.WantedLocations, error)
+
+		// GetNearby locations within radiusKm of the point ordered by great-circle distance.
+		GetNearby(lat, lon, radiusKm float64, limit int) (model.NearbyLocations, error)
+
+		// Create new location, the actor is recorded in the audit log.
+		Create(actor model.Actor, loc model.Location) (model.Location, error)
+
+		// Import locations from csv with a header row, upsert updates existing ids instead of rejecting them.
+		// Every stored line is recorded in the audit log.
+		Import(actor model.Actor, r io.Reader, upsert bool) (model.ImportResult, error)
 	}
 
 	Visit interface {
-		// GetAll user visits by id.
-		GetAll(id string) (model.UserVisits, error)
+		// GetAll user visits by id, filtered by location category and tag.
+		GetAll(id string, filter model.LocationFilter) (model.UserVisits, error)
+
+		// Create new visit, the actor is recorded in the audit log.
+		Create(actor model.Actor, visit model.Visit) (model.Visit, error)
+
+		// Import visits from csv with a header row, upsert updates existing ids instead of rejecting them.
+		// Every stored line is recorded in the audit log.
+		Import(actor model.Actor, r io.Reader, upsert bool) (model.ImportResult, error)
+
+		// DeleteById user visit, it can be restored until purged. The actor is recorded in the audit log.
+		DeleteById(actor model.Actor, id string) error
+
+		// Restore deleted user visit, the actor is recorded in the audit log.
+		Restore(actor model.Actor, id string) error
+	}
+
+	Category interface {
+		// GetAll categories.
+		GetAll() (model.Categories, error)
+
+		// GetById category.
+		GetById(id string) (model.Category, error)
 
-		// Create new visit.
-		Create(visit model.Visit) (model.Visit, error)
+		// Create new category.
+		Create(category model.Category) (model.Category, error)
 
-		// DeleteById user visit.
+		// Update category by id.
+		Update(id string, category model.Category) error
+
+		// DeleteById category, it can be restored until purged.
+		DeleteById(id string) error
+
+		// Restore deleted category.
+		Restore(id string) error
+	}
+
+	Stats interface {
+		// GetCountryStats returns per-country location and visit statistics.
+		GetCountryStats(filter model.VisitFilter, sort model.StatsSort) (model.CountriesStats, error)
+	}
+
+	Trip interface {
+		// GetAll trips of user by id: manual trips plus visits outside them grouped automatically,
+		// a gap of more than gapDays days or a change of country starts a new automatic trip.
+		GetAll(userId string, gapDays int) (model.Trips, error)
+
+		// Create new trip from visits of its user.
+		Create(trip model.TripRecord) (model.TripRecord, error)
+
+		// Update trip name and optionally its visits by id.
+		Update(id string, trip model.TripUpdate) error
+
+		// DeleteById trip, its visits are grouped automatically again. It can be restored until purged.
 		DeleteById(id string) error
+
+		// Restore deleted trip with its visits.
+		Restore(id string) error
+	}
+
+	Wishlist interface {
+		// GetAll wishlist items of user by id matching the filter.
+		GetAll(userId string, filter model.WishlistFilter) (model.Wishlist, error)
+
+		// Create wishlist item of user, priority defaults to 3.
+		Create(userId string, item model.WishlistItem) (model.WishlistItem, error)
+
+		// Update planned date and priority of wishlist item.
+		Update(userId, locationId string, item model.WishlistItem) error
+
+		// DeleteById wishlist item of user, it can be restored until purged or added again.
+		DeleteById(userId, locationId string) error
+
+		// Restore deleted wishlist item of user.
+		Restore(userId, locationId string) error
+	}
+
+	Review interface {
+		// GetByLocation approved reviews of location by id.
+		GetByLocation(id string, query model.ReviewQuery) (model.Reviews, error)
+
+		// GetByStatus reviews waiting for or past moderation.
+		GetByStatus(status string, query model.ReviewQuery) (model.Reviews, error)
+
+		// Moderate review of visit by id.
+		Moderate(visitId string, status string) error
+	}
+
+	Photo interface {
+		// GetByLocation photos of location by id.
+		GetByLocation(id string) (model.Photos, error)
+
+		// GetByVisit photos of visit by id.
+		GetByVisit(id string) (model.Photos, error)
+
+		// UploadForLocation stores a JPEG or PNG image of location by id together with its thumbnail.
+		UploadForLocation(id string, upload model.PhotoUpload) (model.Photo, error)
+
+		// UploadForVisit stores a JPEG or PNG image of visit by id together with its thumbnail.
+		UploadForVisit(id string, upload model.PhotoUpload) (model.Photo, error)
+
+		// Open the original image or the thumbnail of photo by id, the caller closes the file.
+		Open(id string, thumbnail bool) (model.Photo, storage.File, error)
+	}
+
+	Follow interface {
+		// Follow target by user, following a private user waits for the target's approval.
+		Follow(userId, targetId string) (model.Follow, error)
+
+		// Unfollow target by user.
+		Unfollow(userId, targetId string) error
+
+		// GetFollowers of user by id matching the filter.
+		GetFollowers(userId string, filter model.FollowerFilter) (model.Followers, error)
+
+		// Approve pending follow of user by follower.
+		Approve(userId, followerId string) error
+
+		// RemoveFollower of user, pending requests are declined the same way.
+		RemoveFollower(userId, followerId string) error
+
+		// GetFeed returns one page of visits of users followed by user by id, newest first.
+		GetFeed(userId string, query model.FeedQuery) (model.Feed, error)
+	}
+
+	Recommendation interface {
+		// GetForUser returns up to limit unvisited locations for user by id, each with the reason it was suggested.
+		GetForUser(id string, limit int) (model.Recommendations, error)
+	}
+
+	APIKey interface {
+		// GetAll API keys with their usage, revoked and expired keys included.
+		GetAll() (model.APIKeys, error)
+
+		// Create API key, the returned key is not stored and cannot be shown again.
+		Create(request model.APIKeyRequest) (model.NewAPIKey, error)
+
+		// Revoke API key by id.
+		Revoke(id string) error
+
+		// Authenticate checks the API key is valid, records the use and returns the caller.
+		Authenticate(key string) (model.Identity, error)
+	}
+
+	Audit interface {
+		// GetAll audit records matching the filter, one page newest first.
+		GetAll(filter model.AuditFilter) (model.AuditLog, error)
+	}
+
+	Purge interface {
+		// Purge hard deletes visits, trips and categories deleted longer than retention ago,
+		// with the stored files of the purged visit photos.
+		Purge(retention time.Duration) (model.Purged, error)
+	}
+
+	Export interface {
+		// Write streams the dataset to w in the requested format.
+		Write(w io.Writer, opts model.ExportOptions) error
+	}
+
+	Privacy interface {
+		// GetSubjectData every record linked to user by id.
+		GetSubjectData(id string) (model.SubjectData, error)
+
+		// WriteArchive writes the data to w as a zip archive with the photo files.
+		WriteArchive(w io.Writer, data model.SubjectData) error
+
+		// Erase anonymises user by id, keeping the visits as anonymous statistics, and records the erasure.
+		// An erasure with an id is recorded even if an error is returned for a photo file.
+		Erase(actor model.Actor, id string) (model.Erasure, error)
+
+		// GetErasures the erasure log, oldest first, with the result of checking its hash chain.
+		GetErasures() (model.ErasureLog, error)
+	}
+
+	Verification interface {
+		// Confirm sets the email of a verification token on its user as verified, each token works once.
+		Confirm(actor model.Actor, verificationToken string) error
+
+		// Resend mails a new verification for the email of user by id, unless it is verified already.
+		Resend(id string) error
+	}
+
+	Tenancy interface {
+		// Resolve the tenant of a request from its credential, its X-Tenant-ID header or else the default tenant.
+		// ErrUnknownTenant is returned for a malformed header, ErrForbidden when the header names another tenant
+		// than the credential.
+		Resolve(request model.TenantRequest) (uint32, error)
+
+		// GetById tenant, ErrUnknownTenant when there is none.
+		GetById(id uint32) (model.Tenant, error)
+
+		// GetAll tenants by id.
+		GetAll() ([]model.Tenant, error)
 	}
 )
@@ -1,8 +1,14 @@
 package service
 
 import (
+	"fmt"
 	"github.com/rinuccia/travels-api/internal/model"
 	"github.com/rinuccia/travels-api/internal/repository/postgres"
+	"github.com/rinuccia/travels-api/pkg/apperrors"
+	"github.com/rinuccia/travels-api/pkg/countries"
+	"github.com/rinuccia/travels-api/pkg/geo"
+	"io"
+	"strings"
 )
 
 type locationService struct {
@@ -15,8 +21,8 @@ func newLocationService(r postgres.LocationRepository) *locationService {
 	}
 }
 
-func (s *locationService) GetAll() (model.Locations, error) {
-	locations, err := s.repo.FindAll()
+func (s *locationService) GetAll(filter model.LocationFilter) (model.Locations, error) {
+	locations, err := s.repo.FindAll(normalizeFilter(filter))
 	if err != nil {
 		return locations, err
 	}
@@ -31,18 +37,127 @@ func (s *locationService) GetById(id string) (model.Location, error) {
 	return location, nil
 }
 
-func (s *locationService) GetRating(id string) (float32, error) {
-	rating, err := s.repo.FindRating(id)
+func (s *locationService) GetRating(id string, filter model.VisitFilter) (float32, error) {
+	rating, err := s.repo.FindRating(id, filter)
 	if err != nil {
 		return rating, err
 	}
 	return rating, err
 }
 
-func (s *locationService) Create(loc model.Location) (model.Location, error) {
-	location, err := s.repo.Insert(loc)
+func (s *locationService) GetRatingDistribution(id string, filter model.VisitFilter, bucket string) (model.RatingDistribution, error) {
+	counts, err := s.repo.FindMarkCounts(id, filter, bucket)
+	if err != nil {
+		return model.RatingDistribution{}, err
+	}
+	return distribution(counts, bucket != ""), nil
+}
+
+func (s *locationService) GetTop(query model.TopLocationsQuery) (model.TopLocations, error) {
+	if query.Country != "" {
+		country, ok := countries.Lookup(query.Country)
+		if !ok {
+			return model.TopLocations{}, apperrors.ErrUnknownCountry
+		}
+		query.Country = country.Alpha2
+	}
+	return s.repo.FindTop(query)
+}
+
+func (s *locationService) GetMostWanted(limit int) (model.WantedLocations, error) {
+	return s.repo.FindMostWanted(limit)
+}
+
+func (s *locationService) GetNearby(lat, lon, radiusKm float64, limit int) (model.NearbyLocations, error) {
+	box := geo.NewBoundingBox(lat, lon, radiusKm)
+	return s.repo.FindNearby(lat, lon, radiusKm, box, limit)
+}
+
+func (s *locationService) Create(actor model.Actor, loc model.Location) (model.Location, error) {
+	if err := resolveCountry(&loc); err != nil {
+		return loc, err
+	}
+	loc.Tags = normalizeTags(loc.Tags)
+	location, err := s.repo.Insert(actor, loc)
 	if err != nil {
 		return location, err
 	}
 	return location, err
 }
+
+// locationRejected is the import reason for a location the database rejects.
+const locationRejected = "category does not exist or a value is out of range"
+
+func (s *locationService) Import(actor model.Actor, r io.Reader, upsert bool) (model.ImportResult, error) {
+	return importCSV(r, exportColumns[model.ExportEntityLocations], func(fields map[string]string) (bool, error) {
+		id, err := parseUint32(fields, "location_id")
+		if err != nil {
+			return false, err
+		}
+		loc := model.Location{
+			LocationId: id,
+			Place:      fields["place"],
+			Country:    fields["country"],
+			Category:   fields["category"],
+			Tags:       normalizeTags(strings.FieldsFunc(fields["tags"], isTagSeparator)),
+		}
+		if loc.Lat, err = parseFloat(fields, "lat"); err != nil {
+			return false, err
+		}
+		if loc.Lon, err = parseFloat(fields, "lon"); err != nil {
+			return false, err
+		}
+		if err = validate.Struct(loc); err != nil {
+			return false, validationReason(err)
+		}
+		if err = resolveCountry(&loc); err != nil {
+			return false, fmt.Errorf("country: %w", err)
+		}
+
+		if upsert {
+			inserted, err := s.repo.Upsert(actor, loc)
+			if err != nil {
+				return false, storeReason(err, "location could not be saved", locationRejected)
+			}
+			return inserted, nil
+		}
+		if _, err = s.repo.Insert(actor, loc); err != nil {
+			return false, storeReason(err, "location_id already exists", locationRejected)
+		}
+		return true, nil
+	})
+}
+
+// resolveCountry replaces a country name or ISO code with the canonical name and alpha-2 code.
+func resolveCountry(loc *model.Location) error {
+	country, ok := countries.Lookup(loc.Country)
+	if !ok {
+		return apperrors.ErrUnknownCountry
+	}
+	loc.Country, loc.CountryCode = country.Name, country.Alpha2
+	return nil
+}
+
+// normalizeTags lowercases and trims tags and drops empty and duplicate ones, keeping the order.
+func normalizeTags(tags []string) []string {
+	if len(tags) == 0 {
+		return tags
+	}
+	seen := make(map[string]bool, len(tags))
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	return normalized
+}
+
+// normalizeFilter matches the tag filter against normalized tags.
+func normalizeFilter(filter model.LocationFilter) model.LocationFilter {
+	filter.Tag = strings.ToLower(strings.TrimSpace(filter.Tag))
+	return filter
+}
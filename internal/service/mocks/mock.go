@@ -5,10 +5,13 @@
 package mock_service
 
 import (
+	io "io"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	model "github.com/rinuccia/travels-api/internal/model"
+	storage "github.com/rinuccia/travels-api/pkg/storage"
 )
 
 // MockUser is a mock of User interface.
@@ -35,18 +38,18 @@ func (m *MockUser) EXPECT() *MockUserMockRecorder {
 }
 
 // Create mocks base method.
-func (m *MockUser) Create(user model.User) (model.User, error) {
+func (m *MockUser) Create(actor model.Actor, user model.User) (model.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", user)
+	ret := m.ctrl.Call(m, "Create", actor, user)
 	ret0, _ := ret[0].(model.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockUserMockRecorder) Create(user interface{}) *gomock.Call {
+func (mr *MockUserMockRecorder) Create(actor, user interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockUser)(nil).Create), user)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockUser)(nil).Create), actor, user)
 }
 
 // GetById mocks base method.
@@ -64,18 +67,144 @@ func (mr *MockUserMockRecorder) GetById(id interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockUser)(nil).GetById), id)
 }
 
+// GetSummary mocks base method.
+func (m *MockUser) GetSummary(id string) (model.UserSummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSummary", id)
+	ret0, _ := ret[0].(model.UserSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSummary indicates an expected call of GetSummary.
+func (mr *MockUserMockRecorder) GetSummary(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSummary", reflect.TypeOf((*MockUser)(nil).GetSummary), id)
+}
+
+// SetRole mocks base method.
+func (m *MockUser) SetRole(actor model.Actor, id, role string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetRole", actor, id, role)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetRole indicates an expected call of SetRole.
+func (mr *MockUserMockRecorder) SetRole(actor, id, role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRole", reflect.TypeOf((*MockUser)(nil).SetRole), actor, id, role)
+}
+
 // Update mocks base method.
-func (m *MockUser) Update(id string, user model.User) error {
+func (m *MockUser) Update(actor model.Actor, id string, user model.User) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", id, user)
+	ret := m.ctrl.Call(m, "Update", actor, id, user)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockUserMockRecorder) Update(id, user interface{}) *gomock.Call {
+func (mr *MockUserMockRecorder) Update(actor, id, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockUser)(nil).Update), actor, id, user)
+}
+
+// MockAuth is a mock of Auth interface.
+type MockAuth struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuthMockRecorder
+}
+
+// MockAuthMockRecorder is the mock recorder for MockAuth.
+type MockAuthMockRecorder struct {
+	mock *MockAuth
+}
+
+// NewMockAuth creates a new mock instance.
+func NewMockAuth(ctrl *gomock.Controller) *MockAuth {
+	mock := &MockAuth{ctrl: ctrl}
+	mock.recorder = &MockAuthMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuth) EXPECT() *MockAuthMockRecorder {
+	return m.recorder
+}
+
+// Authenticate mocks base method.
+func (m *MockAuth) Authenticate(accessToken string) (model.Identity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authenticate", accessToken)
+	ret0, _ := ret[0].(model.Identity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Authenticate indicates an expected call of Authenticate.
+func (mr *MockAuthMockRecorder) Authenticate(accessToken interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authenticate", reflect.TypeOf((*MockAuth)(nil).Authenticate), accessToken)
+}
+
+// Login mocks base method.
+func (m *MockAuth) Login(credentials model.Credentials) (model.TokenPair, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Login", credentials)
+	ret0, _ := ret[0].(model.TokenPair)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Login indicates an expected call of Login.
+func (mr *MockAuthMockRecorder) Login(credentials interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockAuth)(nil).Login), credentials)
+}
+
+// Logout mocks base method.
+func (m *MockAuth) Logout(refreshToken string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Logout", refreshToken)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Logout indicates an expected call of Logout.
+func (mr *MockAuthMockRecorder) Logout(refreshToken interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logout", reflect.TypeOf((*MockAuth)(nil).Logout), refreshToken)
+}
+
+// Refresh mocks base method.
+func (m *MockAuth) Refresh(refreshToken string) (model.TokenPair, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Refresh", refreshToken)
+	ret0, _ := ret[0].(model.TokenPair)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Refresh indicates an expected call of Refresh.
+func (mr *MockAuthMockRecorder) Refresh(refreshToken interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refresh", reflect.TypeOf((*MockAuth)(nil).Refresh), refreshToken)
+}
+
+// Register mocks base method.
+func (m *MockAuth) Register(actor model.Actor, registration model.Registration) (model.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Register", actor, registration)
+	ret0, _ := ret[0].(model.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Register indicates an expected call of Register.
+func (mr *MockAuthMockRecorder) Register(actor, registration interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockUser)(nil).Update), id, user)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockAuth)(nil).Register), actor, registration)
 }
 
 // MockLocation is a mock of Location interface.
@@ -102,33 +231,33 @@ func (m *MockLocation) EXPECT() *MockLocationMockRecorder {
 }
 
 // Create mocks base method.
-func (m *MockLocation) Create(loc model.Location) (model.Location, error) {
+func (m *MockLocation) Create(actor model.Actor, loc model.Location) (model.Location, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", loc)
+	ret := m.ctrl.Call(m, "Create", actor, loc)
 	ret0, _ := ret[0].(model.Location)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockLocationMockRecorder) Create(loc interface{}) *gomock.Call {
+func (mr *MockLocationMockRecorder) Create(actor, loc interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockLocation)(nil).Create), loc)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockLocation)(nil).Create), actor, loc)
 }
 
 // GetAll mocks base method.
-func (m *MockLocation) GetAll() (model.Locations, error) {
+func (m *MockLocation) GetAll(filter model.LocationFilter) (model.Locations, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAll")
+	ret := m.ctrl.Call(m, "GetAll", filter)
 	ret0, _ := ret[0].(model.Locations)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAll indicates an expected call of GetAll.
-func (mr *MockLocationMockRecorder) GetAll() *gomock.Call {
+func (mr *MockLocationMockRecorder) GetAll(filter interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockLocation)(nil).GetAll))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockLocation)(nil).GetAll), filter)
 }
 
 // GetById mocks base method.
@@ -146,19 +275,94 @@ func (mr *MockLocationMockRecorder) GetById(id interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockLocation)(nil).GetById), id)
 }
 
+// GetMostWanted mocks base method.
+func (m *MockLocation) GetMostWanted(limit int) (model.WantedLocations, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMostWanted", limit)
+	ret0, _ := ret[0].(model.WantedLocations)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMostWanted indicates an expected call of GetMostWanted.
+func (mr *MockLocationMockRecorder) GetMostWanted(limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMostWanted", reflect.TypeOf((*MockLocation)(nil).GetMostWanted), limit)
+}
+
+// GetNearby mocks base method.
+func (m *MockLocation) GetNearby(lat, lon, radiusKm float64, limit int) (model.NearbyLocations, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNearby", lat, lon, radiusKm, limit)
+	ret0, _ := ret[0].(model.NearbyLocations)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNearby indicates an expected call of GetNearby.
+func (mr *MockLocationMockRecorder) GetNearby(lat, lon, radiusKm, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNearby", reflect.TypeOf((*MockLocation)(nil).GetNearby), lat, lon, radiusKm, limit)
+}
+
 // GetRating mocks base method.
-func (m *MockLocation) GetRating(id string) (float32, error) {
+func (m *MockLocation) GetRating(id string, filter model.VisitFilter) (float32, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetRating", id)
+	ret := m.ctrl.Call(m, "GetRating", id, filter)
 	ret0, _ := ret[0].(float32)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetRating indicates an expected call of GetRating.
-func (mr *MockLocationMockRecorder) GetRating(id interface{}) *gomock.Call {
+func (mr *MockLocationMockRecorder) GetRating(id, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRating", reflect.TypeOf((*MockLocation)(nil).GetRating), id, filter)
+}
+
+// GetRatingDistribution mocks base method.
+func (m *MockLocation) GetRatingDistribution(id string, filter model.VisitFilter, bucket string) (model.RatingDistribution, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRatingDistribution", id, filter, bucket)
+	ret0, _ := ret[0].(model.RatingDistribution)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRatingDistribution indicates an expected call of GetRatingDistribution.
+func (mr *MockLocationMockRecorder) GetRatingDistribution(id, filter, bucket interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRatingDistribution", reflect.TypeOf((*MockLocation)(nil).GetRatingDistribution), id, filter, bucket)
+}
+
+// GetTop mocks base method.
+func (m *MockLocation) GetTop(query model.TopLocationsQuery) (model.TopLocations, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTop", query)
+	ret0, _ := ret[0].(model.TopLocations)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTop indicates an expected call of GetTop.
+func (mr *MockLocationMockRecorder) GetTop(query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTop", reflect.TypeOf((*MockLocation)(nil).GetTop), query)
+}
+
+// Import mocks base method.
+func (m *MockLocation) Import(actor model.Actor, r io.Reader, upsert bool) (model.ImportResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Import", actor, r, upsert)
+	ret0, _ := ret[0].(model.ImportResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Import indicates an expected call of Import.
+func (mr *MockLocationMockRecorder) Import(actor, r, upsert interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRating", reflect.TypeOf((*MockLocation)(nil).GetRating), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Import", reflect.TypeOf((*MockLocation)(nil).Import), actor, r, upsert)
 }
 
 // MockVisit is a mock of Visit interface.
@@ -185,45 +389,1122 @@ func (m *MockVisit) EXPECT() *MockVisitMockRecorder {
 }
 
 // Create mocks base method.
-func (m *MockVisit) Create(visit model.Visit) (model.Visit, error) {
+func (m *MockVisit) Create(actor model.Actor, visit model.Visit) (model.Visit, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", visit)
+	ret := m.ctrl.Call(m, "Create", actor, visit)
 	ret0, _ := ret[0].(model.Visit)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockVisitMockRecorder) Create(visit interface{}) *gomock.Call {
+func (mr *MockVisitMockRecorder) Create(actor, visit interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockVisit)(nil).Create), visit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockVisit)(nil).Create), actor, visit)
 }
 
 // DeleteById mocks base method.
-func (m *MockVisit) DeleteById(id string) error {
+func (m *MockVisit) DeleteById(actor model.Actor, id string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteById", id)
+	ret := m.ctrl.Call(m, "DeleteById", actor, id)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteById indicates an expected call of DeleteById.
-func (mr *MockVisitMockRecorder) DeleteById(id interface{}) *gomock.Call {
+func (mr *MockVisitMockRecorder) DeleteById(actor, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteById", reflect.TypeOf((*MockVisit)(nil).DeleteById), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteById", reflect.TypeOf((*MockVisit)(nil).DeleteById), actor, id)
 }
 
 // GetAll mocks base method.
-func (m *MockVisit) GetAll(id string) (model.UserVisits, error) {
+func (m *MockVisit) GetAll(id string, filter model.LocationFilter) (model.UserVisits, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAll", id)
+	ret := m.ctrl.Call(m, "GetAll", id, filter)
 	ret0, _ := ret[0].(model.UserVisits)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAll indicates an expected call of GetAll.
-func (mr *MockVisitMockRecorder) GetAll(id interface{}) *gomock.Call {
+func (mr *MockVisitMockRecorder) GetAll(id, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockVisit)(nil).GetAll), id, filter)
+}
+
+// Import mocks base method.
+func (m *MockVisit) Import(actor model.Actor, r io.Reader, upsert bool) (model.ImportResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Import", actor, r, upsert)
+	ret0, _ := ret[0].(model.ImportResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Import indicates an expected call of Import.
+func (mr *MockVisitMockRecorder) Import(actor, r, upsert interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Import", reflect.TypeOf((*MockVisit)(nil).Import), actor, r, upsert)
+}
+
+// Restore mocks base method.
+func (m *MockVisit) Restore(actor model.Actor, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", actor, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockVisitMockRecorder) Restore(actor, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockVisit)(nil).Restore), actor, id)
+}
+
+// MockCategory is a mock of Category interface.
+type MockCategory struct {
+	ctrl     *gomock.Controller
+	recorder *MockCategoryMockRecorder
+}
+
+// MockCategoryMockRecorder is the mock recorder for MockCategory.
+type MockCategoryMockRecorder struct {
+	mock *MockCategory
+}
+
+// NewMockCategory creates a new mock instance.
+func NewMockCategory(ctrl *gomock.Controller) *MockCategory {
+	mock := &MockCategory{ctrl: ctrl}
+	mock.recorder = &MockCategoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCategory) EXPECT() *MockCategoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockCategory) Create(category model.Category) (model.Category, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", category)
+	ret0, _ := ret[0].(model.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockCategoryMockRecorder) Create(category interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCategory)(nil).Create), category)
+}
+
+// DeleteById mocks base method.
+func (m *MockCategory) DeleteById(id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteById", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteById indicates an expected call of DeleteById.
+func (mr *MockCategoryMockRecorder) DeleteById(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteById", reflect.TypeOf((*MockCategory)(nil).DeleteById), id)
+}
+
+// GetAll mocks base method.
+func (m *MockCategory) GetAll() (model.Categories, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll")
+	ret0, _ := ret[0].(model.Categories)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockCategoryMockRecorder) GetAll() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockCategory)(nil).GetAll))
+}
+
+// GetById mocks base method.
+func (m *MockCategory) GetById(id string) (model.Category, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetById", id)
+	ret0, _ := ret[0].(model.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetById indicates an expected call of GetById.
+func (mr *MockCategoryMockRecorder) GetById(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockCategory)(nil).GetById), id)
+}
+
+// Restore mocks base method.
+func (m *MockCategory) Restore(id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockCategoryMockRecorder) Restore(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockCategory)(nil).Restore), id)
+}
+
+// Update mocks base method.
+func (m *MockCategory) Update(id string, category model.Category) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", id, category)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockCategoryMockRecorder) Update(id, category interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockCategory)(nil).Update), id, category)
+}
+
+// MockStats is a mock of Stats interface.
+type MockStats struct {
+	ctrl     *gomock.Controller
+	recorder *MockStatsMockRecorder
+}
+
+// MockStatsMockRecorder is the mock recorder for MockStats.
+type MockStatsMockRecorder struct {
+	mock *MockStats
+}
+
+// NewMockStats creates a new mock instance.
+func NewMockStats(ctrl *gomock.Controller) *MockStats {
+	mock := &MockStats{ctrl: ctrl}
+	mock.recorder = &MockStatsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStats) EXPECT() *MockStatsMockRecorder {
+	return m.recorder
+}
+
+// GetCountryStats mocks base method.
+func (m *MockStats) GetCountryStats(filter model.VisitFilter, sort model.StatsSort) (model.CountriesStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCountryStats", filter, sort)
+	ret0, _ := ret[0].(model.CountriesStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCountryStats indicates an expected call of GetCountryStats.
+func (mr *MockStatsMockRecorder) GetCountryStats(filter, sort interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCountryStats", reflect.TypeOf((*MockStats)(nil).GetCountryStats), filter, sort)
+}
+
+// MockTrip is a mock of Trip interface.
+type MockTrip struct {
+	ctrl     *gomock.Controller
+	recorder *MockTripMockRecorder
+}
+
+// MockTripMockRecorder is the mock recorder for MockTrip.
+type MockTripMockRecorder struct {
+	mock *MockTrip
+}
+
+// NewMockTrip creates a new mock instance.
+func NewMockTrip(ctrl *gomock.Controller) *MockTrip {
+	mock := &MockTrip{ctrl: ctrl}
+	mock.recorder = &MockTripMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTrip) EXPECT() *MockTripMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockTrip) Create(trip model.TripRecord) (model.TripRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", trip)
+	ret0, _ := ret[0].(model.TripRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockTripMockRecorder) Create(trip interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTrip)(nil).Create), trip)
+}
+
+// DeleteById mocks base method.
+func (m *MockTrip) DeleteById(id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteById", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteById indicates an expected call of DeleteById.
+func (mr *MockTripMockRecorder) DeleteById(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteById", reflect.TypeOf((*MockTrip)(nil).DeleteById), id)
+}
+
+// GetAll mocks base method.
+func (m *MockTrip) GetAll(userId string, gapDays int) (model.Trips, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", userId, gapDays)
+	ret0, _ := ret[0].(model.Trips)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockTripMockRecorder) GetAll(userId, gapDays interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockTrip)(nil).GetAll), userId, gapDays)
+}
+
+// Restore mocks base method.
+func (m *MockTrip) Restore(id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockTripMockRecorder) Restore(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockTrip)(nil).Restore), id)
+}
+
+// Update mocks base method.
+func (m *MockTrip) Update(id string, trip model.TripUpdate) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", id, trip)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockTripMockRecorder) Update(id, trip interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockTrip)(nil).Update), id, trip)
+}
+
+// MockWishlist is a mock of Wishlist interface.
+type MockWishlist struct {
+	ctrl     *gomock.Controller
+	recorder *MockWishlistMockRecorder
+}
+
+// MockWishlistMockRecorder is the mock recorder for MockWishlist.
+type MockWishlistMockRecorder struct {
+	mock *MockWishlist
+}
+
+// NewMockWishlist creates a new mock instance.
+func NewMockWishlist(ctrl *gomock.Controller) *MockWishlist {
+	mock := &MockWishlist{ctrl: ctrl}
+	mock.recorder = &MockWishlistMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWishlist) EXPECT() *MockWishlistMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockWishlist) Create(userId string, item model.WishlistItem) (model.WishlistItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", userId, item)
+	ret0, _ := ret[0].(model.WishlistItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockWishlistMockRecorder) Create(userId, item interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockWishlist)(nil).Create), userId, item)
+}
+
+// DeleteById mocks base method.
+func (m *MockWishlist) DeleteById(userId, locationId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteById", userId, locationId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteById indicates an expected call of DeleteById.
+func (mr *MockWishlistMockRecorder) DeleteById(userId, locationId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteById", reflect.TypeOf((*MockWishlist)(nil).DeleteById), userId, locationId)
+}
+
+// GetAll mocks base method.
+func (m *MockWishlist) GetAll(userId string, filter model.WishlistFilter) (model.Wishlist, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", userId, filter)
+	ret0, _ := ret[0].(model.Wishlist)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockWishlistMockRecorder) GetAll(userId, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockWishlist)(nil).GetAll), userId, filter)
+}
+
+// Restore mocks base method.
+func (m *MockWishlist) Restore(userId, locationId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", userId, locationId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockWishlistMockRecorder) Restore(userId, locationId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockWishlist)(nil).Restore), userId, locationId)
+}
+
+// Update mocks base method.
+func (m *MockWishlist) Update(userId, locationId string, item model.WishlistItem) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", userId, locationId, item)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockWishlistMockRecorder) Update(userId, locationId, item interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockWishlist)(nil).Update), userId, locationId, item)
+}
+
+// MockReview is a mock of Review interface.
+type MockReview struct {
+	ctrl     *gomock.Controller
+	recorder *MockReviewMockRecorder
+}
+
+// MockReviewMockRecorder is the mock recorder for MockReview.
+type MockReviewMockRecorder struct {
+	mock *MockReview
+}
+
+// NewMockReview creates a new mock instance.
+func NewMockReview(ctrl *gomock.Controller) *MockReview {
+	mock := &MockReview{ctrl: ctrl}
+	mock.recorder = &MockReviewMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReview) EXPECT() *MockReviewMockRecorder {
+	return m.recorder
+}
+
+// GetByLocation mocks base method.
+func (m *MockReview) GetByLocation(id string, query model.ReviewQuery) (model.Reviews, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByLocation", id, query)
+	ret0, _ := ret[0].(model.Reviews)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByLocation indicates an expected call of GetByLocation.
+func (mr *MockReviewMockRecorder) GetByLocation(id, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByLocation", reflect.TypeOf((*MockReview)(nil).GetByLocation), id, query)
+}
+
+// GetByStatus mocks base method.
+func (m *MockReview) GetByStatus(status string, query model.ReviewQuery) (model.Reviews, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByStatus", status, query)
+	ret0, _ := ret[0].(model.Reviews)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByStatus indicates an expected call of GetByStatus.
+func (mr *MockReviewMockRecorder) GetByStatus(status, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByStatus", reflect.TypeOf((*MockReview)(nil).GetByStatus), status, query)
+}
+
+// Moderate mocks base method.
+func (m *MockReview) Moderate(visitId, status string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Moderate", visitId, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Moderate indicates an expected call of Moderate.
+func (mr *MockReviewMockRecorder) Moderate(visitId, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Moderate", reflect.TypeOf((*MockReview)(nil).Moderate), visitId, status)
+}
+
+// MockPhoto is a mock of Photo interface.
+type MockPhoto struct {
+	ctrl     *gomock.Controller
+	recorder *MockPhotoMockRecorder
+}
+
+// MockPhotoMockRecorder is the mock recorder for MockPhoto.
+type MockPhotoMockRecorder struct {
+	mock *MockPhoto
+}
+
+// NewMockPhoto creates a new mock instance.
+func NewMockPhoto(ctrl *gomock.Controller) *MockPhoto {
+	mock := &MockPhoto{ctrl: ctrl}
+	mock.recorder = &MockPhotoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPhoto) EXPECT() *MockPhotoMockRecorder {
+	return m.recorder
+}
+
+// GetByLocation mocks base method.
+func (m *MockPhoto) GetByLocation(id string) (model.Photos, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByLocation", id)
+	ret0, _ := ret[0].(model.Photos)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByLocation indicates an expected call of GetByLocation.
+func (mr *MockPhotoMockRecorder) GetByLocation(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByLocation", reflect.TypeOf((*MockPhoto)(nil).GetByLocation), id)
+}
+
+// GetByVisit mocks base method.
+func (m *MockPhoto) GetByVisit(id string) (model.Photos, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByVisit", id)
+	ret0, _ := ret[0].(model.Photos)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByVisit indicates an expected call of GetByVisit.
+func (mr *MockPhotoMockRecorder) GetByVisit(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByVisit", reflect.TypeOf((*MockPhoto)(nil).GetByVisit), id)
+}
+
+// Open mocks base method.
+func (m *MockPhoto) Open(id string, thumbnail bool) (model.Photo, storage.File, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Open", id, thumbnail)
+	ret0, _ := ret[0].(model.Photo)
+	ret1, _ := ret[1].(storage.File)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Open indicates an expected call of Open.
+func (mr *MockPhotoMockRecorder) Open(id, thumbnail interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Open", reflect.TypeOf((*MockPhoto)(nil).Open), id, thumbnail)
+}
+
+// UploadForLocation mocks base method.
+func (m *MockPhoto) UploadForLocation(id string, upload model.PhotoUpload) (model.Photo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UploadForLocation", id, upload)
+	ret0, _ := ret[0].(model.Photo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UploadForLocation indicates an expected call of UploadForLocation.
+func (mr *MockPhotoMockRecorder) UploadForLocation(id, upload interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadForLocation", reflect.TypeOf((*MockPhoto)(nil).UploadForLocation), id, upload)
+}
+
+// UploadForVisit mocks base method.
+func (m *MockPhoto) UploadForVisit(id string, upload model.PhotoUpload) (model.Photo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UploadForVisit", id, upload)
+	ret0, _ := ret[0].(model.Photo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UploadForVisit indicates an expected call of UploadForVisit.
+func (mr *MockPhotoMockRecorder) UploadForVisit(id, upload interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadForVisit", reflect.TypeOf((*MockPhoto)(nil).UploadForVisit), id, upload)
+}
+
+// MockFollow is a mock of Follow interface.
+type MockFollow struct {
+	ctrl     *gomock.Controller
+	recorder *MockFollowMockRecorder
+}
+
+// MockFollowMockRecorder is the mock recorder for MockFollow.
+type MockFollowMockRecorder struct {
+	mock *MockFollow
+}
+
+// NewMockFollow creates a new mock instance.
+func NewMockFollow(ctrl *gomock.Controller) *MockFollow {
+	mock := &MockFollow{ctrl: ctrl}
+	mock.recorder = &MockFollowMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFollow) EXPECT() *MockFollowMockRecorder {
+	return m.recorder
+}
+
+// Approve mocks base method.
+func (m *MockFollow) Approve(userId, followerId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Approve", userId, followerId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Approve indicates an expected call of Approve.
+func (mr *MockFollowMockRecorder) Approve(userId, followerId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Approve", reflect.TypeOf((*MockFollow)(nil).Approve), userId, followerId)
+}
+
+// Follow mocks base method.
+func (m *MockFollow) Follow(userId, targetId string) (model.Follow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Follow", userId, targetId)
+	ret0, _ := ret[0].(model.Follow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Follow indicates an expected call of Follow.
+func (mr *MockFollowMockRecorder) Follow(userId, targetId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Follow", reflect.TypeOf((*MockFollow)(nil).Follow), userId, targetId)
+}
+
+// GetFeed mocks base method.
+func (m *MockFollow) GetFeed(userId string, query model.FeedQuery) (model.Feed, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFeed", userId, query)
+	ret0, _ := ret[0].(model.Feed)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFeed indicates an expected call of GetFeed.
+func (mr *MockFollowMockRecorder) GetFeed(userId, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFeed", reflect.TypeOf((*MockFollow)(nil).GetFeed), userId, query)
+}
+
+// GetFollowers mocks base method.
+func (m *MockFollow) GetFollowers(userId string, filter model.FollowerFilter) (model.Followers, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFollowers", userId, filter)
+	ret0, _ := ret[0].(model.Followers)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFollowers indicates an expected call of GetFollowers.
+func (mr *MockFollowMockRecorder) GetFollowers(userId, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFollowers", reflect.TypeOf((*MockFollow)(nil).GetFollowers), userId, filter)
+}
+
+// RemoveFollower mocks base method.
+func (m *MockFollow) RemoveFollower(userId, followerId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveFollower", userId, followerId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveFollower indicates an expected call of RemoveFollower.
+func (mr *MockFollowMockRecorder) RemoveFollower(userId, followerId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveFollower", reflect.TypeOf((*MockFollow)(nil).RemoveFollower), userId, followerId)
+}
+
+// Unfollow mocks base method.
+func (m *MockFollow) Unfollow(userId, targetId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unfollow", userId, targetId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unfollow indicates an expected call of Unfollow.
+func (mr *MockFollowMockRecorder) Unfollow(userId, targetId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unfollow", reflect.TypeOf((*MockFollow)(nil).Unfollow), userId, targetId)
+}
+
+// MockRecommendation is a mock of Recommendation interface.
+type MockRecommendation struct {
+	ctrl     *gomock.Controller
+	recorder *MockRecommendationMockRecorder
+}
+
+// MockRecommendationMockRecorder is the mock recorder for MockRecommendation.
+type MockRecommendationMockRecorder struct {
+	mock *MockRecommendation
+}
+
+// NewMockRecommendation creates a new mock instance.
+func NewMockRecommendation(ctrl *gomock.Controller) *MockRecommendation {
+	mock := &MockRecommendation{ctrl: ctrl}
+	mock.recorder = &MockRecommendationMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRecommendation) EXPECT() *MockRecommendationMockRecorder {
+	return m.recorder
+}
+
+// GetForUser mocks base method.
+func (m *MockRecommendation) GetForUser(id string, limit int) (model.Recommendations, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetForUser", id, limit)
+	ret0, _ := ret[0].(model.Recommendations)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetForUser indicates an expected call of GetForUser.
+func (mr *MockRecommendationMockRecorder) GetForUser(id, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetForUser", reflect.TypeOf((*MockRecommendation)(nil).GetForUser), id, limit)
+}
+
+// MockAPIKey is a mock of APIKey interface.
+type MockAPIKey struct {
+	ctrl     *gomock.Controller
+	recorder *MockAPIKeyMockRecorder
+}
+
+// MockAPIKeyMockRecorder is the mock recorder for MockAPIKey.
+type MockAPIKeyMockRecorder struct {
+	mock *MockAPIKey
+}
+
+// NewMockAPIKey creates a new mock instance.
+func NewMockAPIKey(ctrl *gomock.Controller) *MockAPIKey {
+	mock := &MockAPIKey{ctrl: ctrl}
+	mock.recorder = &MockAPIKeyMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAPIKey) EXPECT() *MockAPIKeyMockRecorder {
+	return m.recorder
+}
+
+// Authenticate mocks base method.
+func (m *MockAPIKey) Authenticate(key string) (model.Identity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authenticate", key)
+	ret0, _ := ret[0].(model.Identity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Authenticate indicates an expected call of Authenticate.
+func (mr *MockAPIKeyMockRecorder) Authenticate(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authenticate", reflect.TypeOf((*MockAPIKey)(nil).Authenticate), key)
+}
+
+// Create mocks base method.
+func (m *MockAPIKey) Create(request model.APIKeyRequest) (model.NewAPIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", request)
+	ret0, _ := ret[0].(model.NewAPIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAPIKeyMockRecorder) Create(request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAPIKey)(nil).Create), request)
+}
+
+// GetAll mocks base method.
+func (m *MockAPIKey) GetAll() (model.APIKeys, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll")
+	ret0, _ := ret[0].(model.APIKeys)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockAPIKeyMockRecorder) GetAll() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockAPIKey)(nil).GetAll))
+}
+
+// Revoke mocks base method.
+func (m *MockAPIKey) Revoke(id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockAPIKeyMockRecorder) Revoke(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockAPIKey)(nil).Revoke), id)
+}
+
+// MockAudit is a mock of Audit interface.
+type MockAudit struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuditMockRecorder
+}
+
+// MockAuditMockRecorder is the mock recorder for MockAudit.
+type MockAuditMockRecorder struct {
+	mock *MockAudit
+}
+
+// NewMockAudit creates a new mock instance.
+func NewMockAudit(ctrl *gomock.Controller) *MockAudit {
+	mock := &MockAudit{ctrl: ctrl}
+	mock.recorder = &MockAuditMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAudit) EXPECT() *MockAuditMockRecorder {
+	return m.recorder
+}
+
+// GetAll mocks base method.
+func (m *MockAudit) GetAll(filter model.AuditFilter) (model.AuditLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", filter)
+	ret0, _ := ret[0].(model.AuditLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockAuditMockRecorder) GetAll(filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockAudit)(nil).GetAll), filter)
+}
+
+// MockPurge is a mock of Purge interface.
+type MockPurge struct {
+	ctrl     *gomock.Controller
+	recorder *MockPurgeMockRecorder
+}
+
+// MockPurgeMockRecorder is the mock recorder for MockPurge.
+type MockPurgeMockRecorder struct {
+	mock *MockPurge
+}
+
+// NewMockPurge creates a new mock instance.
+func NewMockPurge(ctrl *gomock.Controller) *MockPurge {
+	mock := &MockPurge{ctrl: ctrl}
+	mock.recorder = &MockPurgeMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPurge) EXPECT() *MockPurgeMockRecorder {
+	return m.recorder
+}
+
+// Purge mocks base method.
+func (m *MockPurge) Purge(retention time.Duration) (model.Purged, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", retention)
+	ret0, _ := ret[0].(model.Purged)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockPurgeMockRecorder) Purge(retention interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockPurge)(nil).Purge), retention)
+}
+
+// MockExport is a mock of Export interface.
+type MockExport struct {
+	ctrl     *gomock.Controller
+	recorder *MockExportMockRecorder
+}
+
+// MockExportMockRecorder is the mock recorder for MockExport.
+type MockExportMockRecorder struct {
+	mock *MockExport
+}
+
+// NewMockExport creates a new mock instance.
+func NewMockExport(ctrl *gomock.Controller) *MockExport {
+	mock := &MockExport{ctrl: ctrl}
+	mock.recorder = &MockExportMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExport) EXPECT() *MockExportMockRecorder {
+	return m.recorder
+}
+
+// Write mocks base method.
+func (m *MockExport) Write(w io.Writer, opts model.ExportOptions) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Write", w, opts)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Write indicates an expected call of Write.
+func (mr *MockExportMockRecorder) Write(w, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Write", reflect.TypeOf((*MockExport)(nil).Write), w, opts)
+}
+
+// MockPrivacy is a mock of Privacy interface.
+type MockPrivacy struct {
+	ctrl     *gomock.Controller
+	recorder *MockPrivacyMockRecorder
+}
+
+// MockPrivacyMockRecorder is the mock recorder for MockPrivacy.
+type MockPrivacyMockRecorder struct {
+	mock *MockPrivacy
+}
+
+// NewMockPrivacy creates a new mock instance.
+func NewMockPrivacy(ctrl *gomock.Controller) *MockPrivacy {
+	mock := &MockPrivacy{ctrl: ctrl}
+	mock.recorder = &MockPrivacyMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPrivacy) EXPECT() *MockPrivacyMockRecorder {
+	return m.recorder
+}
+
+// Erase mocks base method.
+func (m *MockPrivacy) Erase(actor model.Actor, id string) (model.Erasure, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Erase", actor, id)
+	ret0, _ := ret[0].(model.Erasure)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Erase indicates an expected call of Erase.
+func (mr *MockPrivacyMockRecorder) Erase(actor, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Erase", reflect.TypeOf((*MockPrivacy)(nil).Erase), actor, id)
+}
+
+// GetErasures mocks base method.
+func (m *MockPrivacy) GetErasures() (model.ErasureLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetErasures")
+	ret0, _ := ret[0].(model.ErasureLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetErasures indicates an expected call of GetErasures.
+func (mr *MockPrivacyMockRecorder) GetErasures() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetErasures", reflect.TypeOf((*MockPrivacy)(nil).GetErasures))
+}
+
+// GetSubjectData mocks base method.
+func (m *MockPrivacy) GetSubjectData(id string) (model.SubjectData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubjectData", id)
+	ret0, _ := ret[0].(model.SubjectData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubjectData indicates an expected call of GetSubjectData.
+func (mr *MockPrivacyMockRecorder) GetSubjectData(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubjectData", reflect.TypeOf((*MockPrivacy)(nil).GetSubjectData), id)
+}
+
+// WriteArchive mocks base method.
+func (m *MockPrivacy) WriteArchive(w io.Writer, data model.SubjectData) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WriteArchive", w, data)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WriteArchive indicates an expected call of WriteArchive.
+func (mr *MockPrivacyMockRecorder) WriteArchive(w, data interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteArchive", reflect.TypeOf((*MockPrivacy)(nil).WriteArchive), w, data)
+}
+
+// MockVerification is a mock of Verification interface.
+type MockVerification struct {
+	ctrl     *gomock.Controller
+	recorder *MockVerificationMockRecorder
+}
+
+// MockVerificationMockRecorder is the mock recorder for MockVerification.
+type MockVerificationMockRecorder struct {
+	mock *MockVerification
+}
+
+// NewMockVerification creates a new mock instance.
+func NewMockVerification(ctrl *gomock.Controller) *MockVerification {
+	mock := &MockVerification{ctrl: ctrl}
+	mock.recorder = &MockVerificationMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockVerification) EXPECT() *MockVerificationMockRecorder {
+	return m.recorder
+}
+
+// Confirm mocks base method.
+func (m *MockVerification) Confirm(actor model.Actor, verificationToken string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Confirm", actor, verificationToken)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Confirm indicates an expected call of Confirm.
+func (mr *MockVerificationMockRecorder) Confirm(actor, verificationToken interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Confirm", reflect.TypeOf((*MockVerification)(nil).Confirm), actor, verificationToken)
+}
+
+// Resend mocks base method.
+func (m *MockVerification) Resend(id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Resend", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Resend indicates an expected call of Resend.
+func (mr *MockVerificationMockRecorder) Resend(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resend", reflect.TypeOf((*MockVerification)(nil).Resend), id)
+}
+
+// MockTenancy is a mock of Tenancy interface.
+type MockTenancy struct {
+	ctrl     *gomock.Controller
+	recorder *MockTenancyMockRecorder
+}
+
+// MockTenancyMockRecorder is the mock recorder for MockTenancy.
+type MockTenancyMockRecorder struct {
+	mock *MockTenancy
+}
+
+// NewMockTenancy creates a new mock instance.
+func NewMockTenancy(ctrl *gomock.Controller) *MockTenancy {
+	mock := &MockTenancy{ctrl: ctrl}
+	mock.recorder = &MockTenancyMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTenancy) EXPECT() *MockTenancyMockRecorder {
+	return m.recorder
+}
+
+// GetAll mocks base method.
+func (m *MockTenancy) GetAll() ([]model.Tenant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll")
+	ret0, _ := ret[0].([]model.Tenant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockTenancyMockRecorder) GetAll() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockTenancy)(nil).GetAll))
+}
+
+// GetById mocks base method.
+func (m *MockTenancy) GetById(id uint32) (model.Tenant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetById", id)
+	ret0, _ := ret[0].(model.Tenant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetById indicates an expected call of GetById.
+func (mr *MockTenancyMockRecorder) GetById(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockTenancy)(nil).GetById), id)
+}
+
+// Resolve mocks base method.
+func (m *MockTenancy) Resolve(request model.TenantRequest) (uint32, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Resolve", request)
+	ret0, _ := ret[0].(uint32)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Resolve indicates an expected call of Resolve.
+func (mr *MockTenancyMockRecorder) Resolve(request interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockVisit)(nil).GetAll), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resolve", reflect.TypeOf((*MockTenancy)(nil).Resolve), request)
 }
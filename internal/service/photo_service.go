@@ -0,0 +1,168 @@
+package service
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/internal/repository/postgres"
+	"github.com/rinuccia/travels-api/pkg/apperrors"
+	"github.com/rinuccia/travels-api/pkg/imaging"
+	"github.com/rinuccia/travels-api/pkg/storage"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+)
+
+// thumbnailSide is the longest side of generated thumbnails in pixels
+const thumbnailSide = 320
+
+// maxPhotoPixels caps the decoded size of photos, a few kilobytes of compressed data may declare gigapixels
+const maxPhotoPixels = 50_000_000
+
+// photoExtensions lists accepted image types, the type is sniffed from the content and never taken from the client.
+var photoExtensions = map[string]string{
+	"image/jpeg": "jpg",
+	"image/png":  "png",
+}
+
+type photoService struct {
+	repo  postgres.PhotoRepository
+	store storage.Storage
+}
+
+func newPhotoService(r postgres.PhotoRepository, store storage.Storage) *photoService {
+	return &photoService{
+		repo:  r,
+		store: store,
+	}
+}
+
+func (s *photoService) GetByLocation(id string) (model.Photos, error) {
+	return s.repo.FindByLocation(id)
+}
+
+func (s *photoService) GetByVisit(id string) (model.Photos, error) {
+	return s.repo.FindByVisit(id)
+}
+
+func (s *photoService) UploadForLocation(id string, upload model.PhotoUpload) (model.Photo, error) {
+	return s.upload(upload, func(photo model.Photo) (model.Photo, error) {
+		return s.repo.InsertForLocation(id, photo)
+	})
+}
+
+func (s *photoService) UploadForVisit(id string, upload model.PhotoUpload) (model.Photo, error) {
+	return s.upload(upload, func(photo model.Photo) (model.Photo, error) {
+		return s.repo.InsertForVisit(id, photo)
+	})
+}
+
+func (s *photoService) Open(id string, thumbnail bool) (model.Photo, storage.File, error) {
+	photo, err := s.repo.FindById(id)
+	if err != nil {
+		return photo, nil, err
+	}
+	original, thumb := photoKeys(photo)
+	key := original
+	if thumbnail {
+		key = thumb
+	}
+	file, err := s.store.Get(key)
+	if err == storage.ErrNotFound {
+		return photo, nil, apperrors.ErrRecordNotFound
+	}
+	return photo, file, err
+}
+
+// upload checks the image type and declared dimensions before decoding it, stores it with its thumbnail
+// and records the metadata with insert. Stored files are removed again when the metadata cannot be saved.
+func (s *photoService) upload(upload model.PhotoUpload, insert func(model.Photo) (model.Photo, error)) (model.Photo, error) {
+	contentType := http.DetectContentType(upload.Data)
+	if _, ok := photoExtensions[contentType]; !ok {
+		return model.Photo{}, apperrors.ErrUnsupportedImage
+	}
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(upload.Data))
+	if err != nil {
+		return model.Photo{}, apperrors.ErrInvalidImage
+	}
+	if cfg.Width*cfg.Height > maxPhotoPixels {
+		return model.Photo{}, apperrors.ErrImageTooLarge
+	}
+	img, _, err := image.Decode(bytes.NewReader(upload.Data))
+	if err != nil {
+		return model.Photo{}, apperrors.ErrInvalidImage
+	}
+
+	data := upload.Data
+	if upload.StripLocation {
+		data = imaging.StripEXIF(data)
+	}
+	var thumb bytes.Buffer
+	if contentType == "image/png" {
+		err = png.Encode(&thumb, imaging.Thumbnail(img, thumbnailSide))
+	} else {
+		err = jpeg.Encode(&thumb, imaging.Thumbnail(img, thumbnailSide), nil)
+	}
+	if err != nil {
+		return model.Photo{}, err
+	}
+
+	key, err := newStorageKey()
+	if err != nil {
+		return model.Photo{}, err
+	}
+	photo := model.Photo{
+		StorageKey:  key,
+		ContentType: contentType,
+		Size:        int64(len(data)),
+		Width:       img.Bounds().Dx(),
+		Height:      img.Bounds().Dy(),
+	}
+	originalKey, thumbKey := photoKeys(photo)
+	if err = s.store.Put(originalKey, bytes.NewReader(data)); err != nil {
+		return model.Photo{}, err
+	}
+	if err = s.store.Put(thumbKey, &thumb); err != nil {
+		_ = s.store.Delete(originalKey)
+		return model.Photo{}, err
+	}
+
+	photo, err = insert(photo)
+	if err != nil {
+		_ = s.store.Delete(originalKey)
+		_ = s.store.Delete(thumbKey)
+	}
+	return photo, err
+}
+
+// photoKeys returns storage keys of the original image and its thumbnail.
+func photoKeys(photo model.Photo) (string, string) {
+	ext := photoExtensions[photo.ContentType]
+	return "photos/" + photo.StorageKey + "." + ext, "photos/" + photo.StorageKey + "_thumb." + ext
+}
+
+// deletePhotoFiles removes the stored files of photos whose rows are gone, a file that fails to delete is only
+// orphaned so the remaining files are still deleted and the first error is returned.
+func deletePhotoFiles(store storage.Storage, photos []model.Photo) error {
+	var firstErr error
+	for _, photo := range photos {
+		original, thumb := photoKeys(photo)
+		for _, key := range []string{original, thumb} {
+			if err := store.Delete(key); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	return firstErr
+}
+
+// newStorageKey returns a random key so files are stored before the photo id is known.
+func newStorageKey() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
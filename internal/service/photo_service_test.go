@@ -0,0 +1,67 @@
+package service
+
+import (
+	"bytes"
+	"encoding/binary"
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/pkg/apperrors"
+	"github.com/stretchr/testify/assert"
+	"hash/crc32"
+	"image"
+	"image/png"
+	"testing"
+)
+
+// pngDeclaring returns a 1x1 PNG whose header claims width x height pixels, with a valid header checksum.
+func pngDeclaring(t *testing.T, width, height uint32) []byte {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, 1, 1))); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+	// signature (8), IHDR length (4) and type (4), then width and height, the crc covers type and data
+	binary.BigEndian.PutUint32(data[16:], width)
+	binary.BigEndian.PutUint32(data[20:], height)
+	binary.BigEndian.PutUint32(data[29:], crc32.ChecksumIEEE(data[12:29]))
+	return data
+}
+
+func TestPhotoService_upload(t *testing.T) {
+	testTable := []struct {
+		name    string
+		data    []byte
+		wantErr error
+	}{
+		{
+			name:    "Declared Dimensions Too Large",
+			data:    pngDeclaring(t, 30000, 30000),
+			wantErr: apperrors.ErrImageTooLarge,
+		},
+		{
+			name:    "Corrupt Image",
+			data:    append([]byte("\x89PNG\r\n\x1a\n"), 0, 0),
+			wantErr: apperrors.ErrInvalidImage,
+		},
+		{
+			name:    "Unsupported Type",
+			data:    []byte("GIF89a"),
+			wantErr: apperrors.ErrUnsupportedImage,
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newPhotoService(nil, nil)
+			inserted := false
+
+			_, err := s.upload(model.PhotoUpload{Data: tt.data}, func(photo model.Photo) (model.Photo, error) {
+				inserted = true
+				return photo, nil
+			})
+
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.False(t, inserted)
+		})
+	}
+	// the oversized image is rejected from a header of a few bytes, not after allocating its pixels
+	assert.Less(t, len(pngDeclaring(t, 30000, 30000)), 100)
+}
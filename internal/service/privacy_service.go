@@ -0,0 +1,95 @@
+package service
+
+import (
+	"archive/zip"
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/internal/repository/postgres"
+	"github.com/rinuccia/travels-api/pkg/storage"
+	"io"
+	"strconv"
+)
+
+type privacyService struct {
+	repo  postgres.PrivacyRepository
+	store storage.Storage
+}
+
+func newPrivacyService(r postgres.PrivacyRepository, store storage.Storage) *privacyService {
+	return &privacyService{
+		repo:  r,
+		store: store,
+	}
+}
+
+func (s *privacyService) GetSubjectData(id string) (model.SubjectData, error) {
+	return s.repo.FindSubjectData(id)
+}
+
+// WriteArchive writes a json file per section and the original photo files named by photo id,
+// a photo whose file is missing is left out.
+func (s *privacyService) WriteArchive(w io.Writer, data model.SubjectData) error {
+	archive := zip.NewWriter(w)
+	for _, section := range data.Sections {
+		file, err := archive.Create(section.Name + ".json")
+		if err != nil {
+			return err
+		}
+		if _, err = file.Write(section.Data); err != nil {
+			return err
+		}
+	}
+	for _, photo := range data.Photos {
+		if err := s.addPhoto(archive, photo); err != nil {
+			return err
+		}
+	}
+	return archive.Close()
+}
+
+// Erase removes the photo files after the rows are gone, the erasure is recorded even when removing a file fails.
+func (s *privacyService) Erase(actor model.Actor, id string) (model.Erasure, error) {
+	erasure, photos, err := s.repo.Erase(actor, id)
+	if err != nil {
+		return erasure, err
+	}
+	return erasure, deletePhotoFiles(s.store, photos)
+}
+
+// GetErasures checks every entry against its hash and the hash of the entry before it.
+func (s *privacyService) GetErasures() (model.ErasureLog, error) {
+	list, err := s.repo.FindErasures()
+	if err != nil {
+		return model.ErasureLog{}, err
+	}
+	erasures := model.ErasureLog{List: list, Valid: true}
+	prevHash := model.ErasureGenesisHash
+	for _, erasure := range list {
+		if erasure.PrevHash != prevHash || erasure.ChainHash() != erasure.Hash {
+			erasures.Valid = false
+			erasures.BrokenAt = erasure.ErasureId
+			break
+		}
+		prevHash = erasure.Hash
+	}
+	return erasures, nil
+}
+
+func (s *privacyService) addPhoto(archive *zip.Writer, photo model.Photo) error {
+	original, _ := photoKeys(photo)
+	src, err := s.store.Get(original)
+	if err == storage.ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	name := "photos/" + strconv.FormatUint(uint64(photo.PhotoId), 10) + "." + photoExtensions[photo.ContentType]
+	file, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(file, src)
+	return err
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/internal/repository/postgres"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+// erasureLogRepo returns its erasure log.
+type erasureLogRepo struct {
+	postgres.PrivacyRepository
+	list []model.Erasure
+}
+
+func (r erasureLogRepo) FindErasures() ([]model.Erasure, error) {
+	return r.list, nil
+}
+
+// erasureChain links erasures of the users by their hashes, oldest first.
+func erasureChain(userIds ...uint32) []model.Erasure {
+	erasedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	chain := make([]model.Erasure, 0, len(userIds))
+	prevHash := model.ErasureGenesisHash
+	for i, userId := range userIds {
+		erasure := model.Erasure{ErasureId: uint64(i + 1), UserId: userId, Actor: "user:1", RequestId: "req-1",
+			ErasedAt: erasedAt.Add(time.Duration(i) * time.Hour), PrevHash: prevHash}
+		erasure.Hash = erasure.ChainHash()
+		chain = append(chain, erasure)
+		prevHash = erasure.Hash
+	}
+	return chain
+}
+
+func TestPrivacyService_GetErasures(t *testing.T) {
+	testTable := []struct {
+		name         string
+		list         func() []model.Erasure
+		wantValid    bool
+		wantBrokenAt uint64
+	}{
+		{
+			name: "Intact Chain",
+			list: func() []model.Erasure {
+				return erasureChain(3, 5, 8)
+			},
+			wantValid: true,
+		},
+		{
+			name: "Changed Field",
+			list: func() []model.Erasure {
+				chain := erasureChain(3, 5, 8)
+				chain[1].UserId = 6
+				return chain
+			},
+			wantBrokenAt: 2,
+		},
+		{
+			name: "Broken Link",
+			list: func() []model.Erasure {
+				chain := erasureChain(3, 5, 8)
+				chain[2].PrevHash = chain[0].Hash
+				chain[2].Hash = chain[2].ChainHash()
+				return chain
+			},
+			wantBrokenAt: 3,
+		},
+		{
+			name: "First Entry Not Linked To Genesis",
+			list: func() []model.Erasure {
+				chain := erasureChain(3, 5)
+				chain[0].PrevHash = chain[1].Hash
+				chain[0].Hash = chain[0].ChainHash()
+				return chain
+			},
+			wantBrokenAt: 1,
+		},
+		{
+			name: "Empty Log",
+			list: func() []model.Erasure {
+				return nil
+			},
+			wantValid: true,
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newPrivacyService(erasureLogRepo{list: tt.list()}, nil)
+
+			got, err := s.GetErasures()
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantValid, got.Valid)
+			assert.Equal(t, tt.wantBrokenAt, got.BrokenAt)
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package service
+
+import (
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/internal/repository/postgres"
+	"github.com/rinuccia/travels-api/pkg/storage"
+	"time"
+)
+
+type purgeService struct {
+	repo  postgres.PurgeRepository
+	store storage.Storage
+}
+
+func newPurgeService(r postgres.PurgeRepository, store storage.Storage) *purgeService {
+	return &purgeService{
+		repo:  r,
+		store: store,
+	}
+}
+
+// Purge removes the photo files after the rows are gone.
+func (s *purgeService) Purge(retention time.Duration) (model.Purged, error) {
+	purged, err := s.repo.Purge(time.Now().Add(-retention))
+	if err != nil {
+		return purged, err
+	}
+	return purged, deletePhotoFiles(s.store, purged.Photos)
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"github.com/rinuccia/travels-api/internal/model"
+	"math"
+)
+
+// maxMark is the highest visit mark, marks range from 0 to maxMark
+const maxMark = 5
+
+// markHistogram holds the number of visits per mark, indexed by mark
+type markHistogram [maxMark + 1]int
+
+// distribution splits mark counts into an overall histogram and per-period histograms,
+// periods keep the order of counts.
+func distribution(counts []model.PeriodMarkCount, bucketed bool) model.RatingDistribution {
+	var total markHistogram
+	var periods []string
+	byPeriod := map[string]*markHistogram{}
+	for _, c := range counts {
+		if c.Mark > maxMark {
+			continue
+		}
+		total[c.Mark] += c.Count
+		if !bucketed {
+			continue
+		}
+		h, ok := byPeriod[c.Period]
+		if !ok {
+			h = &markHistogram{}
+			byPeriod[c.Period] = h
+			periods = append(periods, c.Period)
+		}
+		h[c.Mark] += c.Count
+	}
+
+	result := model.RatingDistribution{RatingSummary: summarize(total)}
+	for _, period := range periods {
+		result.Buckets = append(result.Buckets, model.RatingPeriod{Period: period, RatingSummary: summarize(*byPeriod[period])})
+	}
+	return result
+}
+
+// summarize computes total, mean, median and population standard deviation of a histogram,
+// statistics are rounded to two decimals and zero when there are no visits.
+func summarize(h markHistogram) model.RatingSummary {
+	summary := model.RatingSummary{Histogram: make([]model.MarkCount, 0, len(h))}
+	sum := 0
+	for mark, count := range h {
+		summary.Histogram = append(summary.Histogram, model.MarkCount{Mark: uint8(mark), Count: count})
+		summary.Total += count
+		sum += mark * count
+	}
+	if summary.Total == 0 {
+		return summary
+	}
+
+	mean := float64(sum) / float64(summary.Total)
+	variance := 0.0
+	for mark, count := range h {
+		variance += float64(count) * math.Pow(float64(mark)-mean, 2)
+	}
+	variance /= float64(summary.Total)
+	median := float64(h.markAt((summary.Total-1)/2)+h.markAt(summary.Total/2)) / 2
+
+	summary.Mean = round2(mean)
+	summary.Median = round2(median)
+	summary.StdDev = round2(math.Sqrt(variance))
+	return summary
+}
+
+// markAt returns the mark at zero-based position pos of the sorted marks.
+func (h markHistogram) markAt(pos int) int {
+	for mark, count := range h {
+		if pos < count {
+			return mark
+		}
+		pos -= count
+	}
+	return maxMark
+}
+
+func round2(f float64) float32 {
+	return float32(math.Round(f*100) / 100)
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSummarize(t *testing.T) {
+	testTable := []struct {
+		name      string
+		histogram markHistogram
+		want      model.RatingSummary
+	}{
+		{
+			name: "Empty",
+			want: model.RatingSummary{},
+		},
+		{
+			name:      "Single Visit",
+			histogram: markHistogram{4: 1},
+			want:      model.RatingSummary{Total: 1, Mean: 4, Median: 4},
+		},
+		{
+			name:      "Even Count Median Between Marks",
+			histogram: markHistogram{3: 1, 4: 1},
+			want:      model.RatingSummary{Total: 2, Mean: 3.5, Median: 3.5, StdDev: 0.5},
+		},
+		{
+			name:      "Even Count Median Within Mark",
+			histogram: markHistogram{1: 1, 5: 3},
+			want:      model.RatingSummary{Total: 4, Mean: 4, Median: 5, StdDev: 1.73},
+		},
+		{
+			name:      "Odd Count",
+			histogram: markHistogram{0: 2, 2: 1, 5: 2},
+			want:      model.RatingSummary{Total: 5, Mean: 2.4, Median: 2, StdDev: 2.24},
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			got := summarize(tt.histogram)
+
+			assert.Len(t, got.Histogram, maxMark+1)
+			for mark, count := range tt.histogram {
+				assert.Equal(t, model.MarkCount{Mark: uint8(mark), Count: count}, got.Histogram[mark])
+			}
+			got.Histogram = nil
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMarkHistogram_markAt(t *testing.T) {
+	h := markHistogram{1: 2, 3: 1, 5: 1}
+
+	testTable := []struct {
+		pos  int
+		want int
+	}{
+		{pos: 0, want: 1},
+		{pos: 1, want: 1},
+		{pos: 2, want: 3},
+		{pos: 3, want: 5},
+	}
+	for _, tt := range testTable {
+		assert.Equal(t, tt.want, h.markAt(tt.pos), "position %d", tt.pos)
+	}
+}
+
+func TestDistribution(t *testing.T) {
+	counts := []model.PeriodMarkCount{
+		{Period: "2022", Mark: 5, Count: 2},
+		{Period: "2021", Mark: 3, Count: 1},
+		{Period: "2022", Mark: 4, Count: 2},
+		{Period: "2021", Mark: 9, Count: 7},
+	}
+
+	t.Run("Bucketed", func(t *testing.T) {
+		got := distribution(counts, true)
+
+		assert.Equal(t, 5, got.Total)
+		assert.Equal(t, float32(4.2), got.Mean)
+		assert.Equal(t, float32(4), got.Median)
+		if assert.Len(t, got.Buckets, 2) {
+			assert.Equal(t, "2022", got.Buckets[0].Period)
+			assert.Equal(t, 4, got.Buckets[0].Total)
+			assert.Equal(t, float32(4.5), got.Buckets[0].Median)
+			assert.Equal(t, "2021", got.Buckets[1].Period)
+			assert.Equal(t, 1, got.Buckets[1].Total)
+		}
+	})
+
+	t.Run("Overall Only", func(t *testing.T) {
+		got := distribution(counts, false)
+
+		assert.Equal(t, 5, got.Total)
+		assert.Empty(t, got.Buckets)
+	})
+}
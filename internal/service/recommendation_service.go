@@ -0,0 +1,49 @@
+package service
+
+import (
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/internal/repository/postgres"
+)
+
+type recommendationService struct {
+	repo postgres.RecommendationRepository
+}
+
+func newRecommendationService(r postgres.RecommendationRepository) *recommendationService {
+	return &recommendationService{
+		repo: r,
+	}
+}
+
+// GetForUser prefers locations liked by similar users and fills the remaining places
+// with top rated locations in countries the user liked.
+func (s *recommendationService) GetForUser(id string, limit int) (model.Recommendations, error) {
+	similar, err := s.repo.FindBySimilarUsers(id, limit)
+	if err != nil {
+		return similar, err
+	}
+	result := model.Recommendations{}
+	seen := make(map[uint32]bool, limit)
+	add := func(list []model.Recommendation, source string) {
+		for _, r := range list {
+			if len(result.List) == limit || seen[r.LocationId] {
+				continue
+			}
+			seen[r.LocationId] = true
+			r.Source = source
+			r.Reason = "because you rated " + r.BecausePlace
+			result.List = append(result.List, r)
+		}
+	}
+	add(similar.List, model.RecommendationSimilarUsers)
+	if len(result.List) == limit {
+		return result, nil
+	}
+
+	liked, err := s.repo.FindByLikedCountries(id, limit)
+	if err != nil {
+		return result, err
+	}
+	add(liked.List, model.RecommendationLikedCountries)
+	return result, nil
+}
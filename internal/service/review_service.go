@@ -0,0 +1,28 @@
+package service
+
+import (
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/internal/repository/postgres"
+)
+
+type reviewService struct {
+	repo postgres.ReviewRepository
+}
+
+func newReviewService(r postgres.ReviewRepository) *reviewService {
+	return &reviewService{
+		repo: r,
+	}
+}
+
+func (s *reviewService) GetByLocation(id string, query model.ReviewQuery) (model.Reviews, error) {
+	return s.repo.FindByLocation(id, query)
+}
+
+func (s *reviewService) GetByStatus(status string, query model.ReviewQuery) (model.Reviews, error) {
+	return s.repo.FindByStatus(status, query)
+}
+
+func (s *reviewService) Moderate(visitId string, status string) error {
+	return s.repo.UpdateStatus(visitId, status)
+}
@@ -1,17 +1,53 @@
 package service
 
-import "github.com/rinuccia/travels-api/internal/repository/postgres"
+import (
+	"github.com/rinuccia/travels-api/internal/repository/postgres"
+	"github.com/rinuccia/travels-api/pkg/mailer"
+	"github.com/rinuccia/travels-api/pkg/storage"
+)
 
 type Service struct {
 	User
+	Auth
 	Location
 	Visit
+	Category
+	Stats
+	Trip
+	Wishlist
+	Review
+	Photo
+	Follow
+	Recommendation
+	APIKey
+	Audit
+	Purge
+	Export
+	Privacy
+	Verification
 }
 
-func NewService(repos *postgres.Repository) *Service {
+func NewService(repos *postgres.Repository, store storage.Storage, auth AuthConfig, users UserConfig,
+	mail mailer.Mailer) *Service {
+	verifier := newVerificationService(repos.VerificationRepository, repos.UserRepository, auth, mail, users)
 	return &Service{
-		newUserService(repos.UserRepository),
+		newUserService(repos.UserRepository, users, verifier),
+		newAuthService(repos.AuthRepository, auth, users, verifier),
 		newLocationService(repos.LocationRepository),
 		newVisitService(repos.VisitRepository),
+		newCategoryService(repos.CategoryRepository),
+		newStatsService(repos.StatsRepository),
+		newTripService(repos.TripRepository),
+		newWishlistService(repos.WishlistRepository),
+		newReviewService(repos.ReviewRepository),
+		newPhotoService(repos.PhotoRepository, store),
+		newFollowService(repos.FollowRepository),
+		newRecommendationService(repos.RecommendationRepository),
+		newAPIKeyService(repos.APIKeyRepository),
+		newAuditService(repos.AuditRepository),
+		newPurgeService(repos.PurgeRepository, store),
+		newExportService(repos.ExportRepository),
+		newPrivacyService(repos.PrivacyRepository, store),
+		verifier,
 	}
 }
@@ -0,0 +1,20 @@
+package service
+
+import (
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/internal/repository/postgres"
+)
+
+type statsService struct {
+	repo postgres.StatsRepository
+}
+
+func newStatsService(r postgres.StatsRepository) *statsService {
+	return &statsService{
+		repo: r,
+	}
+}
+
+func (s *statsService) GetCountryStats(filter model.VisitFilter, sort model.StatsSort) (model.CountriesStats, error) {
+	return s.repo.FindCountryStats(filter, sort)
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"errors"
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/internal/repository/postgres"
+	"github.com/rinuccia/travels-api/pkg/apperrors"
+	"github.com/rinuccia/travels-api/pkg/token"
+	"strconv"
+	"time"
+)
+
+type tenancyService struct {
+	repo          postgres.TenantRepository
+	keys          *token.Keyring
+	defaultTenant uint32
+}
+
+// NewTenancy resolves tenants across the deployment, unlike the services of NewService it is not bound to a tenant.
+func NewTenancy(r postgres.TenantRepository, keys *token.Keyring, defaultTenant uint32) Tenancy {
+	return &tenancyService{
+		repo:          r,
+		keys:          keys,
+		defaultTenant: defaultTenant,
+	}
+}
+
+func (s *tenancyService) Resolve(request model.TenantRequest) (uint32, error) {
+	var header uint32
+	if request.Header != "" {
+		id, err := strconv.ParseUint(request.Header, 10, 32)
+		if err != nil || id == 0 {
+			return 0, apperrors.ErrUnknownTenant
+		}
+		header = uint32(id)
+	}
+
+	credential, err := s.credentialTenant(request)
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case credential != 0 && header != 0 && credential != header:
+		return 0, apperrors.ErrForbidden
+	case credential != 0:
+		return credential, nil
+	case header != 0:
+		return header, nil
+	default:
+		return s.defaultTenant, nil
+	}
+}
+
+// credentialTenant returns the tenant named by the access token or API key of the request, 0 when there is none.
+// Invalid credentials name no tenant, they are rejected by the authentication of the tenant.
+func (s *tenancyService) credentialTenant(request model.TenantRequest) (uint32, error) {
+	if request.AccessToken != "" {
+		claims, err := s.keys.Verify(request.AccessToken, time.Now())
+		if err != nil || claims.Audience != "" {
+			return 0, nil
+		}
+		id, err := strconv.ParseUint(claims.Tenant, 10, 32)
+		if err != nil {
+			return 0, nil
+		}
+		return uint32(id), nil
+	}
+	if request.APIKey != "" {
+		id, err := s.repo.FindAPIKeyTenant(hashToken(request.APIKey))
+		if errors.Is(err, apperrors.ErrRecordNotFound) {
+			return 0, nil
+		}
+		return id, err
+	}
+	return 0, nil
+}
+
+func (s *tenancyService) GetById(id uint32) (model.Tenant, error) {
+	tenant, err := s.repo.FindById(id)
+	if errors.Is(err, apperrors.ErrRecordNotFound) {
+		return tenant, apperrors.ErrUnknownTenant
+	}
+	return tenant, err
+}
+
+func (s *tenancyService) GetAll() ([]model.Tenant, error) {
+	return s.repo.FindAll()
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"database/sql/driver"
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/internal/repository/postgres"
+	"github.com/rinuccia/travels-api/pkg/apperrors"
+	"github.com/rinuccia/travels-api/pkg/token"
+	"github.com/stretchr/testify/assert"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// apiKeyTenantRepo finds the tenant of the API keys it lists by key hash, err fails every lookup.
+type apiKeyTenantRepo struct {
+	postgres.TenantRepository
+	keys map[string]uint32
+	err  error
+}
+
+func (r apiKeyTenantRepo) FindAPIKeyTenant(keyHash string) (uint32, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	id, ok := r.keys[keyHash]
+	if !ok {
+		return 0, apperrors.ErrRecordNotFound
+	}
+	return id, nil
+}
+
+func TestTenancyService_Resolve(t *testing.T) {
+	keys, err := token.NewKeyring("k1", map[string][]byte{"k1": []byte("0123456789abcdef0123456789abcdef")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	sign := func(claims token.Claims) string {
+		signed, err := keys.Sign(claims)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signed
+	}
+	accessToken := func(tenantId uint32, expiresAt time.Time) string {
+		return sign(token.Claims{Subject: "1", Tenant: strconv.FormatUint(uint64(tenantId), 10),
+			IssuedAt: now.Add(-time.Hour).Unix(), ExpiresAt: expiresAt.Unix()})
+	}
+	apiKeys := map[string]uint32{hashToken("tk_nomad"): 3}
+
+	testTable := []struct {
+		name    string
+		repoErr error
+		request model.TenantRequest
+		want    uint32
+		wantErr error
+	}{
+		{
+			name:    "Default Tenant",
+			request: model.TenantRequest{},
+			want:    1,
+		},
+		{
+			name:    "Header",
+			request: model.TenantRequest{Header: "2"},
+			want:    2,
+		},
+		{
+			name:    "Malformed Header",
+			request: model.TenantRequest{Header: "abc"},
+			wantErr: apperrors.ErrUnknownTenant,
+		},
+		{
+			name:    "Header Zero",
+			request: model.TenantRequest{Header: "0"},
+			wantErr: apperrors.ErrUnknownTenant,
+		},
+		{
+			name:    "Token Tenant",
+			request: model.TenantRequest{AccessToken: accessToken(3, now.Add(time.Hour))},
+			want:    3,
+		},
+		{
+			name:    "Token Matching Header",
+			request: model.TenantRequest{Header: "3", AccessToken: accessToken(3, now.Add(time.Hour))},
+			want:    3,
+		},
+		{
+			name:    "Token Conflicting Header",
+			request: model.TenantRequest{Header: "2", AccessToken: accessToken(3, now.Add(time.Hour))},
+			wantErr: apperrors.ErrForbidden,
+		},
+		{
+			name:    "Expired Token Falls Back To Header",
+			request: model.TenantRequest{Header: "2", AccessToken: accessToken(3, now.Add(-time.Minute))},
+			want:    2,
+		},
+		{
+			name:    "Invalid Token Falls Back To Default",
+			request: model.TenantRequest{AccessToken: "not.a.token"},
+			want:    1,
+		},
+		{
+			name: "Other Purpose Token Falls Back To Header",
+			request: model.TenantRequest{Header: "2", AccessToken: sign(token.Claims{Subject: "1", Audience: "verify",
+				Tenant: "3", IssuedAt: now.Unix(), ExpiresAt: now.Add(time.Hour).Unix()})},
+			want: 2,
+		},
+		{
+			name:    "API Key Tenant",
+			request: model.TenantRequest{APIKey: "tk_nomad"},
+			want:    3,
+		},
+		{
+			name:    "API Key Of Other Tenant",
+			request: model.TenantRequest{Header: "2", APIKey: "tk_nomad"},
+			wantErr: apperrors.ErrForbidden,
+		},
+		{
+			name:    "Unknown API Key Falls Back To Header",
+			request: model.TenantRequest{Header: "2", APIKey: "tk_guess"},
+			want:    2,
+		},
+		{
+			name:    "API Key Store Failure",
+			repoErr: driver.ErrBadConn,
+			request: model.TenantRequest{APIKey: "tk_nomad"},
+			wantErr: driver.ErrBadConn,
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewTenancy(apiKeyTenantRepo{keys: apiKeys, err: tt.repoErr}, keys, 1)
+
+			got, err := s.Resolve(tt.request)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
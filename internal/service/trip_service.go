@@ -0,0 +1,152 @@
+package service
+
+import (
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/internal/repository/postgres"
+	"sort"
+	"time"
+)
+
+const visitDateLayout = "2006-01-02"
+
+type tripService struct {
+	repo postgres.TripRepository
+}
+
+func newTripService(r postgres.TripRepository) *tripService {
+	return &tripService{
+		repo: r,
+	}
+}
+
+func (s *tripService) GetAll(userId string, gapDays int) (model.Trips, error) {
+	records, err := s.repo.FindAll(userId)
+	if err != nil {
+		return model.Trips{}, err
+	}
+	visits, err := s.repo.FindVisits(userId)
+	if err != nil {
+		return model.Trips{}, err
+	}
+
+	trips := model.Trips{List: make([]model.Trip, 0, len(records))}
+	byId := make(map[uint32]int, len(records))
+	for _, record := range records {
+		byId[record.TripId] = len(trips.List)
+		trips.List = append(trips.List, model.Trip{TripId: record.TripId, Name: record.Name, Visits: []model.TripVisit{}})
+	}
+	var ungrouped []model.TripVisit
+	for _, visit := range visits {
+		if i, ok := byId[visit.TripId]; ok {
+			trips.List[i].Visits = append(trips.List[i].Visits, visit)
+		} else {
+			ungrouped = append(ungrouped, visit)
+		}
+	}
+	trips.List = append(trips.List, groupTrips(ungrouped, gapDays)...)
+
+	for i := range trips.List {
+		summarizeTrip(&trips.List[i])
+	}
+	// trips without visits have no start date and go last
+	sort.SliceStable(trips.List, func(i, j int) bool {
+		a, b := trips.List[i].StartDate, trips.List[j].StartDate
+		if a == "" || b == "" {
+			return b == "" && a != ""
+		}
+		return a < b
+	})
+	return trips, nil
+}
+
+func (s *tripService) Create(trip model.TripRecord) (model.TripRecord, error) {
+	trip.VisitIds = uniqueIds(trip.VisitIds)
+	return s.repo.Insert(trip)
+}
+
+func (s *tripService) Update(id string, trip model.TripUpdate) error {
+	trip.VisitIds = uniqueIds(trip.VisitIds)
+	return s.repo.Update(id, trip)
+}
+
+func (s *tripService) DeleteById(id string) error {
+	return s.repo.DeleteById(id)
+}
+
+func (s *tripService) Restore(id string) error {
+	return s.repo.Restore(id)
+}
+
+// groupTrips splits date ordered visits into automatic trips, a new trip starts when the country changes
+// or when more than gapDays days pass between two visits.
+func groupTrips(visits []model.TripVisit, gapDays int) []model.Trip {
+	var trips []model.Trip
+	var last time.Time
+	for i, visit := range visits {
+		date, err := time.Parse(visitDateLayout, visit.VisitedAt)
+		if i == 0 || err != nil || visit.Country != visits[i-1].Country || date.Sub(last) > time.Duration(gapDays)*24*time.Hour {
+			trips = append(trips, model.Trip{Automatic: true})
+		}
+		trip := &trips[len(trips)-1]
+		trip.Visits = append(trip.Visits, visit)
+		last = date
+	}
+	for i := range trips {
+		trips[i].Name = tripName(trips[i].Visits[0])
+	}
+	return trips
+}
+
+// tripName names an automatic trip after its country and starting month, e.g. "Peru, June 2019".
+func tripName(first model.TripVisit) string {
+	date, err := time.Parse(visitDateLayout, first.VisitedAt)
+	if err != nil {
+		return first.Country
+	}
+	return first.Country + ", " + date.Format("January 2006")
+}
+
+// summarizeTrip fills trip dates and statistics from its date ordered visits.
+func summarizeTrip(trip *model.Trip) {
+	if len(trip.Visits) == 0 {
+		return
+	}
+	trip.StartDate = trip.Visits[0].VisitedAt
+	trip.EndDate = trip.Visits[len(trip.Visits)-1].VisitedAt
+
+	locations := make(map[uint32]bool)
+	countries := make(map[string]bool)
+	marks := 0
+	for _, visit := range trip.Visits {
+		locations[visit.LocationId] = true
+		countries[visit.Country] = true
+		marks += int(visit.Mark)
+	}
+	trip.Stats = model.TripStats{
+		Visits:    len(trip.Visits),
+		Locations: len(locations),
+		Countries: len(countries),
+		AvgMark:   round2(float64(marks) / float64(len(trip.Visits))),
+	}
+	start, errStart := time.Parse(visitDateLayout, trip.StartDate)
+	end, errEnd := time.Parse(visitDateLayout, trip.EndDate)
+	if errStart == nil && errEnd == nil {
+		trip.Stats.Days = int(end.Sub(start).Hours()/24) + 1
+	}
+}
+
+// uniqueIds drops duplicate ids keeping the order, nil stays nil.
+func uniqueIds(ids []uint32) []uint32 {
+	if ids == nil {
+		return nil
+	}
+	seen := make(map[uint32]bool, len(ids))
+	unique := make([]uint32, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
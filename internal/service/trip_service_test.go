@@ -0,0 +1,163 @@
+package service
+
+import (
+	"fmt"
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/internal/repository/postgres"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestGroupTrips(t *testing.T) {
+	peru1 := model.TripVisit{VisitId: 1, Country: "Peru", VisitedAt: "2019-06-01"}
+	peru2 := model.TripVisit{VisitId: 2, Country: "Peru", VisitedAt: "2019-06-04"}
+	chile := model.TripVisit{VisitId: 3, Country: "Chile", VisitedAt: "2019-06-05"}
+	peruLater := model.TripVisit{VisitId: 4, Country: "Peru", VisitedAt: "2019-06-12"}
+
+	testTable := []struct {
+		name    string
+		visits  []model.TripVisit
+		gapDays int
+		want    [][]uint32
+		names   []string
+	}{
+		{
+			name: "No Visits",
+		},
+		{
+			name:    "Same Country Within Gap",
+			visits:  []model.TripVisit{peru1, peru2},
+			gapDays: 3,
+			want:    [][]uint32{{1, 2}},
+			names:   []string{"Peru, June 2019"},
+		},
+		{
+			name:    "Gap Longer Than Limit",
+			visits:  []model.TripVisit{peru1, peru2},
+			gapDays: 2,
+			want:    [][]uint32{{1}, {2}},
+			names:   []string{"Peru, June 2019", "Peru, June 2019"},
+		},
+		{
+			name:    "Country Change",
+			visits:  []model.TripVisit{peru1, peru2, chile},
+			gapDays: 7,
+			want:    [][]uint32{{1, 2}, {3}},
+			names:   []string{"Peru, June 2019", "Chile, June 2019"},
+		},
+		{
+			name:    "Return To Country",
+			visits:  []model.TripVisit{peru2, chile, peruLater},
+			gapDays: 7,
+			want:    [][]uint32{{2}, {3}, {4}},
+			names:   []string{"Peru, June 2019", "Chile, June 2019", "Peru, June 2019"},
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			got := groupTrips(tt.visits, tt.gapDays)
+
+			var ids [][]uint32
+			var names []string
+			for _, trip := range got {
+				assert.True(t, trip.Automatic)
+				var tripIds []uint32
+				for _, visit := range trip.Visits {
+					tripIds = append(tripIds, visit.VisitId)
+				}
+				ids = append(ids, tripIds)
+				names = append(names, trip.Name)
+			}
+			assert.Equal(t, tt.want, ids)
+			assert.Equal(t, tt.names, names)
+		})
+	}
+}
+
+func TestSummarizeTrip(t *testing.T) {
+	testTable := []struct {
+		name      string
+		trip      model.Trip
+		wantStart string
+		wantEnd   string
+		wantStats model.TripStats
+	}{
+		{
+			name: "No Visits",
+			trip: model.Trip{Name: "Empty"},
+		},
+		{
+			name: "Visits",
+			trip: model.Trip{Visits: []model.TripVisit{
+				{LocationId: 1, Country: "Peru", VisitedAt: "2019-06-01", Mark: 5},
+				{LocationId: 2, Country: "Peru", VisitedAt: "2019-06-03", Mark: 4},
+				{LocationId: 1, Country: "Peru", VisitedAt: "2019-06-05", Mark: 4},
+				{LocationId: 3, Country: "Chile", VisitedAt: "2019-06-10", Mark: 2},
+			}},
+			wantStart: "2019-06-01",
+			wantEnd:   "2019-06-10",
+			wantStats: model.TripStats{Visits: 4, Locations: 3, Countries: 2, Days: 10, AvgMark: 3.75},
+		},
+		{
+			name:      "Single Day",
+			trip:      model.Trip{Visits: []model.TripVisit{{LocationId: 1, Country: "Peru", VisitedAt: "2019-06-01", Mark: 3}}},
+			wantStart: "2019-06-01",
+			wantEnd:   "2019-06-01",
+			wantStats: model.TripStats{Visits: 1, Locations: 1, Countries: 1, Days: 1, AvgMark: 3},
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			summarizeTrip(&tt.trip)
+
+			assert.Equal(t, tt.wantStart, tt.trip.StartDate)
+			assert.Equal(t, tt.wantEnd, tt.trip.EndDate)
+			assert.Equal(t, tt.wantStats, tt.trip.Stats)
+		})
+	}
+}
+
+// fixedTripRepo returns the same manual trips and visits for every user.
+type fixedTripRepo struct {
+	postgres.TripRepository
+	records []model.TripRecord
+	visits  []model.TripVisit
+}
+
+func (r fixedTripRepo) FindAll(string) ([]model.TripRecord, error) {
+	return r.records, nil
+}
+
+func (r fixedTripRepo) FindVisits(string) ([]model.TripVisit, error) {
+	return r.visits, nil
+}
+
+func TestTripService_GetAll(t *testing.T) {
+	repo := fixedTripRepo{
+		records: []model.TripRecord{{TripId: 7, Name: "Andes"}, {TripId: 8, Name: "Someday"}},
+		visits: []model.TripVisit{
+			{VisitId: 1, Country: "Peru", VisitedAt: "2019-06-01"},
+			{VisitId: 2, Country: "Peru", VisitedAt: "2019-06-02", TripId: 7},
+			{VisitId: 3, Country: "Chile", VisitedAt: "2019-06-03", TripId: 7},
+			{VisitId: 4, Country: "Peru", VisitedAt: "2019-06-04"},
+		},
+	}
+
+	got, err := newTripService(repo).GetAll("1", 30)
+
+	assert.NoError(t, err)
+	var summary []string
+	for _, trip := range got.List {
+		ids := ""
+		for _, visit := range trip.Visits {
+			ids += fmt.Sprintf(" %s#%d", visit.Country, visit.VisitId)
+		}
+		summary = append(summary, trip.Name+":"+ids)
+	}
+	// manual trips keep their visits across a country change, automatic grouping only sees the rest
+	assert.Equal(t, []string{
+		"Peru, June 2019: Peru#1 Peru#4",
+		"Andes: Peru#2 Chile#3",
+		"Someday:",
+	}, summary)
+}
@@ -3,15 +3,31 @@ package service
 import (
 	"github.com/rinuccia/travels-api/internal/model"
 	"github.com/rinuccia/travels-api/internal/repository/postgres"
+	"github.com/rinuccia/travels-api/pkg/email"
+	"time"
 )
 
+// UserConfig holds how user emails are normalised and verified.
+type UserConfig struct {
+	// LowercaseEmailLocalPart lowercases the part before @ too, the domain is always lowercased.
+	LowercaseEmailLocalPart bool
+	// VerifyTTL is how long a verification email stays valid.
+	VerifyTTL time.Duration
+	// VerifyURL is the page the verification email links to, the token is added as the token query parameter.
+	VerifyURL string
+}
+
 type userService struct {
-	repo postgres.UserRepository
+	repo     postgres.UserRepository
+	cfg      UserConfig
+	verifier *verificationService
 }
 
-func newUserService(r postgres.UserRepository) *userService {
+func newUserService(r postgres.UserRepository, cfg UserConfig, verifier *verificationService) *userService {
 	return &userService{
-		repo: r,
+		repo:     r,
+		cfg:      cfg,
+		verifier: verifier,
 	}
 }
 
@@ -23,19 +39,36 @@ func (s *userService) GetById(id string) (model.User, error) {
 	return user, err
 }
 
-func (s *userService) Create(user model.User) (model.User, error) {
+func (s *userService) GetSummary(id string) (model.UserSummary, error) {
+	return s.repo.FindSummary(id)
+}
+
+func (s *userService) Create(actor model.Actor, user model.User) (model.User, error) {
 	var err error
-	user, err = s.repo.Insert(user)
+	user.Email = email.Normalize(user.Email, s.cfg.LowercaseEmailLocalPart)
+	user.Verified = false
+	user, err = s.repo.Insert(actor, user)
 	if err != nil {
 		return user, err
 	}
-	return user, err
+	return user, s.verifier.send(user.UserId, user.Email)
 }
 
-func (s *userService) Update(id string, user model.User) error {
-	err := s.repo.Update(id, user)
+// Update keeps the current email, a new email is mailed a verification and replaces it once confirmed.
+func (s *userService) Update(actor model.Actor, id string, user model.User) error {
+	current, err := s.repo.FindById(id)
 	if err != nil {
 		return err
 	}
-	return err
+	newEmail := email.Normalize(user.Email, s.cfg.LowercaseEmailLocalPart)
+	user.Email = current.Email
+	err = s.repo.Update(actor, id, user)
+	if err != nil || newEmail == current.Email {
+		return err
+	}
+	return s.verifier.send(current.UserId, newEmail)
+}
+
+func (s *userService) SetRole(actor model.Actor, id string, role string) error {
+	return s.repo.UpdateRole(actor, id, role)
 }
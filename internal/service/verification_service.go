@@ -0,0 +1,115 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/internal/repository/postgres"
+	"github.com/rinuccia/travels-api/pkg/apperrors"
+	"github.com/rinuccia/travels-api/pkg/mailer"
+	"github.com/rinuccia/travels-api/pkg/token"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// verificationAudience marks verification tokens, so they are never accepted as access tokens.
+const verificationAudience = "email-verification"
+
+type verificationService struct {
+	repo   postgres.VerificationRepository
+	users  postgres.UserRepository
+	keys   *token.Keyring
+	tenant string
+	mailer mailer.Mailer
+	cfg    UserConfig
+}
+
+func newVerificationService(r postgres.VerificationRepository, users postgres.UserRepository, auth AuthConfig,
+	m mailer.Mailer, cfg UserConfig) *verificationService {
+	return &verificationService{
+		repo:   r,
+		users:  users,
+		keys:   auth.Keys,
+		tenant: tenantClaim(auth.TenantId),
+		mailer: m,
+		cfg:    cfg,
+	}
+}
+
+func (s *verificationService) Confirm(actor model.Actor, verificationToken string) error {
+	claims, err := s.keys.Verify(verificationToken, time.Now())
+	if err != nil || claims.Audience != verificationAudience || claims.Tenant != s.tenant {
+		return apperrors.ErrInvalidToken
+	}
+	userId, err := strconv.ParseUint(claims.Subject, 10, 32)
+	if err != nil {
+		return apperrors.ErrInvalidToken
+	}
+	err = s.repo.ConfirmEmail(actor, claims.ID, uint32(userId))
+	if errors.Is(err, apperrors.ErrRecordNotFound) {
+		return apperrors.ErrInvalidToken
+	}
+	return err
+}
+
+func (s *verificationService) Resend(id string) error {
+	user, err := s.users.FindById(id)
+	if err != nil {
+		return err
+	}
+	if user.Verified {
+		return apperrors.ErrAlreadyVerified
+	}
+	return s.send(user.UserId, user.Email)
+}
+
+// send mails a signed one-time token confirming address for the user, the token id is stored so it works once.
+// A failed delivery is reported as ErrMailNotSent, the verification can be sent again.
+func (s *verificationService) send(userId uint32, address string) error {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return err
+	}
+	now := time.Now()
+	v := model.EmailVerification{
+		TokenId:   hex.EncodeToString(b),
+		UserId:    userId,
+		Email:     address,
+		ExpiresAt: now.Add(s.cfg.VerifyTTL),
+	}
+	signed, err := s.keys.Sign(token.Claims{
+		Subject:   strconv.FormatUint(uint64(userId), 10),
+		Audience:  verificationAudience,
+		Tenant:    s.tenant,
+		ID:        v.TokenId,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: v.ExpiresAt.Unix(),
+	})
+	if err != nil {
+		return err
+	}
+	link, err := url.Parse(s.cfg.VerifyURL)
+	if err != nil {
+		return err
+	}
+	query := link.Query()
+	query.Set("token", signed)
+	link.RawQuery = query.Encode()
+
+	if err = s.repo.InsertVerification(v); err != nil {
+		return err
+	}
+	err = s.mailer.Send(mailer.Message{
+		To:      address,
+		Subject: "Confirm your email",
+		Body: "Open the link below to confirm " + address + " for your Traveler's Aid account.\n\n" +
+			link.String() + "\n\nThe link works once and expires in " + s.cfg.VerifyTTL.String() + ".",
+	})
+	if err != nil {
+		return fmt.Errorf("%w: %s", apperrors.ErrMailNotSent, err.Error())
+	}
+	return nil
+}
@@ -1,8 +1,11 @@
 package service
 
 import (
+	"errors"
 	"github.com/rinuccia/travels-api/internal/model"
 	"github.com/rinuccia/travels-api/internal/repository/postgres"
+	"io"
+	"strconv"
 )
 
 type visitService struct {
@@ -15,23 +18,67 @@ func newVisitService(r postgres.VisitRepository) *visitService {
 	}
 }
 
-func (s *visitService) GetAll(id string) (model.UserVisits, error) {
-	visits, err := s.repo.FindAll(id)
+func (s *visitService) GetAll(id string, filter model.LocationFilter) (model.UserVisits, error) {
+	visits, err := s.repo.FindAll(id, normalizeFilter(filter))
 	if err != nil {
 		return visits, err
 	}
 	return visits, err
 }
 
-func (s *visitService) Create(visit model.Visit) (model.Visit, error) {
-	v, err := s.repo.Insert(visit)
+func (s *visitService) Create(actor model.Actor, visit model.Visit) (model.Visit, error) {
+	if visit.Review != nil {
+		visit.Review.Status = model.ReviewPending
+	}
+	v, err := s.repo.Insert(actor, visit)
 	if err != nil {
 		return v, err
 	}
 	return v, err
 }
 
-func (s *visitService) DeleteById(id string) error {
-	err := s.repo.DeleteById(id)
+func (s *visitService) DeleteById(actor model.Actor, id string) error {
+	err := s.repo.DeleteById(actor, id)
 	return err
 }
+
+func (s *visitService) Restore(actor model.Actor, id string) error {
+	return s.repo.Restore(actor, id)
+}
+
+func (s *visitService) Import(actor model.Actor, r io.Reader, upsert bool) (model.ImportResult, error) {
+	return importCSV(r, exportColumns[model.ExportEntityVisits], func(fields map[string]string) (bool, error) {
+		visit := model.Visit{VisitedAt: fields["visited_at"]}
+		var err error
+		if visit.VisitId, err = parseUint32(fields, "visit_id"); err != nil {
+			return false, err
+		}
+		if visit.LocationId, err = parseUint32(fields, "location_id"); err != nil {
+			return false, err
+		}
+		if visit.UserId, err = parseUint32(fields, "user_id"); err != nil {
+			return false, err
+		}
+		mark, err := strconv.ParseUint(fields["mark"], 10, 8)
+		if err != nil {
+			return false, errors.New("mark: invalid number")
+		}
+		visit.Mark = uint8(mark)
+		if err = validate.Struct(visit); err != nil {
+			return false, validationReason(err)
+		}
+
+		if upsert {
+			inserted, err := s.repo.Upsert(actor, visit)
+			if err != nil {
+				return false, storeReason(err, "visit_id belongs to a deleted visit, restore it first",
+					"user_id or location_id does not exist")
+			}
+			return inserted, nil
+		}
+		if _, err = s.repo.Insert(actor, visit); err != nil {
+			return false, storeReason(err, "visit_id already exists", "user_id or location_id does not exist")
+		}
+		return true, nil
+	})
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"github.com/rinuccia/travels-api/internal/model"
+	"github.com/rinuccia/travels-api/internal/repository/postgres"
+)
+
+// defaultWishlistPriority is used when an item is saved without priority
+const defaultWishlistPriority = 3
+
+type wishlistService struct {
+	repo postgres.WishlistRepository
+}
+
+func newWishlistService(r postgres.WishlistRepository) *wishlistService {
+	return &wishlistService{
+		repo: r,
+	}
+}
+
+func (s *wishlistService) GetAll(userId string, filter model.WishlistFilter) (model.Wishlist, error) {
+	return s.repo.FindAll(userId, filter)
+}
+
+func (s *wishlistService) Create(userId string, item model.WishlistItem) (model.WishlistItem, error) {
+	if item.Priority == 0 {
+		item.Priority = defaultWishlistPriority
+	}
+	return s.repo.Insert(userId, item)
+}
+
+func (s *wishlistService) Update(userId, locationId string, item model.WishlistItem) error {
+	if item.Priority == 0 {
+		item.Priority = defaultWishlistPriority
+	}
+	return s.repo.Update(userId, locationId, item)
+}
+
+func (s *wishlistService) DeleteById(userId, locationId string) error {
+	return s.repo.DeleteById(userId, locationId)
+}
+
+func (s *wishlistService) Restore(userId, locationId string) error {
+	return s.repo.Restore(userId, locationId)
+}
@@ -1,8 +1,26 @@
 package apperrors
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
-	ErrRecordNotFound = errors.New("record not found")
-	ErrIncorrectQuery = errors.New("incorrect query")
+	ErrRecordNotFound     = errors.New("record not found")
+	ErrIncorrectQuery     = errors.New("incorrect query")
+	ErrAlreadyExists      = errors.New("record already exists")
+	ErrInvalidCSV         = errors.New("invalid csv")
+	ErrUnknownCountry     = errors.New("unknown country")
+	ErrInvalidImage       = errors.New("invalid image")
+	ErrUnsupportedImage   = errors.New("unsupported image type")
+	ErrImageTooLarge      = fmt.Errorf("%w: dimensions too large", ErrInvalidImage)
+	ErrInvalidCredentials = errors.New("invalid email or password")
+	ErrUnauthorized       = errors.New("unauthorized")
+	ErrForbidden          = errors.New("forbidden")
+	ErrInvalidToken       = errors.New("invalid or expired token")
+	ErrAlreadyVerified    = errors.New("email already verified")
+	ErrMailNotSent        = errors.New("email could not be sent")
+	ErrUnknownTenant      = errors.New("unknown tenant")
+	ErrTenantsBusy        = errors.New("too many tenants in use, try again later")
+	ErrEmailCollision     = errors.New("users with emails equal ignoring case")
 )
@@ -0,0 +1,63 @@
+// Package countries provides ISO 3166-1 country reference data.
+// The dataset comes from the Debian iso-codes project, display names use the common short name where one exists.
+package countries
+
+import (
+	_ "embed"
+	"encoding/csv"
+	"strings"
+)
+
+//go:embed iso3166.csv
+var dataset string
+
+// Country is an ISO 3166-1 entry.
+type Country struct {
+	Alpha2  string
+	Alpha3  string
+	Numeric string
+	Name    string
+	Aliases []string
+}
+
+var (
+	all   []Country
+	index = map[string]int{}
+)
+
+func init() {
+	records, err := csv.NewReader(strings.NewReader(dataset)).ReadAll()
+	if err != nil {
+		panic("countries: invalid embedded dataset: " + err.Error())
+	}
+	for _, r := range records[1:] {
+		c := Country{Alpha2: r[0], Alpha3: r[1], Numeric: r[2], Name: r[3]}
+		if r[4] != "" {
+			c.Aliases = strings.Split(r[4], "|")
+		}
+		all = append(all, c)
+
+		i := len(all) - 1
+		for _, key := range append([]string{c.Alpha2, c.Alpha3, c.Name}, c.Aliases...) {
+			index[normalize(key)] = i
+		}
+	}
+}
+
+// All returns every country ordered by alpha-2 code.
+func All() []Country {
+	return all
+}
+
+// Lookup finds a country by alpha-2 or alpha-3 code, name or alias, ignoring case and surrounding spaces.
+func Lookup(s string) (Country, bool) {
+	i, ok := index[normalize(s)]
+	if !ok {
+		return Country{}, false
+	}
+	return all[i], true
+}
+
+func normalize(s string) string {
+	return strings.ToLower(strings.TrimSpace(s))
+}
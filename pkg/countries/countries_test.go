@@ -0,0 +1,33 @@
+package countries
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestLookup(t *testing.T) {
+	testTable := []struct {
+		name  string
+		input string
+		want  string
+		found bool
+	}{
+		{name: "Alpha-2", input: "fr", want: "FR", found: true},
+		{name: "Alpha-3", input: "USA", want: "US", found: true},
+		{name: "Name", input: " France ", want: "FR", found: true},
+		{name: "Official Name", input: "United States of America", want: "US", found: true},
+		{name: "Alias", input: "RF", want: "RU", found: true},
+		{name: "Unknown", input: "Atlantis", found: false},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Lookup(tt.input)
+			assert.Equal(t, tt.found, ok)
+			assert.Equal(t, tt.want, got.Alpha2)
+		})
+	}
+}
+
+func TestAll(t *testing.T) {
+	assert.Len(t, All(), 249)
+}
@@ -0,0 +1,19 @@
+// Package email normalises email addresses so the same mailbox is stored the same way.
+package email
+
+import "strings"
+
+// Normalize trims the address and lowercases the domain, which is case-insensitive.
+// The local part is lowercased only with lowerLocal, mail servers may tell it apart by case.
+func Normalize(address string, lowerLocal bool) string {
+	address = strings.TrimSpace(address)
+	at := strings.LastIndex(address, "@")
+	local, domain := address, ""
+	if at >= 0 {
+		local, domain = address[:at], "@"+strings.ToLower(address[at+1:])
+	}
+	if lowerLocal {
+		local = strings.ToLower(local)
+	}
+	return local + domain
+}
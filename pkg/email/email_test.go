@@ -0,0 +1,26 @@
+package email
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	testTable := []struct {
+		name       string
+		input      string
+		lowerLocal bool
+		want       string
+	}{
+		{name: "Domain", input: "John@Gmail.COM", want: "John@gmail.com"},
+		{name: "Local Part", input: "John@Gmail.COM", lowerLocal: true, want: "john@gmail.com"},
+		{name: "Spaces", input: "  john@gmail.com\t", want: "john@gmail.com"},
+		{name: "Quoted At", input: `"a@b"@Example.org`, want: `"a@b"@example.org`},
+		{name: "No At", input: "John", want: "John"},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Normalize(tt.input, tt.lowerLocal))
+		})
+	}
+}
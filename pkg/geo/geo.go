@@ -0,0 +1,40 @@
+package geo
+
+import "math"
+
+// EarthRadiusKm is the mean Earth radius used for great-circle distances.
+const EarthRadiusKm = 6371.0
+
+// MaxDistanceKm is half of the Earth circumference, no two points are further apart.
+const MaxDistanceKm = math.Pi * EarthRadiusKm
+
+// BoundingBox is a lat/lon rectangle that contains every point within a radius of its centre.
+type BoundingBox struct {
+	MinLat, MaxLat float64
+	MinLon, MaxLon float64
+}
+
+// NewBoundingBox returns the box around (lat, lon) enclosing a circle of radiusKm.
+// Near the poles or across the antimeridian the longitude range is widened to the whole globe,
+// the box is only a prefilter so it may include points that are too far away but never excludes one.
+func NewBoundingBox(lat, lon, radiusKm float64) BoundingBox {
+	angular := radiusKm / EarthRadiusKm
+	dLat := angular * 180 / math.Pi
+
+	box := BoundingBox{
+		MinLat: math.Max(lat-dLat, -90),
+		MaxLat: math.Min(lat+dLat, 90),
+		MinLon: -180,
+		MaxLon: 180,
+	}
+	if box.MinLat == -90 || box.MaxLat == 90 {
+		return box
+	}
+
+	dLon := math.Asin(math.Min(math.Sin(angular)/math.Cos(lat*math.Pi/180), 1)) * 180 / math.Pi
+	if lon-dLon < -180 || lon+dLon > 180 {
+		return box
+	}
+	box.MinLon, box.MaxLon = lon-dLon, lon+dLon
+	return box
+}
@@ -0,0 +1,46 @@
+package geo
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNewBoundingBox(t *testing.T) {
+	testTable := []struct {
+		name      string
+		lat, lon  float64
+		radiusKm  float64
+		want      BoundingBox
+		wholeLons bool
+	}{
+		{
+			name: "Equator",
+			lat:  0, lon: 0, radiusKm: 111.19,
+			want: BoundingBox{MinLat: -1, MaxLat: 1, MinLon: -1, MaxLon: 1},
+		},
+		{
+			name: "Pole",
+			lat:  89.5, lon: 10, radiusKm: 100,
+			wholeLons: true,
+		},
+		{
+			name: "Antimeridian",
+			lat:  0, lon: 179.9, radiusKm: 50,
+			wholeLons: true,
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewBoundingBox(tt.lat, tt.lon, tt.radiusKm)
+			if tt.wholeLons {
+				assert.Equal(t, -180.0, got.MinLon)
+				assert.Equal(t, 180.0, got.MaxLon)
+				return
+			}
+			assert.InDelta(t, tt.want.MinLat, got.MinLat, 0.01)
+			assert.InDelta(t, tt.want.MaxLat, got.MaxLat, 0.01)
+			assert.InDelta(t, tt.want.MinLon, got.MinLon, 0.01)
+			assert.InDelta(t, tt.want.MaxLon, got.MaxLon, 0.01)
+		})
+	}
+}
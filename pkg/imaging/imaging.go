@@ -0,0 +1,118 @@
+// Package imaging makes thumbnails and removes embedded metadata using only the standard library codecs.
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+)
+
+var (
+	jpegSignature = []byte{0xFF, 0xD8}
+	pngSignature  = []byte("\x89PNG\r\n\x1a\n")
+)
+
+// Thumbnail scales src down so its longer side is at most maxSide pixels, keeping the aspect ratio.
+// Each target pixel averages the source pixels it covers, smaller images are copied unscaled.
+func Thumbnail(src image.Image, maxSide int) *image.RGBA {
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	tw, th := w, h
+	if w > maxSide || h > maxSide {
+		if w >= h {
+			tw, th = maxSide, atLeastOne(h*maxSide/w)
+		} else {
+			tw, th = atLeastOne(w*maxSide/h), maxSide
+		}
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, tw, th))
+	for y := 0; y < th; y++ {
+		y0, y1 := b.Min.Y+y*h/th, b.Min.Y+(y+1)*h/th
+		for x := 0; x < tw; x++ {
+			x0, x1 := b.Min.X+x*w/tw, b.Min.X+(x+1)*w/tw
+
+			var r, g, bl, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					r, g, bl, a = r+uint64(cr), g+uint64(cg), bl+uint64(cb), a+uint64(ca)
+					n++
+				}
+			}
+			dst.Set(x, y, color.RGBA64{R: uint16(r / n), G: uint16(g / n), B: uint16(bl / n), A: uint16(a / n)})
+		}
+	}
+	return dst
+}
+
+func atLeastOne(n int) int {
+	if n < 1 {
+		return 1
+	}
+	return n
+}
+
+// StripEXIF returns JPEG or PNG data without its Exif block, which is where cameras and phones record GPS position.
+// The image itself is copied byte for byte, so it is not recompressed. In JPEG every APP1 segment is dropped,
+// that covers XMP packets which may repeat the position. Orientation is lost along with the rest of the Exif data.
+// Other formats and data that cannot be parsed are returned unchanged.
+func StripEXIF(data []byte) []byte {
+	switch {
+	case bytes.HasPrefix(data, jpegSignature):
+		if out, ok := stripJPEG(data); ok {
+			return out
+		}
+	case bytes.HasPrefix(data, pngSignature):
+		if out, ok := stripPNG(data); ok {
+			return out
+		}
+	}
+	return data
+}
+
+func stripJPEG(data []byte) ([]byte, bool) {
+	out := append([]byte{}, jpegSignature...)
+	pos := len(jpegSignature)
+	for pos+4 <= len(data) {
+		if data[pos] != 0xFF {
+			return nil, false
+		}
+		marker := data[pos+1]
+		// start of scan: entropy coded data follows, metadata segments only come before it
+		if marker == 0xDA || marker == 0xD9 {
+			break
+		}
+		length := int(binary.BigEndian.Uint16(data[pos+2:]))
+		end := pos + 2 + length
+		if length < 2 || end > len(data) {
+			return nil, false
+		}
+		if marker != 0xE1 {
+			out = append(out, data[pos:end]...)
+		}
+		pos = end
+	}
+	return append(out, data[pos:]...), true
+}
+
+func stripPNG(data []byte) ([]byte, bool) {
+	out := append([]byte{}, pngSignature...)
+	pos := len(pngSignature)
+	for pos < len(data) {
+		if pos+8 > len(data) {
+			return nil, false
+		}
+		// chunk layout: 4 byte length, 4 byte type, data, 4 byte crc
+		end := pos + 12 + int(binary.BigEndian.Uint32(data[pos:]))
+		if end > len(data) || end < pos {
+			return nil, false
+		}
+		if string(data[pos+4:pos+8]) != "eXIf" {
+			out = append(out, data[pos:end]...)
+		}
+		pos = end
+	}
+	return out, true
+}
@@ -0,0 +1,75 @@
+package imaging
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+func TestThumbnail(t *testing.T) {
+	testTable := []struct {
+		name  string
+		w, h  int
+		wantW int
+		wantH int
+	}{
+		{name: "Landscape", w: 800, h: 600, wantW: 320, wantH: 240},
+		{name: "Portrait", w: 300, h: 900, wantW: 106, wantH: 320},
+		{name: "Small", w: 100, h: 50, wantW: 100, wantH: 50},
+		{name: "Strip", w: 2000, h: 3, wantW: 320, wantH: 1},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			src := image.NewRGBA(image.Rect(10, 10, 10+tt.w, 10+tt.h))
+			for i := range src.Pix {
+				src.Pix[i] = 200
+			}
+
+			got := Thumbnail(src, 320)
+
+			assert.Equal(t, image.Rect(0, 0, tt.wantW, tt.wantH), got.Bounds())
+			assert.Equal(t, color.RGBA{R: 200, G: 200, B: 200, A: 200}, got.RGBAAt(tt.wantW-1, tt.wantH-1))
+		})
+	}
+}
+
+func TestStripEXIF_JPEG(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, jpeg.Encode(&buf, image.NewGray(image.Rect(0, 0, 8, 8)), nil))
+	encoded := buf.Bytes()
+
+	exif := append([]byte{0xFF, 0xE1, 0x00, 0x10}, []byte("Exif\x00\x00GPS-data")...)
+	withExif := append(append(append([]byte{}, encoded[:2]...), exif...), encoded[2:]...)
+
+	got := StripEXIF(withExif)
+
+	assert.Equal(t, encoded, got)
+	_, err := jpeg.Decode(bytes.NewReader(got))
+	assert.NoError(t, err)
+}
+
+func TestStripEXIF_PNG(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, png.Encode(&buf, image.NewGray(image.Rect(0, 0, 8, 8))))
+	encoded := buf.Bytes()
+
+	// signature and IHDR chunk take the first 33 bytes
+	exif := append([]byte{0, 0, 0, 4}, []byte("eXIfGPS!\x00\x00\x00\x00")...)
+	withExif := append(append(append([]byte{}, encoded[:33]...), exif...), encoded[33:]...)
+
+	got := StripEXIF(withExif)
+
+	assert.Equal(t, encoded, got)
+	_, err := png.Decode(bytes.NewReader(got))
+	assert.NoError(t, err)
+}
+
+func TestStripEXIF_Unparsable(t *testing.T) {
+	truncated := []byte{0xFF, 0xD8, 0xFF, 0xE1, 0xFF, 0xFF, 'E', 'x'}
+	assert.Equal(t, truncated, StripEXIF(truncated))
+	assert.Equal(t, []byte("GIF89a"), StripEXIF([]byte("GIF89a")))
+}
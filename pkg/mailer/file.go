@@ -0,0 +1,43 @@
+package mailer
+
+import (
+	"io"
+	"os"
+	"sync"
+)
+
+// File appends every message to a writer instead of delivering it, for local development and tests.
+type File struct {
+	mu   sync.Mutex
+	w    io.Writer
+	from string
+}
+
+// NewFile returns a driver appending messages to the file at path, the file is created when missing.
+// An empty path writes to stdout.
+func NewFile(path, from string) (*File, error) {
+	if path == "" {
+		return NewWriter(os.Stdout, from), nil
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	return NewWriter(f, from), nil
+}
+
+// NewWriter returns a driver writing messages to w.
+func NewWriter(w io.Writer, from string) *File {
+	return &File{w: w, from: from}
+}
+
+func (f *File) Send(msg Message) error {
+	data, err := format(f.from, msg)
+	if err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	_, err = f.w.Write(append(data, "\r\n"...))
+	return err
+}
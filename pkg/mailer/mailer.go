@@ -0,0 +1,67 @@
+// Package mailer sends plain text emails behind a driver-agnostic interface.
+package mailer
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidHeader is returned when a recipient or subject would break the message headers.
+var ErrInvalidHeader = errors.New("invalid header value")
+
+// Message is a plain text email to a single recipient.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers messages.
+type Mailer interface {
+	// Send delivers msg, it returns once the message is handed over.
+	Send(msg Message) error
+}
+
+// Config selects the driver and holds the settings of every driver.
+type Config struct {
+	// Driver is "log" or "smtp", an empty driver selects log.
+	Driver string
+	// From is the sender address of every message.
+	From string
+	// File is where the log driver appends messages, empty writes them to stdout.
+	File string
+	// Host, Port, Username and Password reach the SMTP server, an empty username sends without authentication.
+	Host     string
+	Port     string
+	Username string
+	Password string
+}
+
+// New returns the mailer driver selected by cfg.
+func New(cfg Config) (Mailer, error) {
+	switch cfg.Driver {
+	case "", "log":
+		return NewFile(cfg.File, cfg.From)
+	case "smtp":
+		return NewSMTP(cfg.Host, cfg.Port, cfg.Username, cfg.Password, cfg.From), nil
+	default:
+		return nil, fmt.Errorf("unknown mailer driver %q", cfg.Driver)
+	}
+}
+
+// format renders msg as an RFC 5322 message, header values must not contain line breaks.
+func format(from string, msg Message) ([]byte, error) {
+	for _, value := range []string{from, msg.To, msg.Subject} {
+		if strings.ContainsAny(value, "\r\n") {
+			return nil, ErrInvalidHeader
+		}
+	}
+	body := strings.ReplaceAll(strings.ReplaceAll(msg.Body, "\r\n", "\n"), "\n", "\r\n")
+	return []byte("From: " + from + "\r\n" +
+		"To: " + msg.To + "\r\n" +
+		"Subject: " + msg.Subject + "\r\n" +
+		"MIME-Version: 1.0\r\n" +
+		"Content-Type: text/plain; charset=utf-8\r\n" +
+		"\r\n" + body + "\r\n"), nil
+}
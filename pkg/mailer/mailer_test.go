@@ -0,0 +1,53 @@
+package mailer
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFile_Send(t *testing.T) {
+	var buf bytes.Buffer
+	m := NewWriter(&buf, "noreply@travels.example")
+
+	err := m.Send(Message{To: "john@gmail.com", Subject: "Confirm your email", Body: "Hello,\nconfirm it."})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "From: noreply@travels.example\r\nTo: john@gmail.com\r\nSubject: Confirm your email\r\n"+
+		"MIME-Version: 1.0\r\nContent-Type: text/plain; charset=utf-8\r\n\r\nHello,\r\nconfirm it.\r\n\r\n", buf.String())
+}
+
+func TestFile_SendHeaderInjection(t *testing.T) {
+	var buf bytes.Buffer
+	m := NewWriter(&buf, "noreply@travels.example")
+
+	err := m.Send(Message{To: "john@gmail.com\r\nBcc: kate@mail.ru", Subject: "Hi"})
+
+	assert.ErrorIs(t, err, ErrInvalidHeader)
+	assert.Empty(t, buf.String())
+}
+
+func TestNewFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mail.log")
+	m, err := NewFile(path, "noreply@travels.example")
+	assert.NoError(t, err)
+
+	assert.NoError(t, m.Send(Message{To: "john@gmail.com", Subject: "First"}))
+	assert.NoError(t, m.Send(Message{To: "john@gmail.com", Subject: "Second"}))
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "Subject: First")
+	assert.Contains(t, string(data), "Subject: Second")
+}
+
+func TestNew(t *testing.T) {
+	m, err := New(Config{Driver: "smtp", Host: "localhost", Port: "25", From: "noreply@travels.example"})
+	assert.NoError(t, err)
+	assert.IsType(t, &SMTP{}, m)
+
+	_, err = New(Config{Driver: "pigeon"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,30 @@
+package mailer
+
+import (
+	"net"
+	"net/smtp"
+)
+
+// SMTP delivers messages through an SMTP server, upgrading to TLS when the server offers it.
+type SMTP struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+// NewSMTP returns a driver sending through host:port, PLAIN authentication is used when username is set.
+func NewSMTP(host, port, username, password, from string) *SMTP {
+	s := &SMTP{addr: net.JoinHostPort(host, port), from: from}
+	if username != "" {
+		s.auth = smtp.PlainAuth("", username, password, host)
+	}
+	return s
+}
+
+func (s *SMTP) Send(msg Message) error {
+	data, err := format(s.from, msg)
+	if err != nil {
+		return err
+	}
+	return smtp.SendMail(s.addr, s.auth, s.from, []string{msg.To}, data)
+}
@@ -0,0 +1,50 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often Memory drops full buckets, a full bucket is the same as a missing one.
+const sweepInterval = time.Minute
+
+// Memory keeps token buckets in the memory of one instance.
+type Memory struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	swept   time.Time
+}
+
+func NewMemory() *Memory {
+	return &Memory{
+		buckets: make(map[string]*bucket),
+	}
+}
+
+func (m *Memory) Take(key string, limit Limit, now time.Time) (Result, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if now.Sub(m.swept) >= sweepInterval {
+		m.sweep(now)
+	}
+
+	b, ok := m.buckets[key]
+	if !ok || b.limit != limit {
+		b = &bucket{tokens: float64(limit.Burst), updated: now, limit: limit}
+		m.buckets[key] = b
+	}
+	b.refill(now)
+	return b.take(), nil
+}
+
+// sweep drops the buckets that refilled completely, so idle clients don't hold memory.
+func (m *Memory) sweep(now time.Time) {
+	for key, b := range m.buckets {
+		b.refill(now)
+		if b.tokens >= float64(b.limit.Burst) {
+			delete(m.buckets, key)
+		}
+	}
+	m.swept = now
+}
@@ -0,0 +1,73 @@
+package ratelimit
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestMemory_Take(t *testing.T) {
+	store := NewMemory()
+	limit := Limit{Rate: 2, Burst: 3}
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for i := 2; i >= 0; i-- {
+		res, err := store.Take("user:1", limit, now)
+		assert.NoError(t, err)
+		assert.True(t, res.Allowed)
+		assert.Equal(t, i, res.Remaining)
+	}
+
+	res, _ := store.Take("user:1", limit, now)
+	assert.False(t, res.Allowed)
+	assert.Equal(t, 500*time.Millisecond, res.RetryAfter)
+	assert.Equal(t, 1500*time.Millisecond, res.Reset)
+
+	res, _ = store.Take("user:2", limit, now)
+	assert.True(t, res.Allowed, "buckets are kept per key")
+
+	res, _ = store.Take("user:1", limit, now.Add(500*time.Millisecond))
+	assert.True(t, res.Allowed, "a token is refilled after 1/rate seconds")
+	assert.Equal(t, 0, res.Remaining)
+}
+
+func TestMemory_TakeRefillsUpToBurst(t *testing.T) {
+	store := NewMemory()
+	limit := Limit{Rate: 1, Burst: 2}
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	store.Take("ip:10.0.0.1", limit, now)
+	res, _ := store.Take("ip:10.0.0.1", limit, now.Add(time.Hour))
+
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 1, res.Remaining)
+	assert.Equal(t, time.Second, res.Reset)
+}
+
+func TestMemory_Sweep(t *testing.T) {
+	store := NewMemory()
+	limit := Limit{Rate: 1, Burst: 5}
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	store.Take("idle", limit, now)
+	store.Take("busy", limit, now.Add(sweepInterval-time.Second))
+	store.Take("busy", limit, now.Add(sweepInterval))
+
+	assert.NotContains(t, store.buckets, "idle")
+	assert.Contains(t, store.buckets, "busy")
+}
+
+func TestPrefixed(t *testing.T) {
+	store := NewMemory()
+	limit := Limit{Rate: 1, Burst: 1}
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	res, _ := Prefixed(store, "tenant:1:").Take("user:1", limit, now)
+	assert.True(t, res.Allowed)
+
+	res, _ = Prefixed(store, "tenant:2:").Take("user:1", limit, now)
+	assert.True(t, res.Allowed, "buckets are kept per prefix")
+
+	res, _ = store.Take("tenant:1:user:1", limit, now)
+	assert.False(t, res.Allowed, "the bucket lives in the shared store")
+}
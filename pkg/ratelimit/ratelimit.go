@@ -0,0 +1,83 @@
+// Package ratelimit implements token bucket rate limiting behind a store interface,
+// so buckets can live in memory or in a store shared by several instances.
+package ratelimit
+
+import (
+	"math"
+	"time"
+)
+
+// Limit allows Burst requests at once, the bucket refills at Rate requests per second.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// Result of taking a token from a bucket.
+type Result struct {
+	// Allowed reports whether a token was taken.
+	Allowed bool
+
+	// Remaining whole tokens left in the bucket.
+	Remaining int
+
+	// RetryAfter is the wait until the next token when the request was not allowed.
+	RetryAfter time.Duration
+
+	// Reset is the wait until the bucket is full again.
+	Reset time.Duration
+}
+
+// Store keeps token buckets by key.
+type Store interface {
+	// Take takes a token from the bucket of key, a missing bucket starts full.
+	Take(key string, limit Limit, now time.Time) (Result, error)
+}
+
+// Prefixed returns a store keeping its buckets in store under keys starting with prefix,
+// so several users of one store don't share buckets.
+func Prefixed(store Store, prefix string) Store {
+	return prefixed{store, prefix}
+}
+
+type prefixed struct {
+	store  Store
+	prefix string
+}
+
+func (p prefixed) Take(key string, limit Limit, now time.Time) (Result, error) {
+	return p.store.Take(p.prefix+key, limit, now)
+}
+
+// bucket is the state of one token bucket, tokens are counted as of updated.
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	limit   Limit
+}
+
+// refill adds the tokens earned since the last update.
+func (b *bucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.updated).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(float64(b.limit.Burst), b.tokens+elapsed*b.limit.Rate)
+		b.updated = now
+	}
+}
+
+// take takes a token when one is left and reports the bucket state.
+func (b *bucket) take() Result {
+	res := Result{}
+	if b.tokens >= 1 {
+		b.tokens--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = seconds((1 - b.tokens) / b.limit.Rate)
+	}
+	res.Remaining = int(b.tokens)
+	res.Reset = seconds((float64(b.limit.Burst) - b.tokens) / b.limit.Rate)
+	return res
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
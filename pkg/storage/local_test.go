@@ -0,0 +1,45 @@
+package storage
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestLocal(t *testing.T) {
+	store, err := New("local", t.TempDir())
+	assert.NoError(t, err)
+
+	assert.NoError(t, store.Put("photos/a.jpg", strings.NewReader("first")))
+	assert.NoError(t, store.Put("photos/a.jpg", strings.NewReader("second")))
+
+	f, err := store.Get("photos/a.jpg")
+	assert.NoError(t, err)
+	content, err := io.ReadAll(f)
+	assert.NoError(t, err)
+	assert.NoError(t, f.Close())
+	assert.Equal(t, "second", string(content))
+
+	assert.NoError(t, store.Delete("photos/a.jpg"))
+	assert.NoError(t, store.Delete("photos/a.jpg"))
+
+	_, err = store.Get("photos/a.jpg")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestLocal_InvalidKey(t *testing.T) {
+	store, err := NewLocal(t.TempDir())
+	assert.NoError(t, err)
+
+	for _, key := range []string{"", "../a.jpg", "photos/../../a.jpg", "/etc/passwd"} {
+		assert.Error(t, store.Put(key, strings.NewReader("x")), key)
+		_, err = store.Get(key)
+		assert.Error(t, err, key)
+	}
+}
+
+func TestNew_UnknownDriver(t *testing.T) {
+	_, err := New("s3", t.TempDir())
+	assert.Error(t, err)
+}
@@ -0,0 +1,40 @@
+// Package storage keeps uploaded files behind a driver-agnostic interface.
+// Keys are slash separated relative paths such as "photos/ab12.jpg", every driver maps them to its own layout.
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrNotFound is returned when no file is stored under the key.
+var ErrNotFound = errors.New("file not found")
+
+// File is a stored file opened for reading, it supports seeking so it can serve range requests.
+type File interface {
+	io.ReadSeeker
+	io.Closer
+}
+
+// Storage saves, opens and deletes files by key.
+type Storage interface {
+	// Put stores the content of r under key, replacing any previous file.
+	Put(key string, r io.Reader) error
+
+	// Get opens the file stored under key, the caller closes it.
+	Get(key string) (File, error)
+
+	// Delete removes the file stored under key, deleting a missing file is not an error.
+	Delete(key string) error
+}
+
+// New returns the storage driver by name, an empty name selects the local filesystem.
+func New(driver, dir string) (Storage, error) {
+	switch driver {
+	case "", "local":
+		return NewLocal(dir)
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", driver)
+	}
+}
@@ -0,0 +1,144 @@
+// Package token signs and verifies HS256 JSON Web Tokens.
+// Every token names its signing key in the "kid" header, so keys can be rotated: new tokens are signed with the
+// active key while tokens signed with older keys stay valid until they expire or the key is removed.
+package token
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// minSecretLength is the shortest accepted signing secret, HS256 keys should carry at least 256 bits.
+const minSecretLength = 32
+
+var (
+	ErrInvalidToken = errors.New("invalid token")
+	ErrExpiredToken = errors.New("token expired")
+)
+
+// Claims are the registered JWT claims the API uses.
+// Audience tells tokens issued for other purposes apart from access tokens, which have none.
+// Tenant is the private "tid" claim naming the tenant the subject belongs to.
+type Claims struct {
+	Subject   string `json:"sub"`
+	Audience  string `json:"aud,omitempty"`
+	Tenant    string `json:"tid,omitempty"`
+	ID        string `json:"jti,omitempty"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+type header struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ"`
+	Kid string `json:"kid"`
+}
+
+// Keyring holds the signing secrets by key id, the active key signs new tokens.
+type Keyring struct {
+	active string
+	keys   map[string][]byte
+}
+
+// NewKeyring returns a keyring signing with the active key, every key in keys verifies.
+// An empty active id is allowed only when there is a single key.
+func NewKeyring(active string, keys map[string][]byte) (*Keyring, error) {
+	if active == "" && len(keys) == 1 {
+		for id := range keys {
+			active = id
+		}
+	}
+	if _, ok := keys[active]; !ok {
+		return nil, fmt.Errorf("signing key %q is not configured", active)
+	}
+	for id, secret := range keys {
+		if len(secret) < minSecretLength {
+			return nil, fmt.Errorf("signing key %q is shorter than %d bytes", id, minSecretLength)
+		}
+	}
+	return &Keyring{active: active, keys: keys}, nil
+}
+
+// ParseKeys reads keys written as comma separated "id:secret" pairs.
+func ParseKeys(s string) (map[string][]byte, error) {
+	keys := map[string][]byte{}
+	for _, pair := range strings.Split(s, ",") {
+		id, secret, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		if !ok || id == "" || secret == "" {
+			return nil, errors.New(`signing keys must be "id:secret" pairs separated by commas`)
+		}
+		if _, dup := keys[id]; dup {
+			return nil, fmt.Errorf("signing key %q is listed twice", id)
+		}
+		keys[id] = []byte(secret)
+	}
+	return keys, nil
+}
+
+// Sign returns the token for claims signed with the active key.
+func (k *Keyring) Sign(claims Claims) (string, error) {
+	h, err := json.Marshal(header{Alg: "HS256", Typ: "JWT", Kid: k.active})
+	if err != nil {
+		return "", err
+	}
+	p, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	unsigned := encode(h) + "." + encode(p)
+	return unsigned + "." + encode(sign(k.keys[k.active], unsigned)), nil
+}
+
+// Verify checks the signature and expiry of token at now and returns its claims.
+func (k *Keyring) Verify(token string, now time.Time) (Claims, error) {
+	claims := Claims{}
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return claims, ErrInvalidToken
+	}
+
+	h := header{}
+	if err := decode(parts[0], &h); err != nil || h.Alg != "HS256" {
+		return claims, ErrInvalidToken
+	}
+	secret, ok := k.keys[h.Kid]
+	if !ok {
+		return claims, ErrInvalidToken
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !hmac.Equal(signature, sign(secret, parts[0]+"."+parts[1])) {
+		return claims, ErrInvalidToken
+	}
+
+	if err = decode(parts[1], &claims); err != nil {
+		return claims, ErrInvalidToken
+	}
+	if now.Unix() >= claims.ExpiresAt {
+		return claims, ErrExpiredToken
+	}
+	return claims, nil
+}
+
+func sign(secret []byte, unsigned string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(unsigned))
+	return mac.Sum(nil)
+}
+
+func encode(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decode(s string, v interface{}) error {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
@@ -0,0 +1,88 @@
+package token
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+var (
+	oldSecret = []byte("0123456789abcdef0123456789abcdef")
+	newSecret = []byte("fedcba9876543210fedcba9876543210")
+)
+
+func TestKeyring_Verify(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	claims := Claims{Subject: "1", Audience: "email-verification", Tenant: "2", ID: "ab12", IssuedAt: now.Unix(),
+		ExpiresAt: now.Add(15 * time.Minute).Unix()}
+
+	old, err := NewKeyring("old", map[string][]byte{"old": oldSecret})
+	assert.NoError(t, err)
+	rotated, err := NewKeyring("new", map[string][]byte{"old": oldSecret, "new": newSecret})
+	assert.NoError(t, err)
+	other, err := NewKeyring("old", map[string][]byte{"old": newSecret})
+	assert.NoError(t, err)
+
+	signedOld, err := old.Sign(claims)
+	assert.NoError(t, err)
+	signedNew, err := rotated.Sign(claims)
+	assert.NoError(t, err)
+
+	testTable := []struct {
+		name    string
+		keyring *Keyring
+		token   string
+		now     time.Time
+		wantErr error
+	}{
+		{name: "Ok", keyring: old, token: signedOld, now: now},
+		{name: "Rotated Key Still Verifies", keyring: rotated, token: signedOld, now: now},
+		{name: "Unknown Key", keyring: old, token: signedNew, now: now, wantErr: ErrInvalidToken},
+		{name: "Wrong Secret", keyring: other, token: signedOld, now: now, wantErr: ErrInvalidToken},
+		{name: "Expired", keyring: old, token: signedOld, now: now.Add(15 * time.Minute), wantErr: ErrExpiredToken},
+		{name: "Tampered", keyring: old, token: tamper(signedOld), now: now, wantErr: ErrInvalidToken},
+		{name: "Malformed", keyring: old, token: "abc", now: now, wantErr: ErrInvalidToken},
+		{name: "Alg None", keyring: old, token: "eyJhbGciOiJub25lIiwia2lkIjoib2xkIn0.eyJzdWIiOiIxIn0.", now: now, wantErr: ErrInvalidToken},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.keyring.Verify(tt.token, tt.now)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, claims, got)
+			}
+		})
+	}
+}
+
+// tamper replaces the payload with one claiming another subject, keeping the signature.
+func tamper(token string) string {
+	parts := strings.Split(token, ".")
+	parts[1] = encode([]byte(`{"sub":"2","iat":1700000000,"exp":1700000900}`))
+	return strings.Join(parts, ".")
+}
+
+func TestNewKeyring(t *testing.T) {
+	_, err := NewKeyring("", map[string][]byte{"only": oldSecret})
+	assert.NoError(t, err)
+
+	_, err = NewKeyring("", map[string][]byte{"a": oldSecret, "b": newSecret})
+	assert.Error(t, err)
+
+	_, err = NewKeyring("a", map[string][]byte{"a": []byte("short")})
+	assert.Error(t, err)
+}
+
+func TestParseKeys(t *testing.T) {
+	keys, err := ParseKeys("2024-01:" + string(newSecret) + ", 2023-06:" + string(oldSecret))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]byte{"2024-01": newSecret, "2023-06": oldSecret}, keys)
+
+	for _, s := range []string{"", "nosecret", ":secret", "a:x,a:y"} {
+		_, err = ParseKeys(s)
+		assert.Error(t, err, s)
+	}
+}